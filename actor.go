@@ -10,8 +10,11 @@ import (
 
 	"github.com/pyros2097/spike/g2d"
 	. "github.com/pyros2097/spike/interpolation"
+	"github.com/pyros2097/spike/intersector"
+	"github.com/pyros2097/spike/math/collision"
 	"github.com/pyros2097/spike/math/shape"
 	"github.com/pyros2097/spike/math/vector"
+	ui "github.com/pyros2097/spike/ui/utils"
 	"github.com/pyros2097/spike/utils"
 )
 
@@ -113,14 +116,22 @@ type Actor struct {
 	W, H     float32 // Width and Height
 	Z        uint32  // zindex
 	OX, OY   float32 // origin
-	SX, SY   float32 // scale
+	SX, SY   float32 // scale, 0 and 0 is taken as 1 and 1 so that actors built without a scale are not scaled
 	Rotation float32
 
-	// If false, the actor will not be drawn and will not receive touch events. Default is true.
-	Visible bool
+	// The 3D bounds of the actor in its coordinate system, used by pick rays. They are transformed by the actor and its
+	// parents on x and y like its rectangle. If nil the actor is picked as its rectangle at z 0.
+	Bounds3d *collision.BoundingBox
+
+	// If set, the actor and its children are not drawn and do not receive touch events. Default is false.
+	Hidden bool
 
 	Debug bool
 
+	// If true the actor does not act while it is culled, its actions and Act are paused until it is drawn again. Use it for
+	// actors which only animate themselves, not for actors which move into the view by themselves. Default is false.
+	CullActing bool
+
 	Actions []*Action
 
 	// Called by the framework when an actor is added to or removed from a group.
@@ -132,6 +143,10 @@ type Actor struct {
 	// Determines how touch events are distributed to a actor. Default is {@link Touchable#enabled}.
 	TouchState Touchable
 
+	// If set the actor can get the focus, which is moved between the focusable actors with the arrow keys and controllers.
+	// Default is false, buttons and text fields set it.
+	Focusable bool
+
 	// An application specific object for convenience.
 	UserObject interface{}
 
@@ -153,19 +168,44 @@ type Actor struct {
 	// param parentAlpha Should be multiplied with the actor's alpha, allowing a parent's alpha to affect all children.
 	Draw func(a *Actor, batch g2d.Batch, parentAlpha float32)
 
-	// Called when an input event occurrs
+	// Called when an input event occurrs. The touch events are sent to all the actors which are touchable, the actor
+	// checks whether they are within its bounds with Hit.
 	Input func(a *Actor, event InputEvent)
 
+	// Called when the actor gains or loses the keyboard focus, see SetKeyboardFocus
+	OnFocus func(a *Actor, focused bool)
+
+	// The layout of the actor if it is a widget, set by the widget itself. Containers like Table use it to get the
+	// minimum, preferred and maximum size of the actor and to lay it out after sizing it.
+	Widget ui.ILayout
+
+	// Draws the debug lines of the actor in its coordinates when Debug is set, instead of outlining its bounds
+	DrawDebugLines func(a *Actor, shapes *g2d.ShapeRenderer)
+
 	Children                        []*Actor
-	worldTransform                  *vector.Affine2
+	localTransform, worldTransform  *vector.Affine2
 	computedTransform, oldTransform *vector.Matrix4
 	transform                       bool
+	transformKey                    [7]float32 // the position, rotation, scale and origin the transforms were computed with
+	transformVersion, parentVersion uint32     // the versions of the world transforms of the actor and of its parent
 	cullingArea                     shape.Rectangle
+	clipBounds                      shape.Rectangle // the children are clipped to it if it is not empty
+	modal                           bool            // if set and a child of the scene, only it receives touch events
+	culled                          bool
 	initialized                     bool
 }
 
 var (
 	tmp = vector.NewVector2Empty()
+
+	// incremented every time a world transform is computed, so each computed transform has a unique version
+	transformVersions uint32
+	tmpAffine         = vector.NewAffine2Empty()
+
+	hitBox    = collision.NewBoundingBox()
+	hitPoint  = vector.NewVector3Empty()
+	hitMatrix = vector.NewMatrix4Empty()
+	localRay  = collision.NewRay(vector.NewVector3Empty(), vector.NewVector3Empty())
 )
 
 // Draws the group and its children. The default implementation calls {@link #applyTransform(Batch, Matrix4)} if needed, then
@@ -473,10 +513,127 @@ var (
 //    children.end();
 //  }
 
-// transform:         true,
-// worldTransform:    vector.NewAffine2Empty(),
-// oldTransform:      vector.NewMatrix4Empty(),
-// computedTransform: vector.NewMatrix4Empty(),
+// Returns the transform of the actor's coordinate system to the stage's coordinate system as a matrix, which the batch is
+// set to when the children are drawn. The transforms are cached and only computed again when the position, rotation, scale
+// or origin of the actor or of one of its parents has changed.
+func (a *Actor) ComputeTransform() *vector.Matrix4 {
+	a.computeTransform()
+	return a.computedTransform
+}
+
+// Returns the transform of the actor's coordinate system to the stage's coordinate system
+func (a *Actor) GetWorldTransform() *vector.Affine2 {
+	return a.computeTransform()
+}
+
+// Returns whether the actor moves, rotates or scales the coordinate system of its children. When it does the batch
+// transform matrix is set to the actor's transform while the children are drawn.
+func (a *Actor) IsTransform() bool {
+	a.computeTransform()
+	return a.transform
+}
+
+// Computes the local and world transforms if they are dirty and returns the world transform
+func (a *Actor) computeTransform() *vector.Affine2 {
+	var parent *vector.Affine2
+	var parentVersion uint32
+	if a.Parent != nil {
+		parent = a.Parent.computeTransform()
+		parentVersion = a.Parent.transformVersion
+	}
+	key := [7]float32{a.X, a.Y, a.Rotation, a.SX, a.SY, a.OX, a.OY}
+	if a.worldTransform != nil && key == a.transformKey && parentVersion == a.parentVersion {
+		return a.worldTransform
+	}
+	if a.worldTransform == nil {
+		a.localTransform = vector.NewAffine2Empty()
+		a.worldTransform = vector.NewAffine2Empty()
+		a.computedTransform = vector.NewMatrix4Empty()
+		a.oldTransform = vector.NewMatrix4Empty()
+	}
+	scaleX, scaleY := a.GetScale()
+	a.localTransform.SetToTrnRotScl(a.X+a.OX, a.Y+a.OY, a.Rotation, scaleX, scaleY)
+	if a.OX != 0 || a.OY != 0 {
+		a.localTransform.Translate(-a.OX, -a.OY)
+	}
+	a.transform = !a.localTransform.IsIdt()
+	a.worldTransform.Set(a.localTransform)
+	if parent != nil {
+		a.worldTransform.PreMulA(parent)
+	}
+	a.computedTransform.SetA2(a.worldTransform)
+	transformVersions++
+	a.transformKey, a.transformVersion, a.parentVersion = key, transformVersions, parentVersion
+	return a.worldTransform
+}
+
+// Returns the transform of the actor's coordinate system to its parent's coordinate system, in which the actor is drawn
+func (a *Actor) computeLocalTransform() *vector.Affine2 {
+	a.computeTransform()
+	return a.localTransform
+}
+
+// Sets the batch's transformation matrix to the transform, restoring it with resetTransform
+func (a *Actor) applyTransform(batch g2d.Batch, transform *vector.Matrix4) {
+	a.oldTransform.SetM4(batch.GetTransformMatrix())
+	batch.SetTransformMatrix(transform)
+}
+
+// Restores the batch transform to what it was before applyTransform
+func (a *Actor) resetTransform(batch g2d.Batch) {
+	batch.SetTransformMatrix(a.oldTransform)
+}
+
+// Transforms the point in the actor's coordinates to the stage's coordinates
+func (a *Actor) LocalToStageCoordinates(localCoords *vector.Vector2) *vector.Vector2 {
+	a.computeTransform().ApplyTo(localCoords)
+	return localCoords
+}
+
+// Transforms the point in the stage's coordinates to the actor's coordinates. The point is not changed if the actor is
+// scaled to zero.
+func (a *Actor) StageToLocalCoordinates(stageCoords *vector.Vector2) *vector.Vector2 {
+	invert(tmpAffine.Set(a.computeTransform()), stageCoords)
+	return stageCoords
+}
+
+// Transforms the point in the actor's coordinates to the parent's coordinates
+func (a *Actor) LocalToParentCoordinates(localCoords *vector.Vector2) *vector.Vector2 {
+	a.computeTransform()
+	a.localTransform.ApplyTo(localCoords)
+	return localCoords
+}
+
+// Transforms the point in the parent's coordinates to the actor's coordinates. The point is not changed if the actor is
+// scaled to zero.
+func (a *Actor) ParentToLocalCoordinates(parentCoords *vector.Vector2) *vector.Vector2 {
+	a.computeTransform()
+	invert(tmpAffine.Set(a.localTransform), parentCoords)
+	return parentCoords
+}
+
+// Transforms the point in the actor's coordinates to the coordinates of the ascendant, which does not need to be the direct
+// parent. With a nil ascendant the point is transformed to the stage's coordinates.
+func (a *Actor) LocalToAscendantCoordinates(ascendant *Actor, localCoords *vector.Vector2) *vector.Vector2 {
+	for actor := a; actor != nil && actor != ascendant; actor = actor.Parent {
+		actor.LocalToParentCoordinates(localCoords)
+	}
+	return localCoords
+}
+
+// Transforms the point in the actor's coordinates to the coordinates of the other actor, which may be anywhere in the stage
+func (a *Actor) LocalToActorCoordinates(actor *Actor, localCoords *vector.Vector2) *vector.Vector2 {
+	return actor.StageToLocalCoordinates(a.LocalToStageCoordinates(localCoords))
+}
+
+// Applies the inverse of the transform to the point, if the transform can be inverted. The transform is modified.
+func invert(transform *vector.Affine2, point *vector.Vector2) bool {
+	if transform.Det() == 0 {
+		return false
+	}
+	transform.Inv().ApplyTo(point)
+	return true
+}
 
 // Updates the actor based on time. Typically this is called each frame by {@link Stage#act(float)}.
 // The default implementation calls {@link Action#act(float)} on each action and removes actions that are complete.
@@ -486,29 +643,117 @@ func (a *Actor) act(delta float32) {
 		a.Init(a)
 		a.initialized = true
 	}
-	if len(a.Actions) > 0 {
-		action := a.Actions[0]
-		if action.Act(action, delta) {
-			action.Actor = nil
-			action.Act = nil
-			actionsPool.Put(action)
-			a.Actions = a.Actions[1:len(a.Actions)]
+	if !a.CullActing || !a.culled {
+		if len(a.Actions) > 0 {
+			action := a.Actions[0]
+			if action.Act(action, delta) {
+				action.Actor = nil
+				action.Act = nil
+				actionsPool.Put(action)
+				a.Actions = a.Actions[1:len(a.Actions)]
+			}
+		}
+		if a.Act != nil {
+			a.Act(a, delta)
 		}
 	}
-	if a.Act != nil {
-		a.Act(a, delta)
-	}
-	if a.Children != nil {
-		for _, child := range a.Children {
-			child.act(delta)
+	actChildren(a, delta)
+}
+
+// Acts the children of the actor, a child may remove itself while it acts
+func actChildren(a *Actor, delta float32) {
+	for i := 0; i < len(a.Children); i++ {
+		child := a.Children[i]
+		child.act(delta)
+		if i < len(a.Children) && a.Children[i] != child {
+			i--
 		}
 	}
 }
 
+// Draws the actor if it is in the view of the camera and then its children which are within its culling area, if set. The
+// batch transform matrix is set to the actor's transform while its children are drawn, if it moves, rotates or scales them.
 func (a *Actor) draw(batch g2d.Batch, parentAlpha float32) {
+	if a.Hidden {
+		return
+	}
+	if a == focusedActor {
+		defer a.drawFocus(batch, parentAlpha)
+	}
 	if a.Draw != nil {
-		a.Draw(a, batch, parentAlpha)
+		a.culled = !a.inView()
+		if a.culled {
+			culledActors++
+		} else {
+			drawnActors++
+			a.Draw(a, batch, parentAlpha)
+		}
+	}
+	if len(a.Children) == 0 {
+		return
+	}
+	transform := a.IsTransform()
+	if transform {
+		a.applyTransform(batch, a.computedTransform)
+	}
+	clip := &a.clipBounds
+	clipped := clip.W > 0 && clip.H > 0
+	if !clipped || a.clipBegin(batch, clip.X, clip.Y, clip.W, clip.H) {
+		for _, child := range a.Children {
+			if !a.inCullingArea(child) {
+				child.culled = true
+				culledActors++
+				continue
+			}
+			child.draw(batch, parentAlpha)
+		}
+		if clipped {
+			a.clipEnd(batch)
+		}
+	}
+	if transform {
+		a.resetTransform(batch)
+	}
+}
+
+// Children completely outside of the rectangle will not be drawn, the rectangle is in the coordinates of the actor. This
+// is only valid for use with unrotated and unscaled children. It is cleared with nil.
+func (a *Actor) SetCullingArea(cullingArea *shape.Rectangle) {
+	if cullingArea == nil {
+		a.cullingArea = shape.Rectangle{}
+		return
+	}
+	a.cullingArea = *cullingArea
+}
+
+// Returns the culling area of the children or nil if it is not set
+func (a *Actor) GetCullingArea() *shape.Rectangle {
+	if a.cullingArea.W <= 0 || a.cullingArea.H <= 0 {
+		return nil
 	}
+	return &a.cullingArea
+}
+
+// Returns whether the child is within the culling area, true if it is not set
+func (a *Actor) inCullingArea(child *Actor) bool {
+	area := &a.cullingArea
+	if area.W <= 0 || area.H <= 0 {
+		return true
+	}
+	return child.X <= area.X+area.W && child.Y <= area.Y+area.H && child.X+child.W >= area.X && child.Y+child.H >= area.Y
+}
+
+// Returns whether the bounds of the actor are in the view of the camera the scene is drawn with. Actors with Bounds3d are
+// checked against the whole frustum, the others as their rectangle at z 0.
+func (a *Actor) inView() bool {
+	if !CullingEnabled || cullFrustum == nil {
+		return true
+	}
+	if a.Bounds3d != nil {
+		return cullFrustum.BoundsInFrustumBox(hitBox.SetB(a.Bounds3d).Mul(a.ComputeTransform()))
+	}
+	rect := a.stageBounds(&cullBounds)
+	return cullFrustum.BoundsInFrustumWithoutNearFar(rect.X+rect.W/2, rect.Y+rect.H/2, 0, rect.W/2, rect.H/2, 0)
 }
 
 func (a *Actor) AddAction(action *Action) {
@@ -604,10 +849,15 @@ func (a *Actor) AddActorAt(index int, actor *Actor) {
 // {@link Action#setPool(com.badlogic.gdx.utils.Pool) pool}, if any. This is not done automatically.
 // returns true if the actor was removed from this group.
 func (a *Actor) RemoveActor(actor *Actor) bool {
-	// if (!children.removeValue(actor, true)) return false;
-	a.Parent = nil
-	a.ChildrenChanged()
-	return true
+	for i, child := range a.Children {
+		if child == actor {
+			a.Children = append(a.Children[:i], a.Children[i+1:]...)
+			actor.Parent = nil
+			a.ChildrenChanged()
+			return true
+		}
+	}
+	return false
 }
 
 // Removes all actors from this group.
@@ -630,6 +880,40 @@ func (a *Actor) IsTouchable() bool {
 	return a.TouchState == TouchableEnabled
 }
 
+// Hands the input event to the actor and then to its children. Touch events are not sent to actors which are not
+// touchable, nor to their children unless they are touchable by TouchableChildrenOnly.
+func (a *Actor) input(e InputEvent) {
+	touch := isTouchEvent(e.Type)
+	if touch && a.TouchState == TouchableDisabled {
+		return
+	}
+	if a.Input != nil && (!touch || a.TouchState == TouchableEnabled) {
+		a.Input(a, e)
+	}
+	for _, child := range a.Children {
+		child.input(e)
+	}
+}
+
+// Returns whether the point in stage coordinates is within the bounds of the actor, rotated and scaled with it and its
+// parents. Actors which are hidden, or whose parents are, are never hit. The point is transformed to the actor's
+// coordinates in local.
+func (a *Actor) Hit(x, y float32, local *vector.Vector2) bool {
+	for actor := a; actor != nil; actor = actor.Parent {
+		if actor.Hidden {
+			return false
+		}
+		if clip := &actor.clipBounds; actor != a && clip.W > 0 && clip.H > 0 {
+			actor.StageToLocalCoordinates(local.Set(x, y))
+			if !clip.Contains(local.X, local.Y) {
+				return false
+			}
+		}
+	}
+	a.StageToLocalCoordinates(local.Set(x, y))
+	return local.X >= 0 && local.X < a.W && local.Y >= 0 && local.Y < a.H
+}
+
 // Returns the X position of the specified {@link Align alignment}
 func (a *Actor) GetXAlign(alignment utils.Alignment) float32 {
 	x := a.X
@@ -764,7 +1048,7 @@ func (a *Actor) SetBounds(x, y, w, h float32) {
 		a.positionChanged()
 	}
 	if a.W != w || a.H != h {
-		a.W = h
+		a.W = w
 		a.H = h
 		a.sizeChanged()
 	}
@@ -795,16 +1079,25 @@ func (a *Actor) SetOriginAlign(alignment utils.Alignment) {
 	}
 }
 
-// Sets the scale X and scale Y.
+// Sets the scale X and scale Y. A scale of 0 and 0 is taken as 1 and 1, hide the actor with Hidden instead.
 func (a *Actor) SetScale(scaleX, scaleY float32) {
 	a.SX = scaleX
 	a.SY = scaleY
 }
 
+// Returns the scale the actor is drawn with, 1 and 1 if neither SX nor SY is set
+func (a *Actor) GetScale() (float32, float32) {
+	if a.SX == 0 && a.SY == 0 {
+		return 1, 1
+	}
+	return a.SX, a.SY
+}
+
 // Adds the specified scale to the current scale.
 func (a *Actor) ScaleBy(scaleX, scaleY float32) {
-	a.SX += scaleX
-	a.SY += scaleY
+	sx, sy := a.GetScale()
+	a.SX = sx + scaleX
+	a.SY = sy + scaleY
 }
 
 func (a *Actor) GetRotation() float32 {
@@ -998,35 +1291,21 @@ func (a *Actor) GetZIndex() int32 {
 //     }
 //   }
 
-//   // Calls {@link #clipBegin(float, float, float, float)} to clip a actor's bounds.
-//   public boolean clipBegin () {
-//     return clipBegin(x, y, width, height);
-//   }
-
-//   // Clips the specified screen aligned rectangle, specified relative to the transform matrix of the stage's Batch. The transform
-//    * matrix and the stage's camera must not have rotational components. Calling a method must be followed by a call to
-//    * {@link #clipEnd()} if true is returned.
-//    * @return false if the clipping area is zero and no drawing should occur.
-//    * @see ScissorStack
-//   public boolean clipBegin (float x, float y, float width, float height) {
-//     if (width <= 0 || height <= 0) return false;
-//     Rectangle tableBounds = Rectangle.tmp;
-//     tableBounds.x = x;
-//     tableBounds.y = y;
-//     tableBounds.width = width;
-//     tableBounds.height = height;
-//     Stage stage = a.stage;
-//     Rectangle scissorBounds = Pools.obtain(Rectangle.class);
-//     stage.calculateScissors(tableBounds, scissorBounds);
-//     if (ScissorStack.pushScissors(scissorBounds)) return true;
-//     Pools.free(scissorBounds);
-//     return false;
-//   }
+// Clips the drawing to the rectangle in the actor's coordinates, the actor and the camera must not be rotated. It must be
+// followed by clipEnd if it returns true, it returns false if the clipped area is empty and nothing should be drawn.
+func (a *Actor) clipBegin(batch g2d.Batch, x, y, width, height float32) bool {
+	if width <= 0 || height <= 0 {
+		return false
+	}
+	batch.Flush()
+	return pushScissors(calculateScissors(a, x, y, width, height))
+}
 
-//   // Ends clipping begun by {@link #clipBegin(float, float, float, float)}.
-//   public void clipEnd () {
-//     Pools.free(ScissorStack.popScissors());
-//   }
+// Ends the clipping begun by clipBegin
+func (a *Actor) clipEnd(batch g2d.Batch) {
+	batch.Flush()
+	popScissors()
+}
 
 //   // Transforms the specified point in screen coordinates to the actor's local coordinate system.
 //   public Vector2 screenToLocalCoordinates (Vector2 screenCoords) {
@@ -1150,6 +1429,87 @@ func (a *Actor) Collides(other *Actor) bool {
 	return false
 }
 
+// Returns the position of the actor in stage coordinates, which is its position transformed by its parents
+func (a *Actor) stagePosition() (x, y float32) {
+	if a.Parent == nil {
+		return a.X, a.Y
+	}
+	a.Parent.LocalToStageCoordinates(tmp.Set(a.X, a.Y))
+	return tmp.X, tmp.Y
+}
+
+// Sets the rectangle to the axis aligned bounds of the actor in stage coordinates, transformed by the actor and its parents
+func (a *Actor) stageBounds(rect *shape.Rectangle) *shape.Rectangle {
+	transform := a.computeTransform()
+	corners := [4][2]float32{{0, 0}, {a.W, 0}, {a.W, a.H}, {0, a.H}}
+	for i, corner := range corners {
+		transform.ApplyTo(tmp.Set(corner[0], corner[1]))
+		if i == 0 {
+			rect.Set(tmp.X, tmp.Y, 0, 0)
+		} else {
+			rect.Merge(tmp.X, tmp.Y)
+		}
+	}
+	return rect
+}
+
+// HitRay returns whether the pick ray hits the bounds of the actor. intersection is set to the hit closest to the origin
+// of the ray, it may be nil. The actor is hit as its rectangle at z 0 or as its Bounds3d, transformed by the actor and its
+// parents.
+func (a *Actor) HitRay(ray *collision.Ray, intersection *vector.Vector3) bool {
+	if a.Bounds3d != nil {
+		// the ray is moved to the coordinate system of the actor, where its bounds are axis aligned
+		transform := a.ComputeTransform()
+		if transform.Det() == 0 {
+			return false
+		}
+		localRay.SetRay(ray).Mul(hitMatrix.SetM4(transform).Inv())
+		if !intersector.IntersectRayBounds(localRay, a.Bounds3d, intersection) {
+			return false
+		}
+		if intersection != nil {
+			intersection.Mul(transform)
+		}
+		return true
+	}
+	if ray.Direction.Z == 0 {
+		return false
+	}
+	t := -ray.Origin.Z / ray.Direction.Z
+	if t < 0 {
+		return false
+	}
+	x, y := ray.Origin.X+ray.Direction.X*t, ray.Origin.Y+ray.Direction.Y*t
+	if !invert(tmpAffine.Set(a.computeTransform()), tmp.Set(x, y)) {
+		return false
+	}
+	if tmp.X < 0 || tmp.Y < 0 || tmp.X > a.W || tmp.Y > a.H {
+		return false
+	}
+	if intersection != nil {
+		intersection.Set(x, y, 0)
+	}
+	return true
+}
+
+// Returns the visible and touchable descendant hit closest to the origin of the ray, or hit if none is closer than the
+// squared distance dst2. Children on top and children of the same actor win ties.
+func (a *Actor) hitRay(ray *collision.Ray, hit *Actor, dst2 float32) (*Actor, float32) {
+	for i := len(a.Children) - 1; i >= 0; i-- {
+		child := a.Children[i]
+		if child.Hidden || child.TouchState == TouchableDisabled {
+			continue
+		}
+		hit, dst2 = child.hitRay(ray, hit, dst2)
+		if child.IsTouchable() && child.HitRay(ray, hitPoint) {
+			if d := hitPoint.Dst2V(ray.Origin); hit == nil || d < dst2 {
+				hit, dst2 = child, d
+			}
+		}
+	}
+	return hit, dst2
+}
+
 func (a *Actor) String() string {
 	return a.Name
 }
@@ -1172,7 +1532,7 @@ func (a *Actor) ActionTouchable(touchable Touchable) *Actor {
 func (a *Actor) ActionVisible(visible bool) *Actor {
 	action := actionsPool.Get().(*Action)
 	action.Act = func(action *Action, delta float32) bool {
-		a.Visible = visible
+		a.Hidden = !visible
 		return true
 	}
 	return a
@@ -1428,8 +1788,7 @@ func (a *Actor) ActionSizeBy(amountW, amountH, duration float32, interp Interpol
 
 // ActionScaleTo sets the actor's scale from its current value to a specific value.
 func (a *Actor) ActionScaleTo(endX, endY, duration float32, interp Interpolation) *Actor {
-	startX := a.X
-	startY := a.Y
+	startX, startY := a.GetScale()
 	action := generateTemporalAction(duration, interp, func(percent float32) {
 		a.SetScale(startX+(endX-startX)*percent, startY+(endY-startY)*percent)
 	})
@@ -0,0 +1,143 @@
+package spike
+
+import (
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/collision"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+func TestHidden(t *testing.T) {
+	drawn := map[string]int{}
+	count := func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		drawn[a.Name]++
+	}
+	child := &Actor{Name: "child", X: 5, Y: 5, W: 10, H: 10, SX: 1, SY: 1, Draw: count}
+	parent := &Actor{Name: "parent", W: 100, H: 100, SX: 1, SY: 1, Draw: count}
+	parent.AddActor(child)
+	local := vector.NewVector2Empty()
+
+	// actors built without setting Hidden are drawn and hit
+	parent.draw(tempBatch, 1)
+	if drawn["parent"] != 1 || drawn["child"] != 1 {
+		t.Errorf("drawn %v", drawn)
+	}
+	if !child.Hit(10, 10, local) {
+		t.Error("child not hit")
+	}
+
+	parent.Hidden = true
+	parent.draw(tempBatch, 1)
+	if drawn["parent"] != 1 || drawn["child"] != 1 {
+		t.Errorf("hidden parent drawn %v", drawn)
+	}
+	if child.Hit(10, 10, local) {
+		t.Error("child of a hidden parent hit")
+	}
+}
+
+func TestComputeTransform(t *testing.T) {
+	// actors built without a scale are not scaled
+	actor := &Actor{X: 30, Y: 40}
+	values := actor.ComputeTransform().GetValues()
+	if values[vector.M4_00] != 1 || values[vector.M4_11] != 1 || values[vector.M4_03] != 30 || values[vector.M4_13] != 40 {
+		t.Errorf("unscaled transform %v", values)
+	}
+	if sx, sy := actor.GetScale(); sx != 1 || sy != 1 {
+		t.Errorf("unscaled scale %v %v", sx, sy)
+	}
+	actor.ScaleBy(0.5, 0.5)
+	if actor.SX != 1.5 || actor.SY != 1.5 {
+		t.Errorf("scaled by from unscaled %v %v", actor.SX, actor.SY)
+	}
+
+	// a scale of 0 on one axis still flattens the actor
+	actor.SetScale(0, 1)
+	values = actor.ComputeTransform().GetValues()
+	if values[vector.M4_00] != 0 || values[vector.M4_11] != 1 {
+		t.Errorf("flattened transform %v", values)
+	}
+
+	// the transform is computed again when the actor or its parent moves
+	parent := &Actor{X: 100, Y: 50, Rotation: 90, SX: 2, SY: 2}
+	child := &Actor{X: 10}
+	parent.AddActor(child)
+	values = child.ComputeTransform().GetValues()
+	if !near(values[vector.M4_03], 100, 0.001) || !near(values[vector.M4_13], 70, 0.001) || !near(values[vector.M4_01], -2, 0.001) {
+		t.Errorf("child transform %v", values)
+	}
+	parent.X = 0
+	if values = child.ComputeTransform().GetValues(); !near(values[vector.M4_03], 0, 0.001) {
+		t.Errorf("child transform after the parent moved %v", values)
+	}
+}
+
+func TestCoordinates(t *testing.T) {
+	root := &Actor{X: 100, Y: 50, Rotation: 30, SX: 2, SY: 0.5, OX: 5, OY: 10}
+	middle := &Actor{X: -20, Y: 15, Rotation: -75}
+	leaf := &Actor{X: 3, Y: 4, SX: 1.5, SY: 1.5, W: 10, H: 10}
+	root.AddActor(middle)
+	middle.AddActor(leaf)
+
+	point := vector.NewVector2Empty()
+	for _, p := range [][2]float32{{0, 0}, {10, 10}, {-7, 3.5}, {250, -40}} {
+		leaf.LocalToStageCoordinates(point.Set(p[0], p[1]))
+		leaf.StageToLocalCoordinates(point)
+		if !near(point.X, p[0], 0.001) || !near(point.Y, p[1], 0.001) {
+			t.Errorf("stage round trip of %v gave %v %v", p, point.X, point.Y)
+		}
+		leaf.LocalToParentCoordinates(point.Set(p[0], p[1]))
+		leaf.ParentToLocalCoordinates(point)
+		if !near(point.X, p[0], 0.001) || !near(point.Y, p[1], 0.001) {
+			t.Errorf("parent round trip of %v gave %v %v", p, point.X, point.Y)
+		}
+		// going up through the parents one at a time ends where the stage transform does
+		leaf.LocalToAscendantCoordinates(nil, point.Set(p[0], p[1]))
+		x, y := point.X, point.Y
+		leaf.LocalToStageCoordinates(point.Set(p[0], p[1]))
+		if !near(point.X, x, 0.001) || !near(point.Y, y, 0.001) {
+			t.Errorf("ascendant %v %v and stage %v %v of %v differ", x, y, point.X, point.Y, p)
+		}
+	}
+
+	// a translated, unscaled actor
+	box := &Actor{X: 30, Y: 40, W: 10, H: 10}
+	box.LocalToStageCoordinates(point.Set(1, 2))
+	if point.X != 31 || point.Y != 42 {
+		t.Errorf("local to stage %v %v", point.X, point.Y)
+	}
+	if !box.Hit(35, 45, point) || point.X != 5 || point.Y != 5 || box.Hit(45, 45, point) {
+		t.Error("unscaled actor hit wrong")
+	}
+	// the hit leaf is found at the stage position of its center
+	leaf.LocalToStageCoordinates(point.Set(5, 5))
+	local := vector.NewVector2Empty()
+	if !leaf.Hit(point.X, point.Y, local) || !near(local.X, 5, 0.001) || !near(local.Y, 5, 0.001) {
+		t.Errorf("transformed leaf not hit at its center, local %v %v", local.X, local.Y)
+	}
+}
+
+func TestHitRayBounds3d(t *testing.T) {
+	// the parent turns the child a quarter, its bounds of 10 by 20 cover 80 to 100 on x and 0 to 10 on y
+	parent := &Actor{X: 100, Rotation: 90}
+	child := &Actor{Bounds3d: collision.NewBoundingBoxVector3(vector.NewVector3(0, 0, -5), vector.NewVector3(10, 20, 5))}
+	parent.AddActor(child)
+
+	down := vector.NewVector3(0, 0, -1)
+	intersection := vector.NewVector3Empty()
+	if !child.HitRay(collision.NewRay(vector.NewVector3(90, 5, 100), down), intersection) {
+		t.Fatal("ray through the turned bounds missed")
+	}
+	if !near(intersection.X, 90, 0.001) || !near(intersection.Y, 5, 0.001) || !near(intersection.Z, 5, 0.001) {
+		t.Errorf("intersection %v", intersection)
+	}
+	if child.HitRay(collision.NewRay(vector.NewVector3(105, 15, 100), down), nil) {
+		t.Error("ray through the bounds moved without the rotation hit")
+	}
+	// a ray along the x axis enters the bounds at their side
+	if !child.HitRay(collision.NewRay(vector.NewVector3(0, 5, 0), vector.NewVector3(1, 0, 0)), intersection) ||
+		!near(intersection.X, 80, 0.001) || !near(intersection.Y, 5, 0.001) {
+		t.Errorf("side intersection %v", intersection)
+	}
+}
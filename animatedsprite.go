@@ -0,0 +1,172 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+)
+
+// An AnimatedSprite is an actor which plays an animation. It advances the animation when it acts and draws the region of
+// the current frame stretched to its size, tinted with its color.
+//
+//	run := spike.NewAnimatedSprite(spike.Anim("run"))
+//	run.OnEvent = func(self *spike.AnimatedSprite, event string) {
+//		if event == "step" {
+//			spike.PlaySound("step")
+//		}
+//	}
+//	scene.AddActor(&run.Actor)
+//
+// Every frame that is passed is shown, even if a long frame time passes several frames at once, so that no frame event is
+// missed.
+type AnimatedSprite struct {
+	Actor
+
+	Animation *g2d.Animation
+
+	// Multiplies the time the animation is advanced by. Default is 1.
+	Speed float32
+
+	// Called when a frame is shown, including the first frame
+	OnFrame func(self *AnimatedSprite, frame int)
+
+	// Called when a frame which has an event is shown
+	OnEvent func(self *AnimatedSprite, event string)
+
+	// Called when an animation which does not loop is over
+	OnFinished func(self *AnimatedSprite)
+
+	stateTime, frameTime float32
+	frame                int
+	forward              bool
+	finished, paused     bool
+	started              bool
+}
+
+// Creates a sprite playing the animation, its size is the original size of the region of the first frame
+func NewAnimatedSprite(animation *g2d.Animation) *AnimatedSprite {
+	self := &AnimatedSprite{Speed: 1}
+	self.Actor = Actor{SX: 1, SY: 1}
+	if animation != nil && len(animation.Frames) > 0 {
+		region := animation.Frames[0].Region
+		self.W, self.H = float32(region.OriginalWidth), float32(region.OriginalHeight)
+	}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update(delta)
+	}
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.Play(animation)
+	return self
+}
+
+// Plays the animation from its first frame
+func (self *AnimatedSprite) Play(animation *g2d.Animation) {
+	self.Animation = animation
+	self.Restart()
+}
+
+// Plays the animation again from its first frame
+func (self *AnimatedSprite) Restart() {
+	self.stateTime, self.frameTime = 0, 0
+	self.forward = true
+	self.finished, self.paused, self.started = false, false, false
+	if self.Animation != nil && len(self.Animation.Frames) > 0 {
+		self.frame = self.Animation.FirstKeyFrameIndex()
+	}
+}
+
+// Stops advancing the animation, the current frame is still drawn
+func (self *AnimatedSprite) Pause() {
+	self.paused = true
+}
+
+func (self *AnimatedSprite) Resume() {
+	self.paused = false
+}
+
+func (self *AnimatedSprite) IsPaused() bool {
+	return self.paused
+}
+
+// Returns whether an animation which does not loop is over
+func (self *AnimatedSprite) IsFinished() bool {
+	return self.finished
+}
+
+// Returns the index of the frame which is shown
+func (self *AnimatedSprite) GetFrameIndex() int {
+	return self.frame
+}
+
+// Returns the region of the frame which is shown, or nil if there is no animation
+func (self *AnimatedSprite) GetFrame() *g2d.AtlasRegion {
+	if self.Animation == nil || len(self.Animation.Frames) == 0 {
+		return nil
+	}
+	return self.Animation.Frames[self.frame].Region
+}
+
+// Returns the time in seconds the animation has been played for
+func (self *AnimatedSprite) GetStateTime() float32 {
+	return self.stateTime
+}
+
+// Advances the animation by the time, showing the frames which are passed
+func (self *AnimatedSprite) Update(delta float32) {
+	animation := self.Animation
+	if animation == nil || len(animation.Frames) == 0 || self.paused || self.finished {
+		return
+	}
+	if !self.started {
+		self.started = true
+		self.showFrame()
+	}
+	delta *= self.Speed
+	self.stateTime += delta
+	self.frameTime += delta
+	for !self.finished && self.frameTime >= animation.Frames[self.frame].Duration {
+		if animation.GetAnimationDuration() <= 0 {
+			self.frameTime = 0
+			return
+		}
+		next, forward, ok := animation.NextKeyFrameIndex(self.frame, self.forward)
+		if !ok {
+			self.finished = true
+			if self.OnFinished != nil {
+				self.OnFinished(self)
+			}
+			return
+		}
+		self.frameTime -= animation.Frames[self.frame].Duration
+		self.frame, self.forward = next, forward
+		self.showFrame()
+	}
+}
+
+// Calls the callbacks of the current frame
+func (self *AnimatedSprite) showFrame() {
+	if self.OnFrame != nil {
+		self.OnFrame(self, self.frame)
+	}
+	if event := self.Animation.Frames[self.frame].Event; event != "" && self.OnEvent != nil {
+		self.OnEvent(self, event)
+	}
+}
+
+func (self *AnimatedSprite) draw(batch g2d.Batch, parentAlpha float32) {
+	region := self.GetFrame()
+	if region == nil {
+		return
+	}
+	if self.Color != nil {
+		batch.SetColor(self.Color.R, self.Color.G, self.Color.B, self.Color.A*parentAlpha)
+	} else {
+		batch.SetColor(1, 1, 1, parentAlpha)
+	}
+	self.computeTransform()
+	region.Draw(batch, self.W, self.H, self.localTransform)
+}
@@ -0,0 +1,118 @@
+package spike
+
+import (
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+)
+
+// Returns a sprite playing four frames of a tenth of a second each, frame 1 fires "step" and frame 3 fires "end". It
+// keeps the frames shown, the events fired and the times it finished.
+func newTestSprite(playMode g2d.PlayMode) (sprite *AnimatedSprite, frames *[]int, events *[]string, finished *int) {
+	regions := make([]*g2d.AtlasRegion, 4)
+	for i := range regions {
+		regions[i] = &g2d.AtlasRegion{OriginalWidth: 16, OriginalHeight: 32}
+	}
+	animation := g2d.NewAnimation(0.1, playMode, regions...)
+	animation.SetEvent(1, "step")
+	animation.SetEvent(3, "end")
+	sprite = NewAnimatedSprite(animation)
+	frames, events, finished = &[]int{}, &[]string{}, new(int)
+	sprite.OnFrame = func(self *AnimatedSprite, frame int) {
+		*frames = append(*frames, frame)
+	}
+	sprite.OnEvent = func(self *AnimatedSprite, event string) {
+		*events = append(*events, event)
+	}
+	sprite.OnFinished = func(self *AnimatedSprite) {
+		*finished++
+	}
+	return
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAnimatedSpriteEvents(t *testing.T) {
+	sprite, frames, events, finished := newTestSprite(g2d.PlayNormal)
+	if sprite.W != 16 || sprite.H != 32 {
+		t.Errorf("size %v %v", sprite.W, sprite.H)
+	}
+	sprite.Update(0.05)
+	if !sameInts(*frames, []int{0}) || len(*events) != 0 {
+		t.Errorf("first frames %v events %v", *frames, *events)
+	}
+
+	// a long frame time shows every frame it passes and fires their events
+	sprite.Update(0.3)
+	if !sameInts(*frames, []int{0, 1, 2, 3}) || len(*events) != 2 || (*events)[0] != "step" || (*events)[1] != "end" {
+		t.Errorf("frames %v events %v", *frames, *events)
+	}
+	if sprite.GetFrameIndex() != 3 || sprite.IsFinished() || *finished != 0 {
+		t.Error("finished before the last frame was shown for its duration")
+	}
+	sprite.Update(0.1)
+	if !sprite.IsFinished() || *finished != 1 || sprite.GetFrameIndex() != 3 {
+		t.Errorf("finished %v %d times at frame %d", sprite.IsFinished(), *finished, sprite.GetFrameIndex())
+	}
+	sprite.Update(1)
+	if *finished != 1 || len(*frames) != 4 {
+		t.Error("finished sprite still playing")
+	}
+
+	// restarting plays the first frame again
+	sprite.Restart()
+	sprite.Update(0)
+	if sprite.IsFinished() || len(*frames) != 5 || (*frames)[4] != 0 {
+		t.Errorf("restarted frames %v", *frames)
+	}
+}
+
+func TestAnimatedSpriteLoops(t *testing.T) {
+	sprite, frames, events, finished := newTestSprite(g2d.PlayLoop)
+	sprite.Update(0.95)
+	if !sameInts(*frames, []int{0, 1, 2, 3, 0, 1, 2, 3, 0, 1}) || *finished != 0 {
+		t.Errorf("looped frames %v", *frames)
+	}
+	steps, ends := 0, 0
+	for _, event := range *events {
+		if event == "step" {
+			steps++
+		} else if event == "end" {
+			ends++
+		}
+	}
+	if steps != 3 || ends != 2 {
+		t.Errorf("events %v", *events)
+	}
+
+	// ping pong animations take six steps to play there and back
+	sprite, frames, _, _ = newTestSprite(g2d.PlayLoopPingPong)
+	sprite.Update(0.05)
+	sprite.Update(0.6)
+	if !sameInts(*frames, []int{0, 1, 2, 3, 2, 1, 0}) {
+		t.Errorf("ping pong frames %v", *frames)
+	}
+
+	// the speed multiplies the time, a paused sprite keeps its frame
+	sprite, frames, _, _ = newTestSprite(g2d.PlayLoop)
+	sprite.Speed = 2
+	sprite.Update(0.15)
+	if sprite.GetFrameIndex() != 3 || !near(sprite.GetStateTime(), 0.3, 0.0001) {
+		t.Errorf("at double speed frame %d time %v", sprite.GetFrameIndex(), sprite.GetStateTime())
+	}
+	sprite.Pause()
+	sprite.Update(1)
+	if sprite.GetFrameIndex() != 3 || len(*frames) != 4 {
+		t.Error("paused sprite played")
+	}
+}
@@ -5,6 +5,10 @@
 package spike
 
 import (
+	"image/png"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/g2d/particle"
 	"golang.org/x/mobile/asset"
 	"golang.org/x/mobile/exp/audio"
 )
@@ -24,12 +28,19 @@ var (
 	imagesMap     map[string]int
 	soundsMap     map[string]int
 	musicsMap     map[string]int
-	fontsMap      map[string]int
-	animationsMap map[string]int
+	fontsMap      = make(map[string]*g2d.BitmapFont)
+	animationsMap = make(map[string]*g2d.Animation)
+	effectsMap    = make(map[string]*particle.Effect)
+	patchesMap    = make(map[string]*g2d.NinePatch)
+	skinsMap      = make(map[string]*Skin)
+	atlases       []*g2d.TextureAtlas
 	musicPlayer   *audio.Player
 	soundsPlayer  *audio.Player
 )
 
+// The time in seconds each frame of the animations returned by Anim is shown. Default is 1/12.
+var AnimationFrameDuration float32 = 1.0 / 12
+
 func InitAssets(config *AssetConfig) {
 }
 
@@ -58,11 +69,159 @@ func PlayMusic() {
 func StopMusic() {
 }
 
-func Font() {
+// Returns the bitmap font fonts/<name>.fnt, its pages are in the fonts directory. The font is read once and shared.
+func Font(name string) *g2d.BitmapFont {
+	if font, ok := fontsMap[name]; ok {
+		return font
+	}
+	println("Loading Font: " + name)
+	rc, err := asset.Open("fonts/" + name + ".fnt")
+	if err != nil {
+		panic(err)
+	}
+	defer rc.Close()
+	font, err := g2d.ReadBitmapFont(rc, "fonts")
+	if err != nil {
+		panic(err)
+	}
+	fontsMap[name] = font
+	return font
+}
+
+// Loads the texture atlas atlas/<name>.atlas, its regions can then be found with Tex and its animations with Anim
+func LoadAtlas(name string) *g2d.TextureAtlas {
+	println("Loading Atlas: " + name)
+	rc, err := asset.Open("atlas/" + name + ".atlas")
+	if err != nil {
+		panic(err)
+	}
+	defer rc.Close()
+	atlas, err := g2d.ReadTextureAtlas(rc, "atlas")
+	if err != nil {
+		panic(err)
+	}
+	atlases = append(atlases, atlas)
+	return atlas
+}
+
+// Returns the region with the name from the loaded atlases, or nil if there is none
+func Tex(name string) *g2d.AtlasRegion {
+	for _, atlas := range atlases {
+		if region := atlas.FindRegion(name); region != nil {
+			return region
+		}
+	}
+	println("TextureRegion Not Found: " + name)
+	return nil
+}
+
+// Returns the looping animation of the indexed regions with the name from the loaded atlases, or nil if there are none.
+// The frames are shown for AnimationFrameDuration. The animation is created once and shared, change its frames and play
+// mode for all the sprites playing it or create a new one with g2d.NewAnimationFromAtlas.
+func Anim(name string) *g2d.Animation {
+	if animation, ok := animationsMap[name]; ok {
+		return animation
+	}
+	for _, atlas := range atlases {
+		if animation := g2d.NewAnimationFromAtlas(atlas, name, AnimationFrameDuration, g2d.PlayLoop); animation != nil {
+			animationsMap[name] = animation
+			return animation
+		}
+	}
+	println("Animation Not Found: " + name)
+	return nil
+}
+
+// Returns a copy of the particle effect particles/<name>.part, with the sprites of its emitters from the loaded atlases.
+// The file is read once, every call returns a new copy which can run on its own.
+func Particle(name string) *particle.Effect {
+	if effect, ok := effectsMap[name]; ok {
+		return particle.NewEffectCopy(effect)
+	}
+	println("Loading Particle: " + name)
+	rc, err := asset.Open("particles/" + name + ".part")
+	if err != nil {
+		panic(err)
+	}
+	defer rc.Close()
+	effect, err := particle.ReadEffect(rc)
+	if err != nil {
+		panic(err)
+	}
+	for _, atlas := range atlases {
+		if err = effect.LoadSprites(atlas); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		println("Particle Sprites Not Found: " + name)
+	}
+	effectsMap[name] = effect
+	return particle.NewEffectCopy(effect)
 }
 
-func Tex() {
+// Returns the nine patch with the name, the region with the splits from the loaded atlases or else the Android image
+// images/<name>.9.png, or nil if there is none. The nine patch is created once and shared.
+func Patch(name string) *g2d.NinePatch {
+	if patch, ok := patchesMap[name]; ok {
+		return patch
+	}
+	for _, atlas := range atlases {
+		if region := atlas.FindRegion(name); region != nil && region.Splits != nil {
+			patch, err := g2d.NewNinePatchAtlas(region)
+			if err != nil {
+				panic(err)
+			}
+			patchesMap[name] = patch
+			return patch
+		}
+	}
+	println("Loading NinePatch: " + name)
+	rc, err := asset.Open("images/" + name + ".9.png")
+	if err != nil {
+		println("NinePatch Not Found: " + name)
+		return nil
+	}
+	defer rc.Close()
+	img, err := png.Decode(rc)
+	if err != nil {
+		panic(err)
+	}
+	texture := &g2d.Texture{Path: "images/" + name + ".9.png", Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	patch, err := g2d.NewNinePatchImage(texture, img)
+	if err != nil {
+		panic(err)
+	}
+	patchesMap[name] = patch
+	return patch
+}
 
+// Returns the skin skins/<name>.json with the drawables of the atlas skins/<name>.atlas, if there is one. The fonts of the
+// skin are in the skins directory. The skin is read once and shared.
+func LoadSkin(name string) *Skin {
+	if skin, ok := skinsMap[name]; ok {
+		return skin
+	}
+	println("Loading Skin: " + name)
+	var atlas *g2d.TextureAtlas
+	if rc, err := asset.Open("skins/" + name + ".atlas"); err == nil {
+		atlas, err = g2d.ReadTextureAtlas(rc, "skins")
+		rc.Close()
+		if err != nil {
+			panic(err)
+		}
+	}
+	rc, err := asset.Open("skins/" + name + ".json")
+	if err != nil {
+		panic(err)
+	}
+	defer rc.Close()
+	skin := NewSkin(atlas)
+	if err = skin.Read(rc, "skins"); err != nil {
+		panic(err)
+	}
+	skinsMap[name] = skin
+	return skin
 }
 
 func LoadTmx() {
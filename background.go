@@ -0,0 +1,173 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/utils"
+	"github.com/pyros2097/spike/utils/scaling"
+)
+
+// The background of a scene is drawn behind its actors. It is cleared to BGColor, or filled with a vertical gradient from
+// BGColor at the bottom to BGTopColor at the top, and then its layers are drawn over it in the order they were added. A
+// layer is an image scaled to the view of the camera, it moves by its scroll factors times as far as the camera so that the
+// layers behind move slower than the world:
+//
+//	scene.SetBackgroundGradient(spike.Color{0.1, 0.2, 0.5, 1}, spike.Color{0.5, 0.7, 1, 1})
+//	scene.AddBackgroundLayer(&spike.Tex("mountains").TextureRegion, 0.2, 0).RepeatX = true
+//	scene.AddBackgroundLayer(&spike.Tex("trees").TextureRegion, 0.5, 0).RepeatX = true
+
+// A BackgroundLayer is an image drawn behind the actors of a scene
+type BackgroundLayer struct {
+	Region *g2d.TextureRegion
+
+	// How the image is scaled to the view of the camera. Default is Stretch for SetBackground and FillY for the layers.
+	Scaling scaling.Scaling
+
+	// Where the image is in the view when it does not fill it, before it is scrolled. Default is center.
+	Align utils.Alignment
+
+	// How far the layer moves when the camera moves, in times the distance the camera moved. 0 keeps the layer fixed on the
+	// screen and 1 moves it with the world.
+	ScrollX, ScrollY float32
+
+	// If set the image is repeated to fill the view in the direction
+	RepeatX, RepeatY bool
+
+	// The color the image is tinted with, nil for white
+	Color *Color
+}
+
+var backgroundShapes = newGLShapeRenderer()
+
+// Sets the background to the region scaled to the view of the camera, it is fixed on the screen. The layers of the
+// background are replaced by it.
+func (self *Scene) SetBackground(region *g2d.TextureRegion, mode scaling.Scaling) *BackgroundLayer {
+	layer := &BackgroundLayer{Region: region, Scaling: mode, Align: utils.AlignmentCenter}
+	self.backgroundLayers = []*BackgroundLayer{layer}
+	return layer
+}
+
+// Sets the background to the whole texture scaled to the view of the camera, like SetBackground
+func (self *Scene) SetBackgroundTexture(texture *g2d.Texture, mode scaling.Scaling) *BackgroundLayer {
+	return self.SetBackground(g2d.NewTextureRegion(texture, 0, 0, texture.Width, texture.Height), mode)
+}
+
+// Fills the background with a vertical gradient from the bottom color to the top color, behind the layers
+func (self *Scene) SetBackgroundGradient(bottom, top Color) {
+	self.BGColor = bottom
+	self.BGTopColor = &top
+}
+
+// Adds a layer over the layers of the background, scaled to fill the height of the view. It moves by the scroll factors
+// times as far as the camera, the farther layers have the smaller factors.
+func (self *Scene) AddBackgroundLayer(region *g2d.TextureRegion, scrollX, scrollY float32) *BackgroundLayer {
+	layer := &BackgroundLayer{Region: region, Scaling: scaling.FillY, Align: utils.AlignmentCenter, ScrollX: scrollX,
+		ScrollY: scrollY}
+	self.backgroundLayers = append(self.backgroundLayers, layer)
+	return layer
+}
+
+// Returns the layers of the background, from the back to the front
+func (self *Scene) GetBackgroundLayers() []*BackgroundLayer {
+	return self.backgroundLayers
+}
+
+// Removes the layers and the gradient of the background, it is only cleared to BGColor
+func (self *Scene) RemoveBackground() {
+	self.backgroundLayers = nil
+	self.BGTopColor = nil
+}
+
+// Draws the gradient and the layers of the background over the view of the camera
+func drawBackground(scene *Scene, batch g2d.Batch) {
+	if scene.BGTopColor == nil && len(scene.backgroundLayers) == 0 {
+		return
+	}
+	camera := currentCamera()
+	viewW, viewH := camera.ViewportWidth*camera.Zoom, camera.ViewportHeight*camera.Zoom
+	left, bottom := camera.Position.X-viewW/2, camera.Position.Y-viewH/2
+	if top := scene.BGTopColor; top != nil {
+		bg := scene.BGColor
+		shapes := backgroundShapes
+		shapes.SetProjectionMatrix(camera.Combined)
+		shapes.SetTransformMatrix(identityMatrix)
+		shapes.Begin(g2d.ShapeFilled)
+		low, high := g2d.Color{R: bg.R, G: bg.G, B: bg.B, A: bg.A}, g2d.Color{R: top.R, G: top.G, B: top.B, A: top.A}
+		shapes.RectColors(left, bottom, viewW, viewH, low, low, high, high)
+		shapes.End()
+	}
+	if len(scene.backgroundLayers) == 0 {
+		return
+	}
+	batch.SetProjectionMatrix(camera.Combined)
+	batch.SetTransformMatrix(identityMatrix)
+	// the camera starts in the center of the world, the layers are where they are aligned while it is there
+	worldW, worldH := worldSize()
+	scrolledX, scrolledY := camera.Position.X-worldW/2, camera.Position.Y-worldH/2
+	for _, layer := range scene.backgroundLayers {
+		layer.draw(batch, left, bottom, viewW, viewH, scrolledX, scrolledY)
+	}
+}
+
+// Draws the layer over the view, the camera has scrolled by scrolledX, scrolledY from the center of the world
+func (self *BackgroundLayer) draw(batch g2d.Batch, left, bottom, viewW, viewH, scrolledX, scrolledY float32) {
+	region := self.Region
+	if region == nil || region.RegionWidth == 0 || region.RegionHeight == 0 {
+		return
+	}
+	size := self.Scaling.Apply(float32(region.RegionWidth), float32(region.RegionHeight), viewW, viewH)
+	width, height := size.X, size.Y
+	if width <= 0 || height <= 0 {
+		return
+	}
+	x, y := left+(viewW-width)/2, bottom+(viewH-height)/2
+	if self.Align&utils.AlignmentLeft != 0 {
+		x = left
+	} else if self.Align&utils.AlignmentRight != 0 {
+		x = left + viewW - width
+	}
+	if self.Align&utils.AlignmentBottom != 0 {
+		y = bottom
+	} else if self.Align&utils.AlignmentTop != 0 {
+		y = bottom + viewH - height
+	}
+	// the view follows the camera, the layer moves across it by its part of the scroll
+	x -= scrolledX * self.ScrollX
+	y -= scrolledY * self.ScrollY
+	x1, x2 := x, x
+	if self.RepeatX {
+		x1 = repeatStart(x, width, left)
+		x2 = left + viewW
+	}
+	y1, y2 := y, y
+	if self.RepeatY {
+		y1 = repeatStart(y, height, bottom)
+		y2 = bottom + viewH
+	}
+	if self.Color != nil {
+		batch.SetColor(self.Color.R, self.Color.G, self.Color.B, self.Color.A)
+	} else {
+		batch.SetColor(1, 1, 1, 1)
+	}
+	for ty := y1; ty <= y2 && ty < bottom+viewH; ty += height {
+		for tx := x1; tx <= x2 && tx < left+viewW; tx += width {
+			if tx+width > left && ty+height > bottom {
+				widgetTransform.Idt().Translate(tx, ty)
+				batch.Draw(region, width, height, widgetTransform)
+			}
+		}
+	}
+}
+
+// Returns the position of the first tile at or before the start of the view, of the tiles repeated from the position
+func repeatStart(position, size, start float32) float32 {
+	n := float32(int((position - start) / size))
+	position -= n * size
+	for position > start {
+		position -= size
+	}
+	return position
+}
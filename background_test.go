@@ -0,0 +1,162 @@
+package spike
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+	"github.com/pyros2097/spike/utils"
+	"github.com/pyros2097/spike/utils/scaling"
+)
+
+// The x, y, width and height a region was drawn at, and the color of the batch
+type drawnRegion struct {
+	x, y, width, height float32
+	color               g2d.Color
+}
+
+// A batch which records the regions drawn with it
+type layerBatch struct {
+	SBatch
+	drawn []drawnRegion
+}
+
+// Returns a region of 100 by 100 pixels
+func newTestRegion() *g2d.TextureRegion {
+	return g2d.NewTextureRegion(&g2d.Texture{Width: 100, Height: 100}, 0, 0, 100, 100)
+}
+
+func newLayerBatch() *layerBatch {
+	return &layerBatch{SBatch: SBatch{transform: vector.NewMatrix4Empty(), projection: vector.NewMatrix4Empty()}}
+}
+
+func (self *layerBatch) Draw(region *g2d.TextureRegion, width, height float32, transform *vector.Affine2) {
+	values := transform.GetValues()
+	self.drawn = append(self.drawn, drawnRegion{values[2], values[5], width, height, self.GetColor()})
+}
+
+func TestRepeatStart(t *testing.T) {
+	for _, test := range [][4]float32{
+		{0, 100, 0, 0},
+		{30, 100, 0, -70},
+		{250, 100, 0, -50},
+		{-250, 100, 0, -50},
+		{-300, 100, 0, 0},
+		{-330, 100, -20, -30},
+		{75, 50, 1000, 975},
+	} {
+		if start := repeatStart(test[0], test[1], test[2]); start != test[3] {
+			t.Errorf("tiles of %v from %v start at %v before %v, expected %v", test[1], test[0], start, test[2], test[3])
+		}
+	}
+}
+
+func TestBackgroundLayers(t *testing.T) {
+	white := g2d.Color{R: 1, G: 1, B: 1, A: 1}
+	region := newTestRegion()
+	for _, test := range []struct {
+		name   string
+		layer  BackgroundLayer
+		scroll [2]float32
+		drawn  []drawnRegion
+	}{
+		{"centered", BackgroundLayer{Scaling: scaling.FillY, Align: utils.AlignmentCenter}, [2]float32{},
+			[]drawnRegion{{200, 0, 400, 400, white}}},
+		{"left", BackgroundLayer{Scaling: scaling.FillY, Align: utils.AlignmentLeft}, [2]float32{},
+			[]drawnRegion{{0, 0, 400, 400, white}}},
+		{"right", BackgroundLayer{Scaling: scaling.FillY, Align: utils.AlignmentRight}, [2]float32{},
+			[]drawnRegion{{400, 0, 400, 400, white}}},
+		{"stretched", BackgroundLayer{Scaling: scaling.Stretch, ScrollX: 1, ScrollY: 1}, [2]float32{100, 100},
+			[]drawnRegion{{-100, -100, 800, 400, white}}},
+		{"fixed", BackgroundLayer{Scaling: scaling.Stretch}, [2]float32{100, 100},
+			[]drawnRegion{{0, 0, 800, 400, white}}},
+		// the layer moves half as far as the camera, the tiles fill the view from before its left edge
+		{"scrolled", BackgroundLayer{Scaling: scaling.FillY, Align: utils.AlignmentCenter, ScrollX: 0.5, RepeatX: true},
+			[2]float32{100, 0}, []drawnRegion{{-250, 0, 400, 400, white}, {150, 0, 400, 400, white},
+				{550, 0, 400, 400, white}}},
+		{"tiled", BackgroundLayer{Scaling: scaling.None, Align: utils.AlignmentBottomLeft, ScrollX: 1, ScrollY: 1,
+			RepeatX: true, RepeatY: true, Color: &Color{R: 1, G: 0, B: 0, A: 0.5}}, [2]float32{-330, 150},
+			[]drawnRegion{
+				{-70, -50, 100, 100, g2d.Color{R: 1, G: 0, B: 0, A: 0.5}},
+				{-70, 50, 100, 100, g2d.Color{R: 1, G: 0, B: 0, A: 0.5}},
+				{-70, 150, 100, 100, g2d.Color{R: 1, G: 0, B: 0, A: 0.5}},
+				{-70, 250, 100, 100, g2d.Color{R: 1, G: 0, B: 0, A: 0.5}},
+				{-70, 350, 100, 100, g2d.Color{R: 1, G: 0, B: 0, A: 0.5}},
+			}},
+	} {
+		batch := newLayerBatch()
+		layer := test.layer
+		layer.Region = region
+		// the view is 800 by 400 from 0, 0, of the tiles only the first column is checked
+		layer.draw(batch, 0, 0, 800, 400, test.scroll[0], test.scroll[1])
+		drawn := batch.drawn
+		if layer.RepeatY {
+			drawn = nil
+			for _, region := range batch.drawn {
+				if region.x == batch.drawn[0].x {
+					drawn = append(drawn, region)
+				}
+			}
+			if len(batch.drawn) != 9*5 {
+				t.Errorf("%s: %d tiles drawn", test.name, len(batch.drawn))
+			}
+		}
+		if !reflect.DeepEqual(drawn, test.drawn) {
+			t.Errorf("%s: drawn %v, expected %v", test.name, drawn, test.drawn)
+		}
+	}
+
+	// the region is not drawn when it is empty
+	batch := newLayerBatch()
+	(&BackgroundLayer{Region: &g2d.TextureRegion{}, Scaling: scaling.Fit}).draw(batch, 0, 0, 800, 400, 0, 0)
+	(&BackgroundLayer{Scaling: scaling.Fit}).draw(batch, 0, 0, 800, 400, 0, 0)
+	if len(batch.drawn) != 0 {
+		t.Errorf("drawn empty regions %v", batch.drawn)
+	}
+}
+
+func TestBackgroundGradient(t *testing.T) {
+	oldShapes, oldX, oldY := backgroundShapes, Camera2d.Position.X, Camera2d.Position.Y
+	defer func() {
+		backgroundShapes = oldShapes
+		Camera2d.Position.X, Camera2d.Position.Y = oldX, oldY
+	}()
+	var flushed []float32
+	backgroundShapes = g2d.NewShapeRenderer()
+	backgroundShapes.Flush = func(primitive int, vertices []float32, projection *vector.Matrix4) {
+		flushed = append(flushed, vertices...)
+	}
+	Camera2d.Position.X, Camera2d.Position.Y = 400, 240
+
+	scene := &Scene{}
+	batch := newLayerBatch()
+	drawBackground(scene, batch)
+	if len(flushed) != 0 {
+		t.Errorf("drawn a gradient without a top color %v", flushed)
+	}
+	scene.SetBackgroundGradient(Color{R: 0, G: 0, B: 1, A: 1}, Color{R: 1, G: 1, B: 1, A: 1})
+	drawBackground(scene, batch)
+	if len(flushed) == 0 || len(batch.drawn) != 0 {
+		t.Fatalf("gradient drawn %v with the batch %v", flushed, batch.drawn)
+	}
+	// the gradient covers the view of the camera, blue at the bottom and white at the top
+	for i := 0; i < len(flushed); i += g2d.ShapeVertexSize {
+		v := flushed[i : i+g2d.ShapeVertexSize]
+		if (v[0] != 0 && v[0] != 800) || (v[1] != 0 && v[1] != 480) {
+			t.Errorf("vertex at %v, %v", v[0], v[1])
+		}
+		if blue := v[1] == 0; v[2] != v[3] || (blue && v[2] != 0) || (!blue && v[2] != 1) || v[4] != 1 || v[5] != 1 {
+			t.Errorf("vertex at %v, %v colored %v", v[0], v[1], v[2:])
+		}
+	}
+
+	// the layers are drawn over the gradient, in the view around the camera
+	flushed = nil
+	scene.AddBackgroundLayer(newTestRegion(), 0, 0)
+	drawBackground(scene, batch)
+	if len(flushed) == 0 || len(batch.drawn) != 1 || !near(batch.drawn[0].x, 160, 0.001) ||
+		!near(batch.drawn[0].y, 0, 0.001) || !near(batch.drawn[0].height, 480, 0.001) {
+		t.Errorf("layer drawn %v", batch.drawn)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"encoding/json"
+
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/utils"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// Games should not hard code keys or gestures. Instead named actions like "jump" or "fire" and named axes like "move_x"
+// are bound to the physical inputs and the game only queries the actions and axes.
+//
+//   spike.BindAction("jump", spike.NewKeyBinding(spike.KeySpace), spike.NewGestureBinding(spike.SwipeUp))
+//   spike.BindAxis("move_x", 0.2, spike.NewKeyAxisBinding(spike.KeyA, -1), spike.NewKeyAxisBinding(spike.KeyD, 1))
+//   ...
+//   if spike.ActionJustPressed("jump") {
+//     player.Y += 10
+//   }
+//   player.X += spike.Axis("move_x") * speed * delta
+//
+// Bindings changed with RebindAction or RebindAxis are saved to the Preferences and replace the defaults the next time
+// the action or axis is bound.
+
+// The kind of physical input a Binding listens to.
+type BindingType uint8
+
+const (
+	// A keyboard key or a gamepad button reported as a key code like KeyButtonA.
+	BindKey BindingType = iota
+
+	// A rectangular area of the screen. It is held as long as a touching pointer is inside of it.
+	BindTouchZone
+
+	// A gesture like Tap or SwipeLeft. It is held for the frame in which the gesture was detected.
+	BindGesture
+
+	// An analog axis of a controller or a virtual joystick. Axes get the value of the axis times the scale, actions are held
+	// while the value is at least half way in the direction of the scale.
+	BindControllerAxis
+)
+
+// A Binding connects a physical input to an action or an axis.
+type Binding struct {
+	Type BindingType
+
+	// The key of the binding. Valid for: BindKey.
+	Key KeyCode
+
+	// The gesture of the binding. Valid for: BindGesture.
+	Gesture InputType
+
+	// The touch zone in stage coordinates, the origin is in the lower left corner. Valid for: BindTouchZone.
+	Zone shape.Rectangle
+
+	// The axis of the binding. Valid for: BindControllerAxis.
+	Axis ControllerAxis
+
+	// The value added to an axis while the binding is held, usually -1 or 1. Not used by actions.
+	Scale float32
+}
+
+// Creates a binding for a keyboard key or gamepad button
+func NewKeyBinding(keycode KeyCode) Binding {
+	return Binding{Type: BindKey, Key: keycode, Scale: 1}
+}
+
+// Creates a binding for a gesture like Tap, LongPress or SwipeLeft
+func NewGestureBinding(gesture InputType) Binding {
+	return Binding{Type: BindGesture, Gesture: gesture, Scale: 1}
+}
+
+// Creates a binding for an area of the stage, the origin is in the lower left corner.
+func NewTouchZoneBinding(x, y, w, h float32) Binding {
+	return Binding{Type: BindTouchZone, Zone: shape.Rectangle{X: x, Y: y, W: w, H: h}, Scale: 1}
+}
+
+// Creates a binding for a key which sets the axis to scale while held
+func NewKeyAxisBinding(keycode KeyCode, scale float32) Binding {
+	return Binding{Type: BindKey, Key: keycode, Scale: scale}
+}
+
+// Creates a binding for a controller axis. Use a scale of -1 to bind an action to the negative direction of the axis.
+func NewControllerAxisBinding(axis ControllerAxis, scale float32) Binding {
+	return Binding{Type: BindControllerAxis, Axis: axis, Scale: scale}
+}
+
+// Creates a binding for an area of the screen which sets the axis to scale while touched
+func NewTouchZoneAxisBinding(x, y, w, h, scale float32) Binding {
+	return Binding{Type: BindTouchZone, Zone: shape.Rectangle{X: x, Y: y, W: w, H: h}, Scale: scale}
+}
+
+type actionBinding struct {
+	defaults, bindings  []Binding
+	pressed, wasPressed bool
+}
+
+type axisBinding struct {
+	defaults, bindings []Binding
+	deadZone           float32
+	value              float32
+}
+
+// The bindings which were rebound at runtime, as they are saved in the preferences
+type savedBindings struct {
+	Actions map[string][]Binding
+	Axes    map[string][]Binding
+}
+
+var (
+	actionBindings = make(map[string]*actionBinding)
+	axisBindings   = make(map[string]*axisBinding)
+	saved          *savedBindings
+
+	// The physical inputs which are currently held
+	keysHeld       = make(map[KeyCode]bool)
+	pointersHeld   = make(map[uint8]vector.Vector2)
+	gesturesFired  = make(map[InputType]bool)
+	gesturesActive = make(map[InputType]bool)
+	axesMoved      = make(map[ControllerAxis]float32)
+)
+
+// Returns the bindings that were rebound and saved in the preferences
+func savedRebindings() *savedBindings {
+	if saved == nil {
+		saved = &savedBindings{
+			Actions: make(map[string][]Binding),
+			Axes:    make(map[string][]Binding),
+		}
+		if prefs != nil {
+			if data := prefs.GetString(BINDINGS, ""); data != "" {
+				if err := json.Unmarshal([]byte(data), saved); err != nil {
+					println("Bindings: ignoring invalid saved bindings " + err.Error())
+				}
+			}
+		}
+	}
+	return saved
+}
+
+func saveRebindings() {
+	if prefs == nil {
+		return
+	}
+	data, err := json.Marshal(savedRebindings())
+	if err != nil {
+		println("Bindings: could not save bindings " + err.Error())
+		return
+	}
+	prefs.PutString(BINDINGS, string(data))
+	prefs.Flush()
+}
+
+// BindAction binds the action name to the given default bindings. If the action was rebound earlier with
+// RebindAction the saved bindings are used instead.
+func BindAction(name string, bindings ...Binding) {
+	action := &actionBinding{defaults: bindings, bindings: bindings}
+	if rebound, ok := savedRebindings().Actions[name]; ok {
+		action.bindings = rebound
+	}
+	actionBindings[name] = action
+}
+
+// BindAxis binds the axis name to the given default bindings. Axis values whose magnitude is smaller than deadZone
+// are reported as 0, the dead zone is clamped to [0, 1]. If the axis was rebound earlier with RebindAxis the saved
+// bindings are used instead.
+func BindAxis(name string, deadZone float32, bindings ...Binding) {
+	deadZone = utils.ClampFloat32(deadZone, 0, 1)
+	axis := &axisBinding{defaults: bindings, bindings: bindings, deadZone: deadZone}
+	if rebound, ok := savedRebindings().Axes[name]; ok {
+		axis.bindings = rebound
+	}
+	axisBindings[name] = axis
+}
+
+// RebindAction replaces the bindings of an action and saves them to the preferences
+func RebindAction(name string, bindings ...Binding) {
+	if action, ok := actionBindings[name]; ok {
+		action.bindings = bindings
+	} else {
+		actionBindings[name] = &actionBinding{bindings: bindings}
+	}
+	savedRebindings().Actions[name] = bindings
+	saveRebindings()
+}
+
+// RebindAxis replaces the bindings of an axis and saves them to the preferences
+func RebindAxis(name string, bindings ...Binding) {
+	if axis, ok := axisBindings[name]; ok {
+		axis.bindings = bindings
+	} else {
+		axisBindings[name] = &axisBinding{bindings: bindings}
+	}
+	savedRebindings().Axes[name] = bindings
+	saveRebindings()
+}
+
+// ResetBindings restores the default bindings of all actions and axes and removes the saved ones from the preferences
+func ResetBindings() {
+	for _, action := range actionBindings {
+		action.bindings = action.defaults
+	}
+	for _, axis := range axisBindings {
+		axis.bindings = axis.defaults
+	}
+	saved = nil
+	if prefs != nil {
+		prefs.Remove(BINDINGS)
+		prefs.Flush()
+	}
+}
+
+// Returns the current bindings of an action
+func GetActionBindings(name string) []Binding {
+	if action, ok := actionBindings[name]; ok {
+		return action.bindings
+	}
+	return nil
+}
+
+// Returns the current bindings of an axis
+func GetAxisBindings(name string) []Binding {
+	if axis, ok := axisBindings[name]; ok {
+		return axis.bindings
+	}
+	return nil
+}
+
+// Returns whether any of the bindings of the action is held
+func ActionPressed(name string) bool {
+	if action, ok := actionBindings[name]; ok {
+		return action.pressed
+	}
+	return false
+}
+
+// Returns whether the action started being held this frame
+func ActionJustPressed(name string) bool {
+	if action, ok := actionBindings[name]; ok {
+		return action.pressed && !action.wasPressed
+	}
+	return false
+}
+
+// Returns whether the action stopped being held this frame
+func ActionJustReleased(name string) bool {
+	if action, ok := actionBindings[name]; ok {
+		return !action.pressed && action.wasPressed
+	}
+	return false
+}
+
+// Returns the value of the axis in the range [-1, 1], with the dead zone applied
+func Axis(name string) float32 {
+	if axis, ok := axisBindings[name]; ok {
+		return axis.value
+	}
+	return 0
+}
+
+// Releases all the held inputs, actions and axes
+func releaseBindings() {
+	keysHeld = make(map[KeyCode]bool)
+	pointersHeld = make(map[uint8]vector.Vector2)
+	gesturesFired = make(map[InputType]bool)
+	gesturesActive = make(map[InputType]bool)
+	axesMoved = make(map[ControllerAxis]float32)
+	for _, action := range actionBindings {
+		action.pressed = false
+		action.wasPressed = false
+	}
+	for _, axis := range axisBindings {
+		axis.value = 0
+	}
+}
+
+func isBindingHeld(binding *Binding) bool {
+	switch binding.Type {
+	case BindKey:
+		return keysHeld[binding.Key]
+	case BindGesture:
+		return gesturesActive[binding.Gesture]
+	case BindTouchZone:
+		for _, point := range pointersHeld {
+			if binding.Zone.Contains(point.X, point.Y) {
+				return true
+			}
+		}
+	case BindControllerAxis:
+		return axesMoved[binding.Axis]*binding.Scale >= 0.5
+	}
+	return false
+}
+
+// Returns the value a binding adds to an axis
+func bindingValue(binding *Binding) float32 {
+	if binding.Type == BindControllerAxis {
+		return axesMoved[binding.Axis] * binding.Scale
+	}
+	if isBindingHeld(binding) {
+		return binding.Scale
+	}
+	return 0
+}
+
+// Records the state of the physical inputs from an input event
+func processBinding(e InputEvent) {
+	switch e.Type {
+	case KeyDown:
+		keysHeld[e.KeyCode] = true
+	case KeyUp:
+		delete(keysHeld, e.KeyCode)
+	case TouchDown, TouchDragged:
+		pointersHeld[e.Pointer] = vector.Vector2{X: e.X, Y: e.Y}
+	case TouchUp:
+		delete(pointersHeld, e.Pointer)
+	case AxisMoved:
+		axesMoved[e.Axis] = e.Value
+	case Tap, LongPress, Fling, Pan, PanStop, Zoom, Pinch, SwipeLeft, SwipeRight, SwipeUp, SwipeDown:
+		gesturesFired[e.Type] = true
+	}
+}
+
+// Updates the actions and axes from the inputs held this frame. This is called once every frame after the input events
+// have been processed.
+func updateBindings() {
+	gesturesFired, gesturesActive = gesturesActive, gesturesFired
+	for gesture := range gesturesFired {
+		delete(gesturesFired, gesture)
+	}
+	for _, action := range actionBindings {
+		action.wasPressed = action.pressed
+		action.pressed = false
+		for i := range action.bindings {
+			if isBindingHeld(&action.bindings[i]) {
+				action.pressed = true
+				break
+			}
+		}
+	}
+	for _, axis := range axisBindings {
+		var value float32
+		for i := range axis.bindings {
+			value += bindingValue(&axis.bindings[i])
+		}
+		axis.value = applyDeadZone(value, axis.deadZone)
+	}
+}
+
+// Clamps value to [-1, 1] and rescales it so that it starts from 0 at the edge of the dead zone
+func applyDeadZone(value, deadZone float32) float32 {
+	abs := utils.MinFloat32(utils.AbsFloat32(value), 1)
+	if abs <= deadZone {
+		return 0
+	}
+	abs = (abs - deadZone) / (1 - deadZone)
+	if value < 0 {
+		return -abs
+	}
+	return abs
+}
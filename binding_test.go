@@ -0,0 +1,151 @@
+package spike
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/mobile/event/key"
+)
+
+// Preferences kept in memory, only the methods used by the bindings are implemented
+type memoryPreferences struct {
+	Preferences
+	values  map[string]string
+	flushed int
+}
+
+func newMemoryPreferences() *memoryPreferences {
+	return &memoryPreferences{values: make(map[string]string)}
+}
+
+func (self *memoryPreferences) PutString(key, val string) Preferences {
+	self.values[key] = val
+	return self
+}
+
+func (self *memoryPreferences) GetString(key, defValue string) string {
+	if val, ok := self.values[key]; ok {
+		return val
+	}
+	return defValue
+}
+
+func (self *memoryPreferences) Remove(key string) {
+	delete(self.values, key)
+}
+
+func (self *memoryPreferences) Flush() {
+	self.flushed++
+}
+
+func TestRebindingPersistence(t *testing.T) {
+	defer SetPreferences(nil)
+	defer delete(actionBindings, "jump")
+	first := newMemoryPreferences()
+	SetPreferences(first)
+	BindAction("jump", NewKeyBinding(KeySpace))
+	RebindAction("jump", NewKeyBinding(KeyW))
+	if bindings := GetActionBindings("jump"); len(bindings) != 1 || bindings[0].Key != KeyW {
+		t.Fatalf("rebound bindings %v", bindings)
+	}
+	if first.values[BINDINGS] == "" || first.flushed == 0 {
+		t.Fatal("rebinding not saved")
+	}
+
+	// other preferences without saved bindings give the defaults
+	SetPreferences(newMemoryPreferences())
+	BindAction("jump", NewKeyBinding(KeySpace))
+	if bindings := GetActionBindings("jump"); len(bindings) != 1 || bindings[0].Key != KeySpace {
+		t.Errorf("bindings with new preferences %v", bindings)
+	}
+
+	// the saved bindings replace the defaults the next time the action is bound
+	second := newMemoryPreferences()
+	second.values[BINDINGS] = first.values[BINDINGS]
+	SetPreferences(second)
+	BindAction("jump", NewKeyBinding(KeySpace))
+	if bindings := GetActionBindings("jump"); len(bindings) != 1 || bindings[0].Key != KeyW {
+		t.Errorf("bindings read from the preferences %v", bindings)
+	}
+
+	ResetBindings()
+	if bindings := GetActionBindings("jump"); len(bindings) != 1 || bindings[0].Key != KeySpace {
+		t.Errorf("reset bindings %v", bindings)
+	}
+	if _, ok := second.values[BINDINGS]; ok {
+		t.Error("saved bindings not removed by the reset")
+	}
+}
+
+func TestDeadZone(t *testing.T) {
+	for _, c := range []struct{ value, deadZone, want float32 }{
+		{0.1, 0.2, 0},
+		{-0.2, 0.2, 0},
+		{0.6, 0.2, 0.5},
+		{-0.6, 0.2, -0.5},
+		{1, 0.2, 1},
+		{-3, 0.2, -1},
+		{0.5, 0, 0.5},
+		{2, 1, 0},
+	} {
+		if got := applyDeadZone(c.value, c.deadZone); math.Abs(float64(got-c.want)) > 0.0001 {
+			t.Errorf("value %v with dead zone %v gave %v, expected %v", c.value, c.deadZone, got, c.want)
+		}
+	}
+
+	defer releaseBindings()
+	defer delete(axisBindings, "move_x")
+	// the dead zones out of [0, 1] are clamped
+	for _, c := range []struct{ deadZone, value, want float32 }{{-1, 0.5, 0.5}, {1, 2, 0}, {5, 0.5, 0}} {
+		BindAxis("move_x", c.deadZone, NewControllerAxisBinding(AxisLeftX, 1))
+		processBinding(InputEvent{Type: AxisMoved, Axis: AxisLeftX, Value: c.value})
+		updateBindings()
+		if value := Axis("move_x"); value != c.want {
+			t.Errorf("axis at %v with dead zone %v is %v, expected %v", c.value, c.deadZone, value, c.want)
+		}
+	}
+}
+
+func TestGestureAction(t *testing.T) {
+	defer releaseBindings()
+	defer delete(actionBindings, "tap")
+	BindAction("tap", NewGestureBinding(Tap))
+	processBinding(InputEvent{Type: Tap})
+	updateBindings()
+	if !ActionPressed("tap") || !ActionJustPressed("tap") {
+		t.Error("gesture action not pressed in the frame of the gesture")
+	}
+	updateBindings()
+	if ActionPressed("tap") || !ActionJustReleased("tap") {
+		t.Error("gesture action not released in the next frame")
+	}
+	updateBindings()
+	if ActionPressed("tap") || ActionJustReleased("tap") {
+		t.Error("gesture action still changing after two frames")
+	}
+}
+
+func TestKeyEventDirections(t *testing.T) {
+	StartRecording(1)
+	doKeyEvent(key.Event{Rune: 'a', Code: key.CodeA, Direction: key.DirNone})
+	doKeyEvent(key.Event{Rune: 'b', Code: key.CodeB, Direction: key.DirPress})
+	doKeyEvent(key.Event{Rune: 'b', Code: key.CodeB, Direction: key.DirRelease})
+	rec := StopRecording()
+	for len(InputChannel) > 0 {
+		<-InputChannel
+	}
+	releaseBindings()
+	var types []InputType
+	for _, e := range rec.Events {
+		types = append(types, e.Event.Type)
+	}
+	want := []InputType{KeyTyped, KeyDown, KeyTyped, KeyUp}
+	if len(types) != len(want) {
+		t.Fatalf("sent %v, expected %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("sent %v, expected %v", types, want)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"github.com/pyros2097/spike/utils"
+	"github.com/pyros2097/spike/utils/scaling"
+)
+
+// The point of the last touch in the coordinates of the widget it was checked with
+var widgetPoint = vector.NewVector2Empty()
+
+// The style of a button, its drawables are drawn as the background of the button in its states. Only Up is required, the
+// states without a drawable use it.
+type ButtonStyle struct {
+	Up, Down, Over, Checked, CheckedOver, Disabled ui.Drawable
+
+	// How far the content of the button is moved when it is pressed, not pressed and checked
+	PressedOffsetX, PressedOffsetY     float32
+	UnpressedOffsetX, UnpressedOffsetY float32
+	CheckedOffsetX, CheckedOffsetY     float32
+}
+
+// A BaseButton is a table which is clicked by touching it and releasing the touch over it, its background shows whether it
+// is pressed, under the mouse, checked or disabled. Each click toggles whether the button is checked. TextButton,
+// ImageButton and CheckBox are base buttons with content.
+//
+//	button := spike.NewTextButton("Play", style)
+//	button.OnChange = func(b *spike.BaseButton) {
+//	  spike.SetScene("game")
+//	}
+type BaseButton struct {
+	Table
+
+	style *ButtonStyle
+
+	checked, disabled bool
+	pressed, over     bool
+	pointer           uint8
+
+	// the offset the children are moved by
+	offsetX, offsetY float32
+
+	// If set SetChecked calls OnChange. Default is true.
+	ProgrammaticChangeEvents bool
+
+	// Called when the button is clicked or when it is checked or unchecked
+	OnChange func(self *BaseButton)
+}
+
+// Creates a button without content, actors can be added to it like to a table
+func NewBaseButton(style *ButtonStyle) *BaseButton {
+	self := &BaseButton{}
+	self.initButton(style)
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Initializes the button, the widgets which embed a button call it in their constructors
+func (self *BaseButton) initButton(style *ButtonStyle) {
+	self.initTable()
+	self.widget.init(&self.Actor, self)
+	self.style = style
+	self.ProgrammaticChangeEvents = true
+	self.Focusable = true
+	self.SetBackground(style.Up)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+}
+
+func (self *BaseButton) SetStyle(style *ButtonStyle) {
+	self.style = style
+	self.SetBackground(self.currentBackground())
+}
+
+func (self *BaseButton) GetStyle() *ButtonStyle {
+	return self.style
+}
+
+// Checks or unchecks the button, OnChange is called if ProgrammaticChangeEvents is set
+func (self *BaseButton) SetChecked(checked bool) {
+	self.setChecked(checked, self.ProgrammaticChangeEvents)
+}
+
+func (self *BaseButton) setChecked(checked, fire bool) {
+	if self.checked == checked {
+		return
+	}
+	self.checked = checked
+	if fire && self.OnChange != nil {
+		self.OnChange(self)
+	}
+}
+
+func (self *BaseButton) IsChecked() bool {
+	return self.checked
+}
+
+// Toggles whether the button is checked
+func (self *BaseButton) Toggle() {
+	self.SetChecked(!self.checked)
+}
+
+// A disabled button cannot be clicked and is drawn with the disabled drawables of its style
+func (self *BaseButton) SetDisabled(disabled bool) {
+	self.disabled = disabled
+	if disabled {
+		self.pressed = false
+	}
+}
+
+func (self *BaseButton) IsDisabled() bool {
+	return self.disabled
+}
+
+// Returns whether the button is touched and the touch is over it
+func (self *BaseButton) IsPressed() bool {
+	return self.pressed && self.over
+}
+
+// Returns whether the mouse or a touch of the button is over it
+func (self *BaseButton) IsOver() bool {
+	return self.over
+}
+
+// Clicks the button on the release of a touch which started on it, if the touch is still over it
+func (self *BaseButton) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if self.pressed || self.disabled || !self.Hit(e.X, e.Y, widgetPoint) {
+			return
+		}
+		self.pressed, self.over, self.pointer = true, true, e.Pointer
+	case TouchDragged:
+		if self.pressed && e.Pointer == self.pointer {
+			self.over = self.Hit(e.X, e.Y, widgetPoint)
+		}
+	case TouchUp:
+		if !self.pressed || e.Pointer != self.pointer {
+			return
+		}
+		self.pressed, self.over = false, false
+		// a touch which scrolled the scroll pane the button is in does not click it
+		if !self.disabled && self.Hit(e.X, e.Y, widgetPoint) && !scrollPaneDragged(&self.Actor) {
+			self.setChecked(!self.checked, true)
+		}
+	case MouseMoved:
+		self.over = self.Hit(e.X, e.Y, widgetPoint)
+	case Activated:
+		if !self.disabled {
+			self.setChecked(!self.checked, true)
+		}
+	}
+}
+
+// Returns the drawable of the state of the button, the focused button is drawn like when the mouse is over it
+func (self *BaseButton) currentBackground() ui.Drawable {
+	style := self.style
+	switch {
+	case self.disabled && style.Disabled != nil:
+		return style.Disabled
+	case self.IsPressed() && style.Down != nil:
+		return style.Down
+	case self.checked && style.Checked != nil:
+		if (self.over || self.HasFocus()) && style.CheckedOver != nil {
+			return style.CheckedOver
+		}
+		return style.Checked
+	case (self.over || self.HasFocus()) && style.Over != nil:
+		return style.Over
+	}
+	return style.Up
+}
+
+func (self *BaseButton) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	if background := self.currentBackground(); background != self.background {
+		// the drawables of the states have the same padding, the cells are not laid out again
+		self.background = background
+	}
+	offsetX, offsetY := self.style.UnpressedOffsetX, self.style.UnpressedOffsetY
+	if self.IsPressed() && !self.disabled {
+		offsetX, offsetY = self.style.PressedOffsetX, self.style.PressedOffsetY
+	} else if self.checked && !self.disabled {
+		offsetX, offsetY = self.style.CheckedOffsetX, self.style.CheckedOffsetY
+	}
+	if offsetX != self.offsetX || offsetY != self.offsetY {
+		for _, child := range self.Children {
+			child.MoveBy(offsetX-self.offsetX, offsetY-self.offsetY)
+		}
+		self.offsetX, self.offsetY = offsetX, offsetY
+	}
+	self.Table.draw(batch, parentAlpha)
+}
+
+// Lays the content of the button out, it is moved by the offset of the state of the button again when it is drawn
+func (self *BaseButton) Layout() {
+	self.Table.Layout()
+	self.offsetX, self.offsetY = 0, 0
+}
+
+// The style of a text button, the font colors are of the states of the button like the drawables. Only FontColor is
+// required, nil colors of the other states use it.
+type TextButtonStyle struct {
+	ButtonStyle
+
+	Font *g2d.BitmapFont
+
+	FontColor, DownFontColor, OverFontColor, CheckedFontColor, CheckedOverFontColor, DisabledFontColor *Color
+}
+
+// A TextButton is a button with a label
+type TextButton struct {
+	BaseButton
+
+	style      *TextButtonStyle
+	label      *Label
+	labelStyle LabelStyle
+}
+
+func NewTextButton(text string, style *TextButtonStyle) *TextButton {
+	self := &TextButton{}
+	self.initTextButton(text, style)
+	self.Add(&self.label.Actor).Expand().Fill()
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Initializes the button and its label, without adding the label to it
+func (self *TextButton) initTextButton(text string, style *TextButtonStyle) {
+	self.initButton(&style.ButtonStyle)
+	self.widget.init(&self.Actor, self)
+	self.style = style
+	self.labelStyle = LabelStyle{Font: style.Font, FontColor: style.FontColor}
+	self.label = NewLabel(text, &self.labelStyle)
+	self.label.SetAlignment(utils.AlignmentCenter, utils.AlignmentCenter)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.labelStyle.Font, self.labelStyle.FontColor = self.style.Font, self.fontColor()
+		self.BaseButton.draw(batch, parentAlpha)
+	}
+}
+
+// Invalidates the button, the label is laid out with the font of the style again when the style was changed, like by
+// Skin.Apply
+func (self *TextButton) Invalidate() {
+	if self.labelStyle.Font != self.style.Font {
+		self.labelStyle.Font = self.style.Font
+		self.label.Invalidate()
+	}
+	self.BaseButton.Invalidate()
+}
+
+// Returns the font color of the state of the button
+func (self *TextButton) fontColor() *Color {
+	style := self.style
+	var color *Color
+	switch {
+	case self.disabled:
+		color = style.DisabledFontColor
+	case self.IsPressed():
+		color = style.DownFontColor
+	case self.checked:
+		color = style.CheckedFontColor
+		if self.over && style.CheckedOverFontColor != nil {
+			color = style.CheckedOverFontColor
+		}
+	case self.over:
+		color = style.OverFontColor
+	}
+	if color == nil {
+		color = style.FontColor
+	}
+	return color
+}
+
+func (self *TextButton) GetStyle() *TextButtonStyle {
+	return self.style
+}
+
+func (self *TextButton) SetText(text string) {
+	self.label.SetText(text)
+}
+
+func (self *TextButton) GetText() string {
+	return self.label.GetText()
+}
+
+func (self *TextButton) GetLabel() *Label {
+	return self.label
+}
+
+// The style of an image button, the images are drawn over the background in the states of the button. Only ImageUp is
+// required, the states without an image use it.
+type ImageButtonStyle struct {
+	ButtonStyle
+
+	ImageUp, ImageDown, ImageOver, ImageChecked, ImageCheckedOver, ImageDisabled ui.Drawable
+}
+
+// An ImageButton is a button with an image, which is scaled to fit the button keeping its aspect ratio
+type ImageButton struct {
+	BaseButton
+
+	style *ImageButtonStyle
+	image *Image
+}
+
+func NewImageButton(style *ImageButtonStyle) *ImageButton {
+	self := &ImageButton{style: style}
+	self.initButton(&style.ButtonStyle)
+	self.image = NewImage(style.ImageUp)
+	self.image.SetScaling(scaling.Fit)
+	self.Add(&self.image.Actor)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.image.SetDrawable(self.imageDrawable())
+		self.BaseButton.draw(batch, parentAlpha)
+	}
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Returns the image of the state of the button
+func (self *ImageButton) imageDrawable() ui.Drawable {
+	style := self.style
+	var image ui.Drawable
+	switch {
+	case self.disabled:
+		image = style.ImageDisabled
+	case self.IsPressed():
+		image = style.ImageDown
+	case self.checked:
+		image = style.ImageChecked
+		if self.over && style.ImageCheckedOver != nil {
+			image = style.ImageCheckedOver
+		}
+	case self.over:
+		image = style.ImageOver
+	}
+	if image == nil {
+		image = style.ImageUp
+	}
+	return image
+}
+
+func (self *ImageButton) GetStyle() *ImageButtonStyle {
+	return self.style
+}
+
+func (self *ImageButton) GetImage() *Image {
+	return self.image
+}
+
+// The style of a check box, the box is drawn left of the text. CheckboxOn and CheckboxOff are required, the other states
+// use them.
+type CheckBoxStyle struct {
+	TextButtonStyle
+
+	CheckboxOn, CheckboxOff                 ui.Drawable
+	CheckboxOnOver, CheckboxOver            ui.Drawable
+	CheckboxOnDisabled, CheckboxOffDisabled ui.Drawable
+}
+
+// A CheckBox is a button with a box which shows whether it is checked and a label
+type CheckBox struct {
+	TextButton
+
+	style *CheckBoxStyle
+	image *Image
+}
+
+func NewCheckBox(text string, style *CheckBoxStyle) *CheckBox {
+	self := &CheckBox{style: style}
+	self.initTextButton(text, &style.TextButtonStyle)
+	self.image = NewImage(style.CheckboxOff)
+	self.image.SetScaling(scaling.None)
+	self.label.SetAlignment(utils.AlignmentLeft, utils.AlignmentLeft)
+	self.Add(&self.image.Actor)
+	self.Add(&self.label.Actor).ExpandX().FillX()
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.image.SetDrawable(self.boxDrawable())
+		self.labelStyle.Font, self.labelStyle.FontColor = self.style.Font, self.fontColor()
+		self.BaseButton.draw(batch, parentAlpha)
+	}
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Returns the box of the state of the check box
+func (self *CheckBox) boxDrawable() ui.Drawable {
+	style := self.style
+	var box ui.Drawable
+	switch {
+	case self.disabled && self.checked:
+		box = style.CheckboxOnDisabled
+	case self.disabled:
+		box = style.CheckboxOffDisabled
+	case self.over && self.checked:
+		box = style.CheckboxOnOver
+	case self.over:
+		box = style.CheckboxOver
+	}
+	if box != nil {
+		return box
+	}
+	if self.checked {
+		return style.CheckboxOn
+	}
+	return style.CheckboxOff
+}
+
+func (self *CheckBox) GetStyle() *CheckBoxStyle {
+	return self.style
+}
+
+func (self *CheckBox) GetImage() *Image {
+	return self.image
+}
@@ -10,7 +10,6 @@ import (
 	. "github.com/pyros2097/spike/math"
 	. "github.com/pyros2097/spike/math/collision"
 	. "github.com/pyros2097/spike/math/vector"
-	// "github.com/pyros2097/spike/utils/scaling"
 )
 
 // Base class for OrthographicCamera and PerspectiveCamera
@@ -58,40 +57,48 @@ type Camera struct {
 	UpdateFrustum func(self *Camera, updateFrustum bool)
 }
 
+// The camera for 2D scenes, it is set to the target size by the viewport
+var Camera2d = NewOrthographicCamera(800, 480)
+
+// The camera for 3D scenes
+var Camera3d = NewPerspectiveCamera(67, 800, 480)
+
 // An OrthographicCamera, using the given viewport width and height. For pixel perfect 2D rendering just supply
 // the screen size, for other unit scales (e.g. meters for box2d) proceed accordingly. The camera will show the region
 // [-viewportWidth/2, -(viewportHeight/2-1)] - [(viewportWidth/2-1), viewportHeight/2]
-var Camera2d = &Camera{
-	Position:          NewVector3Empty(),
-	Direction:         NewVector3(0, 0, -1),
-	Up:                NewVector3(0, 1, 0),
-	Projection:        NewMatrix4Empty(),
-	View:              NewMatrix4Empty(),
-	Combined:          NewMatrix4Empty(),
-	InvProjectionView: NewMatrix4Empty(),
-	Near:              0,
-	Far:               100,
-	ViewportWidth:     800, //viewportWidth,
-	ViewportHeight:    480, //viewportHeight,
-	tmpVec:            NewVector3Empty(),
-	tmp:               NewVector3Empty(),
-	Ray:               NewRay(NewVector3Empty(), NewVector3Empty()),
-	Zoom:              1,
-	FieldOfView:       67,
-	frustum:           NewFrustumEmpty(),
-	UpdateFrustum: func(self *Camera, updateFrustum bool) {
-		self.Projection.SetToOrtho2DNear(self.Zoom*-self.ViewportWidth/2, self.Zoom*(self.ViewportWidth/2), self.Zoom*-(self.ViewportHeight/2),
-			self.Zoom*self.ViewportHeight/2, self.Near, self.Far)
-		self.View.SetToLookAtPos(self.Position, self.tmp.SetV(self.Position).AddV(self.Direction), self.Up)
-		self.Combined.SetM4(self.Projection)
-		// Matrix4.MulM4(combined.val, view.val)
-
-		if updateFrustum {
-			self.InvProjectionView.SetM4(self.Combined)
-			// Matrix4.inv(invProjectionView.val)
-			self.frustum.Update(self.InvProjectionView)
-		}
-	},
+func NewOrthographicCamera(viewportWidth, viewportHeight float32) *Camera {
+	return &Camera{
+		Position:          NewVector3Empty(),
+		Direction:         NewVector3(0, 0, -1),
+		Up:                NewVector3(0, 1, 0),
+		Projection:        NewMatrix4Empty(),
+		View:              NewMatrix4Empty(),
+		Combined:          NewMatrix4Empty(),
+		InvProjectionView: NewMatrix4Empty(),
+		Near:              0,
+		Far:               100,
+		ViewportWidth:     viewportWidth,
+		ViewportHeight:    viewportHeight,
+		tmpVec:            NewVector3Empty(),
+		tmp:               NewVector3Empty(),
+		Ray:               NewRay(NewVector3Empty(), NewVector3Empty()),
+		Zoom:              1,
+		FieldOfView:       67,
+		frustum:           NewFrustumEmpty(),
+		UpdateFrustum:     updateOrthographic,
+	}
+}
+
+func updateOrthographic(self *Camera, updateFrustum bool) {
+	self.Projection.SetToOrtho(self.Zoom*-self.ViewportWidth/2, self.Zoom*(self.ViewportWidth/2), self.Zoom*-(self.ViewportHeight/2),
+		self.Zoom*self.ViewportHeight/2, self.Near, self.Far)
+	self.View.SetToLookAtPos(self.Position, self.tmp.SetV(self.Position).AddV(self.Direction), self.Up)
+	self.Combined.SetM4(self.Projection).MulM4(self.View)
+
+	if updateFrustum {
+		self.InvProjectionView.SetM4(self.Combined).Inv()
+		self.frustum.Update(self.InvProjectionView)
+	}
 }
 
 // A PerspectiveCamera with the given field of view and viewport size. The aspect ratio is derived from
@@ -100,42 +107,52 @@ var Camera2d = &Camera{
 //          according to the aspect ratio.
 // @param viewportWidth the viewport width
 // @param viewportHeight the viewport height
-var Camera3d = &Camera{
-	Position:          NewVector3Empty(),
-	Direction:         NewVector3(0, 0, -1),
-	Up:                NewVector3(0, 1, 0),
-	Projection:        NewMatrix4Empty(),
-	View:              NewMatrix4Empty(),
-	Combined:          NewMatrix4Empty(),
-	InvProjectionView: NewMatrix4Empty(),
-	Near:              1, //Near = 0 if orthographic camera
-	Far:               100,
-	ViewportWidth:     800, //viewportWidth,
-	ViewportHeight:    480, //viewportHeight,
-	tmpVec:            NewVector3Empty(),
-	tmp:               NewVector3Empty(),
-	Ray:               NewRay(NewVector3Empty(), NewVector3Empty()),
-	Zoom:              1,
-	FieldOfView:       67,
-	frustum:           NewFrustumEmpty(),
-	UpdateFrustum: func(self *Camera, updateFrustum bool) {
-		aspect := self.ViewportWidth / self.ViewportHeight
-		self.Projection.SetToProjectionNear(float32(math.Abs(float64(self.Near))), float32(math.Abs(float64(self.Far))),
-			self.FieldOfView, aspect) // TODO: check this call overload
-		self.View.SetToLookAtPos(self.Position, self.tmp.SetV(self.Position).AddV(self.Direction), self.Up)
-		self.Combined.SetM4(self.Projection)
-		// Matrix4.mul(combined.val, view.val)
-
-		if updateFrustum {
-			self.InvProjectionView.SetM4(self.Combined)
-			// Matrix4.inv(invProjectionView.val)
-			self.frustum.Update(self.InvProjectionView)
-		}
-	},
+func NewPerspectiveCamera(fieldOfViewY, viewportWidth, viewportHeight float32) *Camera {
+	return &Camera{
+		Position:          NewVector3Empty(),
+		Direction:         NewVector3(0, 0, -1),
+		Up:                NewVector3(0, 1, 0),
+		Projection:        NewMatrix4Empty(),
+		View:              NewMatrix4Empty(),
+		Combined:          NewMatrix4Empty(),
+		InvProjectionView: NewMatrix4Empty(),
+		Near:              1, //Near = 0 if orthographic camera
+		Far:               100,
+		ViewportWidth:     viewportWidth,
+		ViewportHeight:    viewportHeight,
+		tmpVec:            NewVector3Empty(),
+		tmp:               NewVector3Empty(),
+		Ray:               NewRay(NewVector3Empty(), NewVector3Empty()),
+		Zoom:              1,
+		FieldOfView:       fieldOfViewY,
+		frustum:           NewFrustumEmpty(),
+		UpdateFrustum:     updatePerspective,
+	}
+}
+
+func updatePerspective(self *Camera, updateFrustum bool) {
+	aspect := self.ViewportWidth / self.ViewportHeight
+	self.Projection.SetToProjectionNear(float32(math.Abs(float64(self.Near))), float32(math.Abs(float64(self.Far))),
+		self.FieldOfView, aspect)
+	self.View.SetToLookAtPos(self.Position, self.tmp.SetV(self.Position).AddV(self.Direction), self.Up)
+	self.Combined.SetM4(self.Projection).MulM4(self.View)
+
+	if updateFrustum {
+		self.InvProjectionView.SetM4(self.Combined).Inv()
+		self.frustum.Update(self.InvProjectionView)
+	}
 }
 
-// Camera2d.Update()
-// Camera3d.Update()
+// Recalculates the projection and view matrix of this camera and the frustum planes. Use this after you've manipulated
+// any of the attributes of the camera.
+func (self *Camera) Update() {
+	self.UpdateFrustum(self, true)
+}
+
+// Returns the frustum of the camera, the planes are updated by Update
+func (self *Camera) GetFrustum() *Frustum {
+	return self.frustum
+}
 
 // Recalculates the direction of the camera to look at the point (x, y, z). This function assumes the up vector is normalized.
 // param x the x-coordinate of the point to look at
@@ -245,12 +262,10 @@ func (self *Camera) TranslateV3(vec *Vector3) {
 // Rotates the camera by the given angle around the direction vector. The direction and up vector will not be orthogonalized.
 // param angle
 // Sets this camera to an orthographic projection using a viewport fitting the screen resolution, centered at
-// (Gdx.graphics.getWidth()/2, Gdx.graphics.getHeight()/2), with the y-axis pointing up or down.
+// (screen width/2, screen height/2), with the y-axis pointing up or down.
 // param yDown whether y should be pointing down
 func (self *Camera) SetToOrtho(yDown bool) {
-	// Gdx.graphics.getWidth() TODO:
-	// Gdx.graphics.getHeight()
-	self.SetToOrthoVW(yDown, 0, 0)
+	self.SetToOrthoVW(yDown, float32(screenWidth), float32(screenHeight))
 }
 
 // Sets this camera to an orthographic projection, centered at (viewportWidth/2, viewportHeight/2), with the y-axis pointing up
@@ -269,7 +284,7 @@ func (self *Camera) SetToOrthoVW(yDown bool, viewportWidth, viewportHeight float
 	self.Position.Set(self.Zoom*viewportWidth/2.0, self.Zoom*viewportHeight/2.0, 0)
 	self.ViewportWidth = viewportWidth
 	self.ViewportHeight = viewportHeight
-	// self.Update()
+	self.Update()
 }
 
 func (self *Camera) RotateAngle(angle float32) {
@@ -301,11 +316,13 @@ func (self *Camera) TranslateV2(vec *Vector2) {
 // param viewportWidth the width of the viewport in pixels
 // param viewportHeight the height of the viewport in pixels
 func (self *Camera) Unproject(screenCoords *Vector3, viewportX, viewportY, viewportWidth, viewportHeight float32) *Vector3 {
-	x := screenCoords.X
-	y := screenCoords.Y
-	x = x - viewportX
-	y = 0 //Gdx.graphics.getHeight() - y - 1 TODO:
-	y = y - viewportY
+	return self.unproject(screenCoords, float32(screenHeight), viewportX, viewportY, viewportWidth, viewportHeight)
+}
+
+// Unprojects the screen coordinates for a screen of the given height
+func (self *Camera) unproject(screenCoords *Vector3, height, viewportX, viewportY, viewportWidth, viewportHeight float32) *Vector3 {
+	x := screenCoords.X - viewportX
+	y := height - screenCoords.Y - 1 - viewportY
 	screenCoords.X = (2*x)/viewportWidth - 1
 	screenCoords.Y = (2*y)/viewportHeight - 1
 	screenCoords.Z = 2*screenCoords.Z - 1
@@ -320,8 +337,7 @@ func (self *Camera) Unproject(screenCoords *Vector3, viewportX, viewportY, viewp
 // will return a point on the near plane, a z-coordinate of 1 will return a point on the far plane.
 // param screenCoords the point in screen coordinates
 func (self *Camera) UnprojectV3(screenCoords *Vector3) *Vector3 {
-	// self.Unproject(screenCoords, 0, 0, Gdx.graphics.getWidth(), Gdx.graphics.getHeight()) TODO:
-	return screenCoords
+	return self.Unproject(screenCoords, 0, 0, float32(screenWidth), float32(screenHeight))
 }
 
 // Projects the {@link Vector3} given in world space to screen coordinates. It's the same as GLU gluProject with one small
@@ -329,8 +345,7 @@ func (self *Camera) UnprojectV3(screenCoords *Vector3) *Vector3 {
 // <b>bottom</b> left, with the y-axis pointing <b>upwards</b> and the x-axis pointing to the right. This makes it easily
 // useable in conjunction with {@link Batch} and similar classes.
 func (self *Camera) ProjectV3(worldCoords *Vector3) *Vector3 {
-	// self.Project(worldCoords, 0, 0, Gdx.graphics.getWidth(), Gdx.graphics.getHeight()) TODO:
-	return worldCoords
+	return self.Project(worldCoords, 0, 0, float32(screenWidth), float32(screenHeight))
 }
 
 // Projects the {@link Vector3} given in world space to screen coordinates. It's the same as GLU gluProject with one small
@@ -371,437 +386,9 @@ func (self *Camera) GetPickRay(screenX, screenY, viewportX, viewportY, viewportW
 // pointing to the right. The returned instance is not a new instance but an internal member only accessible via this function.
 // return the picking Ray.
 func (self *Camera) GetPickRayXY(screenX, screenY float32) *Ray {
-	//Gdx.graphics.getWidth(), Gdx.graphics.getHeight()
-	return self.GetPickRay(screenX, screenY, 0, 0, 0, 0) // TODO
+	return self.GetPickRay(screenX, screenY, 0, 0, float32(screenWidth), float32(screenHeight))
 }
 
-// A ScalingViewport that uses {@link Scaling#fit} so it keeps the aspect ratio by scaling the world up to fit the screen, adding
-// black bars (letterboxing) for the remaining space.
-// public class FitViewport extends ScalingViewport {
-// 	/** Creates a new viewport using a new {@link OrthographicCamera}. */
-// 	public FitViewport (float worldWidth, float worldHeight) {
-// 		super(Scaling.fit, worldWidth, worldHeight);
-// 	}
-
-// 	public FitViewport (float worldWidth, float worldHeight, Camera camera) {
-// 		super(Scaling.fit, worldWidth, worldHeight, camera);
-// 	}
-// }
-
-// A viewport where the world size is based on the size of the screen. By default 1 world unit == 1 screen pixel, but this ratio
-// can be {@link #setUnitsPerPixel(float) changed}.
-// public class ScreenViewport extends Viewport {
-// 	private float unitsPerPixel = 1;
-
-// 	/** Creates a new viewport using a new {@link OrthographicCamera}. */
-// 	public ScreenViewport () {
-// 		this(new OrthographicCamera());
-// 	}
-
-// 	public ScreenViewport (Camera camera) {
-// 		setCamera(camera);
-// 	}
-
-// 	@Override
-// 	public void update (int screenWidth, int screenHeight, boolean centerCamera) {
-// 		setScreenBounds(0, 0, screenWidth, screenHeight);
-// 		setWorldSize(screenWidth * unitsPerPixel, screenHeight * unitsPerPixel);
-// 		apply(centerCamera);
-// 	}
-
-// 	public float getUnitsPerPixel () {
-// 		return unitsPerPixel;
-// 	}
-
-// 	/** Sets the number of pixels for each world unit. Eg, a scale of 2.5 means there are 2.5 world units for every 1 screen pixel.
-// 	 * Default is 1. */
-// 	public void setUnitsPerPixel (float unitsPerPixel) {
-// 		this.unitsPerPixel = unitsPerPixel;
-// 	}
-// }
-
-// A ScalingViewport that uses {@link Scaling#stretch} so it does not keep the aspect ratio, the world is scaled to take the whole
-// screen.
-// public class StretchViewport extends ScalingViewport {
-// 	/** Creates a new viewport using a new {@link OrthographicCamera}. */
-// 	public StretchViewport (float worldWidth, float worldHeight) {
-// 		super(Scaling.stretch, worldWidth, worldHeight);
-// 	}
-
-// 	public StretchViewport (float worldWidth, float worldHeight, Camera camera) {
-// 		super(Scaling.stretch, worldWidth, worldHeight, camera);
-// 	}
-// }
-
-// A viewport that scales the world using {@link Scaling}.
-// {@link Scaling#fit} keeps the aspect ratio by scaling the world up to fit the screen, adding black bars (letterboxing) for the
-// remaining space.
-// {@link Scaling#fill} keeps the aspect ratio by scaling the world up to take the whole screen (some of the world may be off
-// screen).
-// {@link Scaling#stretch} does not keep the aspect ratio, the world is scaled to take the whole screen.
-// {@link Scaling#none} keeps the aspect ratio by using a fixed size world (the world may not fill the screen or some of the world
-//may be off screen).
-// public class ScalingViewport extends Viewport {
-// 	private Scaling scaling;
-
-// 	/** Creates a new viewport using a new {@link OrthographicCamera}. */
-// 	public ScalingViewport (Scaling scaling, float worldWidth, float worldHeight) {
-// 		this(scaling, worldWidth, worldHeight, new OrthographicCamera());
-// 	}
-
-// 	public ScalingViewport (Scaling scaling, float worldWidth, float worldHeight, Camera camera) {
-// 		this.scaling = scaling;
-// 		setWorldSize(worldWidth, worldHeight);
-// 		setCamera(camera);
-// 	}
-
-// 	@Override
-// 	public void update (int screenWidth, int screenHeight, boolean centerCamera) {
-// 		Vector2 scaled = scaling.apply(getWorldWidth(), getWorldHeight(), screenWidth, screenHeight);
-// 		int viewportWidth = Math.round(scaled.x);
-// 		int viewportHeight = Math.round(scaled.y);
-
-// 		// Center.
-// 		setScreenBounds((screenWidth - viewportWidth) / 2, (screenHeight - viewportHeight) / 2, viewportWidth, viewportHeight);
-
-// 		apply(centerCamera);
-// 	}
-
-// 	public Scaling getScaling () {
-// 		return scaling;
-// 	}
-
-// 	public void setScaling (Scaling scaling) {
-// 		this.scaling = scaling;
-// 	}
-// }
-
-// A ScalingViewport that uses {@link Scaling#fill} so it keeps the aspect ratio by scaling the world up to take the whole screen
-// (some of the world may be off screen).
-// public class FillViewport extends ScalingViewport {
-// 	/** Creates a new viewport using a new {@link OrthographicCamera}. */
-// 	public FillViewport (float worldWidth, float worldHeight) {
-// 		super(Scaling.fill, worldWidth, worldHeight);
-// 	}
-
-// 	public FillViewport (float worldWidth, float worldHeight, Camera camera) {
-// 		super(Scaling.fill, worldWidth, worldHeight, camera);
-// 	}
-// }
-
-// A viewport that keeps the world aspect ratio by extending the world in one direction. The world is first scaled to fit within
-// the viewport, then the shorter dimension is lengthened to fill the viewport. A maximum size can be specified to limit how much
-// the world is extended and black bars (letterboxing) are used for any remaining space.
-// public class ExtendViewport extends Viewport {
-// 	private float minWorldWidth, minWorldHeight;
-// 	private float maxWorldWidth, maxWorldHeight;
-
-// 	/** Creates a new viewport using a new {@link OrthographicCamera} with no maximum world size. */
-// 	public ExtendViewport (float minWorldWidth, float minWorldHeight) {
-// 		this(minWorldWidth, minWorldHeight, 0, 0, new OrthographicCamera());
-// 	}
-
-// 	/** Creates a new viewport with no maximum world size. */
-// 	public ExtendViewport (float minWorldWidth, float minWorldHeight, Camera camera) {
-// 		this(minWorldWidth, minWorldHeight, 0, 0, camera);
-// 	}
-
-// 	/** Creates a new viewport using a new {@link OrthographicCamera} and a maximum world size.
-// 	 * @see ExtendViewport#ExtendViewport(float, float, float, float, Camera) */
-// 	public ExtendViewport (float minWorldWidth, float minWorldHeight, float maxWorldWidth, float maxWorldHeight) {
-// 		this(minWorldWidth, minWorldHeight, maxWorldWidth, maxWorldHeight, new OrthographicCamera());
-// 	}
-
-// 	/** Creates a new viewport with a maximum world size.
-// 	 * @param maxWorldWidth User 0 for no maximum width.
-// 	 * @param maxWorldHeight User 0 for no maximum height. */
-// 	public ExtendViewport (float minWorldWidth, float minWorldHeight, float maxWorldWidth, float maxWorldHeight, Camera camera) {
-// 		this.minWorldWidth = minWorldWidth;
-// 		this.minWorldHeight = minWorldHeight;
-// 		this.maxWorldWidth = maxWorldWidth;
-// 		this.maxWorldHeight = maxWorldHeight;
-// 		setCamera(camera);
-// 	}
-
-// 	@Override
-// 	public void update (int screenWidth, int screenHeight, boolean centerCamera) {
-// 		// Fit min size to the screen.
-// 		float worldWidth = minWorldWidth;
-// 		float worldHeight = minWorldHeight;
-// 		Vector2 scaled = Scaling.fit.apply(worldWidth, worldHeight, screenWidth, screenHeight);
-
-// 		// Extend in the short direction.
-// 		int viewportWidth = Math.round(scaled.x);
-// 		int viewportHeight = Math.round(scaled.y);
-// 		if (viewportWidth < screenWidth) {
-// 			float toViewportSpace = viewportHeight / worldHeight;
-// 			float toWorldSpace = worldHeight / viewportHeight;
-// 			float lengthen = (screenWidth - viewportWidth) * toWorldSpace;
-// 			if (maxWorldWidth > 0) lengthen = Math.min(lengthen, maxWorldWidth - minWorldWidth);
-// 			worldWidth += lengthen;
-// 			viewportWidth += Math.round(lengthen * toViewportSpace);
-// 		} else if (viewportHeight < screenHeight) {
-// 			float toViewportSpace = viewportWidth / worldWidth;
-// 			float toWorldSpace = worldWidth / viewportWidth;
-// 			float lengthen = (screenHeight - viewportHeight) * toWorldSpace;
-// 			if (maxWorldHeight > 0) lengthen = Math.min(lengthen, maxWorldHeight - minWorldHeight);
-// 			worldHeight += lengthen;
-// 			viewportHeight += Math.round(lengthen * toViewportSpace);
-// 		}
-
-// 		setWorldSize(worldWidth, worldHeight);
-
-// 		// Center.
-// 		setScreenBounds((screenWidth - viewportWidth) / 2, (screenHeight - viewportHeight) / 2, viewportWidth, viewportHeight);
-
-// 		apply(centerCamera);
-// 	}
-
-// 	public float getMinWorldWidth () {
-// 		return minWorldWidth;
-// 	}
-
-// 	public void setMinWorldWidth (float minWorldWidth) {
-// 		this.minWorldWidth = minWorldWidth;
-// 	}
-
-// 	public float getMinWorldHeight () {
-// 		return minWorldHeight;
-// 	}
-
-// 	public void setMinWorldHeight (float minWorldHeight) {
-// 		this.minWorldHeight = minWorldHeight;
-// 	}
-
-// 	public float getMaxWorldWidth () {
-// 		return maxWorldWidth;
-// 	}
-
-// 	public void setMaxWorldWidth (float maxWorldWidth) {
-// 		this.maxWorldWidth = maxWorldWidth;
-// 	}
-
-// 	public float getMaxWorldHeight () {
-// 		return maxWorldHeight;
-// 	}
-
-// 	public void setMaxWorldHeight (float maxWorldHeight) {
-// 		this.maxWorldHeight = maxWorldHeight;
-// 	}
-// }
-
-// Manages a {@link Camera} and determines how world coordinates are mapped to and from the screen.
-// public abstract class Viewport {
-// 	private Camera camera;
-// 	private float worldWidth, worldHeight;
-// 	private int screenX, screenY, screenWidth, screenHeight;
-
-// 	private final Vector3 tmp = new Vector3();
-
-// 	/** Calls {@link #apply(boolean)} with false. */
-// 	public void apply () {
-// 		apply(false);
-// 	}
-
-// 	/** Applies the viewport to the camera and sets the glViewport.
-// 	 * @param centerCamera If true, the camera position is set to the center of the world. */
-// 	public void apply (boolean centerCamera) {
-// 		Gdx.gl.glViewport(screenX, screenY, screenWidth, screenHeight);
-// 		camera.viewportWidth = worldWidth;
-// 		camera.viewportHeight = worldHeight;
-// 		if (centerCamera) camera.position.set(worldWidth / 2, worldHeight / 2, 0);
-// 		camera.update();
-// 	}
-
-// 	/** Calls {@link #update(int, int, boolean)} with false. */
-// 	public final void update (int screenWidth, int screenHeight) {
-// 		update(screenWidth, screenHeight, false);
-// 	}
-
-// 	/** Configures this viewport's screen bounds using the specified screen size and calls {@link #apply(boolean)}. Typically called
-// 	 * from {@link ApplicationListener#resize(int, int)} or {@link Screen#resize(int, int)}.
-// 	 * <p>
-// 	 * The default implementation only calls {@link #apply(boolean)}. */
-// 	public void update (int screenWidth, int screenHeight, boolean centerCamera) {
-// 		apply(centerCamera);
-// 	}
-
-// 	/** Transforms the specified screen coordinate to world coordinates.
-// 	 * @return The vector that was passed in, transformed to world coordinates.
-// 	 * @see Camera#unproject(Vector3) */
-// 	public Vector2 unproject (Vector2 screenCoords) {
-// 		tmp.set(screenCoords.x, screenCoords.y, 1);
-// 		camera.unproject(tmp, screenX, screenY, screenWidth, screenHeight);
-// 		screenCoords.set(tmp.x, tmp.y);
-// 		return screenCoords;
-// 	}
-
-// 	/** Transforms the specified world coordinate to screen coordinates.
-// 	 * @return The vector that was passed in, transformed to screen coordinates.
-// 	 * @see Camera#project(Vector3) */
-// 	public Vector2 project (Vector2 worldCoords) {
-// 		tmp.set(worldCoords.x, worldCoords.y, 1);
-// 		camera.project(tmp, screenX, screenY, screenWidth, screenHeight);
-// 		worldCoords.set(tmp.x, tmp.y);
-// 		return worldCoords;
-// 	}
-
-// 	/** Transforms the specified screen coordinate to world coordinates.
-// 	 * @return The vector that was passed in, transformed to world coordinates.
-// 	 * @see Camera#unproject(Vector3) */
-// 	public Vector3 unproject (Vector3 screenCoords) {
-// 		camera.unproject(screenCoords, screenX, screenY, screenWidth, screenHeight);
-// 		return screenCoords;
-// 	}
-
-// 	/** Transforms the specified world coordinate to screen coordinates.
-// 	 * @return The vector that was passed in, transformed to screen coordinates.
-// 	 * @see Camera#project(Vector3) */
-// 	public Vector3 project (Vector3 worldCoords) {
-// 		camera.project(worldCoords, screenX, screenY, screenWidth, screenHeight);
-// 		return worldCoords;
-// 	}
-
-// 	/** @see Camera#getPickRay(float, float, float, float, float, float) */
-// 	public Ray getPickRay (float screenX, float screenY) {
-// 		return camera.getPickRay(screenX, screenY, this.screenX, this.screenY, screenWidth, screenHeight);
-// 	}
-
-// 	/** @see ScissorStack#calculateScissors(Camera, float, float, float, float, Matrix4, Rectangle, Rectangle) */
-// 	public void calculateScissors (Matrix4 batchTransform, Rectangle area, Rectangle scissor) {
-// 		ScissorStack.calculateScissors(camera, screenX, screenY, screenWidth, screenHeight, batchTransform, area, scissor);
-// 	}
-
-// 	/** Transforms a point to real screen coordinates (as opposed to OpenGL ES window coordinates), where the origin is in the top
-// 	 * left and the the y-axis is pointing downwards. */
-// 	public Vector2 toScreenCoordinates (Vector2 worldCoords, Matrix4 transformMatrix) {
-// 		tmp.set(worldCoords.x, worldCoords.y, 0);
-// 		tmp.mul(transformMatrix);
-// 		camera.project(tmp);
-// 		tmp.y = Gdx.graphics.getHeight() - tmp.y;
-// 		worldCoords.x = tmp.x;
-// 		worldCoords.y = tmp.y;
-// 		return worldCoords;
-// 	}
-
-// 	public Camera getCamera () {
-// 		return camera;
-// 	}
-
-// 	public void setCamera (Camera camera) {
-// 		this.camera = camera;
-// 	}
-
-// 	public float getWorldWidth () {
-// 		return worldWidth;
-// 	}
-
-// 	/** The virtual width of this viewport in world coordinates. This width is scaled to the viewport's screen width. */
-// 	public void setWorldWidth (float worldWidth) {
-// 		this.worldWidth = worldWidth;
-// 	}
-
-// 	public float getWorldHeight () {
-// 		return worldHeight;
-// 	}
-
-// 	/** The virtual height of this viewport in world coordinates. This height is scaled to the viewport's screen height. */
-// 	public void setWorldHeight (float worldHeight) {
-// 		this.worldHeight = worldHeight;
-// 	}
-
-// 	public void setWorldSize (float worldWidth, float worldHeight) {
-// 		this.worldWidth = worldWidth;
-// 		this.worldHeight = worldHeight;
-// 	}
-
-// 	public int getScreenX () {
-// 		return screenX;
-// 	}
-
-// 	/** Sets the viewport's offset from the left edge of the screen. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenX (int screenX) {
-// 		this.screenX = screenX;
-// 	}
-
-// 	public int getScreenY () {
-// 		return screenY;
-// 	}
-
-// 	/** Sets the viewport's offset from the bottom edge of the screen. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenY (int screenY) {
-// 		this.screenY = screenY;
-// 	}
-
-// 	public int getScreenWidth () {
-// 		return screenWidth;
-// 	}
-
-// 	/** Sets the viewport's width in screen coordinates. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenWidth (int screenWidth) {
-// 		this.screenWidth = screenWidth;
-// 	}
-
-// 	public int getScreenHeight () {
-// 		return screenHeight;
-// 	}
-
-// 	/** Sets the viewport's height in screen coordinates. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenHeight (int screenHeight) {
-// 		this.screenHeight = screenHeight;
-// 	}
-
-// 	/** Sets the viewport's position in screen coordinates. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenPosition (int screenX, int screenY) {
-// 		this.screenX = screenX;
-// 		this.screenY = screenY;
-// 	}
-
-// 	/** Sets the viewport's size in screen coordinates. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenSize (int screenWidth, int screenHeight) {
-// 		this.screenWidth = screenWidth;
-// 		this.screenHeight = screenHeight;
-// 	}
-
-// 	/** Sets the viewport's bounds in screen coordinates. This is typically set by {@link #update(int, int, boolean)}. */
-// 	public void setScreenBounds (int screenX, int screenY, int screenWidth, int screenHeight) {
-// 		this.screenX = screenX;
-// 		this.screenY = screenY;
-// 		this.screenWidth = screenWidth;
-// 		this.screenHeight = screenHeight;
-// 	}
-
-// 	/** Returns the left gutter (black bar) width in screen coordinates. */
-// 	public int getLeftGutterWidth () {
-// 		return screenX;
-// 	}
-
-// 	/** Returns the right gutter (black bar) x in screen coordinates. */
-// 	public int getRightGutterX () {
-// 		return screenX + screenWidth;
-// 	}
-
-// 	/** Returns the right gutter (black bar) width in screen coordinates. */
-// 	public int getRightGutterWidth () {
-// 		return Gdx.graphics.getWidth() - (screenX + screenWidth);
-// 	}
-
-// 	/** Returns the bottom gutter (black bar) height in screen coordinates. */
-// 	public int getBottomGutterHeight () {
-// 		return screenY;
-// 	}
-
-// 	/** Returns the top gutter (black bar) y in screen coordinates. */
-// 	public int getTopGutterY () {
-// 		return screenY + screenHeight;
-// 	}
-
-// 	/** Returns the top gutter (black bar) height in screen coordinates. */
-// 	public int getTopGutterHeight () {
-// 		return Gdx.graphics.getHeight() - (screenY + screenHeight);
-// 	}
-// }
-
 // public class Camera extends OrthographicCamera {
 // 	private static Camera instance;
 // 	private static float duration;
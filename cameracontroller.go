@@ -0,0 +1,380 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"math"
+
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/utils"
+)
+
+// A CameraController moves a 2D camera every frame. It follows a target actor, keeping it within a dead zone and looking
+// ahead of it, zooms to fit several targets, keeps the camera within the bounds of the world and shakes it. It is an
+// actor so that it is updated with the scene, it also pans the camera when the screen is dragged and zooms it when it is
+// pinched. Dragging pans the camera by the distance dragged times SpeedDrag, once the finger is lifted the camera keeps
+// panning with the velocity of the drag times SpeedPan.
+//
+//	cam := spike.NewCameraController(spike.Camera2d)
+//	cam.Target = &player.Actor
+//	cam.SetBounds(0, 0, mapWidth, mapHeight)
+//	scene.AddActor(&cam.Actor)
+//	...
+//	cam.AddTrauma(0.5) // on explosions
+type CameraController struct {
+	Actor
+
+	Camera *Camera
+
+	// The actor whose center is followed, nil if the camera does not follow an actor
+	Target *Actor
+
+	// The actors whose centers are kept in view. If there are any the camera follows their center instead of Target and
+	// zooms to fit them with Padding world units around them.
+	Targets []*Actor
+	Padding float32
+
+	// The size of the dead zone in world units, the target moves within it without moving the camera
+	DeadZoneWidth, DeadZoneHeight float32
+
+	// How many seconds of the target's movement the camera looks ahead
+	LookAhead float32
+
+	// How the camera moves to the point it follows. Default is CameraLerp.
+	Smoothing CameraSmoothing
+
+	// How fast the camera moves to the point it follows. For CameraLerp it is the rate per second at which the distance
+	// shrinks, for CameraSpring the stiffness of the spring. Default is 5.
+	Stiffness float32
+
+	// The damping of CameraSpring. Default is 2 * sqrt(Stiffness), which makes the spring critically damped.
+	Damping float32
+
+	// The area of the world the camera can show, the camera is not clamped if its size is 0. See SetBounds.
+	Bounds shape.Rectangle
+
+	// The range of the zoom when zooming to fit the targets or pinching. Default is 0.5 to 2.
+	MinZoom, MaxZoom float32
+
+	// If true the camera is panned by dragging and zoomed by pinching. Default is false.
+	DragEnabled bool
+
+	// The trauma decreases by TraumaDecay per second. The camera shakes with the square of the trauma by up to
+	// MaxShakeOffset world units and MaxShakeAngle degrees at ShakeFrequency. Defaults are 1, 10, 3 and 15.
+	Trauma         float32
+	TraumaDecay    float32
+	MaxShakeOffset float32
+	MaxShakeAngle  float32
+	ShakeFrequency float32
+
+	// the position and zoom of the camera without the shake
+	x, y, zoom float32
+
+	velocityX, velocityY     float32
+	followed                 *Actor
+	targetX, targetY         float32
+	targetVelX, targetVelY   float32
+	lookX, lookY             float32
+	dragging                 bool
+	dragPointer              uint8
+	dragX, dragY             float32
+	dragScreenX, dragScreenY float32
+	pinchZoom                float32
+	shakeTime                float32
+	shakeSeed                [3]float32
+	initialized              bool
+}
+
+// How a CameraController moves the camera to the point it follows
+type CameraSmoothing uint8
+
+const (
+	// The camera is at the point at once
+	CameraSnap CameraSmoothing = iota
+
+	// The camera moves a part of the distance every frame, slowing down as it gets close
+	CameraLerp
+
+	// The camera is pulled by a spring, it accelerates and may overshoot if the spring is not damped enough
+	CameraSpring
+)
+
+// Creates a controller for the camera at its current position
+func NewCameraController(camera *Camera) *CameraController {
+	self := &CameraController{
+		Camera:         camera,
+		Smoothing:      CameraLerp,
+		Stiffness:      5,
+		MinZoom:        0.5,
+		MaxZoom:        2,
+		TraumaDecay:    1,
+		MaxShakeOffset: 10,
+		MaxShakeAngle:  3,
+		ShakeFrequency: 15,
+	}
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update(delta)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	return self
+}
+
+// Sets the area of the world the camera can show, like the size of a tile map
+func (self *CameraController) SetBounds(x, y, w, h float32) {
+	self.Bounds.Set(x, y, w, h)
+}
+
+// Adds trauma which shakes the camera, the trauma is kept in the range [0, 1]
+func (self *CameraController) AddTrauma(trauma float32) {
+	if self.Trauma <= 0 {
+		// the shake is random but the same for the same seed, so that replays shake the same
+		for i := range self.shakeSeed {
+			self.shakeSeed[i] = utils.RandomFloat() * 1000
+		}
+	}
+	self.Trauma = utils.ClampFloat32(self.Trauma+trauma, 0, 1)
+}
+
+// Moves the camera to the point it follows at once, without smoothing
+func (self *CameraController) Snap() {
+	self.init()
+	if x, y, ok := self.focus(0); ok {
+		self.x, self.y = x, y
+	}
+	self.velocityX, self.velocityY = 0, 0
+	self.clamp()
+	self.apply()
+}
+
+func (self *CameraController) init() {
+	if self.initialized {
+		return
+	}
+	self.initialized = true
+	self.x, self.y = self.Camera.Position.X, self.Camera.Position.Y
+	self.zoom = self.Camera.Zoom
+}
+
+// Moves the camera for the elapsed time. This is called every frame when the controller is added to the scene.
+func (self *CameraController) Update(delta float32) {
+	self.init()
+	if self.dragging {
+		self.x += self.dragX
+		self.y += self.dragY
+		if delta > 0 {
+			self.velocityX, self.velocityY = self.dragX/delta, self.dragY/delta
+		}
+		self.dragX, self.dragY = 0, 0
+	} else if x, y, ok := self.focus(delta); ok {
+		self.moveTo(x, y, delta)
+	} else {
+		// pan on after a drag
+		self.x += self.velocityX * delta
+		self.y += self.velocityY * delta
+		friction := float32(math.Exp(float64(-5 * delta)))
+		self.velocityX *= friction
+		self.velocityY *= friction
+	}
+	if len(self.Targets) > 0 && !self.dragging {
+		self.zoom = self.smooth(self.zoom, self.fitZoom(), delta)
+	}
+	self.clamp()
+	self.shake(delta)
+	self.apply()
+}
+
+// Returns the point the camera follows, ok is false if there is none
+func (self *CameraController) focus(delta float32) (x, y float32, ok bool) {
+	if len(self.Targets) > 0 {
+		rect := targetsRect(self.Targets)
+		return rect.X + rect.W/2, rect.Y + rect.H/2, true
+	}
+	if self.Target == nil {
+		return 0, 0, false
+	}
+	tx, ty := actorCenter(self.Target)
+	if self.followed != self.Target {
+		self.followed = self.Target
+		self.targetX, self.targetY = tx, ty
+		self.targetVelX, self.targetVelY = 0, 0
+		self.lookX, self.lookY = tx, ty
+	}
+	if delta > 0 {
+		self.targetVelX = (tx - self.targetX) / delta
+		self.targetVelY = (ty - self.targetY) / delta
+	}
+	self.targetX, self.targetY = tx, ty
+	tx += self.targetVelX * self.LookAhead
+	ty += self.targetVelY * self.LookAhead
+
+	// the point only moves once the target leaves the dead zone around it
+	halfW, halfH := self.DeadZoneWidth/2, self.DeadZoneHeight/2
+	if tx > self.lookX+halfW {
+		self.lookX = tx - halfW
+	} else if tx < self.lookX-halfW {
+		self.lookX = tx + halfW
+	}
+	if ty > self.lookY+halfH {
+		self.lookY = ty - halfH
+	} else if ty < self.lookY-halfH {
+		self.lookY = ty + halfH
+	}
+	return self.lookX, self.lookY, true
+}
+
+func (self *CameraController) moveTo(x, y, delta float32) {
+	switch self.Smoothing {
+	case CameraSnap:
+		self.x, self.y = x, y
+	case CameraLerp:
+		self.x = self.smooth(self.x, x, delta)
+		self.y = self.smooth(self.y, y, delta)
+	case CameraSpring:
+		damping := self.Damping
+		if damping == 0 {
+			damping = 2 * float32(math.Sqrt(float64(self.Stiffness)))
+		}
+		self.velocityX += (self.Stiffness*(x-self.x) - damping*self.velocityX) * delta
+		self.velocityY += (self.Stiffness*(y-self.y) - damping*self.velocityY) * delta
+		self.x += self.velocityX * delta
+		self.y += self.velocityY * delta
+	}
+}
+
+// Moves value towards target by the part of the distance given by the stiffness, independent of the frame rate
+func (self *CameraController) smooth(value, target, delta float32) float32 {
+	if self.Smoothing == CameraSnap {
+		return target
+	}
+	return target + (value-target)*float32(math.Exp(float64(-self.Stiffness*delta)))
+}
+
+// Returns the zoom which fits all the targets
+func (self *CameraController) fitZoom() float32 {
+	rect := targetsRect(self.Targets)
+	zoom := (rect.W + 2*self.Padding) / self.Camera.ViewportWidth
+	if h := (rect.H + 2*self.Padding) / self.Camera.ViewportHeight; h > zoom {
+		zoom = h
+	}
+	return utils.ClampFloat32(zoom, self.MinZoom, self.MaxZoom)
+}
+
+// Keeps the area the camera shows within the bounds, it is centered on the bounds if they are smaller
+func (self *CameraController) clamp() {
+	if self.Bounds.W <= 0 || self.Bounds.H <= 0 {
+		return
+	}
+	halfW := self.Camera.ViewportWidth * self.zoom / 2
+	halfH := self.Camera.ViewportHeight * self.zoom / 2
+	self.x, self.velocityX = clampAxis(self.x, self.velocityX, self.Bounds.X+halfW, self.Bounds.X+self.Bounds.W-halfW)
+	self.y, self.velocityY = clampAxis(self.y, self.velocityY, self.Bounds.Y+halfH, self.Bounds.Y+self.Bounds.H-halfH)
+}
+
+func clampAxis(value, velocity, min, max float32) (float32, float32) {
+	switch {
+	case min > max:
+		return (min + max) / 2, 0
+	case value < min:
+		return min, 0
+	case value > max:
+		return max, 0
+	}
+	return value, velocity
+}
+
+func (self *CameraController) shake(delta float32) {
+	self.Trauma -= self.TraumaDecay * delta
+	if self.Trauma <= 0 {
+		self.Trauma = 0
+		self.shakeTime = 0
+		return
+	}
+	self.shakeTime += delta
+}
+
+// Sets the camera to the position and zoom with the shake added
+func (self *CameraController) apply() {
+	x, y, angle := self.x, self.y, float32(0)
+	if self.Trauma > 0 {
+		amount := self.Trauma * self.Trauma
+		t := self.shakeTime * self.ShakeFrequency
+		x += self.MaxShakeOffset * amount * shakeNoise(self.shakeSeed[0]+t)
+		y += self.MaxShakeOffset * amount * shakeNoise(self.shakeSeed[1]+t)
+		angle = self.MaxShakeAngle * amount * shakeNoise(self.shakeSeed[2]+t)
+	}
+	self.Camera.Position.Set(x, y, self.Camera.Position.Z)
+	self.Camera.Up.Set(-utils.SinDeg(angle), utils.CosDeg(angle), 0)
+	self.Camera.Zoom = self.zoom
+	self.Camera.Update()
+}
+
+// A smooth noise in the range [-1, 1]
+func shakeNoise(t float32) float32 {
+	return (utils.Sin(t) + utils.Sin(t*2.3+1.7)*0.5) / 1.5
+}
+
+func (self *CameraController) input(e InputEvent) {
+	if !self.DragEnabled {
+		return
+	}
+	switch e.Type {
+	case TouchDown:
+		if e.Pointer == 0 {
+			self.dragging = true
+			self.dragPointer = e.Pointer
+			self.dragScreenX, self.dragScreenY = e.ScreenX, e.ScreenY
+			self.velocityX, self.velocityY = 0, 0
+		} else if e.Pointer == 1 {
+			self.pinchZoom = self.zoom
+		}
+	case TouchDragged:
+		if self.dragging && e.Pointer == self.dragPointer {
+			// the screen y axis points down while the world y axis points up
+			scale := self.worldPerPixel() * SpeedDrag
+			self.dragX -= (e.ScreenX - self.dragScreenX) * scale
+			self.dragY += (e.ScreenY - self.dragScreenY) * scale
+			self.dragScreenX, self.dragScreenY = e.ScreenX, e.ScreenY
+		}
+	case TouchUp:
+		if self.dragging && e.Pointer == self.dragPointer {
+			self.dragging = false
+			self.velocityX *= SpeedPan
+			self.velocityY *= SpeedPan
+		}
+	case Zoom:
+		if e.DeltaY > 0 && self.pinchZoom > 0 {
+			self.dragging = false
+			self.velocityX, self.velocityY = 0, 0
+			self.zoom = utils.ClampFloat32(self.pinchZoom*e.DeltaX/e.DeltaY, self.MinZoom, self.MaxZoom)
+		}
+	}
+}
+
+// Returns the world units per screen pixel of the camera
+func (self *CameraController) worldPerPixel() float32 {
+	if viewport != nil && viewport.Camera == self.Camera && viewport.ScreenWidth > 0 {
+		return self.Camera.ViewportWidth * self.zoom / float32(viewport.ScreenWidth)
+	}
+	return self.zoom
+}
+
+// Returns the center of the actor in stage coordinates
+func actorCenter(actor *Actor) (x, y float32) {
+	x, y = actor.stagePosition()
+	return x + actor.W/2, y + actor.H/2
+}
+
+// Returns the rectangle around the centers of the actors
+func targetsRect(actors []*Actor) shape.Rectangle {
+	x, y := actorCenter(actors[0])
+	rect := shape.Rectangle{X: x, Y: y}
+	for _, actor := range actors[1:] {
+		rect.Merge(actorCenter(actor))
+	}
+	return rect
+}
@@ -0,0 +1,357 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"math"
+
+	"github.com/pyros2097/spike/math/utils"
+	. "github.com/pyros2097/spike/math/vector"
+)
+
+// An OrbitCameraController rotates a 3D camera around a target point like an arcball. Dragging the screen orbits the
+// camera, the pitch is clamped so that it does not flip over the poles, pinching or scrolling zooms it to and away from
+// the target. It is an actor so that it gets the input and is updated with the scene.
+//
+//	orbit := spike.NewOrbitCameraController(spike.Camera3d, vector.NewVector3Empty())
+//	scene.AddActor(&orbit.Actor)
+type OrbitCameraController struct {
+	Actor
+
+	Camera *Camera
+
+	// The point the camera orbits and looks at
+	Target *Vector3
+
+	// The distance of the camera from the target, it is kept between MinDistance and MaxDistance. Defaults are 1 and 1000.
+	Distance    float32
+	MinDistance float32
+	MaxDistance float32
+
+	// The rotation of the camera around the up axis of the target in degrees, at 0 the camera looks along the negative z axis
+	Yaw float32
+
+	// The height of the camera above the target in degrees, it is kept between MinPitch and MaxPitch. Defaults are -89 and 89.
+	Pitch    float32
+	MinPitch float32
+	MaxPitch float32
+
+	// The degrees the camera is rotated per pixel dragged. Default is 0.5.
+	RotateSpeed float32
+
+	// The part of the distance the camera is zoomed per scroll step. Default is 0.1.
+	ZoomSpeed float32
+
+	rotation      *Quaternion
+	drag          pointerDrag
+	pinchDistance float32
+}
+
+// Creates a controller which orbits the camera around the target starting from its current position
+func NewOrbitCameraController(camera *Camera, target *Vector3) *OrbitCameraController {
+	self := &OrbitCameraController{
+		Camera:      camera,
+		Target:      target,
+		MinDistance: 1,
+		MaxDistance: 1000,
+		MinPitch:    -89,
+		MaxPitch:    89,
+		RotateSpeed: 0.5,
+		ZoomSpeed:   0.1,
+		rotation:    NewQuaternionEmpty(),
+	}
+	offset := NewVector3Empty().SetV(camera.Position).SubV(target)
+	self.Distance = offset.Len()
+	if self.Distance > 0 {
+		self.Yaw = float32(math.Atan2(float64(offset.X), float64(offset.Z))) * utils.RadiansToDegrees
+		self.Pitch = float32(math.Asin(float64(offset.Y/self.Distance))) * utils.RadiansToDegrees
+	}
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update()
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	return self
+}
+
+// Moves the camera to the orbit and updates it. This is called every frame when the controller is added to the scene.
+func (self *OrbitCameraController) Update() {
+	self.Pitch = utils.ClampFloat32(self.Pitch, self.MinPitch, self.MaxPitch)
+	self.Distance = utils.ClampFloat32(self.Distance, self.MinDistance, self.MaxDistance)
+	self.rotation.SetEulerAngles(self.Yaw, -self.Pitch, 0)
+	self.Camera.Direction.Set(0, 0, -1)
+	self.Camera.Up.Set(0, 1, 0)
+	self.rotation.Transform(self.Camera.Direction)
+	self.rotation.Transform(self.Camera.Up)
+	self.Camera.Position.SetV(self.Camera.Direction).SclScalar(-self.Distance).AddV(self.Target)
+	self.Camera.Update()
+}
+
+func (self *OrbitCameraController) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if e.Pointer == 1 {
+			// the second finger starts a pinch
+			self.drag.active = false
+			self.pinchDistance = self.Distance
+		}
+	case Zoom:
+		if e.DeltaY > 0 && self.pinchDistance > 0 {
+			self.Distance = utils.ClampFloat32(self.pinchDistance*e.DeltaX/e.DeltaY, self.MinDistance, self.MaxDistance)
+		}
+	case Scrolled:
+		self.Distance = utils.ClampFloat32(self.Distance*(1+self.ZoomSpeed*float32(e.ScrollAmount)), self.MinDistance,
+			self.MaxDistance)
+	}
+	// dragging right turns the target to the right and dragging down tilts its top towards the camera
+	if dx, dy, ok := self.drag.input(e); ok {
+		self.Yaw -= dx * self.RotateSpeed
+		self.Pitch = utils.ClampFloat32(self.Pitch+dy*self.RotateSpeed, self.MinPitch, self.MaxPitch)
+	}
+}
+
+// A FirstPersonCameraController walks a 3D camera with the keyboard and looks around by dragging the screen or the mouse.
+// The forward and side keys move the camera on the ground plane, the up and down keys along the up axis. The pitch is
+// clamped so that the camera does not look over the poles. It is an actor so that it gets the input and is updated with
+// the scene.
+type FirstPersonCameraController struct {
+	Actor
+
+	Camera *Camera
+
+	// The rotation of the camera around the up axis in degrees, at 0 the camera looks along the negative z axis
+	Yaw float32
+
+	// How far the camera looks up in degrees, it is kept between MinPitch and MaxPitch. Defaults are -89 and 89.
+	Pitch    float32
+	MinPitch float32
+	MaxPitch float32
+
+	// The speed of the camera in world units per second. Default is 5.
+	Velocity float32
+
+	// The degrees the camera is rotated per pixel dragged. Default is 0.5.
+	LookSpeed float32
+
+	// The keys which move the camera. Defaults are W, S, A, D, Q and E.
+	ForwardKey, BackwardKey, LeftKey, RightKey, UpKey, DownKey KeyCode
+
+	rotation *Quaternion
+	keys     heldKeys
+	drag     pointerDrag
+}
+
+// Creates a controller which walks the camera starting from its current position and direction
+func NewFirstPersonCameraController(camera *Camera) *FirstPersonCameraController {
+	self := &FirstPersonCameraController{
+		Camera:      camera,
+		MinPitch:    -89,
+		MaxPitch:    89,
+		Velocity:    5,
+		LookSpeed:   0.5,
+		ForwardKey:  KeyW,
+		BackwardKey: KeyS,
+		LeftKey:     KeyA,
+		RightKey:    KeyD,
+		UpKey:       KeyQ,
+		DownKey:     KeyE,
+		rotation:    NewQuaternionEmpty(),
+		keys:        heldKeys{},
+	}
+	self.Yaw, self.Pitch = yawPitch(camera.Direction)
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update(delta)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	return self
+}
+
+// Moves the camera for the keys held down during the elapsed time and updates it. This is called every frame when the
+// controller is added to the scene.
+func (self *FirstPersonCameraController) Update(delta float32) {
+	self.Pitch = utils.ClampFloat32(self.Pitch, self.MinPitch, self.MaxPitch)
+
+	// the camera walks in the direction of the yaw whatever the pitch
+	self.rotation.SetFromAxis(0, 1, 0, self.Yaw)
+	forward := self.keys.axis(self.ForwardKey, self.BackwardKey)
+	strafe := self.keys.axis(self.RightKey, self.LeftKey)
+	move := NewVector3(strafe, 0, -forward)
+	if move.Len2() > 1 {
+		move.Nor()
+	}
+	self.rotation.Transform(move)
+	move.Y = self.keys.axis(self.UpKey, self.DownKey)
+	self.Camera.Position.MulAdd(move, self.Velocity*delta)
+
+	self.rotation.SetEulerAngles(self.Yaw, self.Pitch, 0)
+	self.Camera.Direction.Set(0, 0, -1)
+	self.Camera.Up.Set(0, 1, 0)
+	self.rotation.Transform(self.Camera.Direction)
+	self.rotation.Transform(self.Camera.Up)
+	self.Camera.Update()
+}
+
+func (self *FirstPersonCameraController) input(e InputEvent) {
+	self.keys.input(e)
+	if dx, dy, ok := self.drag.input(e); ok {
+		self.Yaw -= dx * self.LookSpeed
+		self.Pitch = utils.ClampFloat32(self.Pitch-dy*self.LookSpeed, self.MinPitch, self.MaxPitch)
+	}
+}
+
+// A FlyCameraController flies a 3D camera freely like a plane or a space ship. The keys move the camera along its own
+// axes and roll it, dragging the screen or the mouse turns it around its own axes. The rotation is kept in a Quaternion
+// so the camera can turn in any direction, even upside down, without a gimbal lock. It is an actor so that it gets the
+// input and is updated with the scene.
+type FlyCameraController struct {
+	Actor
+
+	Camera *Camera
+
+	// The speed of the camera in world units per second. Default is 5.
+	Velocity float32
+
+	// The degrees the camera is rotated per pixel dragged. Default is 0.5.
+	LookSpeed float32
+
+	// The degrees the camera is rolled per second. Default is 90.
+	RollSpeed float32
+
+	// The keys which move and roll the camera. Defaults are W, S, A, D, Q, E, Z and C.
+	ForwardKey, BackwardKey, LeftKey, RightKey, UpKey, DownKey, RollLeftKey, RollRightKey KeyCode
+
+	rotation *Quaternion
+	turn     *Quaternion
+	keys     heldKeys
+	drag     pointerDrag
+}
+
+// Creates a controller which flies the camera starting from its current position and direction
+func NewFlyCameraController(camera *Camera) *FlyCameraController {
+	self := &FlyCameraController{
+		Camera:       camera,
+		Velocity:     5,
+		LookSpeed:    0.5,
+		RollSpeed:    90,
+		ForwardKey:   KeyW,
+		BackwardKey:  KeyS,
+		LeftKey:      KeyA,
+		RightKey:     KeyD,
+		UpKey:        KeyQ,
+		DownKey:      KeyE,
+		RollLeftKey:  KeyZ,
+		RollRightKey: KeyC,
+		rotation:     NewQuaternionEmpty(),
+		turn:         NewQuaternionEmpty(),
+		keys:         heldKeys{},
+	}
+	yaw, pitch := yawPitch(camera.Direction)
+	self.rotation.SetEulerAngles(yaw, pitch, 0)
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update(delta)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	return self
+}
+
+// Moves and rolls the camera for the keys held down during the elapsed time and updates it. This is called every frame
+// when the controller is added to the scene.
+func (self *FlyCameraController) Update(delta float32) {
+	if roll := self.keys.axis(self.RollLeftKey, self.RollRightKey); roll != 0 {
+		self.rotate(0, 0, 1, roll*self.RollSpeed*delta)
+	}
+	move := NewVector3(self.keys.axis(self.RightKey, self.LeftKey), self.keys.axis(self.UpKey, self.DownKey),
+		-self.keys.axis(self.ForwardKey, self.BackwardKey))
+	if move.Len2() > 1 {
+		move.Nor()
+	}
+	self.rotation.Transform(move)
+	self.Camera.Position.MulAdd(move, self.Velocity*delta)
+
+	self.Camera.Direction.Set(0, 0, -1)
+	self.Camera.Up.Set(0, 1, 0)
+	self.rotation.Transform(self.Camera.Direction)
+	self.rotation.Transform(self.Camera.Up)
+	self.Camera.Update()
+}
+
+// Rotates the camera around its own axis
+func (self *FlyCameraController) rotate(axisX, axisY, axisZ, degrees float32) {
+	self.rotation.MulQ(self.turn.SetFromAxis(axisX, axisY, axisZ, degrees)).Nor()
+}
+
+func (self *FlyCameraController) input(e InputEvent) {
+	self.keys.input(e)
+	if dx, dy, ok := self.drag.input(e); ok {
+		self.rotate(0, 1, 0, -dx*self.LookSpeed)
+		self.rotate(1, 0, 0, -dy*self.LookSpeed)
+	}
+}
+
+// Returns the yaw and pitch in degrees of the direction, at a yaw of 0 the direction is the negative z axis
+func yawPitch(direction *Vector3) (yaw, pitch float32) {
+	yaw = float32(math.Atan2(float64(-direction.X), float64(-direction.Z))) * utils.RadiansToDegrees
+	pitch = float32(math.Asin(float64(utils.ClampFloat32(direction.Y, -1, 1)))) * utils.RadiansToDegrees
+	return
+}
+
+// The keys held down, tracked with the key events
+type heldKeys map[KeyCode]bool
+
+func (self heldKeys) input(e InputEvent) {
+	switch e.Type {
+	case KeyDown:
+		self[e.KeyCode] = true
+	case KeyUp:
+		delete(self, e.KeyCode)
+	}
+}
+
+// Returns 1 if only the positive key is held, -1 if only the negative key is held and 0 otherwise
+func (self heldKeys) axis(positive, negative KeyCode) float32 {
+	var value float32
+	if self[positive] {
+		value++
+	}
+	if self[negative] {
+		value--
+	}
+	return value
+}
+
+// The drag of the first pointer on the screen
+type pointerDrag struct {
+	active bool
+	x, y   float32
+}
+
+// Returns how many pixels the first pointer was dragged since the last event, ok is false if it was not dragged
+func (self *pointerDrag) input(e InputEvent) (dx, dy float32, ok bool) {
+	if e.Pointer != 0 {
+		return 0, 0, false
+	}
+	switch e.Type {
+	case TouchDown:
+		self.active = true
+		self.x, self.y = e.ScreenX, e.ScreenY
+	case TouchDragged:
+		if self.active {
+			dx, dy = e.ScreenX-self.x, e.ScreenY-self.y
+			self.x, self.y = e.ScreenX, e.ScreenY
+			return dx, dy, true
+		}
+	case TouchUp:
+		self.active = false
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,132 @@
+package spike
+
+import (
+	"testing"
+
+	. "github.com/pyros2097/spike/math/vector"
+)
+
+// Returns the column major view matrix of a camera at the position looking along the direction
+func viewMatrix(position, direction, up [3]float32) [16]float32 {
+	d, u := direction, up
+	r := [3]float32{d[1]*u[2] - d[2]*u[1], d[2]*u[0] - d[0]*u[2], d[0]*u[1] - d[1]*u[0]}
+	dot := func(a, b [3]float32) float32 {
+		return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+	}
+	return [16]float32{
+		r[0], u[0], -d[0], 0,
+		r[1], u[1], -d[1], 0,
+		r[2], u[2], -d[2], 0,
+		-dot(r, position), -dot(u, position), dot(d, position), 1,
+	}
+}
+
+func checkView(t *testing.T, name string, camera *Camera, want [16]float32) {
+	got := camera.View.GetValues()
+	for i := range got {
+		if !near(got[i], want[i], 0.001) {
+			t.Errorf("%s: view matrix %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func drag(input func(*Actor, InputEvent), dx, dy float32) {
+	input(nil, InputEvent{Type: TouchDown, ScreenX: 400, ScreenY: 240})
+	input(nil, InputEvent{Type: TouchDragged, ScreenX: 400 + dx/2, ScreenY: 240 + dy/2})
+	input(nil, InputEvent{Type: TouchDragged, ScreenX: 400 + dx, ScreenY: 240 + dy})
+	input(nil, InputEvent{Type: TouchUp, ScreenX: 400 + dx, ScreenY: 240 + dy})
+}
+
+func newTestCamera3d(x, y, z float32) *Camera {
+	camera := NewPerspectiveCamera(67, 800, 480)
+	camera.Position.Set(x, y, z)
+	camera.LookAt(0, 0, 0)
+	camera.Update()
+	return camera
+}
+
+func TestOrbitCameraController(t *testing.T) {
+	orbit := NewOrbitCameraController(newTestCamera3d(0, 0, 10), NewVector3Empty())
+	orbit.Update()
+	checkView(t, "start", orbit.Camera, viewMatrix([3]float32{0, 0, 10}, [3]float32{0, 0, -1}, [3]float32{0, 1, 0}))
+
+	// dragging right by 180 pixels shows the left side of the target
+	drag(orbit.Input, 180, 0)
+	orbit.Update()
+	checkView(t, "drag right", orbit.Camera, viewMatrix([3]float32{-10, 0, 0}, [3]float32{1, 0, 0}, [3]float32{0, 1, 0}))
+
+	// dragging down raises the camera up to the max pitch
+	drag(orbit.Input, 0, 1000)
+	orbit.Update()
+	if orbit.Pitch != 89 || !near(orbit.Camera.Position.Y, 9.998, 0.001) || orbit.Camera.Up.Y <= 0 {
+		t.Errorf("pitch %v camera at height %v up %v", orbit.Pitch, orbit.Camera.Position.Y, orbit.Camera.Up.Y)
+	}
+	drag(orbit.Input, 0, -58)
+	orbit.Update()
+	checkView(t, "pitch", orbit.Camera, viewMatrix([3]float32{-5, 8.660254, 0}, [3]float32{0.5, -0.8660254, 0},
+		[3]float32{0.8660254, 0.5, 0}))
+
+	orbit.Input(nil, InputEvent{Type: Scrolled, ScrollAmount: 10})
+	orbit.Update()
+	checkView(t, "zoom out", orbit.Camera, viewMatrix([3]float32{-10, 17.320508, 0}, [3]float32{0.5, -0.8660254, 0},
+		[3]float32{0.8660254, 0.5, 0}))
+	orbit.Input(nil, InputEvent{Type: TouchDown, Pointer: 1})
+	orbit.Input(nil, InputEvent{Type: Zoom, DeltaX: 100, DeltaY: 400})
+	orbit.Update()
+	if !near(orbit.Distance, 5, 0.001) {
+		t.Errorf("distance %v after pinching out", orbit.Distance)
+	}
+}
+
+func TestFirstPersonCameraController(t *testing.T) {
+	fps := NewFirstPersonCameraController(newTestCamera3d(0, 0, 10))
+	fps.Velocity = 4
+	fps.Input(nil, InputEvent{Type: KeyDown, KeyCode: KeyW})
+	fps.Update(0.5)
+	checkView(t, "walk", fps.Camera, viewMatrix([3]float32{0, 0, 8}, [3]float32{0, 0, -1}, [3]float32{0, 1, 0}))
+
+	// look right and up, the camera still walks on the ground
+	drag(fps.Input, 180, -60)
+	fps.Update(1)
+	checkView(t, "look", fps.Camera, viewMatrix([3]float32{4, 0, 8}, [3]float32{0.8660254, 0.5, 0},
+		[3]float32{-0.5, 0.8660254, 0}))
+	fps.Input(nil, InputEvent{Type: KeyUp, KeyCode: KeyW})
+	fps.Input(nil, InputEvent{Type: KeyDown, KeyCode: KeyA})
+	fps.Input(nil, InputEvent{Type: KeyDown, KeyCode: KeyQ})
+	fps.Update(1)
+	checkView(t, "strafe", fps.Camera, viewMatrix([3]float32{4, 4, 4}, [3]float32{0.8660254, 0.5, 0},
+		[3]float32{-0.5, 0.8660254, 0}))
+
+	// the pitch is clamped
+	drag(fps.Input, 0, -1000)
+	if fps.Pitch != fps.MaxPitch {
+		t.Errorf("pitch %v not clamped", fps.Pitch)
+	}
+}
+
+func TestFlyCameraController(t *testing.T) {
+	fly := NewFlyCameraController(newTestCamera3d(0, 0, 10))
+	fly.Velocity = 2
+	fly.Update(0)
+	checkView(t, "start", fly.Camera, viewMatrix([3]float32{0, 0, 10}, [3]float32{0, 0, -1}, [3]float32{0, 1, 0}))
+
+	// pull up over the top, the camera ends up flying upside down the other way
+	drag(fly.Input, 0, -360)
+	fly.Input(nil, InputEvent{Type: KeyDown, KeyCode: KeyW})
+	fly.Update(1)
+	checkView(t, "loop", fly.Camera, viewMatrix([3]float32{0, 0, 12}, [3]float32{0, 0, 1}, [3]float32{0, -1, 0}))
+
+	// rolling half a turn flies the right way up again
+	fly.Input(nil, InputEvent{Type: KeyUp, KeyCode: KeyW})
+	fly.Input(nil, InputEvent{Type: KeyDown, KeyCode: KeyC})
+	fly.Update(1)
+	fly.Update(1)
+	checkView(t, "roll", fly.Camera, viewMatrix([3]float32{0, 0, 12}, [3]float32{0, 0, 1}, [3]float32{0, 1, 0}))
+
+	// turning right after the roll turns around the new up axis
+	fly.Input(nil, InputEvent{Type: KeyUp, KeyCode: KeyC})
+	drag(fly.Input, 180, 0)
+	fly.Update(0)
+	checkView(t, "turn", fly.Camera, viewMatrix([3]float32{0, 0, 12}, [3]float32{-1, 0, 0}, [3]float32{0, 1, 0}))
+}
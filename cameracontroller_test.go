@@ -0,0 +1,167 @@
+package spike
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pyros2097/spike/math/utils"
+)
+
+const cameraStep = float32(1) / 60
+
+func near(a, b, epsilon float32) bool {
+	return math.Abs(float64(a-b)) <= float64(epsilon)
+}
+
+func newTestCameraController() (*CameraController, *Actor) {
+	camera := NewOrthographicCamera(800, 480)
+	camera.Position.Set(400, 240, 0)
+	camera.Update()
+	player := &Actor{X: 390, Y: 230, W: 20, H: 20}
+	cam := NewCameraController(camera)
+	cam.Target = player
+	return cam, player
+}
+
+func TestCameraControllerDeadZone(t *testing.T) {
+	cam, player := newTestCameraController()
+	cam.Smoothing = CameraSnap
+	cam.DeadZoneWidth, cam.DeadZoneHeight = 100, 60
+	cam.Snap()
+	player.X += 40
+	player.Y -= 20
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X != 400 || cam.Camera.Position.Y != 240 {
+		t.Errorf("camera moved within the dead zone to %v, %v", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+	player.X += 30
+	player.Y -= 30
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X != 420 || cam.Camera.Position.Y != 220 {
+		t.Errorf("camera at %v, %v after leaving the dead zone", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+}
+
+func TestCameraControllerLerp(t *testing.T) {
+	cam, player := newTestCameraController()
+	cam.Snap()
+	player.X += 100
+	last := float32(100)
+	for i := 0; i < 120; i++ {
+		cam.Update(cameraStep)
+		distance := 500 - cam.Camera.Position.X
+		if distance < 0 || distance > last {
+			t.Fatalf("frame %d distance %v after %v", i, distance, last)
+		}
+		last = distance
+	}
+	if !near(cam.Camera.Position.X, 500, 0.01) {
+		t.Errorf("camera at %v after 2 seconds", cam.Camera.Position.X)
+	}
+
+	// the distance shrinks the same with a different frame rate
+	other, player := newTestCameraController()
+	other.Snap()
+	player.X += 100
+	for i := 0; i < 60; i++ {
+		other.Update(cameraStep * 2)
+	}
+	if !near(other.Camera.Position.X, cam.Camera.Position.X, 0.001) {
+		t.Errorf("camera at %v at 30 fps and %v at 60 fps", other.Camera.Position.X, cam.Camera.Position.X)
+	}
+}
+
+func TestCameraControllerBounds(t *testing.T) {
+	cam, player := newTestCameraController()
+	cam.Smoothing = CameraSnap
+	cam.SetBounds(0, 0, 1000, 600)
+	player.X, player.Y = -200, 900
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X != 400 || cam.Camera.Position.Y != 360 {
+		t.Errorf("camera at %v, %v outside of the bounds", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+
+	// bounds smaller than the view are centered
+	cam.SetBounds(0, 0, 600, 300)
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X != 300 || cam.Camera.Position.Y != 150 {
+		t.Errorf("camera at %v, %v not centered on the bounds", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+}
+
+func TestCameraControllerTargets(t *testing.T) {
+	cam, _ := newTestCameraController()
+	cam.Smoothing = CameraSnap
+	cam.Padding = 50
+	cam.Targets = []*Actor{{X: 0, Y: 0}, {X: 1100, Y: 200}}
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X != 550 || cam.Camera.Position.Y != 100 {
+		t.Errorf("camera at %v, %v not at the center of the targets", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+	if !near(cam.Camera.Zoom, 1.5, 0.0001) {
+		t.Errorf("zoom %v does not fit the targets", cam.Camera.Zoom)
+	}
+	cam.Targets[1].X = 5000
+	cam.Update(cameraStep)
+	if cam.Camera.Zoom != cam.MaxZoom {
+		t.Errorf("zoom %v not clamped", cam.Camera.Zoom)
+	}
+}
+
+func TestCameraControllerShake(t *testing.T) {
+	shake := func() []float32 {
+		utils.SetSeed(7)
+		cam, _ := newTestCameraController()
+		cam.Smoothing = CameraSnap
+		cam.AddTrauma(0.8)
+		offsets := []float32{}
+		for i := 0; i < 60; i++ {
+			cam.Update(cameraStep)
+			offsets = append(offsets, cam.Camera.Position.X-400, cam.Camera.Position.Y-240, cam.Camera.Up.X)
+		}
+		if !near(cam.Trauma, 0, 0.0001) {
+			t.Errorf("trauma %v after a second", cam.Trauma)
+		}
+		if cam.Camera.Position.X != 400 || cam.Camera.Position.Y != 240 || cam.Camera.Up.X != 0 {
+			t.Errorf("camera at %v, %v up %v after the shake", cam.Camera.Position.X, cam.Camera.Position.Y, cam.Camera.Up.X)
+		}
+		return offsets
+	}
+	a, b := shake(), shake()
+	moved := false
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("shake %d is %v and %v with the same seed", i, a[i], b[i])
+		}
+		if a[i] != 0 {
+			moved = true
+		}
+	}
+	if !moved {
+		t.Error("camera did not shake")
+	}
+}
+
+func TestCameraControllerDrag(t *testing.T) {
+	cam, _ := newTestCameraController()
+	cam.Target = nil
+	cam.DragEnabled = true
+	cam.Update(cameraStep)
+	cam.Input(&cam.Actor, InputEvent{Type: TouchDown, ScreenX: 100, ScreenY: 100})
+	cam.Input(&cam.Actor, InputEvent{Type: TouchDragged, ScreenX: 80, ScreenY: 110})
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X != 420 || cam.Camera.Position.Y != 250 {
+		t.Errorf("camera at %v, %v after the drag", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+	cam.Input(&cam.Actor, InputEvent{Type: TouchUp, ScreenX: 80, ScreenY: 110})
+	cam.Update(cameraStep)
+	if cam.Camera.Position.X <= 420 || cam.Camera.Position.Y <= 250 {
+		t.Errorf("camera at %v, %v did not pan on", cam.Camera.Position.X, cam.Camera.Position.Y)
+	}
+
+	cam.Input(&cam.Actor, InputEvent{Type: TouchDown, Pointer: 1})
+	cam.Input(&cam.Actor, InputEvent{Type: Zoom, DeltaX: 100, DeltaY: 200})
+	if cam.Update(cameraStep); cam.Camera.Zoom != 0.5 {
+		t.Errorf("zoom %v after pinching out", cam.Camera.Zoom)
+	}
+}
@@ -81,6 +81,7 @@ const (
 	PANSPEED         = "panspeed"
 	DRAGSPEED        = "dragspeed"
 	KEYBOARD         = "keyboard"
+	BINDINGS         = "bindings"
 	SCORE            = "score"
 )
 
@@ -92,8 +93,8 @@ var (
 	HasVibration bool
 	VolMusic     float32
 	VolSound     float32
-	SpeedPan     float32
-	SpeedDrag    float32
+	SpeedPan     float32 = 1
+	SpeedDrag    float32 = 1
 	Score        int
 )
 
@@ -115,6 +116,13 @@ func initConfig(configName string) {
 	// Score = prefs.GetInteger(SCORE, 0)
 }
 
+// Sets the Preferences used to store the settings of the game
+func SetPreferences(preferences Preferences) {
+	prefs = preferences
+	// the rebound bindings are read again from the new preferences
+	saved = nil
+}
+
 func LoadSaveData() string {
 	return prefs.GetString(SAVEDATA, "")
 }
@@ -0,0 +1,322 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+// Controllers (gamepads and joysticks) are read from a ControllerBackend every frame. The raw buttons, axes and hats of a
+// controller are translated to the standard buttons and axes with its ControllerMapping, see LoadControllerMappings.
+// The buttons are sent to the actors as KeyDown and KeyUp events with key codes like KeyButtonA or KeyDpadUp, the axes
+// as AxisMoved events and the hats as PovMoved events, so they can be bound to actions and axes like any other input.
+// The Controller field of these events tells which controller sent them.
+//
+//   spike.BindAction("jump", spike.NewKeyBinding(spike.KeySpace), spike.NewKeyBinding(spike.KeyButtonA))
+//   spike.BindAxis("move_x", 0.2, spike.NewControllerAxisBinding(spike.AxisLeftX, 1))
+//
+// On linux the joysticks in /dev/input are used by default. Tests can use a FakeControllerBackend instead.
+
+// The analog axes of a controller. The buttons of a controller are reported as key codes like KeyButtonA.
+type ControllerAxis uint8
+
+const (
+	AxisLeftX ControllerAxis = iota
+	AxisLeftY
+	AxisRightX
+	AxisRightY
+	AxisTriggerLeft
+	AxisTriggerRight
+)
+
+func (axis ControllerAxis) String() string {
+	switch axis {
+	case AxisLeftX:
+		return "AxisLeftX"
+	case AxisLeftY:
+		return "AxisLeftY"
+	case AxisRightX:
+		return "AxisRightX"
+	case AxisRightY:
+		return "AxisRightY"
+	case AxisTriggerLeft:
+		return "AxisTriggerLeft"
+	case AxisTriggerRight:
+		return "AxisTriggerRight"
+	default:
+		return "Axis"
+	}
+}
+
+// The direction of a pov (hat switch or d-pad). The diagonals are combinations of the main directions.
+type PovDirection uint8
+
+const (
+	PovCenter    PovDirection = 0
+	PovNorth     PovDirection = 1
+	PovEast      PovDirection = 2
+	PovSouth     PovDirection = 4
+	PovWest      PovDirection = 8
+	PovNorthEast PovDirection = PovNorth | PovEast
+	PovSouthEast PovDirection = PovSouth | PovEast
+	PovSouthWest PovDirection = PovSouth | PovWest
+	PovNorthWest PovDirection = PovNorth | PovWest
+)
+
+func (pov PovDirection) String() string {
+	switch pov {
+	case PovCenter:
+		return "PovCenter"
+	case PovNorth:
+		return "PovNorth"
+	case PovEast:
+		return "PovEast"
+	case PovSouth:
+		return "PovSouth"
+	case PovWest:
+		return "PovWest"
+	case PovNorthEast:
+		return "PovNorthEast"
+	case PovSouthEast:
+		return "PovSouthEast"
+	case PovSouthWest:
+		return "PovSouthWest"
+	case PovNorthWest:
+		return "PovNorthWest"
+	default:
+		return "Pov"
+	}
+}
+
+// The type of a RawControllerEvent
+type RawControllerEventType uint8
+
+const (
+	// A device was connected. Valid fields: Device, Name and GUID.
+	RawDeviceAdded RawControllerEventType = iota
+
+	// A device was disconnected. Valid fields: Device.
+	RawDeviceRemoved
+
+	// A button was pressed or released. Valid fields: Device, Index and Value which is 1 when pressed and 0 when released.
+	RawButton
+
+	// An axis moved. Valid fields: Device, Index and Value in the range [-1, 1].
+	RawAxis
+
+	// A hat moved. Valid fields: Device, Index and Pov.
+	RawHat
+)
+
+// An event as it is reported by a controller device, before it is mapped
+type RawControllerEvent struct {
+	Type RawControllerEventType
+
+	// The id of the device given by the backend
+	Device int
+
+	// The name of the device and its SDL GUID, which is used to look up its mapping
+	Name, GUID string
+
+	// The index of the raw button, axis or hat
+	Index int
+
+	Value float32
+	Pov   PovDirection
+}
+
+// A ControllerBackend reports the events of the controller devices
+type ControllerBackend interface {
+	// Returns the events that happened since the last call. This is called once every frame.
+	Poll() []RawControllerEvent
+
+	// Closes all the devices
+	Close() error
+}
+
+// A Controller is a connected gamepad or joystick
+type Controller struct {
+	// The position of the controller in GetControllers. Indices are reused once a controller is disconnected.
+	Index int
+
+	Name string
+	GUID string
+
+	Connected bool
+
+	mapping *ControllerMapping
+	buttons map[KeyCode]bool
+	axes    map[ControllerAxis]float32
+	povs    map[uint8]PovDirection
+}
+
+var (
+	controllerBackend ControllerBackend
+	controllers       []*Controller
+
+	// The controller index of every connected device of the backend
+	controllerDevices = make(map[int]int)
+
+	// Creates the default backend of the platform, it is nil if the platform has none
+	newDefaultControllerBackend func() ControllerBackend
+)
+
+// SetControllerBackend replaces the backend the controllers are read from, closing the current one.
+func SetControllerBackend(backend ControllerBackend) {
+	if controllerBackend != nil {
+		controllerBackend.Close()
+	}
+	for device, index := range controllerDevices {
+		delete(controllerDevices, device)
+		disconnectController(controllers[index])
+	}
+	controllerBackend = backend
+}
+
+// Returns the controllers that are connected
+func GetControllers() []*Controller {
+	connected := make([]*Controller, 0, len(controllers))
+	for _, controller := range controllers {
+		if controller.Connected {
+			connected = append(connected, controller)
+		}
+	}
+	return connected
+}
+
+// Returns the controller at index or nil if there is no controller connected there
+func GetController(index int) *Controller {
+	if index >= 0 && index < len(controllers) && controllers[index].Connected {
+		return controllers[index]
+	}
+	return nil
+}
+
+// Returns the controller that sent the event or nil if it was not sent by a controller
+func (self *InputEvent) GetController() *Controller {
+	if self.Controller == 0 {
+		return nil
+	}
+	return GetController(int(self.Controller) - 1)
+}
+
+// Returns whether the button with the given key code, like KeyButtonA or KeyDpadUp, is pressed
+func (self *Controller) IsButtonPressed(keycode KeyCode) bool {
+	return self.buttons[keycode]
+}
+
+// Returns the value of the axis in the range [-1, 1], triggers are in the range [0, 1]
+func (self *Controller) GetAxis(axis ControllerAxis) float32 {
+	return self.axes[axis]
+}
+
+// Returns the direction of the pov at index
+func (self *Controller) GetPov(index int) PovDirection {
+	return self.povs[uint8(index)]
+}
+
+// Returns true if a mapping for the controller was found in the database. Controllers without one use a default
+// mapping which fits most xinput compatible gamepads.
+func (self *Controller) IsMapped() bool {
+	return self.mapping != nil && self.mapping != defaultControllerMapping
+}
+
+func (self *Controller) reset() {
+	self.buttons = make(map[KeyCode]bool)
+	self.axes = make(map[ControllerAxis]float32)
+	self.povs = make(map[uint8]PovDirection)
+}
+
+// Returns the controller at index, creating it if needed
+func controllerAt(index int) *Controller {
+	for len(controllers) <= index {
+		controller := &Controller{Index: len(controllers), mapping: defaultControllerMapping}
+		controller.reset()
+		controllers = append(controllers, controller)
+	}
+	return controllers[index]
+}
+
+// Reads the controller events from the backend and feeds them to the input. This is called once every frame.
+func pollControllers() {
+	if controllerBackend == nil {
+		if newDefaultControllerBackend == nil {
+			return
+		}
+		controllerBackend = newDefaultControllerBackend()
+	}
+	for _, raw := range controllerBackend.Poll() {
+		// the events of a replay come from the recording
+		if currentReplay != nil {
+			continue
+		}
+		if raw.Type == RawDeviceAdded {
+			connectController(raw)
+			continue
+		}
+		index, ok := controllerDevices[raw.Device]
+		if !ok {
+			continue
+		}
+		controller := controllers[index]
+		if raw.Type == RawDeviceRemoved {
+			delete(controllerDevices, raw.Device)
+			disconnectController(controller)
+			continue
+		}
+		for _, e := range controller.mapping.translate(controller, raw) {
+			feedInput(e)
+		}
+	}
+}
+
+func connectController(raw RawControllerEvent) {
+	if _, ok := controllerDevices[raw.Device]; ok {
+		return
+	}
+	index := 0
+	for index < len(controllers) && controllers[index].Connected {
+		index++
+	}
+	controller := controllerAt(index)
+	controller.Name = raw.Name
+	controller.GUID = raw.GUID
+	controller.mapping = findControllerMapping(raw.GUID)
+	controllerDevices[raw.Device] = index
+	feedInput(InputEvent{Type: ControllerConnected, Controller: uint8(index + 1)})
+}
+
+func disconnectController(controller *Controller) {
+	id := uint8(controller.Index + 1)
+	for keycode, pressed := range controller.buttons {
+		if pressed {
+			feedInput(InputEvent{Type: KeyUp, KeyCode: keycode, Controller: id})
+		}
+	}
+	for axis, value := range controller.axes {
+		if value != 0 {
+			feedInput(InputEvent{Type: AxisMoved, Axis: axis, Controller: id})
+		}
+	}
+	feedInput(InputEvent{Type: ControllerDisconnected, Controller: id})
+}
+
+// Updates the state of the controller that sent the event and sends it to the actors
+func doControllerEvent(e InputEvent) {
+	controller := controllerAt(int(e.Controller) - 1)
+	switch e.Type {
+	case ControllerConnected:
+		controller.reset()
+		controller.Connected = true
+	case ControllerDisconnected:
+		controller.reset()
+		controller.Connected = false
+	case KeyDown:
+		controller.buttons[e.KeyCode] = true
+	case KeyUp:
+		delete(controller.buttons, e.KeyCode)
+	case AxisMoved:
+		controller.axes[e.Axis] = e.Value
+	case PovMoved:
+		controller.povs[e.Button] = e.Pov
+	}
+	InputChannel <- e
+}
@@ -0,0 +1,66 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+// A FakeControllerBackend is an in-memory controller backend for tests. The devices are connected and moved by calling
+// its methods, the events are reported by the next Poll.
+//
+//	fake := spike.NewFakeControllerBackend()
+//	spike.SetControllerBackend(fake)
+//	pad := fake.Connect("Xbox 360 Controller", "030000005e0400008e02000014010000")
+//	fake.PressButton(pad, 0)
+type FakeControllerBackend struct {
+	events     []RawControllerEvent
+	nextDevice int
+}
+
+// Creates a backend without any devices
+func NewFakeControllerBackend() *FakeControllerBackend {
+	return &FakeControllerBackend{}
+}
+
+// Connects a device and returns its id
+func (self *FakeControllerBackend) Connect(name, guid string) int {
+	device := self.nextDevice
+	self.nextDevice++
+	self.events = append(self.events, RawControllerEvent{Type: RawDeviceAdded, Device: device, Name: name, GUID: guid})
+	return device
+}
+
+// Disconnects a device
+func (self *FakeControllerBackend) Disconnect(device int) {
+	self.events = append(self.events, RawControllerEvent{Type: RawDeviceRemoved, Device: device})
+}
+
+// Presses the raw button at index
+func (self *FakeControllerBackend) PressButton(device, index int) {
+	self.events = append(self.events, RawControllerEvent{Type: RawButton, Device: device, Index: index, Value: 1})
+}
+
+// Releases the raw button at index
+func (self *FakeControllerBackend) ReleaseButton(device, index int) {
+	self.events = append(self.events, RawControllerEvent{Type: RawButton, Device: device, Index: index})
+}
+
+// Moves the raw axis at index to value in the range [-1, 1]
+func (self *FakeControllerBackend) MoveAxis(device, index int, value float32) {
+	self.events = append(self.events, RawControllerEvent{Type: RawAxis, Device: device, Index: index, Value: value})
+}
+
+// Moves the raw hat at index
+func (self *FakeControllerBackend) MoveHat(device, index int, pov PovDirection) {
+	self.events = append(self.events, RawControllerEvent{Type: RawHat, Device: device, Index: index, Pov: pov})
+}
+
+func (self *FakeControllerBackend) Poll() []RawControllerEvent {
+	events := self.events
+	self.events = nil
+	return events
+}
+
+func (self *FakeControllerBackend) Close() error {
+	self.events = nil
+	return nil
+}
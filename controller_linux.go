@@ -0,0 +1,241 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package spike
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Reads the joystick devices of the linux input subsystem (/dev/input/js*). The devices are scanned for once a second.
+// Hats are reported by the kernel as pairs of axes, they are turned back into hats and the remaining axes are numbered
+// like SDL does so that the mappings of the database fit.
+
+const (
+	jsEventButton = 0x01
+	jsEventAxis   = 0x02
+	jsEventInit   = 0x80
+
+	absHat0X = 0x10
+	absHat3Y = 0x17
+
+	// _IOR('j', 0x11, __u8) and _IOR('j', 0x32, __u8[ABS_CNT])
+	jsiocgaxes  = 2<<30 | 1<<16 | 'j'<<8 | 0x11
+	jsiocgaxmap = 2<<30 | 0x40<<16 | 'j'<<8 | 0x32
+)
+
+type joystickBackend struct {
+	mutex      sync.Mutex
+	events     []RawControllerEvent
+	devices    map[string]*joystickDevice
+	nextDevice int
+	lastScan   time.Time
+	closed     bool
+}
+
+type joystickDevice struct {
+	id   int
+	file *os.File
+
+	// The raw axis index of every joystick axis, or the hat and its direction for hat axes
+	axes []joystickAxis
+	hats [4][2]int16
+}
+
+type joystickAxis struct {
+	index int
+	hat   int // -1 if the axis is not part of a hat
+	y     bool
+}
+
+func init() {
+	newDefaultControllerBackend = func() ControllerBackend {
+		return &joystickBackend{devices: make(map[string]*joystickDevice)}
+	}
+}
+
+func (self *joystickBackend) Poll() []RawControllerEvent {
+	if time.Since(self.lastScan) > time.Second {
+		self.scan()
+	}
+	self.mutex.Lock()
+	events := self.events
+	self.events = nil
+	self.mutex.Unlock()
+	return events
+}
+
+func (self *joystickBackend) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.closed = true
+	for path, device := range self.devices {
+		device.file.Close()
+		delete(self.devices, path)
+	}
+	self.events = nil
+	return nil
+}
+
+func (self *joystickBackend) push(e RawControllerEvent) {
+	self.mutex.Lock()
+	if !self.closed {
+		self.events = append(self.events, e)
+	}
+	self.mutex.Unlock()
+}
+
+// Opens the joystick devices which are not open yet
+func (self *joystickBackend) scan() {
+	self.lastScan = time.Now()
+	paths, _ := filepath.Glob("/dev/input/js*")
+	for _, path := range paths {
+		self.mutex.Lock()
+		_, open := self.devices[path]
+		self.mutex.Unlock()
+		if !open {
+			self.open(path)
+		}
+	}
+}
+
+func (self *joystickBackend) open(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	sysfs := "/sys/class/input/" + filepath.Base(path) + "/device/"
+	device := &joystickDevice{file: file, axes: readJoystickAxes(file)}
+	self.mutex.Lock()
+	device.id = self.nextDevice
+	self.nextDevice++
+	self.devices[path] = device
+	self.mutex.Unlock()
+	self.push(RawControllerEvent{
+		Type:   RawDeviceAdded,
+		Device: device.id,
+		Name:   readSysfs(sysfs + "name"),
+		GUID:   joystickGUID(sysfs + "id/"),
+	})
+	go self.read(path, device)
+}
+
+func (self *joystickBackend) read(path string, device *joystickDevice) {
+	buf := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(device.file, buf); err != nil {
+			device.file.Close()
+			self.mutex.Lock()
+			if self.devices[path] == device {
+				delete(self.devices, path)
+			}
+			self.mutex.Unlock()
+			self.push(RawControllerEvent{Type: RawDeviceRemoved, Device: device.id})
+			return
+		}
+		value := int16(binary.LittleEndian.Uint16(buf[4:6]))
+		number := int(buf[7])
+		switch buf[6] &^ jsEventInit {
+		case jsEventButton:
+			e := RawControllerEvent{Type: RawButton, Device: device.id, Index: number}
+			if value != 0 {
+				e.Value = 1
+			}
+			self.push(e)
+		case jsEventAxis:
+			self.push(device.axisEvent(number, value))
+		}
+	}
+}
+
+// Returns the event of a joystick axis, which is either an axis or a hat event
+func (self *joystickDevice) axisEvent(number int, value int16) RawControllerEvent {
+	axis := joystickAxis{index: number, hat: -1}
+	if number < len(self.axes) {
+		axis = self.axes[number]
+	}
+	if axis.hat < 0 {
+		v := float32(value) / 32767
+		if v < -1 {
+			v = -1
+		}
+		return RawControllerEvent{Type: RawAxis, Device: self.id, Index: axis.index, Value: v}
+	}
+	if axis.y {
+		self.hats[axis.hat][1] = value
+	} else {
+		self.hats[axis.hat][0] = value
+	}
+	pov := PovCenter
+	if x := self.hats[axis.hat][0]; x < 0 {
+		pov |= PovWest
+	} else if x > 0 {
+		pov |= PovEast
+	}
+	if y := self.hats[axis.hat][1]; y < 0 {
+		pov |= PovNorth
+	} else if y > 0 {
+		pov |= PovSouth
+	}
+	return RawControllerEvent{Type: RawHat, Device: self.id, Index: axis.hat, Pov: pov}
+}
+
+// Reads which joystick axes are hats from the axis map of the device
+func readJoystickAxes(file *os.File) []joystickAxis {
+	var count uint8
+	axmap := make([]byte, 0x40)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), jsiocgaxes, uintptr(unsafe.Pointer(&count))); errno != 0 {
+		return nil
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), jsiocgaxmap, uintptr(unsafe.Pointer(&axmap[0]))); errno != 0 {
+		return nil
+	}
+	axes := make([]joystickAxis, count)
+	index := 0
+	for i := range axes {
+		code := int(axmap[i])
+		if code >= absHat0X && code <= absHat3Y {
+			axes[i] = joystickAxis{hat: (code - absHat0X) / 2, y: (code-absHat0X)%2 == 1}
+		} else {
+			axes[i] = joystickAxis{index: index, hat: -1}
+			index++
+		}
+	}
+	return axes
+}
+
+func readSysfs(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Builds the SDL GUID of a device from its bus, vendor, product and version, which are 16 bit little endian numbers
+// separated by zeros.
+func joystickGUID(id string) string {
+	guid := ""
+	for _, name := range []string{"bustype", "vendor", "product", "version"} {
+		value, err := strconv.ParseUint(readSysfs(id+name), 16, 16)
+		if err != nil {
+			return ""
+		}
+		guid += fmt.Sprintf("%02x%02x0000", value&0xff, value>>8)
+	}
+	return guid
+}
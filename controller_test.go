@@ -0,0 +1,116 @@
+package spike
+
+import (
+	"strings"
+	"testing"
+)
+
+const testControllerDB = `# Test database
+030000005e0400008e02000014010000,Xbox 360 Controller,a:b0,b:b1,x:b2,y:b3,start:b7,leftx:a0,lefty:a1,lefttrigger:a2,-rightx:b5,+rightx:b4,dpup:h0.1,dpleft:h0.8,platform:Linux,
+030000005e0400008e02000014010000,Xbox 360 Controller,a:b1,platform:Windows,
+not a mapping
+`
+
+func TestParseControllerMapping(t *testing.T) {
+	mapping, err := ParseControllerMapping("030000004c050000c405000000010000,PS4 Controller,a:b1,lefty:a1~,+righty:+a3,dpup:h0.1,misc1:b13,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Name != "PS4 Controller" || len(mapping.entries) != 4 {
+		t.Errorf("parsed %+v", mapping)
+	}
+	if e := mapping.entries[1]; !e.isAxis || e.axis != AxisLeftY || !e.input.invert {
+		t.Errorf("parsed inverted axis %+v", e)
+	}
+	if e := mapping.entries[2]; e.half != 1 || e.input.half != 1 || e.input.index != 3 {
+		t.Errorf("parsed half axis %+v", e)
+	}
+	if e := mapping.entries[3]; e.key != KeyDpadUp || e.input.kind != 'h' || e.input.hatMask != PovNorth {
+		t.Errorf("parsed hat %+v", e)
+	}
+	if _, err := ParseControllerMapping("xyz,Broken,a:b0"); err == nil {
+		t.Error("parsed invalid guid")
+	}
+	if _, err := ParseControllerMapping("030000004c050000c405000000010000,Broken,a:q0"); err == nil {
+		t.Error("parsed invalid input")
+	}
+}
+
+func TestControllers(t *testing.T) {
+	fake := NewFakeControllerBackend()
+	SetControllerBackend(fake)
+	defer func() {
+		SetControllerBackend(nil)
+		controllers = nil
+		controllerMappings = make(map[string]*ControllerMapping)
+		controllerMappingsVersions = make(map[string]*ControllerMapping)
+		delete(actionBindings, "jump")
+		delete(axisBindings, "look")
+		resetInput()
+	}()
+	if err := LoadControllerMappings(strings.NewReader(testControllerDB)); err != nil {
+		t.Fatal(err)
+	}
+	BindAction("jump", NewKeyBinding(KeyButtonA))
+	BindAxis("look", 0, NewControllerAxisBinding(AxisRightX, 1))
+
+	events := []InputEvent{}
+	scene := &Scene{}
+	scene.Children = []*Actor{{Input: func(a *Actor, e InputEvent) {
+		events = append(events, e)
+	}}}
+
+	// a newer version of the controller uses the same mapping
+	pad := fake.Connect("Xbox 360 Controller", "030000005e0400008e02000015010000")
+	other := fake.Connect("Unknown Pad", "03000000ffff0000ffff000000010000")
+	update(scene, 0.016)
+	if len(GetControllers()) != 2 || !GetController(0).IsMapped() || GetController(1).IsMapped() {
+		t.Fatalf("connected %v", GetControllers())
+	}
+	if len(events) != 2 || events[0].Type != ControllerConnected || events[1].GetController() != GetController(1) {
+		t.Errorf("connect events %+v", events)
+	}
+
+	events = nil
+	fake.PressButton(pad, 0)
+	fake.MoveAxis(pad, 2, -1)
+	fake.PressButton(pad, 4)
+	fake.MoveHat(pad, 0, PovNorthWest)
+	fake.PressButton(other, 1)
+	update(scene, 0.016)
+	controller := GetController(0)
+	if !controller.IsButtonPressed(KeyButtonA) || !ActionPressed("jump") {
+		t.Error("button a is not pressed")
+	}
+	if controller.GetAxis(AxisTriggerLeft) != 0 || Axis("look") != 1 {
+		t.Errorf("axes trigger %v look %v", controller.GetAxis(AxisTriggerLeft), Axis("look"))
+	}
+	if controller.GetPov(0) != PovNorthWest || !controller.IsButtonPressed(KeyDpadUp) || !controller.IsButtonPressed(KeyDpadLeft) {
+		t.Error("hat is not mapped")
+	}
+	if !GetController(1).IsButtonPressed(KeyButtonB) {
+		t.Error("default mapping is not used")
+	}
+	if len(events) != 6 {
+		t.Errorf("button events %+v", events)
+	}
+
+	fake.MoveAxis(pad, 2, 1)
+	fake.ReleaseButton(pad, 4)
+	fake.PressButton(pad, 5)
+	update(scene, 0.016)
+	if controller.GetAxis(AxisTriggerLeft) != 1 || Axis("look") != -1 {
+		t.Errorf("axes trigger %v look %v", controller.GetAxis(AxisTriggerLeft), Axis("look"))
+	}
+
+	fake.Disconnect(pad)
+	update(scene, 0.016)
+	if GetController(0) != nil || len(GetControllers()) != 1 || ActionPressed("jump") {
+		t.Error("controller is not disconnected")
+	}
+	fake.Connect("Another Pad", "03000000ffff0000ffff000000010000")
+	update(scene, 0.016)
+	if GetController(0) == nil || GetController(0).Name != "Another Pad" {
+		t.Error("controller index is not reused")
+	}
+}
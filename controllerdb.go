@@ -0,0 +1,328 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mobile/asset"
+)
+
+// Controller mappings use the format of the SDL game controller database (https://github.com/gabomdq/SDL_GameControllerDB).
+// Every line maps the raw inputs of one kind of controller, identified by its GUID, to the standard buttons and axes:
+//
+//   030000005e0400008e02000014010000,Xbox 360 Controller,a:b0,b:b1,leftx:a0,lefty:a1,dpup:h0.1,platform:Linux,
+//
+// Raw inputs are buttons (b0), axes (a0), half axes (+a0, -a0), inverted axes (a0~) and hat directions (h0.1 where 1 is up,
+// 2 right, 4 down and 8 left). Outputs can be half axes as well (+leftx).
+
+// A ControllerMapping translates the raw inputs of a controller to the standard buttons and axes
+type ControllerMapping struct {
+	GUID     string
+	Name     string
+	Platform string
+
+	entries []mappingEntry
+}
+
+// A raw input of a mapping
+type mappingInput struct {
+	kind    byte // 'b' button, 'a' axis or 'h' hat
+	index   int
+	half    int8 // 1 or -1 for half axes
+	invert  bool
+	hatMask PovDirection
+}
+
+// A raw input and the button or axis it is mapped to
+type mappingEntry struct {
+	input  mappingInput
+	isAxis bool
+	key    KeyCode
+	axis   ControllerAxis
+	half   int8 // 1 or -1 for half axes
+}
+
+var (
+	controllerButtonNames = map[string]KeyCode{
+		"a":             KeyButtonA,
+		"b":             KeyButtonB,
+		"x":             KeyButtonX,
+		"y":             KeyButtonY,
+		"back":          KeyButtonSelect,
+		"guide":         KeyButtonMode,
+		"start":         KeyButtonStart,
+		"leftstick":     KeyButtonThumbl,
+		"rightstick":    KeyButtonThumbr,
+		"leftshoulder":  KeyButtonL1,
+		"rightshoulder": KeyButtonR1,
+		"dpup":          KeyDpadUp,
+		"dpdown":        KeyDpadDown,
+		"dpleft":        KeyDpadLeft,
+		"dpright":       KeyDpadRight,
+	}
+	controllerAxisNames = map[string]ControllerAxis{
+		"leftx":        AxisLeftX,
+		"lefty":        AxisLeftY,
+		"rightx":       AxisRightX,
+		"righty":       AxisRightY,
+		"lefttrigger":  AxisTriggerLeft,
+		"righttrigger": AxisTriggerRight,
+	}
+
+	// The mappings by GUID and by GUID without the version, for controllers whose exact version is not in the database
+	controllerMappings         = make(map[string]*ControllerMapping)
+	controllerMappingsVersions = make(map[string]*ControllerMapping)
+
+	// Used for controllers that are not in the database. It fits the xpad driver and most xinput compatible gamepads.
+	defaultControllerMapping, _ = ParseControllerMapping("00000000000000000000000000000000,Default," +
+		"a:b0,b:b1,x:b2,y:b3,leftshoulder:b4,rightshoulder:b5,back:b6,start:b7,guide:b8,leftstick:b9,rightstick:b10," +
+		"leftx:a0,lefty:a1,lefttrigger:a2,rightx:a3,righty:a4,righttrigger:a5," +
+		"dpup:h0.1,dpright:h0.2,dpdown:h0.4,dpleft:h0.8,")
+)
+
+// The name of the platform as it is used in the database
+func controllerPlatform() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "Linux"
+	case "darwin":
+		if runtime.GOARCH == "arm" || runtime.GOARCH == "arm64" {
+			return "iOS"
+		}
+		return "Mac OS X"
+	case "windows":
+		return "Windows"
+	case "android":
+		return "Android"
+	}
+	return runtime.GOOS
+}
+
+// ParseControllerMapping parses a line of the SDL game controller database. Unknown buttons and axes are ignored.
+func ParseControllerMapping(line string) (*ControllerMapping, error) {
+	fields := strings.Split(strings.TrimSpace(line), ",")
+	if len(fields) < 2 || len(fields[0]) != 32 {
+		return nil, errors.New("controller mapping: invalid line " + line)
+	}
+	mapping := &ControllerMapping{GUID: strings.ToLower(fields[0]), Name: fields[1]}
+	for _, field := range fields[2:] {
+		if field == "" {
+			continue
+		}
+		colon := strings.IndexByte(field, ':')
+		if colon < 0 {
+			return nil, errors.New("controller mapping: invalid field " + field)
+		}
+		name, value := field[:colon], field[colon+1:]
+		if name == "platform" {
+			mapping.Platform = value
+			continue
+		}
+		entry := mappingEntry{}
+		if name[0] == '+' || name[0] == '-' {
+			entry.half = halfSign(name[0])
+			name = name[1:]
+		}
+		if key, ok := controllerButtonNames[name]; ok {
+			entry.key = key
+		} else if axis, ok := controllerAxisNames[name]; ok {
+			entry.isAxis = true
+			entry.axis = axis
+		} else {
+			continue
+		}
+		input, err := parseMappingInput(value)
+		if err != nil {
+			return nil, err
+		}
+		entry.input = input
+		mapping.entries = append(mapping.entries, entry)
+	}
+	return mapping, nil
+}
+
+func halfSign(c byte) int8 {
+	if c == '-' {
+		return -1
+	}
+	return 1
+}
+
+func parseMappingInput(value string) (mappingInput, error) {
+	input := mappingInput{}
+	invalid := errors.New("controller mapping: invalid input " + value)
+	if value == "" {
+		return input, invalid
+	}
+	if value[0] == '+' || value[0] == '-' {
+		input.half = halfSign(value[0])
+		value = value[1:]
+	}
+	if strings.HasSuffix(value, "~") {
+		input.invert = true
+		value = value[:len(value)-1]
+	}
+	if len(value) < 2 {
+		return input, invalid
+	}
+	input.kind = value[0]
+	switch input.kind {
+	case 'b', 'a':
+		index, err := strconv.Atoi(value[1:])
+		if err != nil {
+			return input, invalid
+		}
+		input.index = index
+	case 'h':
+		dot := strings.IndexByte(value, '.')
+		if dot < 0 {
+			return input, invalid
+		}
+		index, err := strconv.Atoi(value[1:dot])
+		if err != nil {
+			return input, invalid
+		}
+		mask, err := strconv.Atoi(value[dot+1:])
+		if err != nil {
+			return input, invalid
+		}
+		input.index = index
+		input.hatMask = PovDirection(mask)
+	default:
+		return input, invalid
+	}
+	return input, nil
+}
+
+// AddControllerMapping adds a line of the SDL game controller database to the mappings, replacing the mapping of the
+// same controller. Lines of other platforms are ignored.
+func AddControllerMapping(line string) error {
+	mapping, err := ParseControllerMapping(line)
+	if err != nil {
+		return err
+	}
+	if mapping.Platform != "" && mapping.Platform != controllerPlatform() {
+		return nil
+	}
+	controllerMappings[mapping.GUID] = mapping
+	controllerMappingsVersions[mapping.GUID[:24]] = mapping
+	for _, controller := range controllers {
+		if controller.GUID == mapping.GUID {
+			controller.mapping = mapping
+		}
+	}
+	return nil
+}
+
+// LoadControllerMappings reads the mappings from a gamecontrollerdb.txt file. Invalid lines are skipped.
+func LoadControllerMappings(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if err := AddControllerMapping(line); err != nil {
+			println("Controllers: " + err.Error())
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadControllerMappingsAsset reads the mappings from a gamecontrollerdb.txt file in the assets
+func LoadControllerMappingsAsset(name string) error {
+	file, err := asset.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return LoadControllerMappings(file)
+}
+
+// Returns the mapping for the controller with the given GUID or the default mapping
+func findControllerMapping(guid string) *ControllerMapping {
+	guid = strings.ToLower(guid)
+	if mapping, ok := controllerMappings[guid]; ok {
+		return mapping
+	}
+	if len(guid) == 32 {
+		if mapping, ok := controllerMappingsVersions[guid[:24]]; ok {
+			return mapping
+		}
+	}
+	return defaultControllerMapping
+}
+
+// Returns the value of a raw event for the input of an entry and whether the input is the one of the event
+func (self *mappingInput) value(raw RawControllerEvent) (float32, bool) {
+	switch {
+	case raw.Type == RawButton && self.kind == 'b' && self.index == raw.Index:
+		return raw.Value, true
+	case raw.Type == RawHat && self.kind == 'h' && self.index == raw.Index:
+		if raw.Pov&self.hatMask != 0 {
+			return 1, true
+		}
+		return 0, true
+	case raw.Type == RawAxis && self.kind == 'a' && self.index == raw.Index:
+		value := raw.Value
+		if self.invert {
+			value = -value
+		}
+		if self.half != 0 {
+			value *= float32(self.half)
+			if value < 0 {
+				value = 0
+			}
+		}
+		return value, true
+	}
+	return 0, false
+}
+
+// Translates a raw event of the controller to the input events of the buttons and axes that changed
+func (self *ControllerMapping) translate(controller *Controller, raw RawControllerEvent) []InputEvent {
+	id := uint8(controller.Index + 1)
+	events := []InputEvent{}
+	if raw.Type == RawHat && controller.povs[uint8(raw.Index)] != raw.Pov {
+		events = append(events, InputEvent{Type: PovMoved, Button: uint8(raw.Index), Pov: raw.Pov, Controller: id})
+	}
+	for i := range self.entries {
+		entry := &self.entries[i]
+		value, ok := entry.input.value(raw)
+		if !ok {
+			continue
+		}
+		if !entry.isAxis {
+			pressed := value >= 0.5
+			if pressed == controller.buttons[entry.key] {
+				continue
+			}
+			e := InputEvent{Type: KeyUp, KeyCode: entry.key, Controller: id}
+			if pressed {
+				e.Type = KeyDown
+			}
+			events = append(events, e)
+			continue
+		}
+		isTrigger := entry.axis == AxisTriggerLeft || entry.axis == AxisTriggerRight
+		if isTrigger && entry.input.kind == 'a' && entry.input.half == 0 {
+			// full range axes are mapped to the range of the triggers
+			value = (value + 1) / 2
+		}
+		if entry.half != 0 {
+			value *= float32(entry.half)
+		}
+		if value != controller.axes[entry.axis] {
+			events = append(events, InputEvent{Type: AxisMoved, Axis: entry.axis, Value: value, Controller: id})
+		}
+	}
+	return events
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	. "github.com/pyros2097/spike/math"
+	"github.com/pyros2097/spike/math/shape"
+)
+
+var (
+	// If true the actors outside of the view of the camera are not drawn. The bounds of an actor are its rectangle
+	// scaled and rotated in stage coordinates, or its Bounds3d if set. Default is true.
+	CullingEnabled = true
+
+	// the frustum of the camera the current frame is drawn with
+	cullFrustum *Frustum
+	cullBounds  shape.Rectangle
+
+	drawnActors, culledActors int
+)
+
+// Draws the actors of the scene with the camera of the viewport, skipping the actors outside of its view
+func drawScene(scene *Scene, batch g2d.Batch) {
+	drawnActors, culledActors = 0, 0
+	scissors = scissors[:0]
+	camera := currentCamera()
+	cullFrustum = camera.GetFrustum()
+	batch.SetProjectionMatrix(camera.Combined)
+	for _, child := range scene.Children {
+		child.draw(batch, 1.0)
+	}
+}
+
+// Returns the camera the scene is drawn with, the camera of the viewport if it is set
+func currentCamera() *Camera {
+	if viewport != nil {
+		return viewport.Camera
+	}
+	return Camera2d
+}
+
+// Returns the number of actors drawn in the last frame
+func GetDrawnActors() int {
+	return drawnActors
+}
+
+// Returns the number of actors not drawn in the last frame because they were outside of the view of the camera or the
+// culling area of their parent
+func GetCulledActors() int {
+	return culledActors
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+var (
+	// The color the bounds of the actors with Debug set are outlined with
+	DebugColor = Color{0, 1, 1, 1}
+
+	// The color the selected actor of a scene is outlined with
+	SelectionColor = Color{1, 1, 0, 1}
+
+	debugShapes    = newGLShapeRenderer()
+	identityMatrix = vector.NewMatrix4Empty()
+)
+
+// Outlines the bounds of the actor if its Debug is set, rotated and scaled with it, or draws its DrawDebugLines, and then
+// the bounds of its children. It draws between Begin and End of the shape renderer, which is left with the transform matrix of the last actor drawn.
+func (a *Actor) DrawDebug(shapes *g2d.ShapeRenderer) {
+	if a.Hidden {
+		return
+	}
+	if a.Debug {
+		shapes.SetTransformMatrix(a.ComputeTransform())
+		shapes.SetColor(DebugColor.R, DebugColor.G, DebugColor.B, DebugColor.A)
+		if a.DrawDebugLines != nil {
+			a.DrawDebugLines(a, shapes)
+		} else {
+			shapes.Rect(0, 0, a.W, a.H)
+		}
+	}
+	for _, child := range a.Children {
+		child.DrawDebug(shapes)
+	}
+}
+
+// Draws the grid, the selection, the focus outline and the debug bounds of the actors of the scene over it with the camera
+// of the viewport
+func drawSceneDebug(scene *Scene, shapes *g2d.ShapeRenderer) {
+	shapes.SetProjectionMatrix(currentCamera().Combined)
+	shapes.Begin(g2d.ShapeLine)
+	if scene.ShowGrid {
+		scene.DrawGrid(shapes)
+	}
+	scene.DrawSelection(shapes)
+	drawFocusOutline(scene.Children, shapes)
+	for _, child := range scene.Children {
+		child.DrawDebug(shapes)
+	}
+	shapes.End()
+}
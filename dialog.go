@@ -0,0 +1,312 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+// The style of a window, only TitleFont is required
+type WindowStyle struct {
+	Background ui.Drawable
+
+	TitleFont      *g2d.BitmapFont
+	TitleFontColor *Color
+
+	// Drawn over the whole stage behind a modal window, like a darkened color which dims the scene
+	StageBackground ui.Drawable
+}
+
+// A Window is a table with a title bar, it is moved by dragging the title bar. A modal window is the only actor of the
+// scene which receives touch events while it is shown, the scene behind it is dimmed by the stage background of its style.
+// Its cells are added below the title bar.
+type Window struct {
+	Table
+
+	style           *WindowStyle
+	titleTable      *Table
+	titleLabel      *Label
+	titleLabelStyle LabelStyle
+
+	dragging     bool
+	pointer      uint8
+	dragX, dragY float32
+
+	// If set the window is moved by dragging its title bar. Default is true.
+	Movable bool
+
+	// If set the window is kept within the world of the viewport when it is moved. Default is true.
+	KeepWithinStage bool
+}
+
+func NewWindow(title string, style *WindowStyle) *Window {
+	self := &Window{}
+	self.initWindow(title, style)
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Initializes the window and its title bar, the widgets which embed a window call it in their constructors
+func (self *Window) initWindow(title string, style *WindowStyle) {
+	self.initTable()
+	self.widget.init(&self.Actor, self)
+	self.style, self.Movable, self.KeepWithinStage = style, true, true
+	self.titleLabelStyle = LabelStyle{Font: style.TitleFont, FontColor: style.TitleFontColor}
+	self.titleLabel = NewLabel(title, &self.titleLabelStyle)
+	self.titleLabel.SetEllipsis("...")
+	self.titleTable = NewTable()
+	self.titleTable.Add(&self.titleLabel.Actor).ExpandX().FillX().MinWidth(0)
+	self.SetBackground(style.Background)
+	self.Add(&self.titleTable.Actor).ExpandX().FillX()
+	self.Row()
+	self.TouchState = TouchableEnabled
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+}
+
+func (self *Window) SetStyle(style *WindowStyle) {
+	self.style = style
+	self.titleLabelStyle = LabelStyle{Font: style.TitleFont, FontColor: style.TitleFontColor}
+	self.titleLabel.SetStyle(&self.titleLabelStyle)
+	self.SetBackground(style.Background)
+}
+
+// Invalidates the window, the title is laid out with the font and color of the style again when it was changed, like by
+// Skin.Apply
+func (self *Window) Invalidate() {
+	if self.titleLabelStyle.Font != self.style.TitleFont || self.titleLabelStyle.FontColor != self.style.TitleFontColor {
+		self.titleLabelStyle = LabelStyle{Font: self.style.TitleFont, FontColor: self.style.TitleFontColor}
+		self.titleLabel.Invalidate()
+	}
+	self.Table.Invalidate()
+}
+
+func (self *Window) GetStyle() *WindowStyle {
+	return self.style
+}
+
+// Returns the table of the title bar, which holds the title label. Actors like a close button can be added to it.
+func (self *Window) GetTitleTable() *Table {
+	return self.titleTable
+}
+
+func (self *Window) GetTitleLabel() *Label {
+	return self.titleLabel
+}
+
+// A modal window is the only actor of the scene which receives touch events while it is shown, it must be a child of the
+// scene. Default is false.
+func (self *Window) SetModal(modal bool) {
+	self.modal = modal
+}
+
+func (self *Window) IsModal() bool {
+	return self.modal
+}
+
+// Returns whether the title bar is being dragged
+func (self *Window) IsDragging() bool {
+	return self.dragging
+}
+
+// Moves the window by dragging its title bar
+func (self *Window) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if !self.Movable || self.dragging || !self.titleTable.Hit(e.X, e.Y, widgetPoint) {
+			return
+		}
+		self.dragging, self.pointer, self.dragX, self.dragY = true, e.Pointer, e.X, e.Y
+	case TouchDragged:
+		if !self.dragging || e.Pointer != self.pointer {
+			return
+		}
+		self.MoveBy(e.X-self.dragX, e.Y-self.dragY)
+		self.dragX, self.dragY = e.X, e.Y
+		if self.KeepWithinStage {
+			self.keepWithinStage()
+		}
+	case TouchUp:
+		if e.Pointer == self.pointer {
+			self.dragging = false
+		}
+	}
+}
+
+// Moves the window back within the world, for windows whose parent is the scene
+func (self *Window) keepWithinStage() {
+	width, height := worldSize()
+	x := utils.ClampFloat32(self.X, 0, utils.MaxFloat32(0, width-self.W))
+	y := utils.ClampFloat32(self.Y, 0, utils.MaxFloat32(0, height-self.H))
+	self.SetPosition(x, y)
+}
+
+// Returns the size of the world of the viewport, or the target size of the game if there is no viewport. While the HUD is
+// drawn or sent input it returns the size of the HUD.
+func worldSize() (float32, float32) {
+	if inHud {
+		return hudSize()
+	}
+	if viewport != nil {
+		return viewport.WorldWidth, viewport.WorldHeight
+	}
+	return targetWidth, targetHeight
+}
+
+// Draws the stage background behind a modal window and then the window
+func (self *Window) draw(batch g2d.Batch, parentAlpha float32) {
+	if self.modal && self.style.StageBackground != nil {
+		width, height := worldSize()
+		// the actors added to the scene have no parent, they are drawn in stage coordinates
+		widgetTransform.Idt()
+		if self.Parent != nil {
+			self.Parent.StageToLocalCoordinates(widgetPoint.Set(0, 0))
+			widgetTransform.Set(self.Parent.computeLocalTransform()).Translate(widgetPoint.X, widgetPoint.Y)
+		}
+		setBatchColor(batch, &self.Actor, nil, parentAlpha)
+		self.style.StageBackground.Draw(batch, width, height, widgetTransform)
+	}
+	self.Table.draw(batch, parentAlpha)
+}
+
+// A Dialog is a modal window with a content table and a table of buttons below it. Clicking a button or pressing a key of
+// the dialog hides it, then OnResult is called with the result of the button or key in the next frame.
+//
+//	dialog := spike.NewDialog("Quit", windowStyle)
+//	dialog.Text("Quit the game?", labelStyle)
+//	dialog.Button("Yes", true, buttonStyle).Button("No", false, buttonStyle)
+//	dialog.Key(spike.KeyEnter, true).Key(spike.KeyEscape, false)
+//	dialog.OnResult = func(d *spike.Dialog, result interface{}) {
+//	  if result == true {
+//	    spike.SetScene("menu")
+//	  }
+//	}
+//	dialog.Show(scene)
+type Dialog struct {
+	Window
+
+	contentTable, buttonTable *Table
+	keys                      map[KeyCode]interface{}
+
+	// The scene the dialog is shown in, nil while it is hidden
+	scene *Scene
+
+	result    interface{}
+	hasResult bool
+
+	// Called with the result of the button or key which closed the dialog, after it was hidden
+	OnResult func(self *Dialog, result interface{})
+}
+
+func NewDialog(title string, style *WindowStyle) *Dialog {
+	self := &Dialog{keys: make(map[KeyCode]interface{})}
+	self.initWindow(title, style)
+	self.contentTable, self.buttonTable = NewTable(), NewTable()
+	self.buttonTable.Defaults().Space(6)
+	self.Add(&self.contentTable.Actor).Expand().Fill()
+	self.Row()
+	self.Add(&self.buttonTable.Actor).FillX()
+	self.SetModal(true)
+	self.Act = func(a *Actor, delta float32) {
+		self.act()
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Returns the table the content of the dialog is added to
+func (self *Dialog) GetContentTable() *Table {
+	return self.contentTable
+}
+
+// Returns the table the buttons of the dialog are added to
+func (self *Dialog) GetButtonTable() *Table {
+	return self.buttonTable
+}
+
+// Adds a label with the text to the content table
+func (self *Dialog) Text(text string, style *LabelStyle) *Dialog {
+	self.contentTable.Add(&NewLabel(text, style).Actor)
+	return self
+}
+
+// Adds a text button to the button table, clicking it closes the dialog with the result
+func (self *Dialog) Button(text string, result interface{}, style *TextButtonStyle) *Dialog {
+	button := NewTextButton(text, style)
+	return self.AddButton(&button.BaseButton, result)
+}
+
+// Adds the button to the button table, clicking it closes the dialog with the result. The OnChange of the button is set.
+func (self *Dialog) AddButton(button *BaseButton, result interface{}) *Dialog {
+	self.buttonTable.Add(&button.Actor)
+	button.OnChange = func(b *BaseButton) {
+		// the button is not left checked
+		b.setChecked(false, false)
+		self.setResult(result)
+	}
+	return self
+}
+
+// Closes the dialog with the result when the key is pressed while it is shown
+func (self *Dialog) Key(key KeyCode, result interface{}) *Dialog {
+	self.keys[key] = result
+	return self
+}
+
+// Packs the dialog and shows it in the center of the scene
+func (self *Dialog) Show(scene *Scene) *Dialog {
+	self.Pack()
+	width, height := worldSize()
+	self.SetPosition(float32(int((width-self.W)/2)), float32(int((height-self.H)/2)))
+	scene.AddActor(&self.Actor)
+	self.scene = scene
+	return self
+}
+
+// Removes the dialog from the scene, OnResult is not called
+func (self *Dialog) Hide() {
+	self.hasResult = false
+	if self.scene != nil {
+		self.scene.RemoveActor(&self.Actor)
+		self.scene = nil
+	}
+}
+
+// Closes the dialog with the result in the next frame, the buttons are clicked while the input is sent to the actors
+func (self *Dialog) setResult(result interface{}) {
+	if self.scene == nil || self.hasResult {
+		return
+	}
+	self.result, self.hasResult = result, true
+}
+
+func (self *Dialog) input(e InputEvent) {
+	self.Window.input(e)
+	if e.Type == KeyDown && e.Controller == 0 {
+		if result, ok := self.keys[e.KeyCode]; ok {
+			self.setResult(result)
+		}
+	}
+}
+
+func (self *Dialog) act() {
+	if !self.hasResult {
+		return
+	}
+	result := self.result
+	self.Hide()
+	if self.OnResult != nil {
+		self.OnResult(self, result)
+	}
+}
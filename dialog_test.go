@@ -0,0 +1,175 @@
+package spike
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/vector"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+// A batch which keeps the colors of the vertices drawn with DrawVertices
+type vertexColorBatch struct {
+	SBatch
+	colors []float32
+}
+
+func newVertexColorBatch() *vertexColorBatch {
+	return &vertexColorBatch{SBatch: SBatch{transform: vector.NewMatrix4Empty(), projection: vector.NewMatrix4Empty()}}
+}
+
+func (self *vertexColorBatch) DrawVertices(texture *g2d.Texture, vertices []float32) {
+	for i := g2d.C1; i < len(vertices); i += g2d.V1 - g2d.X1 + 1 {
+		self.colors = append(self.colors, vertices[i])
+	}
+}
+
+func TestToastFades(t *testing.T) {
+	skin := newTestSkin()
+	skin.AddStyle("default", &LabelStyle{Font: skin.GetFont("default"), FontColor: &Color{R: 1, G: 1, B: 1, A: 1}})
+	patch := g2d.NewNinePatch(g2d.NewTextureRegion(&g2d.Texture{Width: 30, Height: 30}, 0, 0, 30, 30), 10, 10, 10, 10)
+	skin.AddDrawable("dialogDim", ui.NewTintedDrawable(patch, g2d.Color{R: 0, G: 0, B: 0, A: 0.5}))
+	scene := &Scene{Name: "toast"}
+	toast := scene.ShowToast(skin, "Saved", 1)
+
+	drawnAlpha := func() float32 {
+		batch := newVertexColorBatch()
+		toast.draw(batch, 1)
+		if len(batch.colors) == 0 {
+			t.Fatal("background not drawn")
+		}
+		for _, color := range batch.colors[1:] {
+			if color != batch.colors[0] {
+				t.Fatal("vertices drawn in different colors")
+			}
+		}
+		return float32(math.Float32bits(batch.colors[0])>>24) / 255
+	}
+	if alpha := drawnAlpha(); !near(alpha, 0.5, 0.01) {
+		t.Errorf("background alpha %v", alpha)
+	}
+
+	// the dimmed nine patch fades out with the toast
+	update(scene, 0.85)
+	if !near(toast.Color.A, 0.5, 0.01) {
+		t.Errorf("toast alpha %v", toast.Color.A)
+	}
+	if alpha := drawnAlpha(); !near(alpha, 0.25, 0.01) {
+		t.Errorf("faded background alpha %v", alpha)
+	}
+	update(scene, 0.2)
+	if len(scene.Children) != 0 {
+		t.Error("toast not removed")
+	}
+}
+
+// Returns a skin with the default label, text button and window styles
+func newDialogSkin() *Skin {
+	skin := newTestSkin()
+	white := &Color{R: 1, G: 1, B: 1, A: 1}
+	font := skin.GetFont("default")
+	skin.AddStyle("default", &LabelStyle{Font: font, FontColor: white})
+	skin.AddStyle("default", &TextButtonStyle{Font: font, FontColor: white})
+	skin.AddStyle("default", &WindowStyle{TitleFont: font, TitleFontColor: white})
+	return skin
+}
+
+// Clicks the actor at its center
+func click(actor *Actor) {
+	bounds := actor.stageBounds(&shape.Rectangle{})
+	x, y := bounds.X+bounds.W/2, bounds.Y+bounds.H/2
+	InputChannel <- newTouchEvent(TouchDown, x, y, 0)
+	InputChannel <- newTouchEvent(TouchUp, x, y, 0)
+}
+
+func TestDialogResult(t *testing.T) {
+	defer resetFocus()
+	scene := &Scene{Name: "dialog"}
+	answers := []bool{}
+	shown := []bool{}
+	dialog := scene.ShowConfirmDialog(newDialogSkin(), "Quit", "Quit the game?", func(confirmed bool) {
+		answers = append(answers, confirmed)
+	})
+	onResult := dialog.OnResult
+	dialog.OnResult = func(d *Dialog, result interface{}) {
+		shown = append(shown, len(scene.Children) != 0)
+		onResult(d, result)
+	}
+	buttons := dialog.GetButtonTable().Children
+	if len(scene.Children) != 1 || !dialog.IsModal() || len(buttons) != 2 {
+		t.Fatalf("dialog not shown with two buttons")
+	}
+
+	// the result of the clicked button is given after the input, once the dialog is hidden
+	yes := buttons[0].Widget.(*TextButton)
+	onChange := yes.OnChange
+	yes.OnChange = func(b *BaseButton) {
+		onChange(b)
+		if len(answers) != 0 {
+			t.Error("result given while the button was clicked")
+		}
+	}
+	click(buttons[0])
+	// a second click in the same frame does not change the result
+	click(buttons[1])
+	update(scene, 0)
+	if len(answers) != 1 || !answers[0] || len(shown) != 1 || shown[0] || yes.IsChecked() {
+		t.Errorf("answered %v, dialog shown %v", answers, shown)
+	}
+	update(scene, 0)
+	if len(answers) != 1 {
+		t.Errorf("answered %v after the dialog was hidden", answers)
+	}
+
+	// the keys of the dialog close it with their results, the keys of the controllers do not
+	answers = nil
+	dialog.Show(scene)
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyEscape, Controller: 1})
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyTab})
+	update(scene, 0)
+	if len(answers) != 0 || len(scene.Children) != 1 {
+		t.Errorf("other keys answered %v", answers)
+	}
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyEscape})
+	dispatchInput(InputEvent{Type: KeyUp, KeyCode: KeyEscape})
+	update(scene, 0)
+	if len(answers) != 1 || answers[0] || len(scene.Children) != 0 {
+		t.Errorf("escape answered %v", answers)
+	}
+
+	// a dialog hidden before the frame gives no result
+	answers = nil
+	dialog.Show(scene)
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyEnter})
+	dispatchInput(InputEvent{Type: KeyUp, KeyCode: KeyEnter})
+	processInput(scene)
+	dialog.Hide()
+	update(scene, 0)
+	if len(answers) != 0 || len(scene.Children) != 0 {
+		t.Errorf("hidden dialog answered %v", answers)
+	}
+}
+
+func TestToastRemoved(t *testing.T) {
+	skin := newDialogSkin()
+	scene := &Scene{Name: "toast"}
+	world := &Actor{Name: "world"}
+	scene.AddActor(world)
+	short := scene.ShowToast(skin, "Saved", 0.5)
+	long := scene.ShowToast(skin, "Level 2", 2)
+	update(scene, 0.1)
+	if len(scene.Children) != 3 || short.Color.A != 1 {
+		t.Fatalf("%d actors, alpha %v", len(scene.Children), short.Color.A)
+	}
+	// each toast is removed at the end of its duration, the other actors stay
+	update(scene, 0.4)
+	if len(scene.Children) != 2 || scene.Children[0] != world || scene.Children[1] != &long.Actor {
+		t.Errorf("%d actors after the short toast", len(scene.Children))
+	}
+	update(scene, 1.5)
+	if len(scene.Children) != 1 || scene.Children[0] != world {
+		t.Errorf("%d actors after the long toast", len(scene.Children))
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"math"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+// The focus lets menus be used without touch. One focusable actor of the scene has the focus, the arrow keys, the d-pad,
+// the pov and the left stick of a controller move it to the nearest focusable actor in their direction, by the bounds of
+// the actors in stage coordinates. Enter, the center of the d-pad and the A button activate the focused actor, a button
+// is clicked and a text field gets the keyboard focus. While an actor has the keyboard focus the keys of the keyboard are
+// left to it, the controllers still move the focus.
+//
+// The actors are sent FocusGained and FocusLost events when they gain and lose the focus and an Activated event when they
+// are activated. The scroll panes the focused actor is in are scrolled to show it.
+//
+//	play := skin.NewTextButton("Play", "default")
+//	options := skin.NewTextButton("Options", "default")
+//	spike.SetFocus(&play.Actor)
+
+var (
+	// The drawable drawn over the focused actor, stretched to its bounds. If nil the focused actor is outlined in FocusColor.
+	FocusHighlight ui.Drawable
+
+	// The color the focused actor is outlined with if there is no FocusHighlight
+	FocusColor = Color{1, 0.8, 0.2, 1}
+
+	// How far the left stick of a controller is pushed to move the focus, it moves once each time the stick is pushed
+	FocusStickThreshold float32 = 0.5
+
+	// The actor which has the focus
+	focusedActor *Actor
+
+	// The controller axes pushed beyond the threshold, so that holding the stick moves the focus only once
+	focusSticks = map[focusStick]bool{}
+
+	focusBounds, focusCandidate shape.Rectangle
+	focusRootActors             []*Actor
+	focusHudRoot                [1]*Actor
+)
+
+type focusStick struct {
+	controller uint8
+	axis       ControllerAxis
+}
+
+// The widgets which can be disabled, disabled widgets cannot get the focus
+type disableable interface {
+	IsDisabled() bool
+}
+
+// Gives the focus to the actor, nil to clear it. The actor losing the focus is sent a FocusLost event and the actor gaining it
+// a FocusGained event. The scroll panes the actor is in are scrolled to show it. The actor does not need to be focusable.
+func SetFocus(actor *Actor) {
+	if focusedActor == actor {
+		return
+	}
+	old := focusedActor
+	focusedActor = actor
+	if old != nil {
+		if keyboardFocus == old {
+			SetKeyboardFocus(nil)
+		}
+		if old.Input != nil {
+			old.Input(old, InputEvent{Type: FocusLost})
+		}
+	}
+	if actor != nil {
+		if actor.Input != nil {
+			actor.Input(actor, InputEvent{Type: FocusGained})
+		}
+		scrollToFocus(actor)
+	}
+}
+
+// Returns the actor which has the focus, or nil
+func GetFocus() *Actor {
+	return focusedActor
+}
+
+// Returns whether the actor has the focus
+func (a *Actor) HasFocus() bool {
+	return focusedActor == a
+}
+
+// Moves the focus to the nearest focusable actor of the scene or its HUD in the direction, y goes up. The world and the
+// HUD actors are compared where they are on the screen. If no actor of the scene has the focus it is given to the
+// focusable actor nearest to the top left corner. While a modal window is shown the focus only moves within it. Returns
+// false if there is no actor to move it to.
+func MoveFocus(scene *Scene, x, y float32) bool {
+	length := float32(math.Sqrt(float64(x*x + y*y)))
+	if length == 0 {
+		return false
+	}
+	x, y = x/length, y/length
+	roots := focusRoots(scene)
+	focused := focusedActor
+	if focused != nil && !inRoots(roots, focused) {
+		focused = nil
+	}
+	var cx, cy float32
+	if focused != nil {
+		focusScreenBounds(scene, focused, &focusBounds)
+		cx, cy = focusBounds.X+focusBounds.W/2, focusBounds.Y+focusBounds.H/2
+	}
+	var best *Actor
+	var bestScore, bestTop, bestLeft float32
+	visitFocusable(roots, func(actor *Actor) {
+		if actor == focused {
+			return
+		}
+		focusScreenBounds(scene, actor, &focusCandidate)
+		if focused == nil {
+			// the first actor from the top, then from the left
+			top, left := focusCandidate.Y+focusCandidate.H, focusCandidate.X
+			if best == nil || top > bestTop || top == bestTop && left < bestLeft {
+				best, bestTop, bestLeft = actor, top, left
+			}
+			return
+		}
+		dx := focusCandidate.X + focusCandidate.W/2 - cx
+		dy := focusCandidate.Y + focusCandidate.H/2 - cy
+		// the distance in the direction, the actors beside the direction are farther away
+		distance := dx*x + dy*y
+		if distance <= 0 {
+			return
+		}
+		score := distance + 2*utils.AbsFloat32(dx*y-dy*x)
+		if best == nil || score < bestScore {
+			best, bestScore = actor, score
+		}
+	})
+	if best == nil {
+		return false
+	}
+	SetFocus(best)
+	return true
+}
+
+// Returns the actors of the scene the focus can move between with the HUD, the modal actor if there is one
+func focusRoots(scene *Scene) []*Actor {
+	if modal := modalActor(scene); modal != nil {
+		return []*Actor{modal}
+	}
+	if scene.hud == nil {
+		return scene.Children
+	}
+	focusRootActors = append(append(focusRootActors[:0], scene.Children...), scene.hud)
+	return focusRootActors
+}
+
+// Sets the rect to the bounds of the actor in stage coordinates. While the scene has HUD actors the bounds of the world
+// actors are given in HUD units instead, so that they are compared with the HUD actors where they are on the screen.
+func focusScreenBounds(scene *Scene, actor *Actor, rect *shape.Rectangle) {
+	actor.stageBounds(rect)
+	if scene.hud == nil || len(scene.hud.Children) == 0 || inRoots(hudRoots(scene), actor) {
+		return
+	}
+	x1, y1, x2, y2 := rect.X, rect.Y, rect.X+rect.W, rect.Y+rect.H
+	if viewport != nil {
+		x1, y1 = viewport.ToScreen(x1, y1)
+		x2, y2 = viewport.ToScreen(x2, y2)
+		height := float32(screenHeight)
+		y1, y2 = height-y1, height-y2
+	}
+	rect.Set(x1*HudUnitsPerPixel, y1*HudUnitsPerPixel, 0, 0)
+	rect.Merge(x2*HudUnitsPerPixel, y2*HudUnitsPerPixel)
+}
+
+// Returns the root of the HUD of the scene as the only root
+func hudRoots(scene *Scene) []*Actor {
+	focusHudRoot[0] = scene.hud
+	return focusHudRoot[:]
+}
+
+// Calls visit with the visible, focusable and enabled actors of the trees
+func visitFocusable(actors []*Actor, visit func(actor *Actor)) {
+	for _, actor := range actors {
+		if actor.Hidden || actor.TouchState == TouchableDisabled {
+			continue
+		}
+		if actor.Focusable && actor.TouchState == TouchableEnabled {
+			if widget, ok := actor.Widget.(disableable); !ok || !widget.IsDisabled() {
+				visit(actor)
+			}
+		}
+		visitFocusable(actor.Children, visit)
+	}
+}
+
+// Returns whether the actor is visible and in the trees of the actors
+func inRoots(roots []*Actor, actor *Actor) bool {
+	for ; actor.Parent != nil; actor = actor.Parent {
+		if actor.Hidden {
+			return false
+		}
+	}
+	if actor.Hidden {
+		return false
+	}
+	for _, root := range roots {
+		if root == actor {
+			return true
+		}
+	}
+	return false
+}
+
+// Sends an Activated event to the focused actor
+func activateFocus(scene *Scene) {
+	if focusedActor != nil && focusedActor.Input != nil && inRoots(focusRoots(scene), focusedActor) {
+		focusedActor.Input(focusedActor, InputEvent{Type: Activated})
+	}
+}
+
+// Scrolls the scroll panes the actor is in to show it, the innermost first
+func scrollToFocus(actor *Actor) {
+	for parent := actor.Parent; parent != nil; parent = parent.Parent {
+		pane, ok := parent.Widget.(*ScrollPane)
+		if !ok || pane.content == nil {
+			continue
+		}
+		actor.stageBounds(&focusBounds)
+		x1, y1 := focusBounds.X, focusBounds.Y
+		x2, y2 := focusBounds.X+focusBounds.W, focusBounds.Y+focusBounds.H
+		pane.content.StageToLocalCoordinates(widgetPoint.Set(x1, y1))
+		x1, y1 = widgetPoint.X, widgetPoint.Y
+		pane.content.StageToLocalCoordinates(widgetPoint.Set(x2, y2))
+		x2, y2 = widgetPoint.X, widgetPoint.Y
+		pane.ScrollTo(&shape.Rectangle{X: utils.MinFloat32(x1, x2), Y: utils.MinFloat32(y1, y2), W: utils.AbsFloat32(x2 - x1), H: utils.AbsFloat32(y2 - y1)})
+	}
+}
+
+// Moves or activates the focus with the input event. typing tells whether an actor had the keyboard focus before the event
+// was sent to the actors, the keys of the keyboard are left to it then.
+func processFocus(scene *Scene, e InputEvent, typing bool) {
+	switch e.Type {
+	case KeyDown:
+		if typing && e.Controller == 0 {
+			return
+		}
+		switch e.KeyCode {
+		case KeyDpadUp:
+			MoveFocus(scene, 0, 1)
+		case KeyDpadDown:
+			MoveFocus(scene, 0, -1)
+		case KeyDpadLeft:
+			MoveFocus(scene, -1, 0)
+		case KeyDpadRight:
+			MoveFocus(scene, 1, 0)
+		case KeyEnter, KeyDpadCenter, KeyButtonA:
+			activateFocus(scene)
+		}
+	case PovMoved:
+		var x, y float32
+		if e.Pov&PovNorth != 0 {
+			y++
+		}
+		if e.Pov&PovSouth != 0 {
+			y--
+		}
+		if e.Pov&PovEast != 0 {
+			x++
+		}
+		if e.Pov&PovWest != 0 {
+			x--
+		}
+		MoveFocus(scene, x, y)
+	case AxisMoved:
+		if e.Axis != AxisLeftX && e.Axis != AxisLeftY {
+			return
+		}
+		stick := focusStick{e.Controller, e.Axis}
+		pushed := utils.AbsFloat32(e.Value) >= FocusStickThreshold
+		if pushed && !focusSticks[stick] {
+			if e.Axis == AxisLeftX {
+				MoveFocus(scene, e.Value, 0)
+			} else {
+				// the stick is pushed up at -1
+				MoveFocus(scene, 0, -e.Value)
+			}
+		}
+		focusSticks[stick] = pushed
+	case ControllerDisconnected:
+		delete(focusSticks, focusStick{e.Controller, AxisLeftX})
+		delete(focusSticks, focusStick{e.Controller, AxisLeftY})
+	}
+}
+
+// Draws the FocusHighlight over the focused actor, it is drawn after the actor and its children
+func (a *Actor) drawFocus(batch g2d.Batch, parentAlpha float32) {
+	if FocusHighlight == nil {
+		return
+	}
+	setBatchColor(batch, a, nil, parentAlpha)
+	drawDrawable(batch, a, FocusHighlight, 0, 0, a.W, a.H)
+}
+
+// Outlines the focused actor in FocusColor if there is no FocusHighlight and it is in the trees of the actors. It draws
+// between Begin and End of the shape renderer.
+func drawFocusOutline(roots []*Actor, shapes *g2d.ShapeRenderer) {
+	if FocusHighlight != nil || focusedActor == nil || !inRoots(roots, focusedActor) {
+		return
+	}
+	shapes.SetTransformMatrix(focusedActor.ComputeTransform())
+	shapes.SetColor(FocusColor.R, FocusColor.G, FocusColor.B, FocusColor.A)
+	shapes.Rect(0, 0, focusedActor.W, focusedActor.H)
+}
+
+// Outlines the focused actor if it is in the HUD of the scene, with the HUD camera over the HUD
+func drawHudFocusOutline(scene *Scene, shapes *g2d.ShapeRenderer) {
+	if scene.hud == nil || FocusHighlight != nil || focusedActor == nil || !inRoots(hudRoots(scene), focusedActor) {
+		return
+	}
+	shapes.SetProjectionMatrix(hudCamera.Combined)
+	shapes.Begin(g2d.ShapeLine)
+	drawFocusOutline(hudRoots(scene), shapes)
+	shapes.End()
+}
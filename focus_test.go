@@ -0,0 +1,253 @@
+package spike
+
+import (
+	"testing"
+)
+
+// Returns a scene with a grid of three by three focusable actors of 100 by 50, 20 apart, the first row at the top. The
+// focus events of the actors are kept as their names and the event types.
+func newFocusGrid() (*Scene, [3][3]*Actor, *[]string) {
+	events := &[]string{}
+	scene := &Scene{Name: "focus"}
+	var grid [3][3]*Actor
+	for row := range grid {
+		for column := range grid[row] {
+			actor := &Actor{Name: string("abc"[row]) + string("123"[column]), X: float32(column) * 120,
+				Y: float32(2-row) * 70, W: 100, H: 50, Focusable: true}
+			actor.Input = func(a *Actor, e InputEvent) {
+				switch e.Type {
+				case FocusGained:
+					*events = append(*events, a.Name+" gained")
+				case FocusLost:
+					*events = append(*events, a.Name+" lost")
+				case Activated:
+					*events = append(*events, a.Name+" activated")
+				}
+			}
+			grid[row][column] = actor
+			scene.AddActor(actor)
+		}
+	}
+	return scene, grid, events
+}
+
+func resetFocus() {
+	SetFocus(nil)
+	SetKeyboardFocus(nil)
+	focusSticks = map[focusStick]bool{}
+	drainInput()
+	for key := range pressedKeys {
+		delete(pressedKeys, key)
+	}
+}
+
+// Sends the key down and up from the controller, 0 is the keyboard
+func sendKey(key KeyCode, controller uint8) {
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: key, Controller: controller})
+	dispatchInput(InputEvent{Type: KeyUp, KeyCode: key, Controller: controller})
+}
+
+func TestMoveFocus(t *testing.T) {
+	defer resetFocus()
+	scene, grid, events := newFocusGrid()
+	// without a focus it goes to the actor at the top left
+	if !MoveFocus(scene, 0, -1) || GetFocus() != grid[0][0] || !grid[0][0].HasFocus() {
+		t.Fatalf("focus given to %v", GetFocus())
+	}
+	for _, c := range []struct {
+		x, y     float32
+		from, to [2]int
+		name     string
+	}{
+		{1, 0, [2]int{1, 1}, [2]int{1, 2}, "right"},
+		{-1, 0, [2]int{1, 1}, [2]int{1, 0}, "left"},
+		{0, 1, [2]int{1, 1}, [2]int{0, 1}, "up"},
+		{0, -1, [2]int{1, 1}, [2]int{2, 1}, "down"},
+		// the direction does not need to be a unit
+		{0, -5, [2]int{0, 2}, [2]int{1, 2}, "down from the top right"},
+		{-3, 0, [2]int{2, 2}, [2]int{2, 1}, "left from the bottom right"},
+	} {
+		SetFocus(grid[c.from[0]][c.from[1]])
+		if !MoveFocus(scene, c.x, c.y) || GetFocus() != grid[c.to[0]][c.to[1]] {
+			t.Errorf("%s moved to %v", c.name, GetFocus())
+		}
+	}
+
+	// there is nothing beyond the edges
+	SetFocus(grid[1][2])
+	*events = nil
+	if MoveFocus(scene, 1, 0) || MoveFocus(scene, 0, 0) || GetFocus() != grid[1][2] || len(*events) != 0 {
+		t.Errorf("moved beyond the right edge to %v", GetFocus())
+	}
+	if !MoveFocus(scene, -1, 0) || len(*events) != 2 || (*events)[0] != "b3 lost" || (*events)[1] != "b2 gained" {
+		t.Errorf("events %v", *events)
+	}
+
+	// hidden and disabled actors are passed over for the ones beside the direction
+	grid[1][2].Hidden = true
+	grid[0][2].TouchState = TouchableDisabled
+	if !MoveFocus(scene, 1, 0) || GetFocus() != grid[2][2] {
+		t.Errorf("moved past hidden actors to %v", GetFocus())
+	}
+	// the focus of a hidden actor starts again at the top left
+	grid[0][0].Hidden = true
+	SetFocus(grid[1][2])
+	if !MoveFocus(scene, 0, -1) || GetFocus() != grid[0][1] {
+		t.Errorf("moved from a hidden actor to %v", GetFocus())
+	}
+}
+
+func TestFocusInput(t *testing.T) {
+	defer resetFocus()
+	scene, grid, events := newFocusGrid()
+	SetFocus(grid[1][1])
+	sendKey(KeyDpadRight, 0)
+	sendKey(KeyDpadUp, 0)
+	update(scene, 0)
+	if GetFocus() != grid[0][2] {
+		t.Errorf("keys moved the focus to %v", GetFocus())
+	}
+	dispatchInput(InputEvent{Type: PovMoved, Pov: PovSouth, Controller: 1})
+	dispatchInput(InputEvent{Type: PovMoved, Pov: PovWest, Controller: 1})
+	update(scene, 0)
+	if GetFocus() != grid[1][1] {
+		t.Errorf("pov moved the focus to %v", GetFocus())
+	}
+
+	// the stick moves the focus once each time it is pushed beyond the threshold
+	for _, c := range []struct {
+		axis  ControllerAxis
+		value float32
+		to    *Actor
+	}{
+		{AxisLeftX, -0.3, grid[1][1]},
+		{AxisLeftX, -0.6, grid[1][0]},
+		{AxisLeftX, 0.2, grid[1][0]},
+		{AxisLeftX, 0.6, grid[1][1]},
+		{AxisLeftX, 0.9, grid[1][1]},
+		{AxisLeftX, 0.2, grid[1][1]},
+		{AxisLeftX, 0.7, grid[1][2]},
+		{AxisLeftX, 0, grid[1][2]},
+		{AxisLeftX, -0.7, grid[1][1]},
+		// the stick is pushed up at -1
+		{AxisLeftY, -0.8, grid[0][1]},
+		{AxisLeftY, 0, grid[0][1]},
+		{AxisLeftY, 0.5, grid[1][1]},
+		// the right stick does not move the focus
+		{AxisRightX, 1, grid[1][1]},
+	} {
+		dispatchInput(InputEvent{Type: AxisMoved, Axis: c.axis, Value: c.value, Controller: 1})
+		update(scene, 0)
+		if GetFocus() != c.to {
+			t.Errorf("stick axis %v at %v moved the focus to %v, expected %v", c.axis, c.value, GetFocus(), c.to.Name)
+		}
+	}
+	// a controller pushed when it was disconnected moves the focus again once it is connected
+	dispatchInput(InputEvent{Type: ControllerDisconnected, Controller: 1})
+	dispatchInput(InputEvent{Type: AxisMoved, Axis: AxisLeftY, Value: 0.6, Controller: 1})
+	update(scene, 0)
+	if GetFocus() != grid[2][1] {
+		t.Errorf("stick after a disconnect moved the focus to %v", GetFocus())
+	}
+
+	// enter, the center of the d-pad and the A button activate the focused actor
+	*events = nil
+	sendKey(KeyEnter, 0)
+	sendKey(KeyDpadCenter, 1)
+	sendKey(KeyButtonA, 1)
+	sendKey(KeyButtonB, 1)
+	update(scene, 0)
+	if len(*events) != 3 || (*events)[0] != "c2 activated" {
+		t.Errorf("events %v", *events)
+	}
+
+	// the keys of the keyboard are left to an actor with the keyboard focus, the controllers still move the focus
+	SetKeyboardFocus(grid[2][1])
+	*events = nil
+	sendKey(KeyDpadUp, 0)
+	sendKey(KeyEnter, 0)
+	update(scene, 0)
+	if GetFocus() != grid[2][1] || len(*events) != 0 {
+		t.Errorf("typing moved the focus to %v with events %v", GetFocus(), *events)
+	}
+	sendKey(KeyDpadUp, 1)
+	update(scene, 0)
+	if GetFocus() != grid[1][1] || GetKeyboardFocus() != nil {
+		t.Errorf("controller moved the focus to %v, keyboard focus %v", GetFocus(), GetKeyboardFocus())
+	}
+}
+
+func TestFocusModal(t *testing.T) {
+	defer resetFocus()
+	scene, grid, events := newFocusGrid()
+	modal := &Actor{Name: "modal", X: 400, Y: 0, W: 200, H: 200}
+	modal.modal = true
+	ok := &Actor{Name: "ok", X: 10, Y: 10, W: 80, H: 30, Focusable: true}
+	cancel := &Actor{Name: "cancel", X: 110, Y: 10, W: 80, H: 30, Focusable: true}
+	cancel.Input = func(a *Actor, e InputEvent) {
+		if e.Type == Activated {
+			*events = append(*events, "cancel activated")
+		}
+	}
+	modal.AddActor(ok)
+	modal.AddActor(cancel)
+	scene.AddActor(modal)
+
+	// the focus outside of the modal actor is not activated and moves into it
+	SetFocus(grid[1][1])
+	*events = nil
+	sendKey(KeyEnter, 0)
+	update(scene, 0)
+	if len(*events) != 0 {
+		t.Errorf("actor behind the modal actor activated %v", *events)
+	}
+	if !MoveFocus(scene, -1, 0) || GetFocus() != ok {
+		t.Fatalf("focus moved to %v", GetFocus())
+	}
+	// and stays within it
+	if !MoveFocus(scene, 1, 0) || GetFocus() != cancel || MoveFocus(scene, 1, 0) || MoveFocus(scene, 0, 1) ||
+		!MoveFocus(scene, -1, 0) || GetFocus() != ok {
+		t.Errorf("focus left the modal actor to %v", GetFocus())
+	}
+	SetFocus(cancel)
+	*events = nil
+	sendKey(KeyEnter, 0)
+	update(scene, 0)
+	if len(*events) != 1 || (*events)[0] != "cancel activated" {
+		t.Errorf("events %v", *events)
+	}
+
+	// once it is hidden the focus moves to the rest of the scene again
+	modal.Hidden = true
+	if !MoveFocus(scene, 0, 1) || GetFocus() != grid[0][0] {
+		t.Errorf("focus moved to %v after the modal actor was hidden", GetFocus())
+	}
+}
+
+func TestScrollToFocus(t *testing.T) {
+	defer resetFocus()
+	pane := newTestScrollPane()
+	content := pane.content
+	top := &Actor{Name: "top", X: 10, Y: 570, W: 50, H: 20, Focusable: true}
+	bottom := &Actor{Name: "bottom", X: 10, Y: 10, W: 50, H: 20, Focusable: true}
+	content.AddActor(top)
+	content.AddActor(bottom)
+	scene := &Scene{Name: "focus"}
+	scene.AddActor(&pane.Actor)
+	pane.Validate()
+	if pane.GetScrollY() != 0 {
+		t.Fatalf("scrolled to %v", pane.GetScrollY())
+	}
+
+	// the focused actor is scrolled into the pane, the pane is not scrolled further than needed
+	if !MoveFocus(scene, 0, 1) || GetFocus() != top || !MoveFocus(scene, 0, -1) || GetFocus() != bottom {
+		t.Fatalf("focus moved to %v", GetFocus())
+	}
+	if pane.GetScrollY() != 440 {
+		t.Errorf("scrolled to %v to show the bottom actor", pane.GetScrollY())
+	}
+	SetFocus(top)
+	if pane.GetScrollY() != 10 {
+		t.Errorf("scrolled to %v to show the top actor", pane.GetScrollY())
+	}
+}
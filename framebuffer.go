@@ -0,0 +1,184 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/pyros2097/spike/g2d"
+	"golang.org/x/mobile/gl"
+)
+
+// The OpenGL functions the frame buffers and the post processing are drawn with. gl.Context implements it, tests can
+// record the calls instead.
+type GLContext interface {
+	ActiveTexture(texture gl.Enum)
+	AttachShader(p gl.Program, s gl.Shader)
+	BindBuffer(target gl.Enum, b gl.Buffer)
+	BindFramebuffer(target gl.Enum, fb gl.Framebuffer)
+	BindTexture(target gl.Enum, t gl.Texture)
+	BufferData(target gl.Enum, src []byte, usage gl.Enum)
+	CheckFramebufferStatus(target gl.Enum) gl.Enum
+	Clear(mask gl.Enum)
+	ClearColor(red, green, blue, alpha float32)
+	CompileShader(s gl.Shader)
+	CreateBuffer() gl.Buffer
+	CreateFramebuffer() gl.Framebuffer
+	CreateProgram() gl.Program
+	CreateShader(ty gl.Enum) gl.Shader
+	CreateTexture() gl.Texture
+	DeleteBuffer(v gl.Buffer)
+	DeleteFramebuffer(v gl.Framebuffer)
+	DeleteProgram(p gl.Program)
+	DeleteShader(s gl.Shader)
+	DeleteTexture(v gl.Texture)
+	Disable(cap gl.Enum)
+	DisableVertexAttribArray(a gl.Attrib)
+	DrawArrays(mode gl.Enum, first, count int)
+	Enable(cap gl.Enum)
+	EnableVertexAttribArray(a gl.Attrib)
+	FramebufferTexture2D(target, attachment, texTarget gl.Enum, t gl.Texture, level int)
+	GetAttribLocation(p gl.Program, name string) gl.Attrib
+	GetProgrami(p gl.Program, pname gl.Enum) int
+	GetProgramInfoLog(p gl.Program) string
+	GetShaderi(s gl.Shader, pname gl.Enum) int
+	GetShaderInfoLog(s gl.Shader) string
+	GetUniformLocation(p gl.Program, name string) gl.Uniform
+	IsEnabled(cap gl.Enum) bool
+	LinkProgram(p gl.Program)
+	ShaderSource(s gl.Shader, src string)
+	TexImage2D(target gl.Enum, level int, internalFormat int, width, height int, format gl.Enum, ty gl.Enum, data []byte)
+	TexParameteri(target, pname gl.Enum, param int)
+	Uniform1f(dst gl.Uniform, v float32)
+	Uniform1i(dst gl.Uniform, v int)
+	Uniform2f(dst gl.Uniform, v0, v1 float32)
+	Uniform3f(dst gl.Uniform, v0, v1, v2 float32)
+	Uniform4f(dst gl.Uniform, v0, v1, v2, v3 float32)
+	UniformMatrix4fv(dst gl.Uniform, src []float32)
+	UseProgram(p gl.Program)
+	VertexAttribPointer(dst gl.Attrib, size int, ty gl.Enum, normalized bool, stride, offset int)
+	Viewport(x, y, width, height int)
+}
+
+var _ GLContext = gl.Context(nil)
+
+// The frame buffers between Begin and End, the last one is drawn into
+var boundFrameBuffers []*FrameBuffer
+
+// A FrameBuffer is an offscreen texture which is drawn into instead of the screen between Begin and End. Frame buffers
+// can be nested, End goes back to the frame buffer before or to the screen.
+//
+//	fb, err := spike.NewFrameBuffer(glctx, 256, 256)
+//	...
+//	fb.DrawActor(batch, &minimap.Actor, minimapCamera)
+type FrameBuffer struct {
+	Width, Height int
+
+	// The texture of the frame buffer, for the size of the regions drawn from it
+	Texture *g2d.Texture
+
+	ctx         GLContext
+	framebuffer gl.Framebuffer
+	texture     gl.Texture
+}
+
+// Creates a frame buffer of the size in pixels with an RGBA texture. Returns an error if the frame buffer is not complete,
+// like when the size is not supported.
+func NewFrameBuffer(ctx GLContext, width, height int) (*FrameBuffer, error) {
+	self := &FrameBuffer{Width: width, Height: height, ctx: ctx, Texture: &g2d.Texture{Width: width, Height: height}}
+	self.texture = ctx.CreateTexture()
+	ctx.BindTexture(gl.TEXTURE_2D, self.texture)
+	ctx.TexImage2D(gl.TEXTURE_2D, 0, int(gl.RGBA), width, height, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	ctx.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	ctx.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	ctx.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	ctx.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	ctx.BindTexture(gl.TEXTURE_2D, gl.Texture{})
+	self.framebuffer = ctx.CreateFramebuffer()
+	ctx.BindFramebuffer(gl.FRAMEBUFFER, self.framebuffer)
+	ctx.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, self.texture, 0)
+	status := ctx.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	ctx.BindFramebuffer(gl.FRAMEBUFFER, boundFramebuffer())
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		self.Dispose()
+		return nil, errors.New("framebuffer: incomplete with status 0x" + strconv.FormatUint(uint64(status), 16))
+	}
+	return self, nil
+}
+
+// Returns the GL frame buffer drawn into, the default one of the screen if no frame buffer is bound
+func boundFramebuffer() gl.Framebuffer {
+	if n := len(boundFrameBuffers); n > 0 {
+		return boundFrameBuffers[n-1].framebuffer
+	}
+	return gl.Framebuffer{}
+}
+
+// Returns the GL texture of the frame buffer
+func (self *FrameBuffer) GetGLTexture() gl.Texture {
+	return self.texture
+}
+
+// Returns a region of the whole texture, flipped because the rows of frame buffers go up
+func (self *FrameBuffer) GetRegion() *g2d.TextureRegion {
+	region := g2d.NewTextureRegion(self.Texture, 0, 0, self.Width, self.Height)
+	region.Flip(false, true)
+	return region
+}
+
+// Draws into the frame buffer until End is called, the GL viewport is set to its size
+func (self *FrameBuffer) Begin() {
+	boundFrameBuffers = append(boundFrameBuffers, self)
+	self.ctx.BindFramebuffer(gl.FRAMEBUFFER, self.framebuffer)
+	self.ctx.Viewport(0, 0, self.Width, self.Height)
+}
+
+// Draws into the frame buffer bound before Begin again, or into the screen bounds of the viewport
+func (self *FrameBuffer) End() {
+	n := len(boundFrameBuffers)
+	if n == 0 || boundFrameBuffers[n-1] != self {
+		panic("framebuffer: End without Begin")
+	}
+	boundFrameBuffers[n-1] = nil
+	boundFrameBuffers = boundFrameBuffers[:n-1]
+	self.ctx.BindFramebuffer(gl.FRAMEBUFFER, boundFramebuffer())
+	if n > 1 {
+		previous := boundFrameBuffers[n-2]
+		self.ctx.Viewport(0, 0, previous.Width, previous.Height)
+	} else if viewport != nil {
+		self.ctx.Viewport(viewport.ScreenX, viewport.ScreenY, viewport.ScreenWidth, viewport.ScreenHeight)
+	} else {
+		self.ctx.Viewport(0, 0, screenWidth, screenHeight)
+	}
+}
+
+// Clears the frame buffer to the color, it must be bound
+func (self *FrameBuffer) Clear(color Color) {
+	self.ctx.ClearColor(color.R, color.G, color.B, color.A)
+	self.ctx.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
+
+// Clears the frame buffer and draws the actor and its children into it with the camera, the projection of the batch is
+// left set to the camera. The actors are clipped in the screen pixels of the viewport, clipping is only right in frame
+// buffers of its screen size.
+func (self *FrameBuffer) DrawActor(batch g2d.Batch, actor *Actor, camera *Camera) {
+	batch.Flush()
+	self.Begin()
+	self.Clear(Color{})
+	oldFrustum := cullFrustum
+	cullFrustum = camera.GetFrustum()
+	batch.SetProjectionMatrix(camera.Combined)
+	actor.draw(batch, 1.0)
+	batch.Flush()
+	cullFrustum = oldFrustum
+	self.End()
+}
+
+// Deletes the GL frame buffer and texture
+func (self *FrameBuffer) Dispose() {
+	self.ctx.DeleteFramebuffer(self.framebuffer)
+	self.ctx.DeleteTexture(self.texture)
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+import (
+	"github.com/pyros2097/spike/math/utils"
+)
+
+// The order in which the frames of an animation are played
+type PlayMode int
+
+const (
+	// Plays the frames once from the first to the last
+	PlayNormal PlayMode = iota
+
+	// Plays the frames once from the last to the first
+	PlayReversed
+
+	// Plays the frames from the first to the last over and over
+	PlayLoop
+
+	// Plays the frames from the last to the first over and over
+	PlayLoopReversed
+
+	// Plays the frames from the first to the last and back over and over, the first and last frames are not repeated
+	PlayLoopPingPong
+
+	// Plays random frames over and over
+	PlayLoopRandom
+)
+
+// A frame of an animation
+type AnimationFrame struct {
+	Region *AtlasRegion
+
+	// The time in seconds the frame is shown
+	Duration float32
+
+	// The name of the event fired when the frame is shown, if not empty
+	Event string
+}
+
+// An Animation is a sequence of frames which are shown for their duration each. It only holds the frames, the time an
+// animation has been played is kept by whoever plays it, like the AnimatedSprite, so an animation can be shared.
+type Animation struct {
+	Frames   []AnimationFrame
+	PlayMode PlayMode
+}
+
+// Creates an animation of the regions which are shown for the same duration
+func NewAnimation(frameDuration float32, playMode PlayMode, regions ...*AtlasRegion) *Animation {
+	animation := &Animation{Frames: make([]AnimationFrame, len(regions)), PlayMode: playMode}
+	for i, region := range regions {
+		animation.Frames[i] = AnimationFrame{Region: region, Duration: frameDuration}
+	}
+	return animation
+}
+
+// Creates an animation of the indexed regions of the atlas with the name, see TextureAtlas.FindRegions. Returns nil if
+// there are none.
+func NewAnimationFromAtlas(atlas *TextureAtlas, name string, frameDuration float32, playMode PlayMode) *Animation {
+	regions := atlas.FindRegions(name)
+	if len(regions) == 0 {
+		return nil
+	}
+	return NewAnimation(frameDuration, playMode, regions...)
+}
+
+// Sets the event fired when the frame is shown
+func (self *Animation) SetEvent(frame int, event string) {
+	self.Frames[frame].Event = event
+}
+
+// Returns the time in seconds to play the frames once, or for ping pong animations to play them there and back
+func (self *Animation) GetAnimationDuration() float32 {
+	duration := float32(0)
+	for i := 0; i < self.steps(); i++ {
+		duration += self.Frames[self.stepFrame(i)].Duration
+	}
+	return duration
+}
+
+// Returns the index of the frame shown after the animation has been played for the state time. Random animations return
+// a random frame.
+func (self *Animation) GetKeyFrameIndex(stateTime float32) int {
+	count := len(self.Frames)
+	if count <= 1 {
+		return 0
+	}
+	if self.PlayMode == PlayLoopRandom {
+		return utils.Random(count)
+	}
+	steps := self.steps()
+	duration := self.GetAnimationDuration()
+	if duration <= 0 {
+		return self.stepFrame(steps - 1)
+	}
+	switch self.PlayMode {
+	case PlayNormal, PlayReversed:
+		if stateTime >= duration {
+			return self.stepFrame(steps - 1)
+		}
+	default:
+		stateTime -= float32(int(stateTime/duration)) * duration
+	}
+	for i := 0; i < steps; i++ {
+		frame := self.stepFrame(i)
+		if stateTime < self.Frames[frame].Duration {
+			return frame
+		}
+		stateTime -= self.Frames[frame].Duration
+	}
+	return self.stepFrame(steps - 1)
+}
+
+// Returns the region of the frame shown after the animation has been played for the state time
+func (self *Animation) GetKeyFrame(stateTime float32) *AtlasRegion {
+	if len(self.Frames) == 0 {
+		return nil
+	}
+	return self.Frames[self.GetKeyFrameIndex(stateTime)].Region
+}
+
+// Returns whether the animation is over after it has been played for the state time, looping animations never are
+func (self *Animation) IsAnimationFinished(stateTime float32) bool {
+	switch self.PlayMode {
+	case PlayNormal, PlayReversed:
+		return stateTime >= self.GetAnimationDuration()
+	}
+	return false
+}
+
+// Returns the index of the first frame, which is random for random animations
+func (self *Animation) FirstKeyFrameIndex() int {
+	switch self.PlayMode {
+	case PlayReversed, PlayLoopReversed:
+		return len(self.Frames) - 1
+	case PlayLoopRandom:
+		return utils.Random(len(self.Frames))
+	}
+	return 0
+}
+
+// Returns the index of the frame shown after the frame. Forward is the direction ping pong animations are played in, the
+// next direction is returned with the next frame. ok is false if the frame is the last one of the animation.
+func (self *Animation) NextKeyFrameIndex(frame int, forward bool) (next int, nextForward bool, ok bool) {
+	count := len(self.Frames)
+	switch self.PlayMode {
+	case PlayNormal:
+		return frame + 1, forward, frame+1 < count
+	case PlayReversed:
+		return frame - 1, forward, frame > 0
+	case PlayLoop:
+		return (frame + 1) % count, forward, true
+	case PlayLoopReversed:
+		return (frame - 1 + count) % count, forward, true
+	case PlayLoopPingPong:
+		if count == 1 {
+			return 0, forward, true
+		}
+		if forward && frame+1 >= count || !forward && frame == 0 {
+			forward = !forward
+		}
+		if forward {
+			return frame + 1, true, true
+		}
+		return frame - 1, false, true
+	}
+	return utils.Random(count), forward, true
+}
+
+// Returns the number of frames shown to play the animation once
+func (self *Animation) steps() int {
+	count := len(self.Frames)
+	if self.PlayMode == PlayLoopPingPong && count > 2 {
+		return count*2 - 2
+	}
+	return count
+}
+
+// Returns the frame shown at the step of playing the animation once
+func (self *Animation) stepFrame(step int) int {
+	count := len(self.Frames)
+	switch self.PlayMode {
+	case PlayReversed, PlayLoopReversed:
+		return count - 1 - step
+	case PlayLoopPingPong:
+		if step >= count {
+			return count*2 - 2 - step
+		}
+	}
+	return step
+}
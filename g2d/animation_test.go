@@ -0,0 +1,150 @@
+package g2d
+
+import (
+	"testing"
+)
+
+// Returns an animation of count frames shown for a tenth of a second each
+func newTestAnimation(count int, playMode PlayMode) *Animation {
+	regions := make([]*AtlasRegion, count)
+	for i := range regions {
+		regions[i] = &AtlasRegion{}
+	}
+	return NewAnimation(0.1, playMode, regions...)
+}
+
+func TestKeyFrameIndex(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		playMode PlayMode
+		duration float32
+		// the frames shown at 0.05, 0.15, ... 0.95 seconds
+		frames []int
+	}{
+		{"normal", PlayNormal, 0.4, []int{0, 1, 2, 3, 3, 3, 3, 3, 3, 3}},
+		{"reversed", PlayReversed, 0.4, []int{3, 2, 1, 0, 0, 0, 0, 0, 0, 0}},
+		{"loop", PlayLoop, 0.4, []int{0, 1, 2, 3, 0, 1, 2, 3, 0, 1}},
+		{"loop reversed", PlayLoopReversed, 0.4, []int{3, 2, 1, 0, 3, 2, 1, 0, 3, 2}},
+		// the first and last frames are not repeated when the direction changes
+		{"ping pong", PlayLoopPingPong, 0.6, []int{0, 1, 2, 3, 2, 1, 0, 1, 2, 3}},
+	} {
+		animation := newTestAnimation(4, c.playMode)
+		if duration := animation.GetAnimationDuration(); duration < c.duration-0.0001 || duration > c.duration+0.0001 {
+			t.Errorf("%s duration %v, expected %v", c.name, duration, c.duration)
+		}
+		for i, want := range c.frames {
+			stateTime := 0.05 + float32(i)*0.1
+			if frame := animation.GetKeyFrameIndex(stateTime); frame != want {
+				t.Errorf("%s frame at %v is %d, expected %d", c.name, stateTime, frame, want)
+			}
+		}
+		if region := animation.GetKeyFrame(0.15); region != animation.Frames[c.frames[1]].Region {
+			t.Errorf("%s region at 0.15 is not the one of frame %d", c.name, c.frames[1])
+		}
+	}
+
+	random := newTestAnimation(4, PlayLoopRandom)
+	for i := 0; i < 20; i++ {
+		if frame := random.GetKeyFrameIndex(float32(i) * 0.1); frame < 0 || frame > 3 {
+			t.Errorf("random frame %d", frame)
+		}
+	}
+
+	// frames with their own durations
+	animation := newTestAnimation(3, PlayNormal)
+	animation.Frames[1].Duration = 0.3
+	for _, c := range []struct {
+		stateTime float32
+		frame     int
+	}{{0.05, 0}, {0.15, 1}, {0.35, 1}, {0.45, 2}} {
+		if frame := animation.GetKeyFrameIndex(c.stateTime); frame != c.frame {
+			t.Errorf("frame at %v is %d, expected %d", c.stateTime, frame, c.frame)
+		}
+	}
+}
+
+func TestPingPongSteps(t *testing.T) {
+	for _, c := range []struct {
+		count    int
+		duration float32
+		frames   []int
+	}{
+		{1, 0.1, []int{0, 0, 0}},
+		// two frames alternate without a step back
+		{2, 0.2, []int{0, 1, 0, 1}},
+		{3, 0.4, []int{0, 1, 2, 1, 0, 1}},
+	} {
+		animation := newTestAnimation(c.count, PlayLoopPingPong)
+		if duration := animation.GetAnimationDuration(); duration < c.duration-0.0001 || duration > c.duration+0.0001 {
+			t.Errorf("%d frames take %v, expected %v", c.count, duration, c.duration)
+		}
+		frame, forward := animation.FirstKeyFrameIndex(), true
+		for i, want := range c.frames {
+			if frame != want {
+				t.Errorf("%d frames, step %d shows %d, expected %d", c.count, i, frame, want)
+			}
+			if stateTime := 0.05 + float32(i)*0.1; animation.GetKeyFrameIndex(stateTime) != want {
+				t.Errorf("%d frames, frame at %v is %d, expected %d", c.count, stateTime,
+					animation.GetKeyFrameIndex(stateTime), want)
+			}
+			var ok bool
+			if frame, forward, ok = animation.NextKeyFrameIndex(frame, forward); !ok {
+				t.Errorf("%d frames, ping pong ended at step %d", c.count, i)
+			}
+		}
+	}
+}
+
+func TestNextKeyFrameIndex(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		playMode PlayMode
+		first    int
+		// the frames after the first, the animation ends after the last one unless it loops
+		frames []int
+		loops  bool
+	}{
+		{"normal", PlayNormal, 0, []int{1, 2, 3}, false},
+		{"reversed", PlayReversed, 3, []int{2, 1, 0}, false},
+		{"loop", PlayLoop, 0, []int{1, 2, 3, 0, 1}, true},
+		{"loop reversed", PlayLoopReversed, 3, []int{2, 1, 0, 3, 2}, true},
+		{"ping pong", PlayLoopPingPong, 0, []int{1, 2, 3, 2, 1, 0, 1}, true},
+	} {
+		animation := newTestAnimation(4, c.playMode)
+		frame, forward := animation.FirstKeyFrameIndex(), true
+		if frame != c.first {
+			t.Errorf("%s starts at %d", c.name, frame)
+		}
+		for _, want := range c.frames {
+			next, nextForward, ok := animation.NextKeyFrameIndex(frame, forward)
+			if !ok || next != want {
+				t.Errorf("%s after %d shows %d %v, expected %d", c.name, frame, next, ok, want)
+			}
+			frame, forward = next, nextForward
+		}
+		if _, _, ok := animation.NextKeyFrameIndex(frame, forward); ok != c.loops {
+			t.Errorf("%s after the last frame %d continues %v", c.name, frame, ok)
+		}
+	}
+
+	random := newTestAnimation(4, PlayLoopRandom)
+	for i := 0; i < 20; i++ {
+		if next, _, ok := random.NextKeyFrameIndex(i%4, true); !ok || next < 0 || next > 3 {
+			t.Errorf("random next frame %d %v", next, ok)
+		}
+	}
+}
+
+func TestIsAnimationFinished(t *testing.T) {
+	for _, playMode := range []PlayMode{PlayNormal, PlayReversed} {
+		animation := newTestAnimation(4, playMode)
+		if animation.IsAnimationFinished(0.35) || !animation.IsAnimationFinished(0.41) {
+			t.Errorf("play mode %d finished wrong", playMode)
+		}
+	}
+	for _, playMode := range []PlayMode{PlayLoop, PlayLoopReversed, PlayLoopPingPong, PlayLoopRandom} {
+		if newTestAnimation(4, playMode).IsAnimationFinished(100) {
+			t.Errorf("looping play mode %d finished", playMode)
+		}
+	}
+}
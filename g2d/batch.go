@@ -4,6 +4,10 @@
 
 package g2d
 
+import (
+	"github.com/pyros2097/spike/math/vector"
+)
+
 const (
 	X1 = 0
 	Y1 = 1
@@ -27,9 +31,49 @@ const (
 	V4 = 19
 )
 
+// The blend factors sprites are drawn with, they have the values of the OpenGL constants
+const (
+	BlendZero             = 0
+	BlendOne              = 1
+	BlendSrcAlpha         = 0x0302
+	BlendOneMinusSrcAlpha = 0x0303
+)
+
 type Batch interface {
 	Begin()
 	End()
+
+	// Returns the transform matrix of the batch, the sprites are drawn transformed by it
+	GetTransformMatrix() *vector.Matrix4
+
+	// Sets the transform matrix to be used by this Batch, the values are copied
+	SetTransformMatrix(transform *vector.Matrix4)
+
+	// Returns the projection matrix of the batch, like the combined matrix of the camera the sprites are drawn with
+	GetProjectionMatrix() *vector.Matrix4
+
+	// Sets the projection matrix to be used by this Batch, the values are copied. The sprites batched so far are drawn
+	// with the old one.
+	SetProjectionMatrix(projection *vector.Matrix4)
+
+	// Sets the color the regions are tinted with, the alpha is multiplied with theirs
+	SetColor(r, g, b, a float32)
+
+	GetColor() Color
+
+	// Draws the region stretched to the width and height, its bottom left corner at the origin transformed by the transform
+	Draw(region *TextureRegion, width, height float32, transform *vector.Affine2)
+
+	// Draws quads of the texture, each made of 4 vertices in the X1 to V4 layout with the colors packed by
+	// Color.ToFloatBits. The vertices are in the coordinates of the transform matrix and the color of the batch is not
+	// applied.
+	DrawVertices(texture *Texture, vertices []float32)
+
+	// Sets the blend factors of the sprites drawn after, default is BlendSrcAlpha and BlendOneMinusSrcAlpha
+	SetBlendFunction(srcFunc, dstFunc int)
+
+	// Draws the sprites batched so far, it is called before the OpenGL state they are drawn with is changed
+	Flush()
 }
 
 /** A Batch is used to draw 2D rectangles that reference a texture (region). The class will batch the drawing commands and optimize
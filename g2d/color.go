@@ -0,0 +1,21 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+import (
+	"math"
+)
+
+// A color with the red, green, blue and alpha components in the range [0, 1]
+type Color struct {
+	R, G, B, A float32
+}
+
+// Packs the components in a 32-bit integer with the format ABGR and returns it as a float, as the vertices store colors.
+// The lowest bit of the alpha is dropped so that the float is never a NaN.
+func (self Color) ToFloatBits() float32 {
+	bits := uint32(255*self.A)<<24 | uint32(255*self.B)<<16 | uint32(255*self.G)<<8 | uint32(255*self.R)
+	return math.Float32frombits(bits & 0xfeffffff)
+}
@@ -0,0 +1,377 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	mathutils "github.com/pyros2097/spike/math/utils"
+	"github.com/pyros2097/spike/math/vector"
+	"github.com/pyros2097/spike/utils"
+)
+
+// A bitmap font draws text with the images of its characters packed in a few textures, the pages. It is read from the
+// text files written by BMFont and Hiero:
+//
+//	info face="Arial" size=-15 bold=0 italic=0
+//	common lineHeight=18 base=14 scaleW=256 scaleH=512 pages=1
+//	page id=0 file="arial-15.png"
+//	char id=65 x=2 y=2 width=10 height=11 xoffset=0 yoffset=3 xadvance=10 page=0 chnl=15
+//	kerning first=65 second=86 amount=-1
+//
+// The offsets of the glyphs are from the top of the line, y goes down in the file like in the images.
+
+// A Glyph is the image of a character of a bitmap font
+type Glyph struct {
+	TextureRegion
+
+	ID rune
+
+	// The size of the image in pixels
+	Width, Height int
+
+	// The offset of the image from the pen position, y from the top of the line going down
+	XOffset, YOffset int
+
+	// How far the pen moves after the character
+	XAdvance int
+
+	// The page the image is on
+	Page int
+
+	// The adjustment of the advance for the characters which may follow this one
+	kerning map[rune]int
+}
+
+// Returns the adjustment of the advance between this character and the next one
+func (self *Glyph) GetKerning(next rune) int {
+	return self.kerning[next]
+}
+
+// A BitmapFont holds the pages and the glyphs of a font
+type BitmapFont struct {
+	Pages  []*Texture
+	Glyphs map[rune]*Glyph
+
+	// The distance between the tops of two lines in pixels
+	LineHeight float32
+
+	// The distance from the top of a line to the baseline in pixels
+	Base float32
+
+	// The scale the glyphs are measured and drawn with. Default is 1.
+	ScaleX, ScaleY float32
+
+	// The glyph drawn for the characters the font does not have, nil to leave them out
+	MissingGlyph *Glyph
+}
+
+var fontTransform = vector.NewAffine2Empty()
+
+// Reads a bitmap font, the paths of the pages are relative to the images directory
+func ReadBitmapFont(r io.Reader, imagesDir string) (*BitmapFont, error) {
+	font := &BitmapFont{Glyphs: map[rune]*Glyph{}, ScaleX: 1, ScaleY: 1}
+	pages := map[int]*Texture{}
+	var width, height int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		tag, fields := fontFields(scanner.Text())
+		switch tag {
+		case "common":
+			font.LineHeight = float32(fontInt(fields, "lineHeight"))
+			font.Base = float32(fontInt(fields, "base"))
+			width, height = fontInt(fields, "scaleW"), fontInt(fields, "scaleH")
+		case "page":
+			file, ok := fields["file"]
+			if !ok {
+				return nil, errors.New("bitmap font: missing file of page " + fields["id"])
+			}
+			page := &Texture{Path: path.Join(imagesDir, file), Width: width, Height: height}
+			pages[fontInt(fields, "id")] = page
+			font.Pages = append(font.Pages, page)
+		case "char":
+			if width == 0 || height == 0 {
+				return nil, errors.New("bitmap font: char before the common line")
+			}
+			glyph := &Glyph{
+				ID:       rune(fontInt(fields, "id")),
+				Width:    fontInt(fields, "width"),
+				Height:   fontInt(fields, "height"),
+				XOffset:  fontInt(fields, "xoffset"),
+				YOffset:  fontInt(fields, "yoffset"),
+				XAdvance: fontInt(fields, "xadvance"),
+				Page:     fontInt(fields, "page"),
+			}
+			page, ok := pages[glyph.Page]
+			if !ok {
+				return nil, errors.New("bitmap font: missing page " + fields["page"] + " of char " + fields["id"])
+			}
+			glyph.Texture = page
+			glyph.SetRegion(fontInt(fields, "x"), fontInt(fields, "y"), glyph.Width, glyph.Height)
+			font.Glyphs[glyph.ID] = glyph
+		case "kerning":
+			if glyph := font.Glyphs[rune(fontInt(fields, "first"))]; glyph != nil {
+				if glyph.kerning == nil {
+					glyph.kerning = map[rune]int{}
+				}
+				glyph.kerning[rune(fontInt(fields, "second"))] = fontInt(fields, "amount")
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(font.Pages) == 0 {
+		return nil, errors.New("bitmap font: no pages")
+	}
+	if font.LineHeight == 0 {
+		return nil, errors.New("bitmap font: missing line height")
+	}
+	return font, nil
+}
+
+// Splits a line of a font file into its tag and its key=value fields, the quotes of the values are removed
+func fontFields(line string) (string, map[string]string) {
+	line = strings.TrimSpace(line)
+	space := strings.IndexByte(line, ' ')
+	if space < 0 {
+		return line, nil
+	}
+	fields := map[string]string{}
+	rest := line[space+1:]
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		equals := strings.IndexByte(rest, '=')
+		if equals < 0 {
+			break
+		}
+		key := rest[:equals]
+		rest = rest[equals+1:]
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			value, rest = rest[1:end+1], rest[end+1:]
+			rest = strings.TrimPrefix(rest, "\"")
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		fields[key] = value
+	}
+	return line[:space], fields
+}
+
+// Returns the integer value of a field, 0 if it is missing or not an integer
+func fontInt(fields map[string]string, key string) int {
+	n, _ := strconv.Atoi(fields[key])
+	return n
+}
+
+// Returns the glyph of the character, the missing glyph if the font does not have it
+func (self *BitmapFont) GetGlyph(c rune) *Glyph {
+	if glyph, ok := self.Glyphs[c]; ok {
+		return glyph
+	}
+	return self.MissingGlyph
+}
+
+// Returns whether the font has a glyph for the character
+func (self *BitmapFont) HasGlyph(c rune) bool {
+	_, ok := self.Glyphs[c]
+	return ok
+}
+
+func (self *BitmapFont) SetScale(scaleX, scaleY float32) {
+	self.ScaleX, self.ScaleY = scaleX, scaleY
+}
+
+// Returns the distance between the tops of two lines with the scale
+func (self *BitmapFont) GetLineHeight() float32 {
+	return self.LineHeight * self.ScaleY
+}
+
+// Returns the positions of the characters of the text on a single line, from the start of the line with the scale. There
+// is one more position than characters, the last one is the width of the text.
+func (self *BitmapFont) ComputeGlyphPositions(text []rune, positions []float32) []float32 {
+	positions = append(positions[:0], 0)
+	x := float32(0)
+	for i, c := range text {
+		glyph := self.GetGlyph(c)
+		if glyph != nil {
+			advance := glyph.XAdvance
+			if i+1 < len(text) {
+				advance += glyph.GetKerning(text[i+1])
+			}
+			x += float32(advance) * self.ScaleX
+		}
+		positions = append(positions, x)
+	}
+	return positions
+}
+
+// Returns the width of the text on a single line with the scale
+func (self *BitmapFont) TextWidth(text []rune) float32 {
+	x := float32(0)
+	for i, c := range text {
+		if glyph := self.GetGlyph(c); glyph != nil {
+			advance := glyph.XAdvance
+			if i+1 < len(text) {
+				advance += glyph.GetKerning(text[i+1])
+			}
+			x += float32(advance) * self.ScaleX
+		}
+	}
+	return x
+}
+
+// Draws the text on a single line with the scale, from the top left corner at x, y transformed by the transform. The
+// batch color is the color of the text.
+func (self *BitmapFont) Draw(batch Batch, text []rune, x, y float32, transform *vector.Affine2) {
+	for i, c := range text {
+		glyph := self.GetGlyph(c)
+		if glyph == nil {
+			continue
+		}
+		self.drawGlyph(batch, glyph, x, y, transform)
+		advance := glyph.XAdvance
+		if i+1 < len(text) {
+			advance += glyph.GetKerning(text[i+1])
+		}
+		x += float32(advance) * self.ScaleX
+	}
+}
+
+// Draws the glyph with the pen at x and the top of the line at y
+func (self *BitmapFont) drawGlyph(batch Batch, glyph *Glyph, x, y float32, transform *vector.Affine2) {
+	if glyph.Width == 0 || glyph.Height == 0 {
+		return
+	}
+	width, height := float32(glyph.Width)*self.ScaleX, float32(glyph.Height)*self.ScaleY
+	fontTransform.Set(transform).Translate(x+float32(glyph.XOffset)*self.ScaleX, y-float32(glyph.YOffset)*self.ScaleY-height)
+	batch.Draw(&glyph.TextureRegion, width, height, fontTransform)
+}
+
+// A GlyphRun is a line of text laid out by a GlyphLayout
+type GlyphRun struct {
+	Text []rune
+
+	// The position of the left edge of the line from the left of the layout, and of its top from the top of the
+	// layout going down
+	X, Y float32
+
+	Width float32
+}
+
+// A GlyphLayout breaks text into lines which fit a width and aligns them, to measure and draw text of several lines
+type GlyphLayout struct {
+	Runs []GlyphRun
+
+	// The size of the text, the width of the widest line and the height of all the lines
+	Width, Height float32
+}
+
+// Lays the text out with the font, lines are broken at newlines. If wrap is set the lines are also broken at the spaces,
+// or in words which do not fit, to fit the target width. Otherwise, if the ellipsis is not empty the lines which do not
+// fit are cut and end with it. The lines are aligned horizontally to each other in the width of the widest line.
+func (self *GlyphLayout) SetText(font *BitmapFont, text string, targetWidth float32, halign utils.Alignment, wrap bool,
+	ellipsis string) {
+	self.Runs = self.Runs[:0]
+	self.Width, self.Height = 0, 0
+	lineHeight := font.GetLineHeight()
+	y := float32(0)
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for {
+			var rest []rune
+			if wrap && targetWidth > 0 {
+				runes, rest = wrapLine(font, runes, targetWidth)
+			} else if ellipsis != "" && font.TextWidth(runes) > targetWidth {
+				runes = truncateLine(font, runes, targetWidth, []rune(ellipsis))
+			}
+			width := font.TextWidth(runes)
+			self.Runs = append(self.Runs, GlyphRun{Text: runes, Y: y, Width: width})
+			self.Width = mathutils.MaxFloat32(self.Width, width)
+			y += lineHeight
+			if len(rest) == 0 {
+				break
+			}
+			runes = rest
+		}
+	}
+	self.Height = y
+	if halign&utils.AlignmentLeft != 0 {
+		return
+	}
+	for i := range self.Runs {
+		run := &self.Runs[i]
+		if halign&utils.AlignmentRight != 0 {
+			run.X = self.Width - run.Width
+		} else {
+			run.X = (self.Width - run.Width) / 2
+		}
+	}
+}
+
+// Breaks the line at the last space which fits the width, or in the word if there is none, and returns the line without
+// the trailing spaces and the rest without the leading ones
+func wrapLine(font *BitmapFont, runes []rune, width float32) ([]rune, []rune) {
+	positions := font.ComputeGlyphPositions(runes, nil)
+	end := len(runes)
+	for end > 0 && positions[end] > width {
+		end--
+	}
+	if end == len(runes) {
+		return runes, nil
+	}
+	// break after the last space which fits, or in the word keeping at least one character
+	i := end
+	for i > 0 && runes[i] != ' ' {
+		i--
+	}
+	if i > 0 {
+		end = i
+	} else if end == 0 {
+		end = 1
+	}
+	line, rest := runes[:end], runes[end:]
+	for len(line) > 0 && line[len(line)-1] == ' ' {
+		line = line[:len(line)-1]
+	}
+	for len(rest) > 0 && rest[0] == ' ' {
+		rest = rest[1:]
+	}
+	return line, rest
+}
+
+// Cuts the line so that it fits the width with the ellipsis at its end
+func truncateLine(font *BitmapFont, runes []rune, width float32, ellipsis []rune) []rune {
+	width -= font.TextWidth(ellipsis)
+	positions := font.ComputeGlyphPositions(runes, nil)
+	end := len(runes)
+	for end > 0 && positions[end] > width {
+		end--
+	}
+	line := make([]rune, 0, end+len(ellipsis))
+	return append(append(line, runes[:end]...), ellipsis...)
+}
+
+// Draws the text laid out by the layout with the font, the top left corner of the layout at x, y transformed by the
+// transform
+func (self *BitmapFont) DrawLayout(batch Batch, layout *GlyphLayout, x, y float32, transform *vector.Affine2) {
+	for _, run := range layout.Runs {
+		self.Draw(batch, run.Text, x+run.X, y-run.Y, transform)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+import (
+	"errors"
+	"image"
+
+	"github.com/pyros2097/spike/math/utils"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// The patches of a nine patch, by row from the top left one
+const (
+	PatchTopLeft = iota
+	PatchTopCenter
+	PatchTopRight
+	PatchMiddleLeft
+	PatchMiddleCenter
+	PatchMiddleRight
+	PatchBottomLeft
+	PatchBottomCenter
+	PatchBottomRight
+)
+
+// A NinePatch draws a region at any size by cutting it in a grid of 3 by 3 patches. The corners keep their size, the top
+// and bottom edges are stretched horizontally, the left and right edges vertically and the center in both directions.
+// If the nine patch is drawn smaller than its corners they are shrunk.
+//
+// The padding is the space around the content drawn over the nine patch, like the text of a button, it is the size of the
+// borders unless it is set.
+type NinePatch struct {
+	Texture *Texture
+
+	// The regions of the patches, nil for the patches which are empty
+	Patches [9]*TextureRegion
+
+	// The widths of the columns and the heights of the rows of patches in the region
+	LeftWidth, MiddleWidth, RightWidth    float32
+	TopHeight, MiddleHeight, BottomHeight float32
+
+	// If set the edges and the center are repeated at their size instead of stretched, the last ones are cut
+	TileEdges, TileCenter bool
+
+	// The color the vertices are tinted with, multiplied by the color of the batch. Default is white.
+	Color Color
+
+	padLeft, padRight, padTop, padBottom float32
+	vertices                             []float32
+	point                                *vector.Vector2
+}
+
+// Creates a nine patch of the region whose borders are the number of pixels from its edges
+func NewNinePatch(region *TextureRegion, left, right, top, bottom int) *NinePatch {
+	self := &NinePatch{Texture: region.Texture, Color: Color{1, 1, 1, 1}, padLeft: -1, padRight: -1, padTop: -1,
+		padBottom: -1, point: vector.NewVector2Empty()}
+	x, y := region.GetRegionX(), region.GetRegionY()
+	middleWidth := region.RegionWidth - left - right
+	middleHeight := region.RegionHeight - top - bottom
+	columns := [3][2]int{{x, left}, {x + left, middleWidth}, {x + left + middleWidth, right}}
+	rows := [3][2]int{{y, top}, {y + top, middleHeight}, {y + top + middleHeight, bottom}}
+	for row := range rows {
+		for column := range columns {
+			if columns[column][1] <= 0 || rows[row][1] <= 0 {
+				continue
+			}
+			self.Patches[row*3+column] = NewTextureRegion(region.Texture, columns[column][0], rows[row][0], columns[column][1],
+				rows[row][1])
+		}
+	}
+	self.LeftWidth, self.MiddleWidth, self.RightWidth = float32(left), float32(middleWidth), float32(right)
+	self.TopHeight, self.MiddleHeight, self.BottomHeight = float32(top), float32(middleHeight), float32(bottom)
+	return self
+}
+
+// Creates a nine patch of a region of an atlas with the splits and padding written by the texture packer
+func NewNinePatchAtlas(region *AtlasRegion) (*NinePatch, error) {
+	if len(region.Splits) != 4 {
+		return nil, errors.New("nine patch: region has no splits " + region.Name)
+	}
+	if region.Rotate {
+		return nil, errors.New("nine patch: region is rotated " + region.Name)
+	}
+	splits := region.Splits
+	self := NewNinePatch(&region.TextureRegion, splits[0], splits[1], splits[2], splits[3])
+	if len(region.Pads) == 4 {
+		pads := region.Pads
+		self.SetPadding(float32(pads[0]), float32(pads[1]), float32(pads[2]), float32(pads[3]))
+	}
+	return self, nil
+}
+
+// Creates a nine patch of an Android .9.png image, which is the texture. The black pixels of its top and left border mark
+// the columns and rows which are stretched and the black pixels of its bottom and right border mark the content, which is
+// the padding. The border is not drawn.
+func NewNinePatchImage(texture *Texture, img image.Image) (*NinePatch, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx()-2, bounds.Dy()-2
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("nine patch: image too small " + texture.Path)
+	}
+	black := func(x, y int) bool {
+		r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		return r == 0 && g == 0 && b == 0 && a == 0xffff
+	}
+	// the first and last marked pixels of the border, along x if vertical is false
+	marks := func(vertical bool, at, length int) (first, last int) {
+		first, last = -1, -1
+		for i := 1; i <= length; i++ {
+			marked := black(i, at)
+			if vertical {
+				marked = black(at, i)
+			}
+			if marked {
+				if first < 0 {
+					first = i - 1
+				}
+				last = i - 1
+			}
+		}
+		return
+	}
+	left, right := marks(false, 0, width)
+	top, bottom := marks(true, 0, height)
+	if left < 0 || top < 0 {
+		return nil, errors.New("nine patch: no splits in the border of " + texture.Path)
+	}
+	self := NewNinePatch(NewTextureRegion(texture, 1, 1, width, height), left, width-1-right, top, height-1-bottom)
+	padLeft, padRight := marks(false, height+1, width)
+	padTop, padBottom := marks(true, width+1, height)
+	if padLeft >= 0 {
+		self.padLeft, self.padRight = float32(padLeft), float32(width-1-padRight)
+	}
+	if padTop >= 0 {
+		self.padTop, self.padBottom = float32(padTop), float32(height-1-padBottom)
+	}
+	return self, nil
+}
+
+// Creates a copy of the nine patch which shares its patches, its color and padding can be changed on their own
+func NewNinePatchCopy(patch *NinePatch) *NinePatch {
+	self := *patch
+	self.vertices, self.point = nil, vector.NewVector2Empty()
+	return &self
+}
+
+// Sets the space around the content drawn over the nine patch
+func (self *NinePatch) SetPadding(left, right, top, bottom float32) {
+	self.padLeft, self.padRight, self.padTop, self.padBottom = left, right, top, bottom
+}
+
+// Returns the left padding, the width of the left border unless it is set
+func (self *NinePatch) GetPadLeft() float32 {
+	if self.padLeft < 0 {
+		return self.LeftWidth
+	}
+	return self.padLeft
+}
+
+// Returns the right padding, the width of the right border unless it is set
+func (self *NinePatch) GetPadRight() float32 {
+	if self.padRight < 0 {
+		return self.RightWidth
+	}
+	return self.padRight
+}
+
+// Returns the top padding, the height of the top border unless it is set
+func (self *NinePatch) GetPadTop() float32 {
+	if self.padTop < 0 {
+		return self.TopHeight
+	}
+	return self.padTop
+}
+
+// Returns the bottom padding, the height of the bottom border unless it is set
+func (self *NinePatch) GetPadBottom() float32 {
+	if self.padBottom < 0 {
+		return self.BottomHeight
+	}
+	return self.padBottom
+}
+
+// Returns the width of the borders, the nine patch is not drawn smaller without shrinking them
+func (self *NinePatch) GetMinWidth() float32 {
+	return self.LeftWidth + self.RightWidth
+}
+
+// Returns the height of the borders, the nine patch is not drawn smaller without shrinking them
+func (self *NinePatch) GetMinHeight() float32 {
+	return self.TopHeight + self.BottomHeight
+}
+
+// Returns the width of the region the nine patch was made of
+func (self *NinePatch) GetTotalWidth() float32 {
+	return self.LeftWidth + self.MiddleWidth + self.RightWidth
+}
+
+// Returns the height of the region the nine patch was made of
+func (self *NinePatch) GetTotalHeight() float32 {
+	return self.TopHeight + self.MiddleHeight + self.BottomHeight
+}
+
+// Draws the nine patch at the width and height, its bottom left corner at the origin transformed by the transform
+func (self *NinePatch) Draw(batch Batch, width, height float32, transform *vector.Affine2) {
+	self.vertices = self.vertices[:0]
+	left, right := shrink(self.LeftWidth, self.RightWidth, width)
+	top, bottom := shrink(self.TopHeight, self.BottomHeight, height)
+	columns := [3][2]float32{{0, left}, {left, width - left - right}, {width - right, right}}
+	rows := [3][2]float32{{height - top, top}, {bottom, height - top - bottom}, {0, bottom}}
+	// DrawVertices does not apply the color of the batch, so that the alpha of the actors fades the nine patch
+	tint := batch.GetColor()
+	color := Color{self.Color.R * tint.R, self.Color.G * tint.G, self.Color.B * tint.B, self.Color.A * tint.A}.ToFloatBits()
+	for row := range rows {
+		for column := range columns {
+			patch := self.Patches[row*3+column]
+			x, w := columns[column][0], columns[column][1]
+			y, h := rows[row][0], rows[row][1]
+			if patch == nil || w <= 0 || h <= 0 {
+				continue
+			}
+			tile := self.TileEdges
+			if row == 1 && column == 1 {
+				tile = self.TileCenter
+			}
+			if !tile {
+				self.addQuad(patch.U, patch.V, patch.U2, patch.V2, x, y, w, h, color)
+				continue
+			}
+			// only the stretched directions are tiled, the corners and the other sides of the edges keep their size
+			tileW, tileH := w, h
+			if column == 1 {
+				tileW = float32(patch.RegionWidth)
+			}
+			if row == 1 {
+				tileH = float32(patch.RegionHeight)
+			}
+			for ty := float32(0); ty < h; ty += tileH {
+				for tx := float32(0); tx < w; tx += tileW {
+					partW, partH := utils.MinFloat32(tileW, w-tx), utils.MinFloat32(tileH, h-ty)
+					// the last tiles are cut, keeping their left and bottom sides
+					u2 := patch.U + (patch.U2-patch.U)*partW/tileW
+					v := patch.V2 + (patch.V-patch.V2)*partH/tileH
+					self.addQuad(patch.U, v, u2, patch.V2, x+tx, y+ty, partW, partH, color)
+				}
+			}
+		}
+	}
+	for i := 0; i < len(self.vertices); i += V1 - X1 + 1 {
+		transform.ApplyTo(self.point.Set(self.vertices[i], self.vertices[i+1]))
+		self.vertices[i], self.vertices[i+1] = self.point.X, self.point.Y
+	}
+	batch.DrawVertices(self.Texture, self.vertices)
+}
+
+// Adds the vertices of a quad at x, y with the texture coordinates of its top left and bottom right corners
+func (self *NinePatch) addQuad(u, v, u2, v2, x, y, width, height, color float32) {
+	self.vertices = append(self.vertices,
+		x, y, color, u, v2,
+		x, y+height, color, u, v,
+		x+width, y+height, color, u2, v,
+		x+width, y, color, u2, v2)
+}
+
+// Returns the sizes of the borders shrunk in proportion so that they fit in the size
+func shrink(first, second, size float32) (float32, float32) {
+	if first+second <= size || first+second == 0 {
+		return first, second
+	}
+	scale := size / (first + second)
+	return first * scale, second * scale
+}
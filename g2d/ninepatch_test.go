@@ -0,0 +1,224 @@
+package g2d
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// A batch which keeps the vertices drawn with DrawVertices
+type vertexBatch struct {
+	Batch
+	color    Color
+	texture  *Texture
+	vertices []float32
+}
+
+func newVertexBatch() *vertexBatch {
+	return &vertexBatch{color: Color{1, 1, 1, 1}}
+}
+
+func (self *vertexBatch) SetColor(r, g, b, a float32) {
+	self.color = Color{r, g, b, a}
+}
+
+func (self *vertexBatch) GetColor() Color {
+	return self.color
+}
+
+func (self *vertexBatch) DrawVertices(texture *Texture, vertices []float32) {
+	self.texture = texture
+	self.vertices = append(self.vertices, vertices...)
+}
+
+// The x, y, width, height and texture coordinates U, V, U2, V2 of a quad drawn by a nine patch
+type patchQuad [8]float32
+
+// Returns the quads of the vertices, from their bottom left and top right corners
+func patchQuads(vertices []float32) []patchQuad {
+	var quads []patchQuad
+	for i := 0; i+V4 < len(vertices); i += V4 + 1 {
+		q := vertices[i:]
+		quads = append(quads, patchQuad{q[X1], q[Y1], q[X3] - q[X1], q[Y3] - q[Y1], q[U1], q[V3], q[U3], q[V1]})
+	}
+	return quads
+}
+
+func samePatchQuad(a, b patchQuad) bool {
+	for i := range a {
+		if !near(a[i], b[i], 0.0001) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkPatchQuads(t *testing.T, name string, vertices []float32, expected []patchQuad) {
+	quads := patchQuads(vertices)
+	if len(quads) != len(expected) {
+		t.Errorf("%s: %d quads %v", name, len(quads), quads)
+		return
+	}
+	for i := range quads {
+		if !samePatchQuad(quads[i], expected[i]) {
+			t.Errorf("%s: quad %d is %v, expected %v", name, i, quads[i], expected[i])
+		}
+	}
+}
+
+func TestNinePatchDraw(t *testing.T) {
+	texture := &Texture{Width: 30, Height: 30}
+	patch := NewNinePatch(NewTextureRegion(texture, 0, 0, 30, 30), 10, 10, 10, 10)
+	third, twoThirds := float32(1)/3, float32(2)/3
+	batch := newVertexBatch()
+	patch.Draw(batch, 50, 40, vector.NewAffine2Empty())
+	if batch.texture != texture {
+		t.Error("drawn with another texture")
+	}
+	// the rows from the top, the corners keep their size
+	checkPatchQuads(t, "stretched", batch.vertices, []patchQuad{
+		{0, 30, 10, 10, 0, 0, third, third}, {10, 30, 30, 10, third, 0, twoThirds, third},
+		{40, 30, 10, 10, twoThirds, 0, 1, third},
+		{0, 10, 10, 20, 0, third, third, twoThirds}, {10, 10, 30, 20, third, third, twoThirds, twoThirds},
+		{40, 10, 10, 20, twoThirds, third, 1, twoThirds},
+		{0, 0, 10, 10, 0, twoThirds, third, 1}, {10, 0, 30, 10, third, twoThirds, twoThirds, 1},
+		{40, 0, 10, 10, twoThirds, twoThirds, 1, 1},
+	})
+	white := Color{R: 1, G: 1, B: 1, A: 1}.ToFloatBits()
+	for i := C1; i < len(batch.vertices); i += V1 - X1 + 1 {
+		if batch.vertices[i] != white {
+			t.Fatalf("vertex %d not white", i/(V1-X1+1))
+		}
+	}
+
+	// the color of the nine patch is multiplied by the one of the batch, so that it fades with its actor
+	batch.vertices = nil
+	patch.Color = Color{1, 0.5, 1, 1}
+	batch.SetColor(0.5, 1, 1, 0.25)
+	patch.Draw(batch, 50, 40, vector.NewAffine2Empty())
+	tinted := Color{0.5, 0.5, 1, 0.25}.ToFloatBits()
+	for i := C1; i < len(batch.vertices); i += V1 - X1 + 1 {
+		if batch.vertices[i] != tinted {
+			t.Fatalf("vertex %d not tinted by the batch", i/(V1-X1+1))
+		}
+	}
+	if batch.GetColor() != (Color{0.5, 1, 1, 0.25}) {
+		t.Error("color of the batch changed")
+	}
+	patch.Color = Color{1, 1, 1, 1}
+	batch.SetColor(1, 1, 1, 1)
+
+	// drawn smaller than its corners they are shrunk and the rest is not drawn
+	batch.vertices = nil
+	patch.Draw(batch, 10, 10, vector.NewAffine2Empty().SetToTranslation(100, 200))
+	checkPatchQuads(t, "shrunk", batch.vertices, []patchQuad{
+		{100, 205, 5, 5, 0, 0, third, third}, {105, 205, 5, 5, twoThirds, 0, 1, third},
+		{100, 200, 5, 5, 0, twoThirds, third, 1}, {105, 200, 5, 5, twoThirds, twoThirds, 1, 1},
+	})
+
+	// the patches of empty borders are not drawn
+	batch.vertices = nil
+	NewNinePatch(NewTextureRegion(texture, 0, 0, 30, 30), 0, 10, 0, 10).Draw(batch, 40, 40, vector.NewAffine2Empty())
+	checkPatchQuads(t, "no left and top borders", batch.vertices, []patchQuad{
+		{0, 10, 30, 30, 0, 0, twoThirds, twoThirds}, {30, 10, 10, 30, twoThirds, 0, 1, twoThirds},
+		{0, 0, 30, 10, 0, twoThirds, twoThirds, 1}, {30, 0, 10, 10, twoThirds, twoThirds, 1, 1},
+	})
+}
+
+func TestNinePatchTiles(t *testing.T) {
+	texture := &Texture{Width: 30, Height: 30}
+	patch := NewNinePatch(NewTextureRegion(texture, 0, 0, 30, 30), 10, 10, 10, 10)
+	patch.TileCenter = true
+	batch := newVertexBatch()
+	patch.Draw(batch, 45, 30, vector.NewAffine2Empty())
+	quads := patchQuads(batch.vertices)
+	if len(quads) != 11 {
+		t.Fatalf("%d quads %v", len(quads), quads)
+	}
+	third, twoThirds := float32(1)/3, float32(2)/3
+	// the center is repeated at its size, the last tile is cut keeping its left side
+	center := []patchQuad{
+		{10, 10, 10, 10, third, third, twoThirds, twoThirds}, {20, 10, 10, 10, third, third, twoThirds, twoThirds},
+		{30, 10, 5, 10, third, third, 0.5, twoThirds},
+	}
+	for i, q := range center {
+		if !samePatchQuad(quads[4+i], q) {
+			t.Errorf("center tile %d is %v, expected %v", i, quads[4+i], q)
+		}
+	}
+	// the edges are still stretched
+	if !samePatchQuad(quads[1], patchQuad{10, 20, 25, 10, third, 0, twoThirds, third}) {
+		t.Errorf("top edge %v", quads[1])
+	}
+
+	patch.TileCenter, patch.TileEdges = false, true
+	batch.vertices = nil
+	patch.Draw(batch, 45, 30, vector.NewAffine2Empty())
+	if quads = patchQuads(batch.vertices); len(quads) != 13 {
+		t.Errorf("%d quads with tiled edges %v", len(quads), quads)
+	}
+}
+
+// Returns a .9.png image of the size of its content, the border pixels are set by the function
+func ninePatchImage(width, height int, border func(x, y int) color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(10, 20, 10+width+2, 20+height+2))
+	for y := 0; y < height+2; y++ {
+		for x := 0; x < width+2; x++ {
+			c := color.Color(color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+			if x == 0 || y == 0 || x == width+1 || y == height+1 {
+				c = border(x, y)
+			}
+			img.Set(10+x, 20+y, c)
+		}
+	}
+	return img
+}
+
+func TestNinePatchImage(t *testing.T) {
+	black := color.NRGBA{A: 255}
+	img := ninePatchImage(5, 4, func(x, y int) color.Color {
+		switch {
+		case y == 0 && (x == 3 || x == 4), x == 0 && y == 2, y == 5 && x >= 2 && x <= 4:
+			return black
+		case y == 0 && x == 1:
+			// only opaque black pixels are marks
+			return color.NRGBA{A: 128}
+		case x == 0 && y == 4:
+			return color.NRGBA{R: 255, A: 255}
+		}
+		return color.NRGBA{}
+	})
+	texture := &Texture{Path: "button.9.png", Width: 7, Height: 6}
+	patch, err := NewNinePatchImage(texture, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patch.LeftWidth != 2 || patch.MiddleWidth != 2 || patch.RightWidth != 1 {
+		t.Errorf("columns %v %v %v", patch.LeftWidth, patch.MiddleWidth, patch.RightWidth)
+	}
+	if patch.TopHeight != 1 || patch.MiddleHeight != 1 || patch.BottomHeight != 2 {
+		t.Errorf("rows %v %v %v", patch.TopHeight, patch.MiddleHeight, patch.BottomHeight)
+	}
+	// the content of the bottom border is the padding, the right border has none so it is the size of the borders
+	if patch.GetPadLeft() != 1 || patch.GetPadRight() != 1 || patch.GetPadTop() != 1 || patch.GetPadBottom() != 2 {
+		t.Errorf("padding %v %v %v %v", patch.GetPadLeft(), patch.GetPadRight(), patch.GetPadTop(), patch.GetPadBottom())
+	}
+	// the border is not drawn
+	topLeft := patch.Patches[PatchTopLeft]
+	if topLeft.GetRegionX() != 1 || topLeft.GetRegionY() != 1 || topLeft.RegionWidth != 2 || topLeft.RegionHeight != 1 {
+		t.Errorf("top left patch %+v", topLeft)
+	}
+	if bottomRight := patch.Patches[PatchBottomRight]; bottomRight.GetRegionX() != 5 || bottomRight.GetRegionY() != 3 {
+		t.Errorf("bottom right patch %+v", bottomRight)
+	}
+
+	if _, err := NewNinePatchImage(texture, image.NewNRGBA(image.Rect(0, 0, 2, 5))); err == nil {
+		t.Error("image without content")
+	}
+	unmarked := ninePatchImage(5, 4, func(x, y int) color.Color { return color.NRGBA{} })
+	if _, err := NewNinePatchImage(texture, unmarked); err == nil {
+		t.Error("image without splits")
+	}
+}
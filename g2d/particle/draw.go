@@ -0,0 +1,51 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package particle
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+var (
+	drawTransform = vector.NewAffine2Empty()
+)
+
+// Draws the particles alive with their sprites centered on their positions, rotated and tinted, transformed by the
+// transform. The alpha of the particles is multiplied by the alpha.
+func (self *Emitter) Draw(batch g2d.Batch, transform *vector.Affine2, alpha float32) {
+	blending := self.Additive || self.PremultipliedAlpha
+	if self.Additive && self.PremultipliedAlpha {
+		batch.SetBlendFunction(g2d.BlendOne, g2d.BlendOne)
+	} else if self.Additive {
+		batch.SetBlendFunction(g2d.BlendSrcAlpha, g2d.BlendOne)
+	} else if self.PremultipliedAlpha {
+		batch.SetBlendFunction(g2d.BlendOne, g2d.BlendOneMinusSrcAlpha)
+	}
+	for i := range self.particles {
+		particle := &self.particles[i]
+		if !particle.active || particle.Region == nil {
+			continue
+		}
+		batch.SetColor(particle.R, particle.G, particle.B, particle.A*alpha)
+		drawTransform.Set(transform).Translate(particle.X, particle.Y).Rotate(particle.Rotation)
+		drawTransform.Translate(-particle.Width/2, -particle.Height/2)
+		particle.Region.Draw(batch, particle.Width, particle.Height, drawTransform)
+	}
+	if blending {
+		batch.SetBlendFunction(g2d.BlendSrcAlpha, g2d.BlendOneMinusSrcAlpha)
+	}
+}
+
+// Draws the emitters which are behind first and then the others
+func (self *Effect) Draw(batch g2d.Batch, transform *vector.Affine2, alpha float32) {
+	for _, behind := range []bool{true, false} {
+		for _, emitter := range self.Emitters {
+			if emitter.Behind == behind {
+				emitter.Draw(batch, transform, alpha)
+			}
+		}
+	}
+}
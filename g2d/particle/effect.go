@@ -0,0 +1,347 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package particle runs particle effects saved by the libgdx particle editor as .part files.
+//
+// An Effect is read with ReadEffect and is made of emitters, which spawn and move the particles. The particles are
+// simulated by Update without drawing them, so an effect can run without a batch:
+//
+//	effect, err := particle.ReadEffect(file)
+//	err = effect.LoadSprites(atlas)
+//	effect.SetPosition(x, y)
+//	effect.Start()
+//	...
+//	effect.Update(delta)
+//	effect.Draw(batch, transform, 1)
+//
+// Every emitter keeps its particles in a pool, the effect should be copied with NewEffectCopy to be shown several times.
+package particle
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pyros2097/spike/g2d"
+)
+
+// An Effect is a group of emitters at the same position
+type Effect struct {
+	Emitters []*Emitter
+}
+
+// Creates an effect with copies of the emitters of the effect
+func NewEffectCopy(effect *Effect) *Effect {
+	self := &Effect{}
+	for _, emitter := range effect.Emitters {
+		self.Emitters = append(self.Emitters, NewEmitterCopy(emitter))
+	}
+	return self
+}
+
+func (self *Effect) Start() {
+	for _, emitter := range self.Emitters {
+		emitter.Start()
+	}
+}
+
+// Removes the particles alive and starts the emitters again
+func (self *Effect) Reset() {
+	for _, emitter := range self.Emitters {
+		emitter.Reset()
+	}
+}
+
+// Advances the emitters by the time in seconds
+func (self *Effect) Update(delta float32) {
+	for _, emitter := range self.Emitters {
+		emitter.Update(delta)
+	}
+}
+
+func (self *Effect) SetPosition(x, y float32) {
+	for _, emitter := range self.Emitters {
+		emitter.SetPosition(x, y)
+	}
+}
+
+// Lets the continuous emitters complete at the end of their durations
+func (self *Effect) AllowCompletion() {
+	for _, emitter := range self.Emitters {
+		emitter.AllowCompletion()
+	}
+}
+
+// Returns whether all the emitters are complete
+func (self *Effect) IsComplete() bool {
+	for _, emitter := range self.Emitters {
+		if !emitter.IsComplete() {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the number of particles alive in all the emitters
+func (self *Effect) GetActiveCount() int {
+	count := 0
+	for _, emitter := range self.Emitters {
+		count += emitter.GetActiveCount()
+	}
+	return count
+}
+
+// Returns the emitter with the name, or nil
+func (self *Effect) FindEmitter(name string) *Emitter {
+	for _, emitter := range self.Emitters {
+		if emitter.Name == name {
+			return emitter
+		}
+	}
+	return nil
+}
+
+// Sets the sprites of the emitters to the regions of the atlas named as their images, without the directories and the
+// extension
+func (self *Effect) LoadSprites(atlas *g2d.TextureAtlas) error {
+	for _, emitter := range self.Emitters {
+		emitter.Sprites = emitter.Sprites[:0]
+		for _, imagePath := range emitter.ImagePaths {
+			name := path.Base(strings.Replace(imagePath, "\\", "/", -1))
+			name = strings.TrimSuffix(name, path.Ext(name))
+			region := atlas.FindRegion(name)
+			if region == nil {
+				return errors.New("particle: region not found " + name)
+			}
+			emitter.Sprites = append(emitter.Sprites, region)
+		}
+	}
+	return nil
+}
+
+// Reads an effect in the format of the libgdx particle editor. Emitters are separated by empty lines, they start with
+// their name and then have a section for each value, a line like "- Emission -" followed by lines like "lowMin: 0.0".
+// Values which are left out are not active, so files of older versions can be read.
+func ReadEffect(r io.Reader) (*Effect, error) {
+	effect := &Effect{}
+	scanner := bufio.NewScanner(r)
+	lines := []string{}
+	for {
+		more := scanner.Scan()
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+		if (!more || line == "") && len(lines) > 0 {
+			emitter, err := readEmitter(lines)
+			if err != nil {
+				return nil, err
+			}
+			effect.Emitters = append(effect.Emitters, emitter)
+			lines = lines[:0]
+		}
+		if !more {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(effect.Emitters) == 0 {
+		return nil, errors.New("particle: no emitters")
+	}
+	return effect, nil
+}
+
+// The lines of a section of an emitter, with the values by their keys
+type section struct {
+	values map[string]string
+	lines  []string
+}
+
+type emitterReader struct {
+	sections map[string]*section
+	err      error
+}
+
+func readEmitter(lines []string) (*Emitter, error) {
+	reader := emitterReader{sections: make(map[string]*section)}
+	var current *section
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "-") && strings.HasSuffix(line, "-") && len(line) > 1 {
+			current = &section{values: make(map[string]string)}
+			reader.sections[strings.TrimSpace(line[1:len(line)-1])] = current
+			continue
+		}
+		if current == nil {
+			return nil, errors.New("particle: value outside of a section " + line)
+		}
+		current.lines = append(current.lines, line)
+		if i := strings.Index(line, ":"); i > 0 {
+			current.values[line[:i]] = strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	emitter := &Emitter{Name: lines[0]}
+	emitter.Delay = reader.ranged("Delay")
+	emitter.Duration = reader.ranged("Duration")
+	count := reader.section("Count")
+	emitter.MinParticleCount = reader.int(count, "min")
+	emitter.SetMaxParticleCount(reader.int(count, "max"))
+	emitter.Emission = reader.scaled("Emission")
+	emitter.Life = reader.scaled("Life")
+	emitter.LifeOffset = reader.scaled("Life Offset")
+	emitter.XOffset = reader.ranged("X Offset")
+	emitter.YOffset = reader.ranged("Y Offset")
+	emitter.SpawnShape = reader.spawnShape()
+	emitter.SpawnWidth = reader.scaled("Spawn Width")
+	emitter.SpawnHeight = reader.scaled("Spawn Height")
+	if _, ok := reader.sections["X Scale"]; ok {
+		emitter.XScale = reader.scaled("X Scale")
+		emitter.YScale = reader.scaled("Y Scale")
+	} else {
+		emitter.XScale = reader.scaled("Scale")
+	}
+	emitter.Velocity = reader.scaled("Velocity")
+	emitter.Angle = reader.scaled("Angle")
+	emitter.Rotation = reader.scaled("Rotation")
+	emitter.Wind = reader.scaled("Wind")
+	emitter.Gravity = reader.scaled("Gravity")
+	emitter.Tint = reader.gradient("Tint")
+	emitter.Transparency = reader.scaled("Transparency")
+	if options := reader.sections["Options"]; options != nil {
+		emitter.Attached = reader.bool(options, "attached")
+		emitter.Continuous = reader.bool(options, "continuous")
+		emitter.Aligned = reader.bool(options, "aligned")
+		emitter.Additive = reader.bool(options, "additive")
+		emitter.Behind = reader.bool(options, "behind")
+		emitter.PremultipliedAlpha = reader.bool(options, "premultipliedAlpha")
+		switch options.values["spriteMode"] {
+		case "", "single":
+			emitter.SpriteMode = SpriteSingle
+		case "random":
+			emitter.SpriteMode = SpriteRandom
+		case "animated":
+			emitter.SpriteMode = SpriteAnimated
+		default:
+			reader.fail("spriteMode", options.values["spriteMode"])
+		}
+	}
+	if images := reader.sections["Image Paths"]; images != nil {
+		emitter.ImagePaths = images.lines
+	} else if image := reader.sections["Image Path"]; image != nil {
+		emitter.ImagePaths = image.lines
+	}
+	if reader.err != nil {
+		return nil, reader.err
+	}
+	return emitter, nil
+}
+
+func (self *emitterReader) fail(key, value string) {
+	if self.err == nil {
+		self.err = errors.New("particle: invalid " + key + " " + value)
+	}
+}
+
+// Returns the section with the name, or an empty section if it is left out
+func (self *emitterReader) section(name string) *section {
+	if section := self.sections[name]; section != nil {
+		return section
+	}
+	return &section{values: map[string]string{"active": "false"}}
+}
+
+func (self *emitterReader) float(section *section, key string) float32 {
+	value, err := strconv.ParseFloat(section.values[key], 32)
+	if err != nil {
+		self.fail(key, section.values[key])
+	}
+	return float32(value)
+}
+
+func (self *emitterReader) int(section *section, key string) int {
+	value, err := strconv.Atoi(section.values[key])
+	if err != nil {
+		self.fail(key, section.values[key])
+	}
+	return value
+}
+
+func (self *emitterReader) bool(section *section, key string) bool {
+	return section.values[key] == "true"
+}
+
+// Reads the values key0, key1... whose number is keyCount
+func (self *emitterReader) floats(section *section, key string) []float32 {
+	values := make([]float32, self.int(section, key+"Count"))
+	for i := range values {
+		values[i] = self.float(section, key+strconv.Itoa(i))
+	}
+	return values
+}
+
+// Values which are always active have no active key
+func (self *emitterReader) ranged(name string) RangedValue {
+	section := self.section(name)
+	value := RangedValue{Active: section.values["active"] != "false"}
+	if value.Active {
+		value.LowMin = self.float(section, "lowMin")
+		value.LowMax = self.float(section, "lowMax")
+	}
+	return value
+}
+
+func (self *emitterReader) scaled(name string) ScaledValue {
+	section := self.section(name)
+	value := ScaledValue{RangedValue: self.ranged(name)}
+	if value.Active {
+		value.HighMin = self.float(section, "highMin")
+		value.HighMax = self.float(section, "highMax")
+		value.Relative = self.bool(section, "relative")
+		value.Scaling = self.floats(section, "scaling")
+		value.Timeline = self.floats(section, "timeline")
+	}
+	return value
+}
+
+func (self *emitterReader) gradient(name string) GradientColorValue {
+	section := self.section(name)
+	if section.values["active"] == "false" {
+		return GradientColorValue{Colors: []float32{1, 1, 1}, Timeline: []float32{0}}
+	}
+	return GradientColorValue{Colors: self.floats(section, "colors"), Timeline: self.floats(section, "timeline")}
+}
+
+func (self *emitterReader) spawnShape() SpawnShapeValue {
+	section := self.section("Spawn Shape")
+	value := SpawnShapeValue{}
+	switch shape := section.values["shape"]; shape {
+	case "", "point":
+		value.Shape = ShapePoint
+	case "line":
+		value.Shape = ShapeLine
+	case "square":
+		value.Shape = ShapeSquare
+	case "ellipse":
+		value.Shape = ShapeEllipse
+		value.Edges = self.bool(section, "edges")
+		switch side := section.values["side"]; side {
+		case "", "both":
+			value.Side = SideBoth
+		case "top":
+			value.Side = SideTop
+		case "bottom":
+			value.Side = SideBottom
+		default:
+			self.fail("side", side)
+		}
+	default:
+		self.fail("shape", shape)
+	}
+	return value
+}
@@ -0,0 +1,467 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package particle
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/utils"
+)
+
+// The ways the sprites of an emitter are given to its particles
+const (
+	// Every particle has the first sprite
+	SpriteSingle = iota
+
+	// Every particle has a sprite chosen at random when it is spawned
+	SpriteRandom
+
+	// The particles show the sprites one after the other over their lives
+	SpriteAnimated
+)
+
+// A Particle is a sprite which moves, turns, scales and fades over its life. Its position is of its center.
+type Particle struct {
+	X, Y, Width, Height, Rotation float32
+	R, G, B, A                    float32
+	Region                        *g2d.AtlasRegion
+
+	// The life and the time left to live in milliseconds
+	Life, CurrentLife float32
+
+	active                         bool
+	xScale, xScaleDiff             float32
+	yScale, yScaleDiff             float32
+	velocity, velocityDiff         float32
+	angle, angleDiff               float32
+	rotation, rotationDiff         float32
+	wind, windDiff                 float32
+	gravity, gravityDiff           float32
+	transparency, transparencyDiff float32
+}
+
+// An Emitter spawns particles and moves them according to its values. The times of the values are in milliseconds, the
+// distances in pixels, the angles in degrees counter clockwise and the velocities, wind and gravity in pixels per second.
+//
+// The emitter emits for its duration, which starts after its delay. A continuous emitter starts again when its duration is
+// over, until AllowCompletion is called. Particles are emitted at the rate of the emission per second, and at once when
+// there are less than MinParticleCount alive, which makes bursts. The particles are kept in a pool of MaxParticleCount
+// particles, no more are alive at once.
+type Emitter struct {
+	Name string
+
+	Delay, Duration            RangedValue
+	Emission, Life, LifeOffset ScaledValue
+
+	// The offset of the position the particles are spawned at from the position of the emitter
+	XOffset, YOffset RangedValue
+
+	SpawnShape              SpawnShapeValue
+	SpawnWidth, SpawnHeight ScaledValue
+
+	// The size of the particles in pixels, the height keeps the aspect ratio of the sprite if YScale is not active
+	XScale, YScale ScaledValue
+
+	Velocity, Angle, Rotation, Wind, Gravity ScaledValue
+	Tint                                     GradientColorValue
+	Transparency                             ScaledValue
+
+	// If set the particles move with the emitter, in the coordinates of the emitter, instead of staying where they are
+	// spawned when the emitter moves
+	Attached bool
+
+	Continuous bool
+
+	// If set the particles are rotated by the angle they move at
+	Aligned bool
+
+	// If set the particles are drawn with additive blending, lighting up what is behind them
+	Additive bool
+
+	// If set the emitter is drawn before the other emitters of the effect
+	Behind bool
+
+	// If set the sprites have their colors multiplied by their alpha
+	PremultipliedAlpha bool
+
+	SpriteMode int
+
+	// The paths of the images of the sprites as they are in the file, the sprites are found by their names
+	ImagePaths []string
+	Sprites    []*g2d.AtlasRegion
+
+	MinParticleCount int
+
+	particles   []Particle
+	activeCount int
+	x, y        float32
+
+	firstUpdate, allowCompletion bool
+	delay, delayTimer            float32
+	duration, durationTimer      float32
+	emissionDelta                float32
+
+	emission, emissionDiff       float32
+	life, lifeDiff               float32
+	lifeOffset, lifeOffsetDiff   float32
+	spawnWidth, spawnWidthDiff   float32
+	spawnHeight, spawnHeightDiff float32
+}
+
+// Creates an emitter which emits for a second with the values inactive, up to the max number of particles at once
+func NewEmitter(maxParticleCount int) *Emitter {
+	self := &Emitter{}
+	self.Duration = RangedValue{Active: true, LowMin: 1000, LowMax: 1000}
+	for _, value := range []*ScaledValue{&self.Emission, &self.Life, &self.SpawnWidth, &self.SpawnHeight, &self.XScale,
+		&self.Transparency} {
+		value.Active = true
+		value.Scaling, value.Timeline = []float32{1}, []float32{0}
+	}
+	self.Transparency.HighMin, self.Transparency.HighMax = 1, 1
+	self.Tint = GradientColorValue{Colors: []float32{1, 1, 1}, Timeline: []float32{0}}
+	self.SetMaxParticleCount(maxParticleCount)
+	return self
+}
+
+// Creates an emitter with the values and sprites of the emitter and an empty pool of particles
+func NewEmitterCopy(emitter *Emitter) *Emitter {
+	self := &Emitter{}
+	*self = *emitter
+	self.particles = nil
+	self.SetMaxParticleCount(emitter.GetMaxParticleCount())
+	return self
+}
+
+// Sets the number of particles in the pool, the particles alive are kept if they fit
+func (self *Emitter) SetMaxParticleCount(maxParticleCount int) {
+	particles := make([]Particle, maxParticleCount)
+	self.activeCount = 0
+	for _, particle := range self.particles {
+		if particle.active && self.activeCount < maxParticleCount {
+			particles[self.activeCount] = particle
+			self.activeCount++
+		}
+	}
+	self.particles = particles
+}
+
+func (self *Emitter) GetMaxParticleCount() int {
+	return len(self.particles)
+}
+
+// Returns the number of particles alive
+func (self *Emitter) GetActiveCount() int {
+	return self.activeCount
+}
+
+// Returns the particles alive
+func (self *Emitter) GetActiveParticles() []*Particle {
+	active := make([]*Particle, 0, self.activeCount)
+	for i := range self.particles {
+		if self.particles[i].active {
+			active = append(active, &self.particles[i])
+		}
+	}
+	return active
+}
+
+// Sets the position the particles are spawned at. The particles alive are moved with the emitter if it is attached.
+func (self *Emitter) SetPosition(x, y float32) {
+	if self.Attached {
+		dx, dy := x-self.x, y-self.y
+		for i := range self.particles {
+			if self.particles[i].active {
+				self.particles[i].X += dx
+				self.particles[i].Y += dy
+			}
+		}
+	}
+	self.x, self.y = x, y
+}
+
+func (self *Emitter) GetX() float32 {
+	return self.x
+}
+
+func (self *Emitter) GetY() float32 {
+	return self.y
+}
+
+// Starts emitting from the beginning of the delay, the particles alive are kept
+func (self *Emitter) Start() {
+	self.firstUpdate = true
+	self.allowCompletion = false
+	self.emissionDelta = 0
+	self.durationTimer = self.duration
+	self.restart()
+}
+
+// Removes the particles alive and starts emitting again
+func (self *Emitter) Reset() {
+	for i := range self.particles {
+		self.particles[i].active = false
+	}
+	self.activeCount = 0
+	self.Start()
+}
+
+// Chooses the random values of a new duration
+func (self *Emitter) restart() {
+	self.delay = 0
+	if self.Delay.Active {
+		self.delay = self.Delay.NewLowValue()
+	}
+	self.delayTimer = 0
+	self.durationTimer -= self.duration
+	self.duration = self.Duration.NewLowValue()
+	self.emission, self.emissionDiff = self.Emission.newValues()
+	self.life, self.lifeDiff = self.Life.newValues()
+	self.lifeOffset, self.lifeOffsetDiff = 0, 0
+	if self.LifeOffset.Active {
+		self.lifeOffset, self.lifeOffsetDiff = self.LifeOffset.newValues()
+	}
+	self.spawnWidth, self.spawnWidthDiff = self.SpawnWidth.newValues()
+	self.spawnHeight, self.spawnHeightDiff = self.SpawnHeight.newValues()
+}
+
+// Lets a continuous emitter complete at the end of its duration
+func (self *Emitter) AllowCompletion() {
+	self.allowCompletion = true
+	self.durationTimer = self.duration
+}
+
+// Returns whether the emitter does not emit anymore and all its particles are dead
+func (self *Emitter) IsComplete() bool {
+	if self.Continuous && !self.allowCompletion {
+		return false
+	}
+	if self.delayTimer < self.delay {
+		return false
+	}
+	return self.durationTimer >= self.duration && self.activeCount == 0
+}
+
+// Returns the fraction of the duration which has passed
+func (self *Emitter) GetPercentComplete() float32 {
+	if self.delayTimer < self.delay {
+		return 0
+	}
+	if self.duration <= 0 {
+		return 1
+	}
+	return utils.ClampFloat32(self.durationTimer/self.duration, 0, 1)
+}
+
+// Emits the particles due in the time in seconds and moves the particles alive
+func (self *Emitter) Update(delta float32) {
+	deltaMillis := delta * 1000
+	if self.delayTimer < self.delay {
+		self.delayTimer += deltaMillis
+	} else {
+		done := false
+		if self.firstUpdate {
+			self.firstUpdate = false
+			self.AddParticles(1)
+		}
+		if self.durationTimer < self.duration {
+			self.durationTimer += deltaMillis
+		} else if !self.Continuous || self.allowCompletion {
+			done = true
+		} else {
+			self.restart()
+		}
+		if !done {
+			self.emit(deltaMillis)
+		}
+	}
+	for i := range self.particles {
+		particle := &self.particles[i]
+		if particle.active && !self.updateParticle(particle, delta, deltaMillis) {
+			particle.active = false
+			self.activeCount--
+		}
+	}
+}
+
+// Adds the particles due at the emission rate, and the ones missing to have the min particle count
+func (self *Emitter) emit(deltaMillis float32) {
+	self.emissionDelta += deltaMillis
+	emission := self.emission + self.emissionDiff*self.Emission.GetScale(self.GetPercentComplete())
+	if emission > 0 {
+		emissionTime := 1000 / emission
+		if self.emissionDelta >= emissionTime {
+			count := int(self.emissionDelta / emissionTime)
+			self.emissionDelta -= float32(count) * emissionTime
+			self.AddParticles(count)
+		}
+	}
+	if self.activeCount < self.MinParticleCount {
+		self.AddParticles(self.MinParticleCount - self.activeCount)
+	}
+}
+
+// Spawns the particles at once, as many as there are free in the pool
+func (self *Emitter) AddParticles(count int) {
+	for i := range self.particles {
+		if count <= 0 {
+			return
+		}
+		if !self.particles[i].active {
+			self.activateParticle(&self.particles[i])
+			count--
+		}
+	}
+}
+
+func (self *Emitter) activateParticle(particle *Particle) {
+	percent := self.GetPercentComplete()
+	*particle = Particle{active: true}
+	self.activeCount++
+	particle.Life = self.life + self.lifeDiff*self.Life.GetScale(percent)
+	particle.CurrentLife = particle.Life
+
+	if len(self.Sprites) > 0 {
+		particle.Region = self.Sprites[0]
+		if self.SpriteMode == SpriteRandom {
+			particle.Region = self.Sprites[utils.Random(len(self.Sprites))]
+		}
+	}
+	if self.Velocity.Active {
+		particle.velocity, particle.velocityDiff = self.Velocity.newValues()
+	}
+	if self.Angle.Active {
+		particle.angle, particle.angleDiff = self.Angle.newValues()
+	}
+	if self.XScale.Active {
+		particle.xScale, particle.xScaleDiff = self.XScale.newValues()
+	}
+	if self.YScale.Active {
+		particle.yScale, particle.yScaleDiff = self.YScale.newValues()
+	}
+	if self.Rotation.Active {
+		particle.rotation, particle.rotationDiff = self.Rotation.newValues()
+	}
+	if self.Wind.Active {
+		particle.wind, particle.windDiff = self.Wind.newValues()
+	}
+	if self.Gravity.Active {
+		particle.gravity, particle.gravityDiff = self.Gravity.newValues()
+	}
+	particle.transparency, particle.transparencyDiff = self.Transparency.newValues()
+	self.poseParticle(particle, 0)
+
+	x, y := self.x, self.y
+	if self.XOffset.Active {
+		x += self.XOffset.NewLowValue()
+	}
+	if self.YOffset.Active {
+		y += self.YOffset.NewLowValue()
+	}
+	width := self.spawnWidth + self.spawnWidthDiff*self.SpawnWidth.GetScale(percent)
+	height := self.spawnHeight + self.spawnHeightDiff*self.SpawnHeight.GetScale(percent)
+	switch self.SpawnShape.Shape {
+	case ShapeSquare:
+		x += utils.RandomFloat()*width - width/2
+		y += utils.RandomFloat()*height - height/2
+	case ShapeEllipse:
+		radiusX, radiusY := width/2, height/2
+		if radiusX == 0 || radiusY == 0 {
+			break
+		}
+		if self.SpawnShape.Edges {
+			angle := utils.RandomFloat() * 360
+			switch self.SpawnShape.Side {
+			case SideTop:
+				angle /= 2
+			case SideBottom:
+				angle = 180 + angle/2
+			}
+			x += utils.CosDeg(angle) * radiusX
+			y += utils.SinDeg(angle) * radiusY
+			break
+		}
+		for {
+			px, py := utils.RandomFloat()*2-1, utils.RandomFloat()*2-1
+			if px*px+py*py <= 1 {
+				x += px * radiusX
+				y += py * radiusY
+				break
+			}
+		}
+	case ShapeLine:
+		if width != 0 {
+			lineX := width * utils.RandomFloat()
+			x += lineX
+			y += lineX * (height / width)
+		} else {
+			y += height * utils.RandomFloat()
+		}
+	}
+	particle.X, particle.Y = x, y
+
+	if self.LifeOffset.Active {
+		offset := self.lifeOffset + self.lifeOffsetDiff*self.LifeOffset.GetScale(percent)
+		if offset > 0 {
+			if offset >= particle.CurrentLife {
+				offset = particle.CurrentLife - 1
+			}
+			self.updateParticle(particle, offset/1000, offset)
+		}
+	}
+}
+
+// Moves the particle by the time in seconds and milliseconds, returns false if it is dead
+func (self *Emitter) updateParticle(particle *Particle, delta, deltaMillis float32) bool {
+	life := particle.CurrentLife - deltaMillis
+	if life <= 0 {
+		return false
+	}
+	particle.CurrentLife = life
+	percent := 1 - life/particle.Life
+	angle := self.poseParticle(particle, percent)
+	var velocityX, velocityY float32
+	if self.Velocity.Active {
+		velocity := (particle.velocity + particle.velocityDiff*self.Velocity.GetScale(percent)) * delta
+		velocityX, velocityY = velocity*utils.CosDeg(angle), velocity*utils.SinDeg(angle)
+	}
+	if self.Wind.Active {
+		velocityX += (particle.wind + particle.windDiff*self.Wind.GetScale(percent)) * delta
+	}
+	if self.Gravity.Active {
+		velocityY += (particle.gravity + particle.gravityDiff*self.Gravity.GetScale(percent)) * delta
+	}
+	particle.X += velocityX
+	particle.Y += velocityY
+	if self.SpriteMode == SpriteAnimated && len(self.Sprites) > 0 {
+		frame := int(percent * float32(len(self.Sprites)))
+		if frame >= len(self.Sprites) {
+			frame = len(self.Sprites) - 1
+		}
+		particle.Region = self.Sprites[frame]
+	}
+	return true
+}
+
+// Sets the size, rotation and color of the particle at the percent of its life, returns the angle it moves at
+func (self *Emitter) poseParticle(particle *Particle, percent float32) float32 {
+	particle.Width = particle.xScale + particle.xScaleDiff*self.XScale.GetScale(percent)
+	if self.YScale.Active {
+		particle.Height = particle.yScale + particle.yScaleDiff*self.YScale.GetScale(percent)
+	} else if region := particle.Region; region != nil && region.OriginalWidth > 0 {
+		particle.Height = particle.Width * float32(region.OriginalHeight) / float32(region.OriginalWidth)
+	} else {
+		particle.Height = particle.Width
+	}
+	angle := particle.angle + particle.angleDiff*self.Angle.GetScale(percent)
+	particle.Rotation = 0
+	if self.Rotation.Active {
+		particle.Rotation = particle.rotation + particle.rotationDiff*self.Rotation.GetScale(percent)
+	}
+	if self.Aligned {
+		particle.Rotation += angle
+	}
+	particle.R, particle.G, particle.B = self.Tint.GetColor(percent)
+	particle.A = particle.transparency + particle.transparencyDiff*self.Transparency.GetScale(percent)
+	return angle
+}
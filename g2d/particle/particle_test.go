@@ -0,0 +1,558 @@
+package particle
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// a continuous emitter as saved by the current editor and a burst in the format of older versions
+const testEffect = `fire
+- Delay -
+active: false
+- Duration -
+lowMin: 1000.0
+lowMax: 1000.0
+- Count -
+min: 0
+max: 200
+- Emission -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 100.0
+highMax: 100.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Life -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 2000.0
+highMax: 2000.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+independent: false
+- Life Offset -
+active: false
+independent: false
+- X Offset -
+active: false
+- Y Offset -
+active: false
+- Spawn Shape -
+shape: square
+- Spawn Width -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 40.0
+highMax: 40.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Spawn Height -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 10.0
+highMax: 10.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- X Scale -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 16.0
+highMax: 16.0
+relative: false
+scalingCount: 2
+scaling0: 1.0
+scaling1: 0.5
+timelineCount: 2
+timeline0: 0.0
+timeline1: 1.0
+- Y Scale -
+active: false
+- Velocity -
+active: true
+lowMin: 0.0
+lowMax: 0.0
+highMin: 50.0
+highMax: 50.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Angle -
+active: true
+lowMin: 90.0
+lowMax: 90.0
+highMin: 90.0
+highMax: 90.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Rotation -
+active: false
+- Wind -
+active: false
+- Gravity -
+active: false
+- Tint -
+colorsCount: 6
+colors0: 1.0
+colors1: 1.0
+colors2: 0.0
+colors3: 1.0
+colors4: 0.0
+colors5: 0.0
+timelineCount: 2
+timeline0: 0.0
+timeline1: 1.0
+- Transparency -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 1.0
+highMax: 1.0
+relative: false
+scalingCount: 3
+scaling0: 1.0
+scaling1: 1.0
+scaling2: 0.0
+timelineCount: 3
+timeline0: 0.0
+timeline1: 0.5
+timeline2: 1.0
+- Options -
+attached: false
+continuous: true
+aligned: false
+additive: true
+behind: false
+premultipliedAlpha: false
+spriteMode: single
+- Image Paths -
+flame.png
+
+sparks
+- Delay -
+active: false
+- Duration -
+lowMin: 100.0
+lowMax: 100.0
+- Count -
+min: 5
+max: 10
+- Emission -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 0.0
+highMax: 0.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Life -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 1000.0
+highMax: 1000.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Life Offset -
+active: false
+- X Offset -
+active: false
+- Y Offset -
+active: false
+- Spawn Shape -
+shape: point
+- Spawn Width -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 0.0
+highMax: 0.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Spawn Height -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 0.0
+highMax: 0.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Scale -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 4.0
+highMax: 4.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Velocity -
+active: true
+lowMin: 0.0
+lowMax: 0.0
+highMin: 100.0
+highMax: 100.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Angle -
+active: true
+lowMin: 90.0
+lowMax: 90.0
+highMin: 90.0
+highMax: 90.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Rotation -
+active: false
+- Wind -
+active: true
+lowMin: 0.0
+lowMax: 0.0
+highMin: 20.0
+highMax: 20.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Gravity -
+active: true
+lowMin: 0.0
+lowMax: 0.0
+highMin: -200.0
+highMax: -200.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Tint -
+colorsCount: 3
+colors0: 1.0
+colors1: 1.0
+colors2: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Transparency -
+lowMin: 0.0
+lowMax: 0.0
+highMin: 1.0
+highMax: 1.0
+relative: false
+scalingCount: 1
+scaling0: 1.0
+timelineCount: 1
+timeline0: 0.0
+- Options -
+attached: true
+continuous: false
+aligned: false
+additive: false
+behind: true
+premultipliedAlpha: false
+- Image Path -
+C:\effects\spark.png
+`
+
+const testAtlas = `particles.png
+size: 32, 32
+format: RGBA8888
+filter: Linear,Linear
+repeat: none
+flame
+  rotate: false
+  xy: 0, 0
+  size: 16, 8
+  orig: 16, 8
+  offset: 0, 0
+  index: -1
+spark
+  rotate: false
+  xy: 16, 0
+  size: 4, 4
+  orig: 4, 4
+  offset: 0, 0
+  index: -1
+`
+
+func near(a, b, epsilon float32) bool {
+	return math.Abs(float64(a-b)) <= float64(epsilon)
+}
+
+func readTestEffect(t *testing.T) *Effect {
+	effect, err := ReadEffect(strings.NewReader(testEffect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return effect
+}
+
+func TestReadEffect(t *testing.T) {
+	effect := readTestEffect(t)
+	if len(effect.Emitters) != 2 {
+		t.Fatalf("%d emitters", len(effect.Emitters))
+	}
+	fire, sparks := effect.FindEmitter("fire"), effect.FindEmitter("sparks")
+	if fire.Delay.Active || !fire.Duration.Active || fire.Duration.LowMin != 1000 || fire.GetMaxParticleCount() != 200 {
+		t.Errorf("fire delay %v duration %v count %d", fire.Delay, fire.Duration, fire.GetMaxParticleCount())
+	}
+	if fire.Emission.HighMax != 100 || fire.Life.HighMin != 2000 || fire.LifeOffset.Active || fire.SpawnShape.Shape != ShapeSquare {
+		t.Errorf("fire emission %v life %v shape %v", fire.Emission, fire.Life, fire.SpawnShape)
+	}
+	if len(fire.XScale.Scaling) != 2 || fire.XScale.Scaling[1] != 0.5 || fire.YScale.Active || len(fire.Tint.Colors) != 6 {
+		t.Errorf("fire scale %v tint %v", fire.XScale, fire.Tint)
+	}
+	if !fire.Continuous || !fire.Additive || fire.Attached || fire.Behind || len(fire.ImagePaths) != 1 {
+		t.Errorf("fire options %v %v %v %v images %v", fire.Continuous, fire.Additive, fire.Attached, fire.Behind,
+			fire.ImagePaths)
+	}
+	if sparks.MinParticleCount != 5 || sparks.XScale.HighMin != 4 || !sparks.Gravity.Active || sparks.Gravity.HighMin != -200 {
+		t.Errorf("sparks count %d scale %v gravity %v", sparks.MinParticleCount, sparks.XScale, sparks.Gravity)
+	}
+	if !sparks.Attached || sparks.Continuous || !sparks.Behind || sparks.ImagePaths[0] != `C:\effects\spark.png` {
+		t.Errorf("sparks options %v %v %v images %v", sparks.Attached, sparks.Continuous, sparks.Behind, sparks.ImagePaths)
+	}
+
+	if _, err := ReadEffect(strings.NewReader("broken\n- Count -\nmin: x\nmax: 1\n")); err == nil {
+		t.Error("invalid count read")
+	}
+	if _, err := ReadEffect(strings.NewReader("\n\n")); err == nil {
+		t.Error("empty effect read")
+	}
+}
+
+func TestScaledValue(t *testing.T) {
+	value := ScaledValue{Scaling: []float32{0, 1, 0.5}, Timeline: []float32{0, 0.5, 1}}
+	for _, c := range [][2]float32{{0, 0}, {0.25, 0.5}, {0.5, 1}, {0.75, 0.75}, {1, 0.5}, {2, 0.5}} {
+		if scale := value.GetScale(c[0]); !near(scale, c[1], 0.0001) {
+			t.Errorf("scale %v at %v, want %v", scale, c[0], c[1])
+		}
+	}
+	tint := GradientColorValue{Colors: []float32{1, 1, 0, 1, 0, 0}, Timeline: []float32{0, 1}}
+	if r, g, b := tint.GetColor(0.25); r != 1 || g != 0.75 || b != 0 {
+		t.Errorf("color %v %v %v", r, g, b)
+	}
+}
+
+func TestContinuousEmission(t *testing.T) {
+	effect := readTestEffect(t)
+	fire := effect.FindEmitter("fire")
+	fire.SetPosition(100, 50)
+	fire.Start()
+	// 100 particles per second and the one spawned on the first update
+	for i := 0; i < 4; i++ {
+		fire.Update(0.125)
+	}
+	if count := fire.GetActiveCount(); count != 51 {
+		t.Errorf("%d particles after half a second", count)
+	}
+	for _, particle := range fire.GetActiveParticles() {
+		// the particles are spawned in a 40x10 square and move up by 50 pixels per second
+		age := (particle.Life - particle.CurrentLife) / 1000
+		if particle.X < 80 || particle.X > 120 || particle.Y < 45+50*age-0.1 || particle.Y > 55+50*age+0.1 {
+			t.Errorf("particle at %v, %v after %v seconds", particle.X, particle.Y, age)
+		}
+	}
+
+	// the emitter starts again after its duration and the first particles die after 2 seconds, the particles are emitted
+	// before the dead ones are freed so one does not fit in the pool
+	for i := 0; i < 16; i++ {
+		fire.Update(0.125)
+	}
+	if count := fire.GetActiveCount(); count != 187 || fire.IsComplete() {
+		t.Errorf("%d particles after 2.5 seconds", count)
+	}
+	for _, particle := range fire.GetActiveParticles() {
+		if !near(particle.CurrentLife, 1000, 0.01) {
+			continue
+		}
+		// at half of their life the particles are at 3/4 of their size, opaque and orange
+		if !near(particle.Width, 12, 0.01) || !near(particle.Height, 12, 0.01) || !near(particle.A, 1, 0.0001) ||
+			!near(particle.G, 0.5, 0.0001) {
+			t.Errorf("particle at half life %v", particle)
+		}
+	}
+
+	fire.AllowCompletion()
+	for i := 0; i < 20 && !fire.IsComplete(); i++ {
+		fire.Update(0.125)
+	}
+	if !fire.IsComplete() || fire.GetActiveCount() != 0 {
+		t.Errorf("%d particles after completion", fire.GetActiveCount())
+	}
+}
+
+func TestBurst(t *testing.T) {
+	effect := readTestEffect(t)
+	sparks := effect.FindEmitter("sparks")
+	sparks.SetPosition(10, 20)
+	sparks.Start()
+	sparks.Update(0.25)
+	if count := sparks.GetActiveCount(); count != 5 {
+		t.Fatalf("%d particles in the burst", count)
+	}
+	// moved up by the velocity and down by the gravity, to the right by the wind
+	for _, particle := range sparks.GetActiveParticles() {
+		if !near(particle.X, 15, 0.1) || !near(particle.Y, -5, 0.1) || particle.Width != 4 || particle.Height != 4 {
+			t.Errorf("particle at %v, %v sized %v, %v", particle.X, particle.Y, particle.Width, particle.Height)
+		}
+	}
+
+	// the attached particles move with the emitter
+	sparks.SetPosition(20, 10)
+	if particle := sparks.GetActiveParticles()[0]; !near(particle.X, 25, 0.1) || !near(particle.Y, -15, 0.1) {
+		t.Errorf("attached particle at %v, %v", particle.X, particle.Y)
+	}
+
+	sparks.Update(0.5)
+	if sparks.GetActiveCount() != 5 || sparks.IsComplete() {
+		t.Errorf("%d particles after 0.75 seconds", sparks.GetActiveCount())
+	}
+	sparks.Update(0.5)
+	if sparks.GetActiveCount() != 0 || !sparks.IsComplete() {
+		t.Errorf("%d particles after their life", sparks.GetActiveCount())
+	}
+
+	// no more particles than the pool holds are alive
+	sparks.Reset()
+	sparks.AddParticles(20)
+	if sparks.GetActiveCount() != 10 {
+		t.Errorf("%d particles in a pool of 10", sparks.GetActiveCount())
+	}
+	copy := NewEffectCopy(effect)
+	if copy.FindEmitter("sparks").GetActiveCount() != 0 || copy.FindEmitter("sparks").GetMaxParticleCount() != 10 {
+		t.Error("the particles of the copy are not pooled apart")
+	}
+}
+
+type drawCall struct {
+	region             *g2d.TextureRegion
+	width, height      float32
+	x, y               float32
+	r, g, b, a         float32
+	blendSrc, blendDst int
+}
+
+type testBatch struct {
+	transform          *vector.Matrix4
+	projection         *vector.Matrix4
+	r, g, b, a         float32
+	blendSrc, blendDst int
+	calls              []drawCall
+}
+
+func (self *testBatch) Begin() {}
+func (self *testBatch) End()   {}
+
+func (self *testBatch) GetTransformMatrix() *vector.Matrix4 {
+	return self.transform
+}
+
+func (self *testBatch) SetTransformMatrix(transform *vector.Matrix4) {
+	self.transform.SetM4(transform)
+}
+
+func (self *testBatch) GetProjectionMatrix() *vector.Matrix4 {
+	return self.projection
+}
+
+func (self *testBatch) SetProjectionMatrix(projection *vector.Matrix4) {
+	self.projection.SetM4(projection)
+}
+
+func (self *testBatch) SetColor(r, g, b, a float32) {
+	self.r, self.g, self.b, self.a = r, g, b, a
+}
+
+func (self *testBatch) GetColor() g2d.Color {
+	return g2d.Color{R: self.r, G: self.g, B: self.b, A: self.a}
+}
+
+func (self *testBatch) SetBlendFunction(srcFunc, dstFunc int) {
+	self.blendSrc, self.blendDst = srcFunc, dstFunc
+}
+
+func (self *testBatch) Flush() {}
+
+func (self *testBatch) Draw(region *g2d.TextureRegion, width, height float32, transform *vector.Affine2) {
+	origin := vector.NewVector2Empty()
+	transform.ApplyTo(origin)
+	self.calls = append(self.calls, drawCall{region, width, height, origin.X, origin.Y, self.r, self.g, self.b, self.a,
+		self.blendSrc, self.blendDst})
+}
+
+func (self *testBatch) DrawVertices(texture *g2d.Texture, vertices []float32) {}
+
+func TestDraw(t *testing.T) {
+	effect := readTestEffect(t)
+	atlas, err := g2d.ReadTextureAtlas(strings.NewReader(testAtlas), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := effect.LoadSprites(atlas); err != nil {
+		t.Fatal(err)
+	}
+	effect.SetPosition(10, 20)
+	effect.Start()
+	effect.Update(0.125)
+	fire, sparks := effect.FindEmitter("fire"), effect.FindEmitter("sparks")
+	batch := &testBatch{transform: vector.NewMatrix4Empty(), projection: vector.NewMatrix4Empty()}
+	effect.Draw(batch, vector.NewAffine2Empty().SetToTranslation(100, 0), 0.5)
+	if len(batch.calls) != fire.GetActiveCount()+sparks.GetActiveCount() {
+		t.Fatalf("%d particles drawn", len(batch.calls))
+	}
+	// the sparks are behind and are drawn first with the default blending, the fire is additive
+	spark := batch.calls[0]
+	particle := sparks.GetActiveParticles()[0]
+	if spark.region != &atlas.FindRegion("spark").TextureRegion || spark.blendDst != 0 || spark.a != 0.5 ||
+		!near(spark.x, 100+particle.X-2, 0.01) || !near(spark.y, particle.Y-2, 0.01) {
+		t.Errorf("spark drawn %v for particle at %v, %v", spark, particle.X, particle.Y)
+	}
+	flame := batch.calls[len(batch.calls)-1]
+	if flame.region != &atlas.FindRegion("flame").TextureRegion || flame.blendSrc != g2d.BlendSrcAlpha ||
+		flame.blendDst != g2d.BlendOne || !near(flame.width, 15.5, 0.01) || !near(flame.height, 7.75, 0.01) {
+		t.Errorf("flame drawn %v", flame)
+	}
+	if batch.blendSrc != g2d.BlendSrcAlpha || batch.blendDst != g2d.BlendOneMinusSrcAlpha {
+		t.Errorf("blending left at %v, %v", batch.blendSrc, batch.blendDst)
+	}
+
+	atlas.Regions = atlas.Regions[:1]
+	if effect.LoadSprites(atlas) == nil {
+		t.Error("missing region loaded")
+	}
+}
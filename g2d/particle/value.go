@@ -0,0 +1,125 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package particle
+
+import (
+	"github.com/pyros2097/spike/math/utils"
+)
+
+// A RangedValue is a value chosen at random between LowMin and LowMax. Values which are not active are not used by the
+// emitter.
+type RangedValue struct {
+	Active         bool
+	LowMin, LowMax float32
+}
+
+// Returns a random value between LowMin and LowMax
+func (self *RangedValue) NewLowValue() float32 {
+	return self.LowMin + (self.LowMax-self.LowMin)*utils.RandomFloat()
+}
+
+// A ScaledValue changes over the life of a particle, or the duration of the emitter, from a value chosen between LowMin and
+// LowMax to a value chosen between HighMin and HighMax. The fraction of the change is given by the Scaling curve, which is
+// interpolated between the points of the Timeline from 0 to 1. If the value is relative the high value is added to the low
+// value.
+type ScaledValue struct {
+	RangedValue
+	HighMin, HighMax float32
+	Relative         bool
+	Scaling          []float32
+	Timeline         []float32
+}
+
+// Returns a random value between HighMin and HighMax
+func (self *ScaledValue) NewHighValue() float32 {
+	return self.HighMin + (self.HighMax-self.HighMin)*utils.RandomFloat()
+}
+
+// Returns the scaling at the percent of the timeline, from 0 to 1
+func (self *ScaledValue) GetScale(percent float32) float32 {
+	return interpolate(self.Scaling, self.Timeline, percent)
+}
+
+// Returns a random low value and the change to a random high value
+func (self *ScaledValue) newValues() (value, diff float32) {
+	value, diff = self.NewLowValue(), self.NewHighValue()
+	if !self.Relative {
+		diff -= value
+	}
+	return value, diff
+}
+
+// Interpolates the values linearly between the points of the timeline, the first and last values are kept before and
+// after the timeline
+func interpolate(values, timeline []float32, percent float32) float32 {
+	n := len(values)
+	if len(timeline) < n {
+		n = len(timeline)
+	}
+	if n == 0 {
+		return 1
+	}
+	for end := 1; end < n; end++ {
+		if timeline[end] > percent {
+			start := end - 1
+			startTime := timeline[start]
+			return values[start] + (values[end]-values[start])*((percent-startTime)/(timeline[end]-startTime))
+		}
+	}
+	return values[n-1]
+}
+
+// A GradientColorValue tints the particles over their lives with the colors interpolated between the points of the
+// timeline. Colors holds the red, green and blue components of each color of the timeline.
+type GradientColorValue struct {
+	Colors   []float32
+	Timeline []float32
+}
+
+// Returns the red, green and blue components of the color at the percent of the timeline, from 0 to 1
+func (self *GradientColorValue) GetColor(percent float32) (r, g, b float32) {
+	n := len(self.Colors) / 3
+	if len(self.Timeline) < n {
+		n = len(self.Timeline)
+	}
+	if n == 0 {
+		return 1, 1, 1
+	}
+	c := self.Colors
+	for end := 1; end < n; end++ {
+		if self.Timeline[end] > percent {
+			start := end - 1
+			startTime := self.Timeline[start]
+			factor := (percent - startTime) / (self.Timeline[end] - startTime)
+			s, e := start*3, end*3
+			return c[s] + (c[e]-c[s])*factor, c[s+1] + (c[e+1]-c[s+1])*factor, c[s+2] + (c[e+2]-c[s+2])*factor
+		}
+	}
+	last := (n - 1) * 3
+	return c[last], c[last+1], c[last+2]
+}
+
+// The shapes of the area the particles are spawned in
+const (
+	ShapePoint = iota
+	ShapeLine
+	ShapeSquare
+	ShapeEllipse
+)
+
+// The sides of an ellipse the particles are spawned on
+const (
+	SideBoth = iota
+	SideTop
+	SideBottom
+)
+
+// A SpawnShapeValue is the shape of the area the particles are spawned in, centered on the position of the emitter. If
+// Edges is set particles are only spawned on the outline of an ellipse, on the sides given by Side.
+type SpawnShapeValue struct {
+	Shape int
+	Edges bool
+	Side  int
+}
@@ -0,0 +1,458 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+import (
+	"math"
+
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// The ways the shapes of a ShapeRenderer are drawn
+type ShapeType int
+
+const (
+	// Only the vertices of the shapes are drawn, as points
+	ShapePoint ShapeType = iota
+
+	// The outlines of the shapes are drawn
+	ShapeLine
+
+	// The shapes are filled
+	ShapeFilled
+)
+
+// The primitives the vertices of the shapes are drawn as, they have the values of the OpenGL constants
+const (
+	PrimitivePoints    = 0x0000
+	PrimitiveLines     = 0x0001
+	PrimitiveTriangles = 0x0004
+)
+
+const (
+	// The x, y, red, green, blue and alpha of a vertex
+	ShapeVertexSize = 6
+
+	maxShapeVertices = 5000
+)
+
+// A ShapeRenderer draws points, lines and shapes with solid colors, outlined or filled. Shapes are drawn between Begin
+// and End:
+//
+//	shapes.SetProjectionMatrix(camera.Combined)
+//	shapes.Begin(g2d.ShapeLine)
+//	shapes.SetColor(1, 0, 0, 1)
+//	shapes.Rect(10, 10, 100, 50)
+//	shapes.Circle(60, 35, 20, 0)
+//	shapes.End()
+//
+// The vertices are collected and given to Flush by primitive, so shapes of the same kind are drawn together. Lines wider
+// than 1 are drawn as filled quads. The positions are transformed by the transform matrix and the colors of the vertices
+// are the color set last, or given for each vertex by the methods ending with Colors.
+type ShapeRenderer struct {
+	// Draws the vertices as the primitive with the projection matrix, each vertex has ShapeVertexSize values. It is called
+	// when the renderer is ended, its buffer is full or the primitive changes, the vertices are only valid during the call.
+	Flush func(primitive int, vertices []float32, projection *vector.Matrix4)
+
+	shapeType       ShapeType
+	drawing         bool
+	primitive       int
+	vertices        []float32
+	color           Color
+	lineWidth       float32
+	projection      *vector.Matrix4
+	transform       *vector.Matrix4
+	transformValues [16]float32
+}
+
+func NewShapeRenderer() *ShapeRenderer {
+	self := &ShapeRenderer{
+		vertices:   make([]float32, 0, maxShapeVertices*ShapeVertexSize),
+		color:      Color{1, 1, 1, 1},
+		lineWidth:  1,
+		projection: vector.NewMatrix4Empty(),
+		transform:  vector.NewMatrix4Empty(),
+	}
+	self.transformValues = self.transform.GetValues()
+	return self
+}
+
+// Starts drawing shapes of the type, End must be called before Begin is called again
+func (self *ShapeRenderer) Begin(shapeType ShapeType) {
+	if self.drawing {
+		panic("shape renderer: End must be called before Begin")
+	}
+	self.shapeType = shapeType
+	self.drawing = true
+}
+
+// Draws the shapes which have not been flushed yet
+func (self *ShapeRenderer) End() {
+	self.flush()
+	self.drawing = false
+}
+
+func (self *ShapeRenderer) IsDrawing() bool {
+	return self.drawing
+}
+
+// Changes the type of the shapes drawn after, while drawing
+func (self *ShapeRenderer) Set(shapeType ShapeType) {
+	self.shapeType = shapeType
+}
+
+func (self *ShapeRenderer) GetShapeType() ShapeType {
+	return self.shapeType
+}
+
+// Sets the color of the vertices of the shapes drawn after
+func (self *ShapeRenderer) SetColor(r, g, b, a float32) {
+	self.color = Color{r, g, b, a}
+}
+
+func (self *ShapeRenderer) GetColor() Color {
+	return self.color
+}
+
+// Sets the width of the lines and outlines drawn after, lines wider than 1 are drawn as quads. Default is 1.
+func (self *ShapeRenderer) SetLineWidth(width float32) {
+	self.lineWidth = width
+}
+
+func (self *ShapeRenderer) GetLineWidth() float32 {
+	return self.lineWidth
+}
+
+func (self *ShapeRenderer) GetProjectionMatrix() *vector.Matrix4 {
+	return self.projection
+}
+
+// Sets the projection matrix the shapes are drawn with, the values are copied. The shapes drawn before are flushed.
+func (self *ShapeRenderer) SetProjectionMatrix(projection *vector.Matrix4) {
+	self.flush()
+	self.projection.SetM4(projection)
+}
+
+func (self *ShapeRenderer) GetTransformMatrix() *vector.Matrix4 {
+	return self.transform
+}
+
+// Sets the matrix the vertices of the shapes drawn after are transformed by, the values are copied
+func (self *ShapeRenderer) SetTransformMatrix(transform *vector.Matrix4) {
+	self.transform.SetM4(transform)
+	self.transformValues = self.transform.GetValues()
+}
+
+// Makes room for the vertices of a shape of the primitive, flushing the vertices of other primitives
+func (self *ShapeRenderer) check(primitive, count int) {
+	if !self.drawing {
+		panic("shape renderer: Begin must be called first")
+	}
+	if primitive != self.primitive || len(self.vertices)+count*ShapeVertexSize > cap(self.vertices) {
+		self.flush()
+		self.primitive = primitive
+	}
+}
+
+func (self *ShapeRenderer) flush() {
+	if len(self.vertices) == 0 {
+		return
+	}
+	if self.Flush != nil {
+		self.Flush(self.primitive, self.vertices, self.projection)
+	}
+	self.vertices = self.vertices[:0]
+}
+
+func (self *ShapeRenderer) vertex(x, y float32, color Color) {
+	m := &self.transformValues
+	self.vertices = append(self.vertices,
+		m[vector.M4_00]*x+m[vector.M4_01]*y+m[vector.M4_03],
+		m[vector.M4_10]*x+m[vector.M4_11]*y+m[vector.M4_13],
+		color.R, color.G, color.B, color.A)
+}
+
+func (self *ShapeRenderer) triangle(x1, y1, x2, y2, x3, y3 float32, c1, c2, c3 Color) {
+	self.check(PrimitiveTriangles, 3)
+	self.vertex(x1, y1, c1)
+	self.vertex(x2, y2, c2)
+	self.vertex(x3, y3, c3)
+}
+
+func (self *ShapeRenderer) quad(x1, y1, x2, y2, x3, y3, x4, y4 float32, c1, c2, c3, c4 Color) {
+	self.check(PrimitiveTriangles, 6)
+	self.vertex(x1, y1, c1)
+	self.vertex(x2, y2, c2)
+	self.vertex(x3, y3, c3)
+	self.vertex(x3, y3, c3)
+	self.vertex(x4, y4, c4)
+	self.vertex(x1, y1, c1)
+}
+
+// Draws the edge of an outline, as a line or a quad of the line width
+func (self *ShapeRenderer) edge(x1, y1, x2, y2 float32, c1, c2 Color) {
+	if self.lineWidth > 1 {
+		self.thickLine(x1, y1, x2, y2, self.lineWidth, c1, c2)
+		return
+	}
+	self.check(PrimitiveLines, 2)
+	self.vertex(x1, y1, c1)
+	self.vertex(x2, y2, c2)
+}
+
+func (self *ShapeRenderer) thickLine(x1, y1, x2, y2, width float32, c1, c2 Color) {
+	length := float32(math.Hypot(float64(x2-x1), float64(y2-y1)))
+	if length == 0 {
+		return
+	}
+	// the normal of the line, half the width long
+	nx, ny := -(y2-y1)/length*width/2, (x2-x1)/length*width/2
+	self.quad(x1+nx, y1+ny, x1-nx, y1-ny, x2-nx, y2-ny, x2+nx, y2+ny, c1, c1, c2, c2)
+}
+
+// Draws the vertices of the closed outline as points, as edges or filled as a convex polygon
+func (self *ShapeRenderer) outline(vertices []float32, colors []Color, closed bool) {
+	n := len(vertices) / 2
+	switch {
+	case self.shapeType == ShapePoint:
+		self.check(PrimitivePoints, n)
+		for i := 0; i < n; i++ {
+			self.vertex(vertices[i*2], vertices[i*2+1], colors[i%len(colors)])
+		}
+	case self.shapeType == ShapeFilled && closed:
+		for i := 1; i < n-1; i++ {
+			self.triangle(vertices[0], vertices[1], vertices[i*2], vertices[i*2+1], vertices[i*2+2], vertices[i*2+3],
+				colors[0], colors[i%len(colors)], colors[(i+1)%len(colors)])
+		}
+	default:
+		last := n - 1
+		if closed {
+			last = n
+		}
+		for i := 0; i < last; i++ {
+			j := (i + 1) % n
+			self.edge(vertices[i*2], vertices[i*2+1], vertices[j*2], vertices[j*2+1], colors[i%len(colors)],
+				colors[j%len(colors)])
+		}
+	}
+}
+
+// Draws a point
+func (self *ShapeRenderer) Point(x, y float32) {
+	self.check(PrimitivePoints, 1)
+	self.vertex(x, y, self.color)
+}
+
+func (self *ShapeRenderer) Line(x1, y1, x2, y2 float32) {
+	self.LineColors(x1, y1, x2, y2, self.color, self.color)
+}
+
+// Draws a line whose color goes from the first color at its start to the second color at its end
+func (self *ShapeRenderer) LineColors(x1, y1, x2, y2 float32, c1, c2 Color) {
+	self.outline([]float32{x1, y1, x2, y2}, []Color{c1, c2}, false)
+}
+
+// Draws a line of the width as a quad, filled or outlined
+func (self *ShapeRenderer) RectLine(x1, y1, x2, y2, width float32) {
+	if self.shapeType == ShapeFilled {
+		self.thickLine(x1, y1, x2, y2, width, self.color, self.color)
+		return
+	}
+	length := float32(math.Hypot(float64(x2-x1), float64(y2-y1)))
+	if length == 0 {
+		return
+	}
+	nx, ny := -(y2-y1)/length*width/2, (x2-x1)/length*width/2
+	self.outline([]float32{x1 + nx, y1 + ny, x1 - nx, y1 - ny, x2 - nx, y2 - ny, x2 + nx, y2 + ny}, []Color{self.color},
+		true)
+}
+
+// Draws a rectangle with its bottom left corner at x, y
+func (self *ShapeRenderer) Rect(x, y, width, height float32) {
+	self.RectColors(x, y, width, height, self.color, self.color, self.color, self.color)
+}
+
+// Draws a rectangle with the colors of its corners counter clockwise from the bottom left one
+func (self *ShapeRenderer) RectColors(x, y, width, height float32, c1, c2, c3, c4 Color) {
+	self.outline([]float32{x, y, x + width, y, x + width, y + height, x, y + height}, []Color{c1, c2, c3, c4}, true)
+}
+
+// Draws a rectangle with its bottom left corner at x, y scaled and rotated around the origin, which is relative to the
+// corner
+func (self *ShapeRenderer) RectRotated(x, y, originX, originY, width, height, scaleX, scaleY, degrees float32) {
+	transform := vector.NewAffine2Empty().SetToTrnRotScl(x+originX, y+originY, degrees, scaleX, scaleY)
+	transform.Translate(-originX, -originY)
+	corners := []float32{0, 0, width, 0, width, height, 0, height}
+	point := vector.NewVector2Empty()
+	for i := 0; i < len(corners); i += 2 {
+		transform.ApplyTo(point.Set(corners[i], corners[i+1]))
+		corners[i], corners[i+1] = point.X, point.Y
+	}
+	self.outline(corners, []Color{self.color}, true)
+}
+
+func (self *ShapeRenderer) Triangle(x1, y1, x2, y2, x3, y3 float32) {
+	self.TriangleColors(x1, y1, x2, y2, x3, y3, self.color, self.color, self.color)
+}
+
+// Draws a triangle with a color for each of its corners
+func (self *ShapeRenderer) TriangleColors(x1, y1, x2, y2, x3, y3 float32, c1, c2, c3 Color) {
+	self.outline([]float32{x1, y1, x2, y2, x3, y3}, []Color{c1, c2, c3}, true)
+}
+
+// Returns the number of segments for a round shape of the radius, so it looks smooth
+func segmentsFor(segments int, radius float32) int {
+	if segments > 0 {
+		return segments
+	}
+	segments = int(6 * math.Cbrt(float64(radius)))
+	if segments < 1 {
+		segments = 1
+	}
+	return segments
+}
+
+// Draws a circle centered at x, y with the number of segments, or enough segments to look round if it is not positive
+func (self *ShapeRenderer) Circle(x, y, radius float32, segments int) {
+	self.Ellipse(x, y, radius*2, radius*2, segments)
+}
+
+// Draws an ellipse centered at x, y with the number of segments, or enough segments to look round if it is not positive
+func (self *ShapeRenderer) Ellipse(x, y, width, height float32, segments int) {
+	segments = segmentsFor(segments, float32(math.Max(float64(width), float64(height)))/2)
+	vertices := make([]float32, 0, segments*2+2)
+	if self.shapeType == ShapeFilled {
+		// a fan from the center
+		vertices = append(vertices, x, y)
+	}
+	for i := 0; i <= segments; i++ {
+		if i == segments && self.shapeType != ShapeFilled {
+			break
+		}
+		angle := 2 * math.Pi * float64(i) / float64(segments)
+		vertices = append(vertices, x+width/2*float32(math.Cos(angle)), y+height/2*float32(math.Sin(angle)))
+	}
+	self.outline(vertices, []Color{self.color}, true)
+}
+
+// Draws an arc of the circle centered at x, y from the start angle counter clockwise by the degrees. Filled arcs are
+// pie slices and outlined arcs are closed by the lines to the center.
+func (self *ShapeRenderer) Arc(x, y, radius, start, degrees float32, segments int) {
+	segments = segmentsFor(segments, radius)
+	vertices := make([]float32, 0, segments*2+4)
+	vertices = append(vertices, x, y)
+	for i := 0; i <= segments; i++ {
+		angle := float64(start+degrees*float32(i)/float32(segments)) * math.Pi / 180
+		vertices = append(vertices, x+radius*float32(math.Cos(angle)), y+radius*float32(math.Sin(angle)))
+	}
+	self.outline(vertices, []Color{self.color}, true)
+}
+
+// Draws the polygon of the x, y pairs of the vertices. Filled polygons may be concave, they are cut in triangles.
+func (self *ShapeRenderer) Polygon(vertices []float32) {
+	if len(vertices) < 6 {
+		panic("shape renderer: polygons need at least 3 vertices")
+	}
+	if self.shapeType != ShapeFilled {
+		self.outline(vertices, []Color{self.color}, true)
+		return
+	}
+	indices := triangulate(vertices)
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := indices[i]*2, indices[i+1]*2, indices[i+2]*2
+		self.triangle(vertices[a], vertices[a+1], vertices[b], vertices[b+1], vertices[c], vertices[c+1], self.color,
+			self.color, self.color)
+	}
+}
+
+// Draws the lines between the x, y pairs of the vertices, the line is not closed
+func (self *ShapeRenderer) Polyline(vertices []float32) {
+	if len(vertices) < 4 {
+		panic("shape renderer: polylines need at least 2 vertices")
+	}
+	self.outline(vertices, []Color{self.color}, false)
+}
+
+// Draws a cross centered at x, y whose lines are size long
+func (self *ShapeRenderer) X(x, y, size float32) {
+	self.outline([]float32{x - size/2, y - size/2, x + size/2, y + size/2}, []Color{self.color}, false)
+	self.outline([]float32{x - size/2, y + size/2, x + size/2, y - size/2}, []Color{self.color}, false)
+}
+
+func (self *ShapeRenderer) DrawRectangle(rectangle *shape.Rectangle) {
+	self.Rect(rectangle.X, rectangle.Y, rectangle.W, rectangle.H)
+}
+
+func (self *ShapeRenderer) DrawCircle(circle *shape.Circle) {
+	self.Circle(circle.X, circle.Y, circle.Radius, 0)
+}
+
+func (self *ShapeRenderer) DrawEllipse(ellipse *shape.Ellipse) {
+	self.Ellipse(ellipse.X, ellipse.Y, ellipse.W, ellipse.H, 0)
+}
+
+// Draws the polygon with its position, rotation and scale
+func (self *ShapeRenderer) DrawPolygon(polygon *shape.Polygon) {
+	self.Polygon(polygon.GetTransformedVertices())
+}
+
+// Draws the polyline with its position, rotation and scale
+func (self *ShapeRenderer) DrawPolyline(polyline *shape.Polyline) {
+	self.Polyline(polyline.GetTransformedVertices())
+}
+
+// Cuts the polygon in triangles by clipping its ears, returns the indices of the vertices of the triangles
+func triangulate(vertices []float32) []int {
+	n := len(vertices) / 2
+	area := float32(0)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += vertices[i*2]*vertices[j*2+1] - vertices[j*2]*vertices[i*2+1]
+	}
+	// the vertices left, counter clockwise
+	left := make([]int, n)
+	for i := range left {
+		left[i] = i
+		if area < 0 {
+			left[i] = n - 1 - i
+		}
+	}
+	cross := func(a, b, c int) float32 {
+		return (vertices[b*2]-vertices[a*2])*(vertices[c*2+1]-vertices[a*2+1]) -
+			(vertices[b*2+1]-vertices[a*2+1])*(vertices[c*2]-vertices[a*2])
+	}
+	indices := make([]int, 0, (n-2)*3)
+	for len(left) > 3 {
+		ear := -1
+		for i := range left {
+			a, b, c := left[(i+len(left)-1)%len(left)], left[i], left[(i+1)%len(left)]
+			if cross(a, b, c) <= 0 {
+				continue
+			}
+			inside := false
+			for _, p := range left {
+				if p != a && p != b && p != c && cross(a, b, p) >= 0 && cross(b, c, p) >= 0 && cross(c, a, p) >= 0 {
+					inside = true
+					break
+				}
+			}
+			if !inside {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			// the polygon crosses itself, the rest is drawn as a fan
+			break
+		}
+		indices = append(indices, left[(ear+len(left)-1)%len(left)], left[ear], left[(ear+1)%len(left)])
+		left = append(left[:ear], left[ear+1:]...)
+	}
+	for i := 1; i+1 < len(left); i++ {
+		indices = append(indices, left[0], left[i], left[i+1])
+	}
+	return indices
+}
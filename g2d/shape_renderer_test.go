@@ -0,0 +1,249 @@
+package g2d
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/pyros2097/spike/math/vector"
+)
+
+type flushedShapes struct {
+	primitive int
+	vertices  []float32
+}
+
+// Returns a shape renderer whose flushes are recorded
+func newRecordingShapes() (*ShapeRenderer, *[]flushedShapes) {
+	var flushed []flushedShapes
+	shapes := NewShapeRenderer()
+	shapes.Flush = func(primitive int, vertices []float32, projection *vector.Matrix4) {
+		flushed = append(flushed, flushedShapes{primitive, append([]float32(nil), vertices...)})
+	}
+	return shapes, &flushed
+}
+
+// Returns the x, y pairs of the vertices
+func positions(vertices []float32) []float32 {
+	var xy []float32
+	for i := 0; i < len(vertices); i += ShapeVertexSize {
+		xy = append(xy, vertices[i], vertices[i+1])
+	}
+	return xy
+}
+
+// Returns the colors of the vertices
+func colors(vertices []float32) []Color {
+	var c []Color
+	for i := 0; i < len(vertices); i += ShapeVertexSize {
+		c = append(c, Color{R: vertices[i+2], G: vertices[i+3], B: vertices[i+4], A: vertices[i+5]})
+	}
+	return c
+}
+
+func TestShapeOutlines(t *testing.T) {
+	shapes, flushed := newRecordingShapes()
+	shapes.Begin(ShapeLine)
+	shapes.Rect(1, 2, 10, 5)
+	shapes.Polyline([]float32{0, 0, 1, 0, 1, 1})
+	shapes.End()
+	if len(*flushed) != 1 || (*flushed)[0].primitive != PrimitiveLines {
+		t.Fatalf("flushed %v", *flushed)
+	}
+	expected := []float32{1, 2, 11, 2, 11, 2, 11, 7, 11, 7, 1, 7, 1, 7, 1, 2, 0, 0, 1, 0, 1, 0, 1, 1}
+	if xy := positions((*flushed)[0].vertices); !reflect.DeepEqual(xy, expected) {
+		t.Errorf("outline %v", xy)
+	}
+
+	// filled shapes are fans from the first vertex with the colors of their corners
+	red, green, blue, white := Color{R: 1, A: 1}, Color{G: 1, A: 1}, Color{B: 1, A: 1}, Color{R: 1, G: 1, B: 1, A: 1}
+	*flushed = nil
+	shapes.Begin(ShapeFilled)
+	shapes.RectColors(0, 0, 2, 1, red, green, blue, white)
+	shapes.End()
+	if len(*flushed) != 1 || (*flushed)[0].primitive != PrimitiveTriangles {
+		t.Fatalf("flushed %v", *flushed)
+	}
+	if xy := positions((*flushed)[0].vertices); !reflect.DeepEqual(xy, []float32{0, 0, 2, 0, 2, 1, 0, 0, 2, 1, 0, 1}) {
+		t.Errorf("filled rect %v", xy)
+	}
+	if c := colors((*flushed)[0].vertices); !reflect.DeepEqual(c, []Color{red, green, blue, red, blue, white}) {
+		t.Errorf("filled rect colors %v", c)
+	}
+
+	// a filled circle is a fan from its center
+	*flushed = nil
+	shapes.Begin(ShapeFilled)
+	shapes.Circle(5, 5, 1, 4)
+	shapes.End()
+	vertices := (*flushed)[0].vertices
+	if len(vertices) != 4*3*ShapeVertexSize {
+		t.Fatalf("circle has %d vertices", len(vertices)/ShapeVertexSize)
+	}
+	for i := 0; i < len(vertices); i += 3 * ShapeVertexSize {
+		if vertices[i] != 5 || vertices[i+1] != 5 {
+			t.Errorf("circle triangle %d does not start at the center", i/3/ShapeVertexSize)
+		}
+	}
+
+	// the points of the vertices
+	*flushed = nil
+	shapes.Begin(ShapePoint)
+	shapes.Triangle(0, 0, 1, 0, 0, 1)
+	shapes.End()
+	if len(*flushed) != 1 || (*flushed)[0].primitive != PrimitivePoints ||
+		!reflect.DeepEqual(positions((*flushed)[0].vertices), []float32{0, 0, 1, 0, 0, 1}) {
+		t.Errorf("points %v", *flushed)
+	}
+}
+
+func TestShapeFlushes(t *testing.T) {
+	shapes, flushed := newRecordingShapes()
+	shapes.Begin(ShapeLine)
+	shapes.Line(0, 0, 1, 1)
+	shapes.Point(2, 2)
+	shapes.Line(3, 3, 4, 4)
+	shapes.End()
+	var primitives []int
+	for _, f := range *flushed {
+		primitives = append(primitives, f.primitive)
+	}
+	if !reflect.DeepEqual(primitives, []int{PrimitiveLines, PrimitivePoints, PrimitiveLines}) {
+		t.Errorf("primitives flushed %v", primitives)
+	}
+
+	// the buffer is flushed when it is full
+	*flushed = nil
+	shapes.Begin(ShapeLine)
+	for i := 0; i < maxShapeVertices; i++ {
+		shapes.Line(0, 0, 1, 1)
+	}
+	shapes.End()
+	if len(*flushed) != 2 || len((*flushed)[0].vertices) != maxShapeVertices*ShapeVertexSize ||
+		len((*flushed)[1].vertices) != maxShapeVertices*ShapeVertexSize {
+		t.Errorf("flushed %d times", len(*flushed))
+	}
+
+	// the vertices are transformed
+	*flushed = nil
+	shapes.SetTransformMatrix(vector.NewMatrix4Empty().SetToTranslationAndScaling(10, 20, 0, 2, 3, 1))
+	shapes.Begin(ShapeLine)
+	shapes.Line(1, 1, 2, 2)
+	shapes.End()
+	if xy := positions((*flushed)[0].vertices); !reflect.DeepEqual(xy, []float32{12, 23, 14, 26}) {
+		t.Errorf("transformed line %v", xy)
+	}
+}
+
+func TestThickLines(t *testing.T) {
+	shapes, flushed := newRecordingShapes()
+	shapes.SetLineWidth(4)
+	shapes.Begin(ShapeLine)
+	shapes.Line(0, 0, 10, 0)
+	shapes.End()
+	if len(*flushed) != 1 || (*flushed)[0].primitive != PrimitiveTriangles {
+		t.Fatalf("flushed %v", *flushed)
+	}
+	// a quad around the line, half the width on each side
+	expected := []float32{0, 2, 0, -2, 10, -2, 10, -2, 10, 2, 0, 2}
+	if xy := positions((*flushed)[0].vertices); !reflect.DeepEqual(xy, expected) {
+		t.Errorf("thick line %v", xy)
+	}
+
+	// a filled rect line has its own width
+	*flushed = nil
+	shapes.Begin(ShapeFilled)
+	shapes.RectLine(0, 0, 0, 10, 2)
+	shapes.End()
+	expected = []float32{-1, 0, 1, 0, 1, 10, 1, 10, -1, 10, -1, 0}
+	if xy := positions((*flushed)[0].vertices); !reflect.DeepEqual(xy, expected) {
+		t.Errorf("filled rect line %v", xy)
+	}
+
+	// lines of no length are not drawn
+	*flushed = nil
+	shapes.Begin(ShapeLine)
+	shapes.Line(5, 5, 5, 5)
+	shapes.End()
+	if len(*flushed) != 0 {
+		t.Errorf("empty line drawn %v", *flushed)
+	}
+}
+
+func near(a, b, epsilon float32) bool {
+	return math.Abs(float64(a-b)) <= float64(epsilon)
+}
+
+// Returns the signed area of the polygon, positive if it is counter clockwise
+func polygonArea(vertices []float32) float32 {
+	area := float32(0)
+	n := len(vertices) / 2
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += vertices[i*2]*vertices[j*2+1] - vertices[j*2]*vertices[i*2+1]
+	}
+	return area / 2
+}
+
+// Returns if the point is inside the polygon, by counting the edges crossed by a ray to the right
+func insidePolygon(vertices []float32, x, y float32) bool {
+	inside := false
+	n := len(vertices) / 2
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		xi, yi, xj, yj := vertices[i*2], vertices[i*2+1], vertices[j*2], vertices[j*2+1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func TestTriangulate(t *testing.T) {
+	polygons := map[string][]float32{
+		"L":         {0, 0, 2, 0, 2, 1, 1, 1, 1, 2, 0, 2},
+		"clockwise": {0, 2, 1, 2, 1, 1, 2, 1, 2, 0, 0, 0},
+		"comb":      {0, 0, 5, 0, 5, 3, 4, 3, 4, 1, 3, 1, 3, 3, 2, 3, 2, 1, 1, 1, 1, 3, 0, 3},
+		"arrow":     {0, 0, 2, 1, 4, 0, 2, 4},
+	}
+	for name, vertices := range polygons {
+		n := len(vertices) / 2
+		indices := triangulate(vertices)
+		if len(indices) != (n-2)*3 {
+			t.Errorf("%s: %d triangles", name, len(indices)/3)
+			continue
+		}
+		total := float32(0)
+		for i := 0; i < len(indices); i += 3 {
+			a, b, c := indices[i]*2, indices[i+1]*2, indices[i+2]*2
+			triangle := []float32{vertices[a], vertices[a+1], vertices[b], vertices[b+1], vertices[c], vertices[c+1]}
+			area := polygonArea(triangle)
+			if area <= 0 {
+				t.Errorf("%s: triangle %v is not counter clockwise", name, triangle)
+			}
+			cx, cy := (triangle[0]+triangle[2]+triangle[4])/3, (triangle[1]+triangle[3]+triangle[5])/3
+			if !insidePolygon(vertices, cx, cy) {
+				t.Errorf("%s: triangle %v is outside", name, triangle)
+			}
+			total += area
+		}
+		if area := polygonArea(vertices); !near(total, area, 0.0001) && !near(total, -area, 0.0001) {
+			t.Errorf("%s: triangles cover %v of %v", name, total, area)
+		}
+	}
+
+	// filled polygons are drawn as the triangles
+	shapes, flushed := newRecordingShapes()
+	shapes.Begin(ShapeFilled)
+	shapes.Polygon(polygons["L"])
+	shapes.End()
+	if len(*flushed) != 1 || len((*flushed)[0].vertices) != 4*3*ShapeVertexSize {
+		t.Errorf("filled polygon %v", *flushed)
+	}
+	*flushed = nil
+	shapes.Begin(ShapeLine)
+	shapes.Polygon(polygons["L"])
+	shapes.End()
+	if len(*flushed) != 1 || len((*flushed)[0].vertices) != 6*2*ShapeVertexSize {
+		t.Errorf("outlined polygon %v", *flushed)
+	}
+}
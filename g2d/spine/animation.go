@@ -0,0 +1,305 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spine
+
+// An Animation changes the pose of a skeleton over time with its timelines
+type Animation struct {
+	Name      string
+	Timelines []Timeline
+	Duration  float32
+}
+
+// A Timeline changes a property of a bone or slot with the values of its key frames
+type Timeline interface {
+	// Sets the property to the value at the time, mixed with the current value by alpha. The timeline is not applied
+	// before its first key frame.
+	Apply(skeleton *Skeleton, time, alpha float32)
+
+	// Identifies the property of the bone or slot the timeline changes
+	property() int
+}
+
+// The kinds of properties timelines change, which are combined with the index of the bone or slot
+const (
+	propertyRotate = iota << 24
+	propertyTranslate
+	propertyScale
+	propertyColor
+	propertyAttachment
+)
+
+// Applies the timelines to the skeleton at the time, which is wrapped around the duration if the animation loops. The
+// properties are mixed with their current values by alpha.
+func (self *Animation) Apply(skeleton *Skeleton, time float32, loop bool, alpha float32) {
+	time = self.wrapTime(time, loop)
+	for _, timeline := range self.Timelines {
+		timeline.Apply(skeleton, time, alpha)
+	}
+}
+
+func (self *Animation) wrapTime(time float32, loop bool) float32 {
+	if loop && self.Duration > 0 {
+		time -= float32(int(time/self.Duration)) * self.Duration
+	}
+	return time
+}
+
+// Returns whether the animation has a timeline for the property
+func (self *Animation) hasProperty(property int) bool {
+	for _, timeline := range self.Timelines {
+		if timeline.property() == property {
+			return true
+		}
+	}
+	return false
+}
+
+// The interpolation between a key frame and the next one
+const (
+	curveLinear = iota
+	curveStepped
+	curveBezier
+)
+
+// The curves of the key frames of a timeline. A Bezier curve goes from (0, 0) to (1, 1), the time between two key frames
+// and the change of the value, with the control points (cx1, cy1) and (cx2, cy2).
+type curves struct {
+	kinds  []int
+	bezier [][4]float32
+}
+
+func newCurves(frames int) curves {
+	return curves{kinds: make([]int, frames), bezier: make([][4]float32, frames)}
+}
+
+func (self *curves) setStepped(frame int) {
+	self.kinds[frame] = curveStepped
+}
+
+func (self *curves) setBezier(frame int, cx1, cy1, cx2, cy2 float32) {
+	self.kinds[frame] = curveBezier
+	self.bezier[frame] = [4]float32{cx1, cy1, cx2, cy2}
+}
+
+// Returns the change of the value for the fraction of the time from the key frame to the next one
+func (self *curves) percent(frame int, percent float32) float32 {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 1 {
+		percent = 1
+	}
+	switch self.kinds[frame] {
+	case curveStepped:
+		return 0
+	case curveBezier:
+		c := self.bezier[frame]
+		// the x of the curve increases with t, so the t where the curve is at the time is found by bisection
+		low, high := float32(0), float32(1)
+		t := percent
+		for i := 0; i < 24; i++ {
+			if bezier(t, c[0], c[2]) < percent {
+				low = t
+			} else {
+				high = t
+			}
+			t = (low + high) / 2
+		}
+		return bezier(t, c[1], c[3])
+	}
+	return percent
+}
+
+// Returns a coordinate of the cubic Bezier curve from 0 to 1 with the control points p1 and p2 at t
+func bezier(t, p1, p2 float32) float32 {
+	u := 1 - t
+	return 3*u*u*t*p1 + 3*u*t*t*p2 + t*t*t
+}
+
+// Returns the index of the last key frame at or before the time, -1 if the time is before the first key frame
+func searchFrame(times []float32, time float32) int {
+	frame := -1
+	for i, t := range times {
+		if t > time {
+			break
+		}
+		frame = i
+	}
+	return frame
+}
+
+// Returns the key frame before the time and the fraction of the time to the next key frame mapped by the curve, which is 0
+// after the last key frame
+func (self *curves) frameAt(times []float32, time float32) (frame int, percent float32) {
+	frame = searchFrame(times, time)
+	if frame < 0 || frame == len(times)-1 {
+		return frame, 0
+	}
+	percent = (time - times[frame]) / (times[frame+1] - times[frame])
+	return frame, self.percent(frame, percent)
+}
+
+// Wraps the angle in degrees to the range [-180, 180]
+func wrapDegrees(degrees float32) float32 {
+	return degrees - float32(16384-int(16384.499999999996-degrees/360))*360
+}
+
+// Changes the rotation of a bone relative to its setup rotation, always taking the shortest way between two angles
+type RotateTimeline struct {
+	curves
+	Bone   int
+	Times  []float32
+	Angles []float32
+}
+
+func NewRotateTimeline(bone, frames int) *RotateTimeline {
+	return &RotateTimeline{curves: newCurves(frames), Bone: bone, Times: make([]float32, frames),
+		Angles: make([]float32, frames)}
+}
+
+func (self *RotateTimeline) property() int {
+	return propertyRotate + self.Bone
+}
+
+func (self *RotateTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
+	frame, percent := self.frameAt(self.Times, time)
+	if frame < 0 {
+		return
+	}
+	bone := skeleton.Bones[self.Bone]
+	angle := self.Angles[frame]
+	if percent != 0 {
+		angle += wrapDegrees(self.Angles[frame+1]-angle) * percent
+	}
+	bone.Rotation += wrapDegrees(bone.Data.Rotation+angle-bone.Rotation) * alpha
+}
+
+// Changes the position of a bone relative to its setup position
+type TranslateTimeline struct {
+	curves
+	Bone  int
+	Times []float32
+	X, Y  []float32
+}
+
+func NewTranslateTimeline(bone, frames int) *TranslateTimeline {
+	return &TranslateTimeline{curves: newCurves(frames), Bone: bone, Times: make([]float32, frames),
+		X: make([]float32, frames), Y: make([]float32, frames)}
+}
+
+func (self *TranslateTimeline) property() int {
+	return propertyTranslate + self.Bone
+}
+
+func (self *TranslateTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
+	frame, percent := self.frameAt(self.Times, time)
+	if frame < 0 {
+		return
+	}
+	bone := skeleton.Bones[self.Bone]
+	x, y := self.X[frame], self.Y[frame]
+	if percent != 0 {
+		x += (self.X[frame+1] - x) * percent
+		y += (self.Y[frame+1] - y) * percent
+	}
+	bone.X += (bone.Data.X + x - bone.X) * alpha
+	bone.Y += (bone.Data.Y + y - bone.Y) * alpha
+}
+
+// Changes the scale of a bone as a multiple of its setup scale
+type ScaleTimeline struct {
+	curves
+	Bone  int
+	Times []float32
+	X, Y  []float32
+}
+
+func NewScaleTimeline(bone, frames int) *ScaleTimeline {
+	return &ScaleTimeline{curves: newCurves(frames), Bone: bone, Times: make([]float32, frames),
+		X: make([]float32, frames), Y: make([]float32, frames)}
+}
+
+func (self *ScaleTimeline) property() int {
+	return propertyScale + self.Bone
+}
+
+func (self *ScaleTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
+	frame, percent := self.frameAt(self.Times, time)
+	if frame < 0 {
+		return
+	}
+	bone := skeleton.Bones[self.Bone]
+	x, y := self.X[frame], self.Y[frame]
+	if percent != 0 {
+		x += (self.X[frame+1] - x) * percent
+		y += (self.Y[frame+1] - y) * percent
+	}
+	bone.ScaleX += (bone.Data.ScaleX*x - bone.ScaleX) * alpha
+	bone.ScaleY += (bone.Data.ScaleY*y - bone.ScaleY) * alpha
+}
+
+// Changes the color of a slot
+type ColorTimeline struct {
+	curves
+	Slot   int
+	Times  []float32
+	Colors []Color
+}
+
+func NewColorTimeline(slot, frames int) *ColorTimeline {
+	return &ColorTimeline{curves: newCurves(frames), Slot: slot, Times: make([]float32, frames),
+		Colors: make([]Color, frames)}
+}
+
+func (self *ColorTimeline) property() int {
+	return propertyColor + self.Slot
+}
+
+func (self *ColorTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
+	frame, percent := self.frameAt(self.Times, time)
+	if frame < 0 {
+		return
+	}
+	slot := skeleton.Slots[self.Slot]
+	color := self.Colors[frame]
+	if percent != 0 {
+		next := self.Colors[frame+1]
+		color.R += (next.R - color.R) * percent
+		color.G += (next.G - color.G) * percent
+		color.B += (next.B - color.B) * percent
+		color.A += (next.A - color.A) * percent
+	}
+	slot.Color.R += (color.R - slot.Color.R) * alpha
+	slot.Color.G += (color.G - slot.Color.G) * alpha
+	slot.Color.B += (color.B - slot.Color.B) * alpha
+	slot.Color.A += (color.A - slot.Color.A) * alpha
+}
+
+// Changes the attachment of a slot, an empty name removes it. Attachments are not mixed, the attachment of the key frame
+// is set whenever the timeline is applied.
+type AttachmentTimeline struct {
+	Slot  int
+	Times []float32
+	Names []string
+}
+
+func NewAttachmentTimeline(slot, frames int) *AttachmentTimeline {
+	return &AttachmentTimeline{Slot: slot, Times: make([]float32, frames), Names: make([]string, frames)}
+}
+
+func (self *AttachmentTimeline) property() int {
+	return propertyAttachment + self.Slot
+}
+
+func (self *AttachmentTimeline) Apply(skeleton *Skeleton, time, alpha float32) {
+	frame := searchFrame(self.Times, time)
+	if frame < 0 || alpha <= 0 {
+		return
+	}
+	slot := skeleton.Slots[self.Slot]
+	slot.Attachment = nil
+	if name := self.Names[frame]; name != "" {
+		slot.Attachment = skeleton.GetAttachment(self.Slot, name)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spine
+
+import (
+	"errors"
+)
+
+// AnimationStateData holds the durations of the crossfades between animations
+type AnimationStateData struct {
+	SkeletonData *SkeletonData
+
+	// The duration of the crossfades between animations which have no mix duration set
+	DefaultMix float32
+
+	mixes map[[2]*Animation]float32
+}
+
+func NewAnimationStateData(data *SkeletonData) *AnimationStateData {
+	return &AnimationStateData{SkeletonData: data, mixes: make(map[[2]*Animation]float32)}
+}
+
+// Sets the duration of the crossfade from an animation to another by their names
+func (self *AnimationStateData) SetMix(from, to string, duration float32) error {
+	fromAnimation := self.SkeletonData.FindAnimation(from)
+	if fromAnimation == nil {
+		return errors.New("spine: animation not found " + from)
+	}
+	toAnimation := self.SkeletonData.FindAnimation(to)
+	if toAnimation == nil {
+		return errors.New("spine: animation not found " + to)
+	}
+	self.mixes[[2]*Animation{fromAnimation, toAnimation}] = duration
+	return nil
+}
+
+// Returns the duration of the crossfade from an animation to another
+func (self *AnimationStateData) GetMix(from, to *Animation) float32 {
+	if duration, ok := self.mixes[[2]*Animation{from, to}]; ok {
+		return duration
+	}
+	return self.DefaultMix
+}
+
+// A TrackEntry is an animation played on a track of an AnimationState
+type TrackEntry struct {
+	Animation *Animation
+	Loop      bool
+
+	// The time in seconds the animation has been played for
+	Time float32
+
+	// For queued entries, the time of the entry before which has to pass until this one is played
+	Delay float32
+
+	// Multiplies the time the animation is advanced by. Default is 1.
+	TimeScale float32
+
+	// How much the animation changes the pose of the lower tracks, from 0 to 1. Default is 1.
+	Alpha float32
+
+	// The duration of the crossfade from the animation played before on the track
+	MixDuration float32
+
+	// Called every time the animation has been played to its end, once for animations which do not loop
+	OnComplete func(entry *TrackEntry)
+
+	mixTime  float32
+	previous *TrackEntry
+	next     *TrackEntry
+}
+
+// Returns whether an animation which does not loop has been played to its end
+func (self *TrackEntry) IsComplete() bool {
+	return !self.Loop && self.Time >= self.Animation.Duration
+}
+
+// Returns the fraction of the crossfade from the animation played before which is done, 1 if there is none
+func (self *TrackEntry) GetMixPercent() float32 {
+	if self.previous == nil || self.MixDuration <= 0 || self.mixTime >= self.MixDuration {
+		return 1
+	}
+	return self.mixTime / self.MixDuration
+}
+
+// An AnimationState plays animations on tracks and crossfades between the animations played on the same track. The
+// animations of higher tracks are applied over the ones of the lower tracks.
+type AnimationState struct {
+	Data *AnimationStateData
+
+	// Multiplies the time the animations are advanced by. Default is 1.
+	TimeScale float32
+
+	tracks []*TrackEntry
+}
+
+func NewAnimationState(data *AnimationStateData) *AnimationState {
+	return &AnimationState{Data: data, TimeScale: 1}
+}
+
+// Plays the animation on the track, replacing the queued animations. It is crossfaded from the animation that was played
+// on the track.
+func (self *AnimationState) SetAnimation(track int, animation *Animation, loop bool) *TrackEntry {
+	for len(self.tracks) <= track {
+		self.tracks = append(self.tracks, nil)
+	}
+	entry := newTrackEntry(animation, loop)
+	self.setCurrent(track, entry)
+	return entry
+}
+
+// Queues the animation to be played on the track after the last animation queued. It is started when the entry before has
+// been played for delay seconds. If the delay is not positive it is added to the duration of the entry before minus the
+// mix duration, so the animation is started when the one before ends.
+func (self *AnimationState) AddAnimation(track int, animation *Animation, loop bool, delay float32) *TrackEntry {
+	last := self.GetCurrent(track)
+	if last == nil {
+		entry := self.SetAnimation(track, animation, loop)
+		if delay > 0 {
+			entry.Delay = delay
+		}
+		return entry
+	}
+	for last.next != nil {
+		last = last.next
+	}
+	entry := newTrackEntry(animation, loop)
+	entry.MixDuration = self.Data.GetMix(last.Animation, animation)
+	if delay <= 0 {
+		delay += last.Animation.Duration - entry.MixDuration
+		if delay < 0 {
+			delay = 0
+		}
+	}
+	entry.Delay = delay
+	last.next = entry
+	return entry
+}
+
+func newTrackEntry(animation *Animation, loop bool) *TrackEntry {
+	return &TrackEntry{Animation: animation, Loop: loop, TimeScale: 1, Alpha: 1}
+}
+
+// Returns the entry played on the track, or nil
+func (self *AnimationState) GetCurrent(track int) *TrackEntry {
+	if track >= len(self.tracks) {
+		return nil
+	}
+	return self.tracks[track]
+}
+
+// Stops playing the track, the pose it has set is kept
+func (self *AnimationState) ClearTrack(track int) {
+	if track < len(self.tracks) {
+		self.tracks[track] = nil
+	}
+}
+
+func (self *AnimationState) ClearTracks() {
+	self.tracks = self.tracks[:0]
+}
+
+func (self *AnimationState) setCurrent(track int, entry *TrackEntry) {
+	if current := self.tracks[track]; current != nil {
+		entry.MixDuration = self.Data.GetMix(current.Animation, entry.Animation)
+		if entry.MixDuration > 0 {
+			// a crossfade which is interrupted is continued from the animation that was faded in
+			current.previous = nil
+			entry.previous = current
+		}
+	}
+	self.tracks[track] = entry
+}
+
+// Advances the animations and crossfades by the time and starts the queued animations which are due
+func (self *AnimationState) Update(delta float32) {
+	delta *= self.TimeScale
+	for track, entry := range self.tracks {
+		if entry == nil {
+			continue
+		}
+		entryDelta := delta * entry.TimeScale
+		if entry.Delay > 0 {
+			entry.Delay -= entryDelta
+			if entry.Delay > 0 {
+				continue
+			}
+			entryDelta = -entry.Delay
+			entry.Delay = 0
+		}
+		entry.advance(entryDelta)
+		if previous := entry.previous; previous != nil {
+			previous.Time += delta * previous.TimeScale
+			entry.mixTime += entryDelta
+			if entry.mixTime >= entry.MixDuration {
+				entry.previous = nil
+			}
+		}
+		if next := entry.next; next != nil && entry.Time >= next.Delay {
+			overtime := entry.Time - next.Delay
+			next.Delay = 0
+			self.setCurrent(track, next)
+			next.advance(overtime)
+			next.mixTime = overtime
+		}
+	}
+}
+
+// Advances the time of the entry and calls OnComplete when the end of the animation is passed
+func (self *TrackEntry) advance(delta float32) {
+	before := self.Time
+	self.Time += delta
+	duration := self.Animation.Duration
+	if self.OnComplete == nil || delta <= 0 {
+		return
+	}
+	if !self.Loop {
+		if before < duration && self.Time >= duration {
+			self.OnComplete(self)
+		}
+		return
+	}
+	if duration > 0 {
+		for loops := int(self.Time/duration) - int(before/duration); loops > 0; loops-- {
+			self.OnComplete(self)
+		}
+	}
+}
+
+// Poses the skeleton with the animations of the tracks. The skeleton is set to its setup pose first, so the bones and
+// slots which are not animated are in their setup pose. While an animation is crossfaded, the properties it shares with
+// the animation before are mixed between them and the others between the setup pose and the animations.
+func (self *AnimationState) Apply(skeleton *Skeleton) {
+	skeleton.SetToSetupPose()
+	for _, entry := range self.tracks {
+		if entry == nil || entry.Delay > 0 {
+			continue
+		}
+		mix := entry.GetMixPercent()
+		if previous := entry.previous; previous != nil && mix < 1 {
+			time := previous.Animation.wrapTime(previous.Time, previous.Loop)
+			for _, timeline := range previous.Animation.Timelines {
+				alpha := previous.Alpha * entry.Alpha
+				if !entry.Animation.hasProperty(timeline.property()) {
+					alpha *= 1 - mix
+				}
+				timeline.Apply(skeleton, time, alpha)
+			}
+		}
+		entry.Animation.Apply(skeleton, entry.Time, entry.Loop, entry.Alpha*mix)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package spine is a runtime for skeletal animations exported by Spine as JSON, the format of the Spine 3 versions which
+// DragonBones can export to as well.
+//
+// The setup pose of a skeleton, its bones, slots, skins and animations are read into a SkeletonData which is shared by the
+// skeletons created from it. A Skeleton is posed by applying animations to it, directly or with an AnimationState which
+// plays and mixes animations on tracks, and then by computing the world transforms of its bones:
+//
+//	data, err := spine.ReadSkeletonData(file, atlas, 1)
+//	skeleton := spine.NewSkeleton(data)
+//	state := spine.NewAnimationState(spine.NewAnimationStateData(data))
+//	state.SetAnimation(0, data.FindAnimation("walk"), true)
+//	...
+//	state.Update(delta)
+//	state.Apply(skeleton)
+//	skeleton.UpdateWorldTransform()
+//	skeleton.Draw(batch, transform, 1, 1, 1, 1)
+//
+// Only region attachments are supported, the other kinds of attachments are left out when the data is read.
+package spine
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// A color with the red, green, blue and alpha components in the range [0, 1]
+type Color struct {
+	R, G, B, A float32
+}
+
+var white = Color{1, 1, 1, 1}
+
+// Parses a color in the hex format rrggbbaa, the alpha may be left out
+func parseColor(hex string) (Color, error) {
+	if len(hex) != 6 && len(hex) != 8 {
+		return white, errors.New("spine: invalid color " + hex)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return white, errors.New("spine: invalid color " + hex)
+	}
+	return Color{
+		R: float32(value>>24&0xff) / 255,
+		G: float32(value>>16&0xff) / 255,
+		B: float32(value>>8&0xff) / 255,
+		A: float32(value&0xff) / 255,
+	}, nil
+}
+
+// Multiplies the color with the other color
+func (self *Color) mul(other Color) {
+	self.R *= other.R
+	self.G *= other.G
+	self.B *= other.B
+	self.A *= other.A
+}
+
+// The setup pose of a bone. Bones are ordered so that the parents come before their children.
+type BoneData struct {
+	Index  int
+	Name   string
+	Parent *BoneData
+	Length float32
+
+	// The position, rotation in degrees, scale and shear in degrees relative to the parent bone
+	X, Y, Rotation, ScaleX, ScaleY, ShearX, ShearY float32
+}
+
+// The setup pose of a slot, which holds the attachment drawn for a bone. Slots are ordered as they are drawn.
+type SlotData struct {
+	Index int
+	Name  string
+	Bone  *BoneData
+	Color Color
+
+	// The name of the attachment which is visible in the setup pose, empty if there is none
+	AttachmentName string
+}
+
+// A RegionAttachment is an image of an atlas which is drawn centered at its position relative to its bone
+type RegionAttachment struct {
+	Name   string
+	Path   string
+	Region *g2d.AtlasRegion
+
+	X, Y, Rotation, ScaleX, ScaleY, Width, Height float32
+	Color                                         Color
+
+	transform *vector.Affine2
+}
+
+// Computes the transform of the bottom left corner of the attachment to the coordinates of its bone, it must be called
+// after the position, rotation, scale or size of the attachment are changed
+func (self *RegionAttachment) UpdateTransform() {
+	self.transform = vector.NewAffine2Empty().SetToTrnRotScl(self.X, self.Y, self.Rotation, self.ScaleX, self.ScaleY)
+	self.transform.Translate(-self.Width/2, -self.Height/2)
+}
+
+// Sets the vertex to the corner of the attachment in the coordinates of the bone, the corners are numbered counter
+// clockwise from the bottom left one
+func (self *RegionAttachment) GetCorner(corner int, vertex *vector.Vector2) *vector.Vector2 {
+	switch corner {
+	case 0:
+		vertex.Set(0, 0)
+	case 1:
+		vertex.Set(self.Width, 0)
+	case 2:
+		vertex.Set(self.Width, self.Height)
+	default:
+		vertex.Set(0, self.Height)
+	}
+	self.transform.ApplyTo(vertex)
+	return vertex
+}
+
+type skinKey struct {
+	slot int
+	name string
+}
+
+// A Skin holds the attachments which can be shown in the slots by their names
+type Skin struct {
+	Name        string
+	attachments map[skinKey]*RegionAttachment
+}
+
+func NewSkin(name string) *Skin {
+	return &Skin{Name: name, attachments: make(map[skinKey]*RegionAttachment)}
+}
+
+// Adds the attachment for the slot with the name
+func (self *Skin) AddAttachment(slot int, name string, attachment *RegionAttachment) {
+	self.attachments[skinKey{slot, name}] = attachment
+}
+
+// Returns the attachment of the slot with the name, or nil
+func (self *Skin) GetAttachment(slot int, name string) *RegionAttachment {
+	return self.attachments[skinKey{slot, name}]
+}
+
+// SkeletonData holds the setup pose and the animations of a skeleton
+type SkeletonData struct {
+	Width, Height float32
+	Bones         []*BoneData
+	Slots         []*SlotData
+	Skins         []*Skin
+	DefaultSkin   *Skin
+	Animations    []*Animation
+}
+
+// Returns the bone with the name, or nil
+func (self *SkeletonData) FindBone(name string) *BoneData {
+	for _, bone := range self.Bones {
+		if bone.Name == name {
+			return bone
+		}
+	}
+	return nil
+}
+
+// Returns the slot with the name, or nil
+func (self *SkeletonData) FindSlot(name string) *SlotData {
+	for _, slot := range self.Slots {
+		if slot.Name == name {
+			return slot
+		}
+	}
+	return nil
+}
+
+// Returns the skin with the name, or nil
+func (self *SkeletonData) FindSkin(name string) *Skin {
+	for _, skin := range self.Skins {
+		if skin.Name == name {
+			return skin
+		}
+	}
+	return nil
+}
+
+// Returns the animation with the name, or nil
+func (self *SkeletonData) FindAnimation(name string) *Animation {
+	for _, animation := range self.Animations {
+		if animation.Name == name {
+			return animation
+		}
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spine
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/pyros2097/spike/g2d"
+)
+
+type jsonSkeletonData struct {
+	Skeleton struct {
+		Width, Height float32
+	}
+	Bones      []jsonBone
+	Slots      []jsonSlot
+	Skins      json.RawMessage
+	Animations map[string]jsonAnimation
+}
+
+type jsonBone struct {
+	Name, Parent           string
+	Length, X, Y, Rotation float32
+	ScaleX, ScaleY         *float32
+	ShearX, ShearY         float32
+}
+
+type jsonSlot struct {
+	Name, Bone, Color, Attachment string
+}
+
+// The attachments of a skin by the names of the slots and attachments
+type jsonSkin map[string]map[string]jsonAttachment
+
+type jsonAttachment struct {
+	Type, Name, Path              string
+	X, Y, Rotation, Width, Height float32
+	ScaleX, ScaleY                *float32
+	Color                         string
+}
+
+type jsonAnimation struct {
+	Bones map[string]map[string][]jsonKeyFrame
+	Slots map[string]map[string][]jsonKeyFrame
+}
+
+type jsonKeyFrame struct {
+	Time       float32
+	Angle      float32
+	X, Y       *float32
+	Color      string
+	Name       *string
+	Curve      json.RawMessage
+	C2, C3, C4 float32
+}
+
+// Reads the skeleton data from the JSON exported by Spine. The regions of the attachments are found in the atlas by their
+// path, or their name if they have none. The atlas may be nil, then the attachments have no regions, which is enough to
+// pose the skeleton. The positions and sizes are multiplied by the scale.
+func ReadSkeletonData(r io.Reader, atlas *g2d.TextureAtlas, scale float32) (*SkeletonData, error) {
+	root := jsonSkeletonData{}
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+	data := &SkeletonData{Width: root.Skeleton.Width * scale, Height: root.Skeleton.Height * scale}
+	for i, jsonBone := range root.Bones {
+		bone := &BoneData{
+			Index:    i,
+			Name:     jsonBone.Name,
+			Length:   jsonBone.Length * scale,
+			X:        jsonBone.X * scale,
+			Y:        jsonBone.Y * scale,
+			Rotation: jsonBone.Rotation,
+			ScaleX:   optional(jsonBone.ScaleX, 1),
+			ScaleY:   optional(jsonBone.ScaleY, 1),
+			ShearX:   jsonBone.ShearX,
+			ShearY:   jsonBone.ShearY,
+		}
+		if jsonBone.Parent != "" {
+			if bone.Parent = data.FindBone(jsonBone.Parent); bone.Parent == nil {
+				return nil, errors.New("spine: parent bone not found " + jsonBone.Parent)
+			}
+		}
+		data.Bones = append(data.Bones, bone)
+	}
+	for i, jsonSlot := range root.Slots {
+		slot := &SlotData{Index: i, Name: jsonSlot.Name, Color: white, AttachmentName: jsonSlot.Attachment}
+		if slot.Bone = data.FindBone(jsonSlot.Bone); slot.Bone == nil {
+			return nil, errors.New("spine: slot bone not found " + jsonSlot.Bone)
+		}
+		if jsonSlot.Color != "" {
+			color, err := parseColor(jsonSlot.Color)
+			if err != nil {
+				return nil, err
+			}
+			slot.Color = color
+		}
+		data.Slots = append(data.Slots, slot)
+	}
+	if err := readSkins(data, root.Skins, atlas, scale); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(root.Animations))
+	for name := range root.Animations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		animation, err := readAnimation(data, name, root.Animations[name], scale)
+		if err != nil {
+			return nil, err
+		}
+		data.Animations = append(data.Animations, animation)
+	}
+	return data, nil
+}
+
+func optional(value *float32, defaultValue float32) float32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+// Reads the skins, which are an object of skins by their names before Spine 3.8 and an array of named skins since
+func readSkins(data *SkeletonData, raw json.RawMessage, atlas *g2d.TextureAtlas, scale float32) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	skins := map[string]jsonSkin{}
+	names := []string{}
+	if raw[0] == '[' {
+		list := []struct {
+			Name        string
+			Attachments jsonSkin
+		}{}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return err
+		}
+		for _, skin := range list {
+			skins[skin.Name] = skin.Attachments
+			names = append(names, skin.Name)
+		}
+	} else {
+		if err := json.Unmarshal(raw, &skins); err != nil {
+			return err
+		}
+		for name := range skins {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		skin := NewSkin(name)
+		for slotName, attachments := range skins[name] {
+			slot := data.FindSlot(slotName)
+			if slot == nil {
+				return errors.New("spine: skin slot not found " + slotName)
+			}
+			for attachmentName, jsonAttachment := range attachments {
+				if jsonAttachment.Type != "" && jsonAttachment.Type != "region" {
+					continue
+				}
+				attachment, err := readAttachment(attachmentName, jsonAttachment, atlas, scale)
+				if err != nil {
+					return err
+				}
+				skin.AddAttachment(slot.Index, attachmentName, attachment)
+			}
+		}
+		data.Skins = append(data.Skins, skin)
+		if name == "default" {
+			data.DefaultSkin = skin
+		}
+	}
+	return nil
+}
+
+func readAttachment(name string, jsonAttachment jsonAttachment, atlas *g2d.TextureAtlas, scale float32) (*RegionAttachment, error) {
+	if jsonAttachment.Name != "" {
+		name = jsonAttachment.Name
+	}
+	attachment := &RegionAttachment{
+		Name:     name,
+		Path:     jsonAttachment.Path,
+		X:        jsonAttachment.X * scale,
+		Y:        jsonAttachment.Y * scale,
+		Rotation: jsonAttachment.Rotation,
+		ScaleX:   optional(jsonAttachment.ScaleX, 1),
+		ScaleY:   optional(jsonAttachment.ScaleY, 1),
+		Width:    jsonAttachment.Width * scale,
+		Height:   jsonAttachment.Height * scale,
+		Color:    white,
+	}
+	if attachment.Path == "" {
+		attachment.Path = name
+	}
+	if jsonAttachment.Color != "" {
+		color, err := parseColor(jsonAttachment.Color)
+		if err != nil {
+			return nil, err
+		}
+		attachment.Color = color
+	}
+	if atlas != nil {
+		if attachment.Region = atlas.FindRegion(attachment.Path); attachment.Region == nil {
+			return nil, errors.New("spine: region not found " + attachment.Path)
+		}
+	}
+	attachment.UpdateTransform()
+	return attachment, nil
+}
+
+func readAnimation(data *SkeletonData, name string, jsonAnimation jsonAnimation, scale float32) (*Animation, error) {
+	animation := &Animation{Name: name}
+	for _, timelines := range []map[string]map[string][]jsonKeyFrame{jsonAnimation.Bones, jsonAnimation.Slots} {
+		for _, kinds := range timelines {
+			for _, frames := range kinds {
+				if len(frames) > 0 && frames[len(frames)-1].Time > animation.Duration {
+					animation.Duration = frames[len(frames)-1].Time
+				}
+			}
+		}
+	}
+	for boneName, timelines := range jsonAnimation.Bones {
+		bone := data.FindBone(boneName)
+		if bone == nil {
+			return nil, errors.New("spine: animation bone not found " + boneName)
+		}
+		for kind, frames := range timelines {
+			var timeline Timeline
+			var times []float32
+			var timelineCurves *curves
+			switch kind {
+			case "rotate":
+				rotate := NewRotateTimeline(bone.Index, len(frames))
+				for i, frame := range frames {
+					rotate.Angles[i] = frame.Angle
+				}
+				timeline, times, timelineCurves = rotate, rotate.Times, &rotate.curves
+			case "translate", "scale":
+				defaultValue, valueScale := float32(0), scale
+				if kind == "scale" {
+					defaultValue, valueScale = 1, 1
+				}
+				x, y := make([]float32, len(frames)), make([]float32, len(frames))
+				for i, frame := range frames {
+					x[i] = optional(frame.X, defaultValue) * valueScale
+					y[i] = optional(frame.Y, defaultValue) * valueScale
+				}
+				if kind == "translate" {
+					translate := NewTranslateTimeline(bone.Index, len(frames))
+					translate.X, translate.Y = x, y
+					timeline, times, timelineCurves = translate, translate.Times, &translate.curves
+				} else {
+					scaleTimeline := NewScaleTimeline(bone.Index, len(frames))
+					scaleTimeline.X, scaleTimeline.Y = x, y
+					timeline, times, timelineCurves = scaleTimeline, scaleTimeline.Times, &scaleTimeline.curves
+				}
+			default:
+				continue
+			}
+			if err := readFrames(frames, times, timelineCurves); err != nil {
+				return nil, err
+			}
+			animation.Timelines = append(animation.Timelines, timeline)
+		}
+	}
+	for slotName, timelines := range jsonAnimation.Slots {
+		slot := data.FindSlot(slotName)
+		if slot == nil {
+			return nil, errors.New("spine: animation slot not found " + slotName)
+		}
+		for kind, frames := range timelines {
+			switch kind {
+			case "color":
+				color := NewColorTimeline(slot.Index, len(frames))
+				for i, frame := range frames {
+					value, err := parseColor(frame.Color)
+					if err != nil {
+						return nil, err
+					}
+					color.Colors[i] = value
+				}
+				if err := readFrames(frames, color.Times, &color.curves); err != nil {
+					return nil, err
+				}
+				animation.Timelines = append(animation.Timelines, color)
+			case "attachment":
+				attachment := NewAttachmentTimeline(slot.Index, len(frames))
+				for i, frame := range frames {
+					attachment.Times[i] = frame.Time
+					if frame.Name != nil {
+						attachment.Names[i] = *frame.Name
+					}
+				}
+				if err := readFrames(frames, attachment.Times, nil); err != nil {
+					return nil, err
+				}
+				animation.Timelines = append(animation.Timelines, attachment)
+			}
+		}
+	}
+	// the timelines are sorted so that animations are applied the same way every time
+	sort.SliceStable(animation.Timelines, func(i, j int) bool {
+		return animation.Timelines[i].property() < animation.Timelines[j].property()
+	})
+	return animation, nil
+}
+
+// Reads the times and curves of the key frames
+func readFrames(frames []jsonKeyFrame, times []float32, frameCurves *curves) error {
+	for i, frame := range frames {
+		times[i] = frame.Time
+		if i > 0 && times[i] < times[i-1] {
+			return errors.New("spine: key frames out of order")
+		}
+		if frameCurves == nil || len(frame.Curve) == 0 {
+			continue
+		}
+		var curve interface{}
+		if err := json.Unmarshal(frame.Curve, &curve); err != nil {
+			return err
+		}
+		switch curve := curve.(type) {
+		case string:
+			if curve == "stepped" {
+				frameCurves.setStepped(i)
+			}
+		case []interface{}:
+			if len(curve) != 4 {
+				return errors.New("spine: invalid curve " + string(frame.Curve))
+			}
+			values := [4]float32{}
+			for j, value := range curve {
+				number, ok := value.(float64)
+				if !ok {
+					return errors.New("spine: invalid curve " + string(frame.Curve))
+				}
+				values[j] = float32(number)
+			}
+			frameCurves.setBezier(i, values[0], values[1], values[2], values[3])
+		case float64:
+			frameCurves.setBezier(i, float32(curve), frame.C2, frame.C3, frame.C4)
+		}
+	}
+	return nil
+}
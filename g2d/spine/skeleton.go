@@ -0,0 +1,287 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spine
+
+import (
+	"errors"
+	"math"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// A Bone has a pose relative to its parent, which animations change, and a world transform computed from it
+type Bone struct {
+	Data   *BoneData
+	Parent *Bone
+
+	// The position, rotation in degrees, scale and shear in degrees relative to the parent bone
+	X, Y, Rotation, ScaleX, ScaleY, ShearX, ShearY float32
+
+	// The transform of the bone's coordinates to the skeleton's coordinates, computed by Skeleton.UpdateWorldTransform
+	World *vector.Affine2
+}
+
+func newBone(data *BoneData, parent *Bone) *Bone {
+	bone := &Bone{Data: data, Parent: parent, World: vector.NewAffine2Empty()}
+	bone.SetToSetupPose()
+	return bone
+}
+
+func (self *Bone) SetToSetupPose() {
+	data := self.Data
+	self.X, self.Y = data.X, data.Y
+	self.Rotation = data.Rotation
+	self.ScaleX, self.ScaleY = data.ScaleX, data.ScaleY
+	self.ShearX, self.ShearY = data.ShearX, data.ShearY
+}
+
+func cosDeg(degrees float32) float32 {
+	return float32(math.Cos(float64(degrees) * math.Pi / 180))
+}
+
+func sinDeg(degrees float32) float32 {
+	return float32(math.Sin(float64(degrees) * math.Pi / 180))
+}
+
+// Computes the world transform of the bone, the world transform of its parent must be up to date
+func (self *Bone) updateWorldTransform() {
+	rotationY := self.Rotation + 90 + self.ShearY
+	la := cosDeg(self.Rotation+self.ShearX) * self.ScaleX
+	lb := cosDeg(rotationY) * self.ScaleY
+	lc := sinDeg(self.Rotation+self.ShearX) * self.ScaleX
+	ld := sinDeg(rotationY) * self.ScaleY
+	self.World.SetValues(la, lb, self.X, lc, ld, self.Y)
+	if self.Parent != nil {
+		self.World.PreMulA(self.Parent.World)
+	}
+}
+
+// Returns the position of the bone in the skeleton's coordinates
+func (self *Bone) GetWorldX() float32 {
+	return self.World.GetValues()[2]
+}
+
+func (self *Bone) GetWorldY() float32 {
+	return self.World.GetValues()[5]
+}
+
+// Returns the rotation of the x axis of the bone in the skeleton's coordinates in degrees
+func (self *Bone) GetWorldRotationX() float32 {
+	m := self.World.GetValues()
+	return float32(math.Atan2(float64(m[3]), float64(m[0])) * 180 / math.Pi)
+}
+
+// Returns the scale of the x axis of the bone in the skeleton's coordinates
+func (self *Bone) GetWorldScaleX() float32 {
+	m := self.World.GetValues()
+	return float32(math.Hypot(float64(m[0]), float64(m[3])))
+}
+
+// Returns the scale of the y axis of the bone in the skeleton's coordinates, negative if the bone is mirrored
+func (self *Bone) GetWorldScaleY() float32 {
+	scaleX := self.GetWorldScaleX()
+	if scaleX == 0 {
+		return 0
+	}
+	return self.World.Det() / scaleX
+}
+
+// Transforms the point in the bone's coordinates to the skeleton's coordinates
+func (self *Bone) LocalToWorld(local *vector.Vector2) *vector.Vector2 {
+	self.World.ApplyTo(local)
+	return local
+}
+
+// Transforms the point in the skeleton's coordinates to the bone's coordinates. The point is not changed if the bone is
+// scaled to zero.
+func (self *Bone) WorldToLocal(world *vector.Vector2) *vector.Vector2 {
+	if self.World.Det() != 0 {
+		vector.NewAffine2Copy(self.World).Inv().ApplyTo(world)
+	}
+	return world
+}
+
+// A Slot shows an attachment on its bone
+type Slot struct {
+	Data       *SlotData
+	Bone       *Bone
+	Color      Color
+	Attachment *RegionAttachment
+}
+
+// A Skeleton is an instance of a SkeletonData which is posed by animations
+type Skeleton struct {
+	Data  *SkeletonData
+	Bones []*Bone
+	Slots []*Slot
+
+	// The slots in the order they are drawn
+	DrawOrder []*Slot
+
+	// The skin whose attachments are looked up first, then the ones of the default skin. It may be nil.
+	Skin *Skin
+
+	// The color all the attachments are tinted with
+	Color Color
+}
+
+var (
+	drawTransform = vector.NewAffine2Empty()
+)
+
+// Creates a skeleton in its setup pose, with its world transforms computed
+func NewSkeleton(data *SkeletonData) *Skeleton {
+	skeleton := &Skeleton{Data: data, Color: white}
+	for _, boneData := range data.Bones {
+		var parent *Bone
+		if boneData.Parent != nil {
+			parent = skeleton.Bones[boneData.Parent.Index]
+		}
+		skeleton.Bones = append(skeleton.Bones, newBone(boneData, parent))
+	}
+	for _, slotData := range data.Slots {
+		slot := &Slot{Data: slotData, Bone: skeleton.Bones[slotData.Bone.Index]}
+		skeleton.Slots = append(skeleton.Slots, slot)
+	}
+	skeleton.DrawOrder = append([]*Slot{}, skeleton.Slots...)
+	skeleton.SetSlotsToSetupPose()
+	skeleton.UpdateWorldTransform()
+	return skeleton
+}
+
+// Computes the world transforms of the bones from their poses
+func (self *Skeleton) UpdateWorldTransform() {
+	for _, bone := range self.Bones {
+		bone.updateWorldTransform()
+	}
+}
+
+// Sets the bones and slots to their setup pose
+func (self *Skeleton) SetToSetupPose() {
+	self.SetBonesToSetupPose()
+	self.SetSlotsToSetupPose()
+}
+
+func (self *Skeleton) SetBonesToSetupPose() {
+	for _, bone := range self.Bones {
+		bone.SetToSetupPose()
+	}
+}
+
+// Sets the colors and attachments of the slots to their setup pose and restores the draw order
+func (self *Skeleton) SetSlotsToSetupPose() {
+	copy(self.DrawOrder, self.Slots)
+	for _, slot := range self.Slots {
+		slot.Color = slot.Data.Color
+		slot.Attachment = nil
+		if slot.Data.AttachmentName != "" {
+			slot.Attachment = self.GetAttachment(slot.Data.Index, slot.Data.AttachmentName)
+		}
+	}
+}
+
+// Returns the bone with the name, or nil
+func (self *Skeleton) FindBone(name string) *Bone {
+	for _, bone := range self.Bones {
+		if bone.Data.Name == name {
+			return bone
+		}
+	}
+	return nil
+}
+
+// Returns the slot with the name, or nil
+func (self *Skeleton) FindSlot(name string) *Slot {
+	for _, slot := range self.Slots {
+		if slot.Data.Name == name {
+			return slot
+		}
+	}
+	return nil
+}
+
+// Sets the skin with the name, an empty name removes the skin. The slots which show an attachment of the old skin show the
+// attachment with the same name of the new skin, if it has one.
+func (self *Skeleton) SetSkin(name string) error {
+	var skin *Skin
+	if name != "" {
+		if skin = self.Data.FindSkin(name); skin == nil {
+			return errors.New("spine: skin not found " + name)
+		}
+	}
+	if self.Skin != nil && skin != nil {
+		for _, slot := range self.Slots {
+			for key, attachment := range self.Skin.attachments {
+				if key.slot == slot.Data.Index && slot.Attachment == attachment {
+					if other := skin.GetAttachment(key.slot, key.name); other != nil {
+						slot.Attachment = other
+					}
+				}
+			}
+		}
+	}
+	if self.Skin == nil && skin != nil {
+		// without a skin before, the slots show the setup attachments of the new skin
+		for _, slot := range self.Slots {
+			if name := slot.Data.AttachmentName; name != "" {
+				if attachment := skin.GetAttachment(slot.Data.Index, name); attachment != nil {
+					slot.Attachment = attachment
+				}
+			}
+		}
+	}
+	self.Skin = skin
+	return nil
+}
+
+// Returns the attachment with the name for the slot from the skin, or else from the default skin, or nil
+func (self *Skeleton) GetAttachment(slot int, name string) *RegionAttachment {
+	if self.Skin != nil {
+		if attachment := self.Skin.GetAttachment(slot, name); attachment != nil {
+			return attachment
+		}
+	}
+	if self.Data.DefaultSkin != nil {
+		return self.Data.DefaultSkin.GetAttachment(slot, name)
+	}
+	return nil
+}
+
+// Sets the attachment of the slot, an empty attachment name removes it
+func (self *Skeleton) SetAttachment(slotName, attachmentName string) error {
+	slot := self.FindSlot(slotName)
+	if slot == nil {
+		return errors.New("spine: slot not found " + slotName)
+	}
+	if attachmentName == "" {
+		slot.Attachment = nil
+		return nil
+	}
+	attachment := self.GetAttachment(slot.Data.Index, attachmentName)
+	if attachment == nil {
+		return errors.New("spine: attachment not found " + attachmentName)
+	}
+	slot.Attachment = attachment
+	return nil
+}
+
+// Draws the attachments of the slots in the draw order, transformed by the world transforms of their bones and then by the
+// transform. They are tinted with the color of the skeleton, of their slot and the given color.
+func (self *Skeleton) Draw(batch g2d.Batch, transform *vector.Affine2, r, g, b, a float32) {
+	for _, slot := range self.DrawOrder {
+		attachment := slot.Attachment
+		if attachment == nil || attachment.Region == nil {
+			continue
+		}
+		color := Color{r, g, b, a}
+		color.mul(self.Color)
+		color.mul(slot.Color)
+		color.mul(attachment.Color)
+		batch.SetColor(color.R, color.G, color.B, color.A)
+		drawTransform.Set(transform).Mul(slot.Bone.World).Mul(attachment.transform)
+		attachment.Region.Draw(batch, attachment.Width, attachment.Height, drawTransform)
+	}
+}
@@ -0,0 +1,259 @@
+package spine
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pyros2097/spike/math/vector"
+)
+
+const testSkeleton = `{
+	"skeleton": { "width": 40, "height": 60 },
+	"bones": [
+		{ "name": "root" },
+		{ "name": "hip", "parent": "root", "x": 10, "y": 20, "rotation": 90 },
+		{ "name": "arm", "parent": "hip", "x": 5, "rotation": -45, "scaleX": 2, "length": 10 },
+		{ "name": "hand", "parent": "arm", "x": 10 }
+	],
+	"slots": [
+		{ "name": "body", "bone": "hip", "attachment": "body" },
+		{ "name": "weapon", "bone": "hand", "color": "ff8080ff", "attachment": "sword" }
+	],
+	"skins": {
+		"default": {
+			"body": { "body": { "width": 20, "height": 40 } },
+			"weapon": {
+				"sword": { "x": 5, "width": 10, "height": 2 },
+				"axe": { "x": 4, "width": 8, "height": 4 },
+				"box": { "type": "boundingbox", "vertices": [0, 0, 1, 0, 1, 1] }
+			}
+		},
+		"gold": {
+			"weapon": { "sword": { "name": "gold-sword", "x": 6, "width": 12, "height": 2 } }
+		}
+	},
+	"animations": {
+		"wave": {
+			"bones": {
+				"arm": { "rotate": [ { "time": 0, "angle": 0 }, { "time": 1, "angle": 90 } ] },
+				"hip": { "translate": [ { "time": 0, "x": 0, "y": 0, "curve": [0.5, 0, 1, 1] }, { "time": 1, "x": 10, "y": 0 } ] }
+			},
+			"slots": {
+				"weapon": {
+					"color": [ { "time": 0, "color": "ffffffff" }, { "time": 1, "color": "00000000" } ],
+					"attachment": [ { "time": 0, "name": "sword" }, { "time": 0.5, "name": "axe" }, { "time": 0.75, "name": null } ]
+				}
+			}
+		},
+		"idle": {
+			"bones": { "arm": { "rotate": [ { "time": 0, "angle": 30 } ] } }
+		},
+		"jump": {
+			"bones": {
+				"hip": {
+					"scale": [ { "time": 0 }, { "time": 0.5, "x": 2, "curve": "stepped" }, { "time": 1, "x": 3 } ]
+				}
+			}
+		}
+	}
+}`
+
+func near(a, b, epsilon float32) bool {
+	return math.Abs(float64(a-b)) <= float64(epsilon)
+}
+
+func readTestSkeleton(t *testing.T) (*SkeletonData, *Skeleton) {
+	data, err := ReadSkeletonData(strings.NewReader(testSkeleton), nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, NewSkeleton(data)
+}
+
+func checkBone(t *testing.T, name string, bone *Bone, x, y, rotation, scaleX float32) {
+	if !near(bone.GetWorldX(), x, 0.001) || !near(bone.GetWorldY(), y, 0.001) ||
+		!near(bone.GetWorldRotationX(), rotation, 0.001) || !near(bone.GetWorldScaleX(), scaleX, 0.001) {
+		t.Errorf("%s: %s at %v, %v rotated %v scaled %v, want %v, %v rotated %v scaled %v", name, bone.Data.Name,
+			bone.GetWorldX(), bone.GetWorldY(), bone.GetWorldRotationX(), bone.GetWorldScaleX(), x, y, rotation, scaleX)
+	}
+}
+
+func TestSetupPose(t *testing.T) {
+	data, skeleton := readTestSkeleton(t)
+	if len(data.Bones) != 4 || len(data.Slots) != 2 || len(data.Skins) != 2 || len(data.Animations) != 3 {
+		t.Fatalf("%d bones %d slots %d skins %d animations", len(data.Bones), len(data.Slots), len(data.Skins),
+			len(data.Animations))
+	}
+	checkBone(t, "setup", skeleton.FindBone("hip"), 10, 20, 90, 1)
+	checkBone(t, "setup", skeleton.FindBone("arm"), 10, 25, 45, 2)
+	checkBone(t, "setup", skeleton.FindBone("hand"), 24.142136, 39.142136, 45, 2)
+
+	hand := skeleton.FindBone("hand")
+	point := hand.WorldToLocal(vector.NewVector2(24.142136, 49.142136))
+	if !near(point.X, 3.535534, 0.001) || !near(point.Y, 7.071068, 0.001) {
+		t.Errorf("world point in the hand at %v, %v", point.X, point.Y)
+	}
+
+	weapon := skeleton.FindSlot("weapon")
+	if weapon.Attachment == nil || weapon.Attachment.Name != "sword" || weapon.Color.G != float32(0x80)/255 {
+		t.Fatalf("weapon slot %v", weapon)
+	}
+	corner := weapon.Attachment.GetCorner(2, vector.NewVector2Empty())
+	if !near(corner.X, 10, 0.001) || !near(corner.Y, 1, 0.001) {
+		t.Errorf("top right corner of the sword at %v, %v", corner.X, corner.Y)
+	}
+	if skeleton.GetAttachment(weapon.Data.Index, "box") != nil {
+		t.Error("bounding box attachment read")
+	}
+}
+
+func TestTimelines(t *testing.T) {
+	data, skeleton := readTestSkeleton(t)
+	wave := data.FindAnimation("wave")
+	if wave.Duration != 1 {
+		t.Errorf("duration %v", wave.Duration)
+	}
+	wave.Apply(skeleton, 0.5, false, 1)
+	skeleton.UpdateWorldTransform()
+	// the hip is moved by 2.78 on the curve
+	checkBone(t, "wave", skeleton.FindBone("arm"), 12.780661, 25, 90, 2)
+	checkBone(t, "wave", skeleton.FindBone("hand"), 12.780661, 45, 90, 2)
+	weapon := skeleton.FindSlot("weapon")
+	if color := weapon.Color; !near(color.R, 0.5, 0.001) || !near(color.A, 0.5, 0.001) {
+		t.Errorf("color %v", color)
+	}
+	if weapon.Attachment == nil || weapon.Attachment.Name != "axe" {
+		t.Errorf("attachment %v at 0.5", weapon.Attachment)
+	}
+
+	// the translation follows the Bezier curve
+	skeleton.SetToSetupPose()
+	wave.Apply(skeleton, 0.25, false, 1)
+	if hip := skeleton.FindBone("hip"); !near(hip.X, 10.754022, 0.001) {
+		t.Errorf("hip at %v on the curve", hip.X)
+	}
+	wave.Apply(skeleton, 0.8, false, 1)
+	if weapon.Attachment != nil {
+		t.Errorf("attachment %v at 0.8", weapon.Attachment)
+	}
+
+	// looping wraps the time
+	wave.Apply(skeleton, 2.5, true, 1)
+	if arm := skeleton.FindBone("arm"); !near(arm.Rotation, 0, 0.001) {
+		t.Errorf("arm rotated %v at 2.5", arm.Rotation)
+	}
+
+	jump := data.FindAnimation("jump")
+	for _, time := range []float32{0.25, 0.75, 1} {
+		jump.Apply(skeleton, time, false, 1)
+		want := map[float32]float32{0.25: 1.5, 0.75: 2, 1: 3}[time]
+		if hip := skeleton.FindBone("hip"); !near(hip.ScaleX, want, 0.001) || hip.ScaleY != 1 {
+			t.Errorf("hip scaled %v, %v at %v", hip.ScaleX, hip.ScaleY, time)
+		}
+	}
+}
+
+func TestRotateShortestWay(t *testing.T) {
+	_, skeleton := readTestSkeleton(t)
+	timeline := NewRotateTimeline(0, 2)
+	timeline.Times[1] = 1
+	timeline.Angles[0], timeline.Angles[1] = 170, -170
+	timeline.Apply(skeleton, 0.5, 1)
+	if root := skeleton.Bones[0]; !near(wrapDegrees(root.Rotation), 180, 0.001) &&
+		!near(wrapDegrees(root.Rotation), -180, 0.001) {
+		t.Errorf("root rotated %v", root.Rotation)
+	}
+	skeleton.Bones[0].Rotation = 170
+	NewRotateTimeline(0, 1).Apply(skeleton, 0, 0.5)
+	if root := skeleton.Bones[0]; !near(root.Rotation, 85, 0.001) {
+		t.Errorf("root rotated %v mixing to 0", root.Rotation)
+	}
+}
+
+func TestAnimationStateCrossfade(t *testing.T) {
+	data, skeleton := readTestSkeleton(t)
+	stateData := NewAnimationStateData(data)
+	if err := stateData.SetMix("wave", "idle", 0.4); err != nil {
+		t.Fatal(err)
+	}
+	state := NewAnimationState(stateData)
+	state.SetAnimation(0, data.FindAnimation("wave"), true)
+	state.Update(0.5)
+	idle := state.SetAnimation(0, data.FindAnimation("idle"), false)
+	state.Update(0.1)
+	state.Apply(skeleton)
+	if idle.GetMixPercent() != 0.25 {
+		t.Errorf("mix %v", idle.GetMixPercent())
+	}
+	// the arm is mixed between the animations, the hip and the color between the setup pose and wave
+	if arm := skeleton.FindBone("arm"); !near(arm.Rotation, 3, 0.001) {
+		t.Errorf("arm rotated %v while mixing", arm.Rotation)
+	}
+	if hip := skeleton.FindBone("hip"); !near(hip.X, 10+3.893976*0.75, 0.001) {
+		t.Errorf("hip at %v while mixing", hip.X)
+	}
+	if color := skeleton.FindSlot("weapon").Color; !near(color.G, 0.501961+(0.4-0.501961)*0.75, 0.001) {
+		t.Errorf("color %v while mixing", color)
+	}
+
+	state.Update(0.4)
+	state.Apply(skeleton)
+	skeleton.UpdateWorldTransform()
+	if idle.GetMixPercent() != 1 {
+		t.Errorf("mix %v after the mix duration", idle.GetMixPercent())
+	}
+	checkBone(t, "idle", skeleton.FindBone("hip"), 10, 20, 90, 1)
+	checkBone(t, "idle", skeleton.FindBone("arm"), 10, 25, 75, 2)
+}
+
+func TestAnimationStateTracks(t *testing.T) {
+	data, skeleton := readTestSkeleton(t)
+	state := NewAnimationState(NewAnimationStateData(data))
+	wave := state.SetAnimation(0, data.FindAnimation("wave"), true)
+	completed := 0
+	wave.OnComplete = func(entry *TrackEntry) {
+		completed++
+	}
+	state.Update(2.5)
+	if completed != 2 {
+		t.Errorf("completed %d times", completed)
+	}
+
+	// the second track is applied over the first one
+	state.SetAnimation(1, data.FindAnimation("idle"), true).Alpha = 0.5
+	state.Apply(skeleton)
+	if arm := skeleton.FindBone("arm"); !near(arm.Rotation, -7.5, 0.001) {
+		t.Errorf("arm rotated %v with two tracks", arm.Rotation)
+	}
+	state.ClearTrack(1)
+
+	// the queued animation starts when the one before ends
+	jump := state.SetAnimation(0, data.FindAnimation("jump"), false)
+	idle := state.AddAnimation(0, data.FindAnimation("idle"), false, 0)
+	state.Update(0.9)
+	if state.GetCurrent(0) != jump {
+		t.Error("queued animation started early")
+	}
+	state.Update(0.2)
+	if state.GetCurrent(0) != idle || !near(idle.Time, 0.1, 0.0001) {
+		t.Errorf("queued animation not started, %v", idle.Time)
+	}
+}
+
+func TestSkins(t *testing.T) {
+	data, skeleton := readTestSkeleton(t)
+	if err := skeleton.SetSkin("gold"); err != nil {
+		t.Fatal(err)
+	}
+	weapon := skeleton.FindSlot("weapon")
+	if weapon.Attachment == nil || weapon.Attachment.Name != "gold-sword" {
+		t.Errorf("attachment %v with the gold skin", weapon.Attachment)
+	}
+	if err := skeleton.SetAttachment("weapon", "axe"); err != nil || weapon.Attachment != data.DefaultSkin.GetAttachment(1, "axe") {
+		t.Errorf("axe from the default skin not found, %v", err)
+	}
+	if skeleton.SetSkin("missing") == nil || skeleton.SetAttachment("weapon", "missing") == nil {
+		t.Error("missing skin or attachment set")
+	}
+}
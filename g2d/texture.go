@@ -0,0 +1,90 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+// A Texture is an image which is drawn by the batch, it is identified by the path of its file
+type Texture struct {
+	Path          string
+	Width, Height int
+}
+
+// A TextureRegion is a rectangular part of a texture. The texture coordinates U, V are of the top left corner and U2, V2 of
+// the bottom right corner of the region, they are swapped if the region is flipped.
+type TextureRegion struct {
+	Texture                   *Texture
+	U, V, U2, V2              float32
+	RegionWidth, RegionHeight int
+}
+
+// Creates a region of the texture, x and y are of the top left corner in pixels
+func NewTextureRegion(texture *Texture, x, y, width, height int) *TextureRegion {
+	region := &TextureRegion{Texture: texture}
+	region.SetRegion(x, y, width, height)
+	return region
+}
+
+// Sets the region to the rectangle of the texture, x and y are of the top left corner in pixels
+func (self *TextureRegion) SetRegion(x, y, width, height int) {
+	invTexWidth := 1 / float32(self.Texture.Width)
+	invTexHeight := 1 / float32(self.Texture.Height)
+	self.U = float32(x) * invTexWidth
+	self.V = float32(y) * invTexHeight
+	self.U2 = float32(x+width) * invTexWidth
+	self.V2 = float32(y+height) * invTexHeight
+	self.RegionWidth = width
+	self.RegionHeight = height
+}
+
+// Returns the x position of the left edge of the region in pixels
+func (self *TextureRegion) GetRegionX() int {
+	u := self.U
+	if self.U2 < u {
+		u = self.U2
+	}
+	return int(u*float32(self.Texture.Width) + 0.5)
+}
+
+// Returns the y position of the top edge of the region in pixels
+func (self *TextureRegion) GetRegionY() int {
+	v := self.V
+	if self.V2 < v {
+		v = self.V2
+	}
+	return int(v*float32(self.Texture.Height) + 0.5)
+}
+
+// Flips the region horizontally and or vertically by swapping its texture coordinates
+func (self *TextureRegion) Flip(x, y bool) {
+	if x {
+		self.U, self.U2 = self.U2, self.U
+	}
+	if y {
+		self.V, self.V2 = self.V2, self.V
+	}
+}
+
+func (self *TextureRegion) IsFlipX() bool {
+	return self.U > self.U2
+}
+
+func (self *TextureRegion) IsFlipY() bool {
+	return self.V > self.V2
+}
+
+// Splits the region into tiles of the size, from the top left corner to the bottom right corner. The tiles are returned
+// by row, the tiles which do not fit completely are left out.
+func (self *TextureRegion) Split(tileWidth, tileHeight int) [][]*TextureRegion {
+	x, y := self.GetRegionX(), self.GetRegionY()
+	rows := self.RegionHeight / tileHeight
+	cols := self.RegionWidth / tileWidth
+	tiles := make([][]*TextureRegion, rows)
+	for row := range tiles {
+		tiles[row] = make([]*TextureRegion, cols)
+		for col := range tiles[row] {
+			tiles[row][col] = NewTextureRegion(self.Texture, x+col*tileWidth, y+row*tileHeight, tileWidth, tileHeight)
+		}
+	}
+	return tiles
+}
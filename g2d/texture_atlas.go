@@ -0,0 +1,244 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package g2d
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// A texture atlas packs many images into a few textures, the pages. It is read from the text files written by the libgdx
+// texture packer:
+//
+//	sprites.png
+//	size: 256, 128
+//	filter: Linear, Linear
+//	run
+//	  rotate: false
+//	  xy: 2, 2
+//	  size: 30, 40
+//	  orig: 32, 48
+//	  offset: 1, 4
+//	  index: 0
+//	button
+//	  xy: 34, 2
+//	  size: 24, 24
+//	  split: 8, 8, 6, 10
+//	  pad: 4, 4, 2, 2
+//
+// The packer strips the whitespace around the images, the offset and the original size keep where the image was in its
+// original size. Images with names ending in an underscore and a number, like run_0, are indexed regions named without the
+// suffix, which is how the frames of animations are packed. The images of nine patches have their splits and padding,
+// see NewNinePatchAtlas.
+
+// An AtlasRegion is a region of a page of a texture atlas
+type AtlasRegion struct {
+	TextureRegion
+
+	Name string
+
+	// The number at the end of the name of the image, -1 if there was none
+	Index int
+
+	// The position of the packed image in the original image, from the bottom left corner
+	OffsetX, OffsetY float32
+
+	// The size of the packed image, without the whitespace stripped by the packer
+	PackedWidth, PackedHeight int
+
+	// The size of the image before the whitespace was stripped
+	OriginalWidth, OriginalHeight int
+
+	// If true the image was rotated 90 degrees counter clockwise by the packer, the texture region is the rotated image
+	Rotate bool
+
+	// The widths of the left and right and the heights of the top and bottom borders of a nine patch, nil if the image
+	// is not one
+	Splits []int
+
+	// The left, right, top and bottom padding of the content of a nine patch, nil if it is the same as its splits
+	Pads []int
+}
+
+// A TextureAtlas holds the pages and the regions of an atlas
+type TextureAtlas struct {
+	Pages   []*Texture
+	Regions []*AtlasRegion
+}
+
+var atlasTransform = vector.NewAffine2Empty()
+
+// Reads a texture atlas, the paths of the pages are relative to the images directory
+func ReadTextureAtlas(r io.Reader, imagesDir string) (*TextureAtlas, error) {
+	atlas := &TextureAtlas{}
+	var page *Texture
+	var region *AtlasRegion
+	var x, y int
+	finish := func() {
+		if region == nil {
+			return
+		}
+		if region.OriginalWidth == 0 && region.OriginalHeight == 0 {
+			region.OriginalWidth, region.OriginalHeight = region.PackedWidth, region.PackedHeight
+		}
+		region.Texture = page
+		if region.Rotate {
+			region.SetRegion(x, y, region.PackedHeight, region.PackedWidth)
+		} else {
+			region.SetRegion(x, y, region.PackedWidth, region.PackedHeight)
+		}
+		atlas.Regions = append(atlas.Regions, region)
+		region = nil
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			finish()
+			page = nil
+			continue
+		}
+		if page == nil {
+			page = &Texture{Path: path.Join(imagesDir, line)}
+			atlas.Pages = append(atlas.Pages, page)
+			continue
+		}
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			finish()
+			region = &AtlasRegion{Name: line, Index: -1}
+			continue
+		}
+		name := strings.TrimSpace(line[:colon])
+		values, err := atlasValues(line[colon+1:])
+		if err != nil {
+			return nil, errors.New("texture atlas: invalid field " + line)
+		}
+		if region == nil {
+			if name == "size" && len(values) == 2 {
+				page.Width, page.Height = values[0], values[1]
+			}
+			continue
+		}
+		switch {
+		case name == "rotate":
+			value := strings.TrimSpace(line[colon+1:])
+			region.Rotate = value == "true" || value == "90"
+		case name == "xy" && len(values) == 2:
+			x, y = values[0], values[1]
+		case name == "size" && len(values) == 2:
+			region.PackedWidth, region.PackedHeight = values[0], values[1]
+		case name == "bounds" && len(values) == 4:
+			x, y = values[0], values[1]
+			region.PackedWidth, region.PackedHeight = values[2], values[3]
+		case name == "orig" && len(values) == 2:
+			region.OriginalWidth, region.OriginalHeight = values[0], values[1]
+		case name == "offset" && len(values) == 2:
+			region.OffsetX, region.OffsetY = float32(values[0]), float32(values[1])
+		case name == "offsets" && len(values) == 4:
+			region.OffsetX, region.OffsetY = float32(values[0]), float32(values[1])
+			region.OriginalWidth, region.OriginalHeight = values[2], values[3]
+		case name == "split" && len(values) == 4:
+			region.Splits = values
+		case name == "pad" && len(values) == 4:
+			region.Pads = values
+		case name == "index" && len(values) == 1:
+			region.Index = values[0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	finish()
+	for _, page := range atlas.Pages {
+		if page.Width == 0 || page.Height == 0 {
+			return nil, errors.New("texture atlas: missing size of page " + page.Path)
+		}
+	}
+	return atlas, nil
+}
+
+// Parses the comma separated integers of a field, the values which are not integers are ignored
+func atlasValues(field string) ([]int, error) {
+	values := []int{}
+	for _, value := range strings.Split(field, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, errors.New("empty value")
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			values = append(values, n)
+		}
+	}
+	return values, nil
+}
+
+// Returns the first region with the name, or nil
+func (self *TextureAtlas) FindRegion(name string) *AtlasRegion {
+	for _, region := range self.Regions {
+		if region.Name == name {
+			return region
+		}
+	}
+	return nil
+}
+
+// Returns the region with the name and index, or nil
+func (self *TextureAtlas) FindRegionIndex(name string, index int) *AtlasRegion {
+	for _, region := range self.Regions {
+		if region.Name == name && region.Index == index {
+			return region
+		}
+	}
+	return nil
+}
+
+// Returns the indexed regions with the name sorted by their index. If there are none the regions named name_0, name_1 and
+// so on are returned, up to the first missing number.
+func (self *TextureAtlas) FindRegions(name string) []*AtlasRegion {
+	regions := []*AtlasRegion{}
+	for _, region := range self.Regions {
+		if region.Name == name && region.Index >= 0 {
+			regions = append(regions, region)
+		}
+	}
+	if len(regions) > 0 {
+		sort.SliceStable(regions, func(i, j int) bool {
+			return regions[i].Index < regions[j].Index
+		})
+		return regions
+	}
+	for i := 0; ; i++ {
+		region := self.FindRegion(name + "_" + strconv.Itoa(i))
+		if region == nil {
+			return regions
+		}
+		regions = append(regions, region)
+	}
+}
+
+// Draws the image of the region in its original size stretched to the width and height, transformed by the transform.
+// The whitespace stripped by the packer is kept and rotated regions are drawn upright.
+func (self *AtlasRegion) Draw(batch Batch, width, height float32, transform *vector.Affine2) {
+	scaleX, scaleY := float32(1), float32(1)
+	if self.OriginalWidth > 0 && self.OriginalHeight > 0 {
+		scaleX = width / float32(self.OriginalWidth)
+		scaleY = height / float32(self.OriginalHeight)
+	}
+	atlasTransform.Set(transform).Translate(self.OffsetX*scaleX, self.OffsetY*scaleY)
+	if self.Rotate {
+		atlasTransform.Translate(0, float32(self.PackedHeight)*scaleY).Rotate(-90)
+		batch.Draw(&self.TextureRegion, float32(self.PackedHeight)*scaleY, float32(self.PackedWidth)*scaleX, atlasTransform)
+		return
+	}
+	batch.Draw(&self.TextureRegion, float32(self.PackedWidth)*scaleX, float32(self.PackedHeight)*scaleY, atlasTransform)
+}
@@ -0,0 +1,364 @@
+package spike
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/mobile/gl"
+)
+
+// A FakeGL is a GLContext for tests which records the calls instead of drawing. Each call is recorded as its name and
+// arguments separated by spaces, the handles by their value, the known enums by their name and the uniforms by their name.
+//
+//	fake := NewFakeGL()
+//	fb, _ := NewFrameBuffer(fake, 64, 64)
+//	fake.Reset()
+//	fb.Begin()
+//	// fake.Calls is ["BindFramebuffer FRAMEBUFFER 2", "Viewport 0 0 64 64"]
+type FakeGL struct {
+	// The recorded calls
+	Calls []string
+
+	// Returned by CheckFramebufferStatus, FRAMEBUFFER_COMPLETE by default
+	FramebufferStatus gl.Enum
+
+	// If not empty the shaders whose source contains it fail to compile, with it as their info log
+	ShaderError string
+
+	next     uint32
+	enabled  map[gl.Enum]bool
+	sources  map[gl.Shader]string
+	uniforms map[gl.Uniform]string
+}
+
+// Creates a recorder with complete frame buffers and shaders which compile
+func NewFakeGL() *FakeGL {
+	return &FakeGL{FramebufferStatus: gl.FRAMEBUFFER_COMPLETE, enabled: map[gl.Enum]bool{},
+		sources: map[gl.Shader]string{}, uniforms: map[gl.Uniform]string{}}
+}
+
+// Forgets the recorded calls
+func (self *FakeGL) Reset() {
+	self.Calls = nil
+}
+
+// Returns the recorded calls which start with the prefix
+func (self *FakeGL) Filter(prefix string) []string {
+	var calls []string
+	for _, call := range self.Calls {
+		if strings.HasPrefix(call, prefix) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+var fakeEnums = map[gl.Enum]string{
+	gl.ARRAY_BUFFER:       "ARRAY_BUFFER",
+	gl.BLEND:              "BLEND",
+	gl.CLAMP_TO_EDGE:      "CLAMP_TO_EDGE",
+	gl.COLOR_ATTACHMENT0:  "COLOR_ATTACHMENT0",
+	gl.COLOR_BUFFER_BIT:   "COLOR_BUFFER_BIT",
+	gl.COMPILE_STATUS:     "COMPILE_STATUS",
+	gl.DEPTH_BUFFER_BIT:   "DEPTH_BUFFER_BIT",
+	gl.FLOAT:              "FLOAT",
+	gl.FRAGMENT_SHADER:    "FRAGMENT_SHADER",
+	gl.FRAMEBUFFER:        "FRAMEBUFFER",
+	gl.LINEAR:             "LINEAR",
+	gl.LINES:              "LINES",
+	gl.LINK_STATUS:        "LINK_STATUS",
+	gl.RGBA:               "RGBA",
+	gl.STATIC_DRAW:        "STATIC_DRAW",
+	gl.STENCIL_BUFFER_BIT: "STENCIL_BUFFER_BIT",
+	gl.STREAM_DRAW:        "STREAM_DRAW",
+	gl.TEXTURE0:           "TEXTURE0",
+	gl.TEXTURE1:           "TEXTURE1",
+	gl.TEXTURE_2D:         "TEXTURE_2D",
+	gl.TEXTURE_MAG_FILTER: "TEXTURE_MAG_FILTER",
+	gl.TEXTURE_MIN_FILTER: "TEXTURE_MIN_FILTER",
+	gl.TEXTURE_WRAP_S:     "TEXTURE_WRAP_S",
+	gl.TEXTURE_WRAP_T:     "TEXTURE_WRAP_T",
+	gl.TRIANGLES:          "TRIANGLES",
+	gl.TRIANGLE_STRIP:     "TRIANGLE_STRIP",
+	gl.UNSIGNED_BYTE:      "UNSIGNED_BYTE",
+	gl.VERTEX_SHADER:      "VERTEX_SHADER",
+}
+
+func (self *FakeGL) record(name string, args ...interface{}) {
+	parts := []string{name}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case gl.Enum:
+			if s, ok := fakeEnums[v]; ok {
+				parts = append(parts, s)
+			} else {
+				parts = append(parts, "0x"+strconv.FormatUint(uint64(v), 16))
+			}
+		case gl.Uniform:
+			if s, ok := self.uniforms[v]; ok {
+				parts = append(parts, s)
+			} else {
+				parts = append(parts, strconv.Itoa(int(v.Value)))
+			}
+		case gl.Attrib:
+			parts = append(parts, strconv.Itoa(int(v.Value)))
+		case gl.Buffer:
+			parts = append(parts, strconv.Itoa(int(v.Value)))
+		case gl.Framebuffer:
+			parts = append(parts, strconv.Itoa(int(v.Value)))
+		case gl.Program:
+			parts = append(parts, strconv.Itoa(int(v.Value)))
+		case gl.Shader:
+			parts = append(parts, strconv.Itoa(int(v.Value)))
+		case gl.Texture:
+			parts = append(parts, strconv.Itoa(int(v.Value)))
+		case float32:
+			parts = append(parts, strconv.FormatFloat(float64(v), 'g', -1, 32))
+		case []float32:
+			for _, f := range v {
+				parts = append(parts, strconv.FormatFloat(float64(f), 'g', -1, 32))
+			}
+		case int:
+			parts = append(parts, strconv.Itoa(v))
+		case bool:
+			parts = append(parts, strconv.FormatBool(v))
+		case string:
+			parts = append(parts, v)
+		}
+	}
+	self.Calls = append(self.Calls, strings.Join(parts, " "))
+}
+
+// Returns the next handle, the handles start at 1 since 0 is the default of GL
+func (self *FakeGL) handle() uint32 {
+	self.next++
+	return self.next
+}
+
+func (self *FakeGL) ActiveTexture(texture gl.Enum) {
+	self.record("ActiveTexture", texture)
+}
+
+func (self *FakeGL) AttachShader(p gl.Program, s gl.Shader) {
+	self.record("AttachShader", p, s)
+}
+
+func (self *FakeGL) BindBuffer(target gl.Enum, b gl.Buffer) {
+	self.record("BindBuffer", target, b)
+}
+
+func (self *FakeGL) BindFramebuffer(target gl.Enum, fb gl.Framebuffer) {
+	self.record("BindFramebuffer", target, fb)
+}
+
+func (self *FakeGL) BindTexture(target gl.Enum, t gl.Texture) {
+	self.record("BindTexture", target, t)
+}
+
+func (self *FakeGL) BufferData(target gl.Enum, src []byte, usage gl.Enum) {
+	self.record("BufferData", target, len(src), usage)
+}
+
+func (self *FakeGL) CheckFramebufferStatus(target gl.Enum) gl.Enum {
+	self.record("CheckFramebufferStatus", target)
+	return self.FramebufferStatus
+}
+
+// The mask is recorded as the names of its bits joined by |
+func (self *FakeGL) Clear(mask gl.Enum) {
+	var bits []string
+	for _, bit := range []gl.Enum{gl.COLOR_BUFFER_BIT, gl.DEPTH_BUFFER_BIT, gl.STENCIL_BUFFER_BIT} {
+		if mask&bit != 0 {
+			bits = append(bits, fakeEnums[bit])
+		}
+	}
+	self.record("Clear", strings.Join(bits, "|"))
+}
+
+func (self *FakeGL) ClearColor(red, green, blue, alpha float32) {
+	self.record("ClearColor", red, green, blue, alpha)
+}
+
+func (self *FakeGL) CompileShader(s gl.Shader) {
+	self.record("CompileShader", s)
+}
+
+func (self *FakeGL) CreateBuffer() gl.Buffer {
+	b := gl.Buffer{Value: self.handle()}
+	self.record("CreateBuffer", b)
+	return b
+}
+
+func (self *FakeGL) CreateFramebuffer() gl.Framebuffer {
+	fb := gl.Framebuffer{Value: self.handle()}
+	self.record("CreateFramebuffer", fb)
+	return fb
+}
+
+func (self *FakeGL) CreateProgram() gl.Program {
+	p := gl.Program{Init: true, Value: self.handle()}
+	self.record("CreateProgram", p)
+	return p
+}
+
+func (self *FakeGL) CreateShader(ty gl.Enum) gl.Shader {
+	s := gl.Shader{Value: self.handle()}
+	self.record("CreateShader", ty, s)
+	return s
+}
+
+func (self *FakeGL) CreateTexture() gl.Texture {
+	t := gl.Texture{Value: self.handle()}
+	self.record("CreateTexture", t)
+	return t
+}
+
+func (self *FakeGL) DeleteBuffer(v gl.Buffer) {
+	self.record("DeleteBuffer", v)
+}
+
+func (self *FakeGL) DeleteFramebuffer(v gl.Framebuffer) {
+	self.record("DeleteFramebuffer", v)
+}
+
+func (self *FakeGL) DeleteProgram(p gl.Program) {
+	self.record("DeleteProgram", p)
+}
+
+func (self *FakeGL) DeleteShader(s gl.Shader) {
+	self.record("DeleteShader", s)
+	delete(self.sources, s)
+}
+
+func (self *FakeGL) DeleteTexture(v gl.Texture) {
+	self.record("DeleteTexture", v)
+}
+
+func (self *FakeGL) Disable(cap gl.Enum) {
+	self.enabled[cap] = false
+	self.record("Disable", cap)
+}
+
+func (self *FakeGL) DisableVertexAttribArray(a gl.Attrib) {
+	self.record("DisableVertexAttribArray", a)
+}
+
+func (self *FakeGL) DrawArrays(mode gl.Enum, first, count int) {
+	self.record("DrawArrays", mode, first, count)
+}
+
+func (self *FakeGL) Enable(cap gl.Enum) {
+	self.enabled[cap] = true
+	self.record("Enable", cap)
+}
+
+func (self *FakeGL) EnableVertexAttribArray(a gl.Attrib) {
+	self.record("EnableVertexAttribArray", a)
+}
+
+func (self *FakeGL) FramebufferTexture2D(target, attachment, texTarget gl.Enum, t gl.Texture, level int) {
+	self.record("FramebufferTexture2D", target, attachment, texTarget, t, level)
+}
+
+func (self *FakeGL) GetAttribLocation(p gl.Program, name string) gl.Attrib {
+	self.record("GetAttribLocation", p, name)
+	return gl.Attrib{}
+}
+
+func (self *FakeGL) GetProgrami(p gl.Program, pname gl.Enum) int {
+	self.record("GetProgrami", p, pname)
+	return 1
+}
+
+func (self *FakeGL) GetProgramInfoLog(p gl.Program) string {
+	self.record("GetProgramInfoLog", p)
+	return ""
+}
+
+func (self *FakeGL) GetShaderi(s gl.Shader, pname gl.Enum) int {
+	self.record("GetShaderi", s, pname)
+	if pname == gl.COMPILE_STATUS && self.failed(s) {
+		return 0
+	}
+	return 1
+}
+
+func (self *FakeGL) GetShaderInfoLog(s gl.Shader) string {
+	self.record("GetShaderInfoLog", s)
+	if self.failed(s) {
+		return self.ShaderError
+	}
+	return ""
+}
+
+func (self *FakeGL) failed(s gl.Shader) bool {
+	return self.ShaderError != "" && strings.Contains(self.sources[s], self.ShaderError)
+}
+
+// The uniforms get a new location each time they are looked up, which is recorded by the name
+func (self *FakeGL) GetUniformLocation(p gl.Program, name string) gl.Uniform {
+	u := gl.Uniform{Value: int32(self.handle())}
+	self.uniforms[u] = name
+	self.record("GetUniformLocation", p, name)
+	return u
+}
+
+// The capabilities are enabled by Enable, all of them start disabled
+func (self *FakeGL) IsEnabled(cap gl.Enum) bool {
+	self.record("IsEnabled", cap)
+	return self.enabled[cap]
+}
+
+func (self *FakeGL) LinkProgram(p gl.Program) {
+	self.record("LinkProgram", p)
+}
+
+func (self *FakeGL) ShaderSource(s gl.Shader, src string) {
+	self.record("ShaderSource", s)
+	self.sources[s] = src
+}
+
+func (self *FakeGL) TexImage2D(target gl.Enum, level int, internalFormat int, width, height int, format gl.Enum,
+	ty gl.Enum, data []byte) {
+	self.record("TexImage2D", target, level, gl.Enum(internalFormat), width, height, format, ty)
+}
+
+func (self *FakeGL) TexParameteri(target, pname gl.Enum, param int) {
+	self.record("TexParameteri", target, pname, gl.Enum(param))
+}
+
+func (self *FakeGL) Uniform1f(dst gl.Uniform, v float32) {
+	self.record("Uniform1f", dst, v)
+}
+
+func (self *FakeGL) Uniform1i(dst gl.Uniform, v int) {
+	self.record("Uniform1i", dst, v)
+}
+
+func (self *FakeGL) Uniform2f(dst gl.Uniform, v0, v1 float32) {
+	self.record("Uniform2f", dst, v0, v1)
+}
+
+func (self *FakeGL) Uniform3f(dst gl.Uniform, v0, v1, v2 float32) {
+	self.record("Uniform3f", dst, v0, v1, v2)
+}
+
+func (self *FakeGL) Uniform4f(dst gl.Uniform, v0, v1, v2, v3 float32) {
+	self.record("Uniform4f", dst, v0, v1, v2, v3)
+}
+
+func (self *FakeGL) UniformMatrix4fv(dst gl.Uniform, src []float32) {
+	self.record("UniformMatrix4fv", dst, src)
+}
+
+func (self *FakeGL) UseProgram(p gl.Program) {
+	self.record("UseProgram", p)
+}
+
+func (self *FakeGL) VertexAttribPointer(dst gl.Attrib, size int, ty gl.Enum, normalized bool, stride, offset int) {
+	self.record("VertexAttribPointer", dst, size, ty, normalized, stride, offset)
+}
+
+func (self *FakeGL) Viewport(x, y, width, height int) {
+	self.record("Viewport", x, y, width, height)
+}
@@ -0,0 +1,186 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/utils"
+)
+
+// The HUD of a scene is drawn over the world with its own camera, which covers the whole screen and never moves, so the
+// HUD actors are not moved, zoomed or shaken with the camera of the world. Its coordinates are in HUD units from the lower
+// left corner of the screen. The HUD actors are anchored to the edges and corners of the screen, they keep their place
+// when the screen is resized.
+//
+// The touches are tested against the HUD first. A touch which goes down on a HUD actor with an Input is not sent to the
+// world actors, nor are its drags, its release and its gestures, so that the buttons over the playfield work.
+//
+//	pause := skin.NewImageButton("pause")
+//	scene.AddHud(&pause.Actor, utils.AlignmentTop|utils.AlignmentRight, 16, 16)
+
+var (
+	// The number of HUD units per screen pixel, the HUD is as large as the screen in these units. Default is 1.
+	HudUnitsPerPixel float32 = 1
+
+	// The camera the HUD is drawn with
+	hudCamera = NewOrthographicCamera(800, 480)
+
+	// Set while the HUD is drawn or sent input, its actors are in HUD units instead of world units
+	inHud bool
+
+	// The pointers whose touch went down on the HUD
+	hudTouches = map[uint8]bool{}
+
+	// Set if the first pointer of the current gesture went down on the HUD
+	hudGesture bool
+)
+
+// How a HUD actor is placed on the screen
+type hudAnchor struct {
+	actor            *Actor
+	alignment        utils.Alignment
+	marginX, marginY float32
+}
+
+// Adds the actor to the HUD, anchored to the edges of the screen of the alignment and kept the margins away from them. It
+// is centered on the axes without an edge, like AlignmentTop is centered horizontally at the top of the screen.
+func (self *Scene) AddHud(actor *Actor, alignment utils.Alignment, marginX, marginY float32) {
+	if self.hud == nil {
+		self.hud = &Actor{SX: 1, SY: 1}
+	}
+	self.RemoveHud(actor)
+	self.hud.AddActor(actor)
+	self.hudAnchors = append(self.hudAnchors, hudAnchor{actor, alignment, marginX, marginY})
+	self.layoutHud()
+}
+
+// Removes the actor from the HUD
+func (self *Scene) RemoveHud(actor *Actor) {
+	for i, anchor := range self.hudAnchors {
+		if anchor.actor == actor {
+			self.hudAnchors = append(self.hudAnchors[:i], self.hudAnchors[i+1:]...)
+			self.hud.RemoveActor(actor)
+			return
+		}
+	}
+}
+
+// Removes all the actors from the HUD
+func (self *Scene) ClearHud() {
+	if self.hud != nil {
+		self.hud.ClearChildren()
+	}
+	self.hudAnchors = nil
+}
+
+// Returns the actors of the HUD
+func (self *Scene) GetHud() []*Actor {
+	if self.hud == nil {
+		return nil
+	}
+	return self.hud.Children
+}
+
+// Returns the size of the HUD in HUD units, which is the size of the screen
+func hudSize() (float32, float32) {
+	return float32(screenWidth) * HudUnitsPerPixel, float32(screenHeight) * HudUnitsPerPixel
+}
+
+// Moves the HUD actors to their anchors for the current size of the screen, the actors removed from the HUD are forgotten
+func (self *Scene) layoutHud() {
+	width, height := hudSize()
+	anchors := self.hudAnchors[:0]
+	for _, anchor := range self.hudAnchors {
+		actor := anchor.actor
+		if actor.Parent != self.hud {
+			continue
+		}
+		anchors = append(anchors, anchor)
+		x, y := (width-actor.W)/2, (height-actor.H)/2
+		if anchor.alignment&utils.AlignmentLeft != 0 {
+			x = anchor.marginX
+		} else if anchor.alignment&utils.AlignmentRight != 0 {
+			x = width - actor.W - anchor.marginX
+		}
+		if anchor.alignment&utils.AlignmentBottom != 0 {
+			y = anchor.marginY
+		} else if anchor.alignment&utils.AlignmentTop != 0 {
+			y = height - actor.H - anchor.marginY
+		}
+		actor.SetPosition(float32(int(x)), float32(int(y)))
+	}
+	self.hudAnchors = anchors
+}
+
+// Draws the HUD of the scene over the world with the HUD camera, on the whole screen
+func drawHud(scene *Scene, batch g2d.Batch) {
+	if scene.hud == nil || len(scene.hud.Children) == 0 {
+		return
+	}
+	width, height := hudSize()
+	hudCamera.SetToOrthoVW(false, width, height)
+	batch.Flush()
+	if drawContext != nil {
+		drawContext.Viewport(0, 0, screenWidth, screenHeight)
+	}
+	inHud = true
+	cullFrustum = hudCamera.GetFrustum()
+	batch.SetProjectionMatrix(hudCamera.Combined)
+	scene.hud.draw(batch, 1.0)
+	batch.Flush()
+	inHud = false
+}
+
+// Sends the input event to the HUD actors, with the position in HUD units. Returns whether the event is kept from the
+// world actors.
+func hudInput(scene *Scene, e InputEvent) bool {
+	if scene.hud == nil {
+		return false
+	}
+	consumed := false
+	switch e.Type {
+	case TouchDown, TouchUp, TouchDragged, MouseMoved, Enter, Exit, Scrolled, Tap, LongPress, SwipeLeft, SwipeRight,
+		SwipeUp, SwipeDown:
+		e.X, e.Y = e.ScreenX*HudUnitsPerPixel, (float32(screenHeight)-e.ScreenY)*HudUnitsPerPixel
+	}
+	switch e.Type {
+	case TouchDown:
+		consumed = hudHit(scene.hud.Children, e.X, e.Y)
+		hudTouches[e.Pointer] = consumed
+		if e.Pointer == 0 {
+			hudGesture = consumed
+		}
+	case TouchDragged:
+		consumed = hudTouches[e.Pointer]
+	case TouchUp:
+		consumed = hudTouches[e.Pointer]
+		delete(hudTouches, e.Pointer)
+	case MouseMoved, Enter, Exit, Scrolled:
+		consumed = hudHit(scene.hud.Children, e.X, e.Y)
+	case Tap, LongPress, Fling, Pan, PanStop, Zoom, Pinch, SwipeLeft, SwipeRight, SwipeUp, SwipeDown:
+		consumed = hudGesture
+	}
+	inHud = true
+	scene.hud.input(e)
+	inHud = false
+	return consumed
+}
+
+// Returns whether the point in HUD units is over one of the actors or their children which take input
+func hudHit(actors []*Actor, x, y float32) bool {
+	for i := len(actors) - 1; i >= 0; i-- {
+		actor := actors[i]
+		if actor.Hidden || actor.TouchState == TouchableDisabled {
+			continue
+		}
+		if actor.Input != nil && actor.TouchState == TouchableEnabled && actor.Hit(x, y, widgetPoint) {
+			return true
+		}
+		if hudHit(actor.Children, x, y) {
+			return true
+		}
+	}
+	return false
+}
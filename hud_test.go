@@ -0,0 +1,153 @@
+package spike
+
+import (
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+	"github.com/pyros2097/spike/utils"
+)
+
+// Sets the size of the screen and the HUD units per pixel, the returned function restores them
+func setHudScreen(width, height int, unitsPerPixel float32) func() {
+	oldWidth, oldHeight, oldUnits := screenWidth, screenHeight, HudUnitsPerPixel
+	screenWidth, screenHeight, HudUnitsPerPixel = width, height, unitsPerPixel
+	return func() {
+		screenWidth, screenHeight, HudUnitsPerPixel = oldWidth, oldHeight, oldUnits
+	}
+}
+
+func TestHudLayout(t *testing.T) {
+	defer setHudScreen(800, 480, 1)()
+	scene := &Scene{Name: "hud"}
+	actors := []*Actor{
+		{W: 100, H: 50}, {W: 100, H: 50}, {W: 101, H: 51}, {W: 100, H: 50}, {W: 100, H: 50},
+	}
+	scene.AddHud(actors[0], utils.AlignmentBottomLeft, 10, 20)
+	scene.AddHud(actors[1], utils.AlignmentTopRight, 16, 16)
+	// centered on the axes without an edge, at whole units
+	scene.AddHud(actors[2], utils.AlignmentTop, 0, 5)
+	scene.AddHud(actors[3], utils.AlignmentCenter, 10, 10)
+	scene.AddHud(actors[4], utils.AlignmentRight, 10, 0)
+	check := func(name string, expected [][2]float32) {
+		for i, actor := range actors {
+			if actor.Parent == scene.hud && (actor.X != expected[i][0] || actor.Y != expected[i][1]) {
+				t.Errorf("%s: actor %d at %v %v, expected %v", name, i, actor.X, actor.Y, expected[i])
+			}
+		}
+	}
+	check("added", [][2]float32{{10, 20}, {684, 414}, {349, 424}, {350, 215}, {690, 215}})
+
+	// the HUD is as large as the screen in HUD units, the actors keep their anchors when it is resized
+	setHudScreen(1000, 600, 0.5)
+	scene.layoutHud()
+	check("resized", [][2]float32{{10, 20}, {384, 234}, {199, 244}, {200, 125}, {390, 125}})
+
+	scene.RemoveHud(actors[1])
+	scene.hud.RemoveActor(actors[4])
+	scene.layoutHud()
+	if len(scene.hudAnchors) != 3 || len(scene.GetHud()) != 3 || actors[1].Parent != nil {
+		t.Errorf("%d anchors and %d actors after removing two", len(scene.hudAnchors), len(scene.GetHud()))
+	}
+	scene.ClearHud()
+	if len(scene.hudAnchors) != 0 || len(scene.GetHud()) != 0 {
+		t.Error("HUD not cleared")
+	}
+}
+
+func TestHudInput(t *testing.T) {
+	defer setHudScreen(800, 480, 1)()
+	drainInput()
+	scene := &Scene{Name: "hud"}
+	var world, hud []InputType
+	ground := &Actor{W: 800, H: 480}
+	ground.Input = func(a *Actor, e InputEvent) {
+		world = append(world, e.Type)
+	}
+	scene.AddActor(ground)
+	button := &Actor{W: 100, H: 50}
+	button.Input = func(a *Actor, e InputEvent) {
+		hud = append(hud, e.Type)
+	}
+	scene.AddHud(button, utils.AlignmentBottomLeft, 10, 10)
+
+	// the button covers 10, 10 to 110, 60 in HUD units, which is 10, 420 to 110, 470 on the screen
+	InputChannel <- newTouchEvent(TouchDown, 50, 440, 0)
+	InputChannel <- newTouchEvent(TouchDragged, 300, 200, 0)
+	InputChannel <- newTouchEvent(TouchUp, 300, 200, 0)
+	update(scene, 0)
+	if len(world) != 0 || len(hud) != 3 {
+		t.Errorf("touch on the HUD button sent %v to the world and %v to the HUD", world, hud)
+	}
+
+	// a touch beside the button goes to the world, the HUD actors are still sent it
+	world, hud = nil, nil
+	InputChannel <- newTouchEvent(TouchDown, 300, 200, 1)
+	InputChannel <- newTouchEvent(TouchDragged, 50, 440, 1)
+	InputChannel <- newTouchEvent(TouchUp, 50, 440, 1)
+	update(scene, 0)
+	if len(world) != 3 || len(hud) != 3 {
+		t.Errorf("touch beside the HUD button sent %v to the world and %v to the HUD", world, hud)
+	}
+
+	// a hidden button does not keep the touches from the world
+	world = nil
+	button.Hidden = true
+	InputChannel <- newTouchEvent(TouchDown, 50, 440, 0)
+	InputChannel <- newTouchEvent(TouchUp, 50, 440, 0)
+	update(scene, 0)
+	if len(world) != 2 {
+		t.Errorf("touch on the hidden HUD button sent %v to the world", world)
+	}
+	drainInput()
+}
+
+func TestHudFocus(t *testing.T) {
+	defer setHudScreen(800, 480, 1)()
+	defer SetFocus(nil)
+	scene := &Scene{Name: "hud"}
+	world := &Actor{X: 350, Y: 215, W: 100, H: 50, Focusable: true}
+	scene.AddActor(world)
+	var activated int
+	button := &Actor{W: 100, H: 50, Focusable: true}
+	button.Input = func(a *Actor, e InputEvent) {
+		if e.Type == Activated {
+			activated++
+		}
+	}
+	scene.AddHud(button, utils.AlignmentTopLeft, 10, 10)
+
+	// the focus moves between the world and the HUD by where they are on the screen
+	SetFocus(world)
+	if MoveFocus(scene, 1, 0) || !MoveFocus(scene, -1, 1) || GetFocus() != button {
+		t.Fatalf("focus moved to %v", GetFocus())
+	}
+	InputChannel <- InputEvent{Type: KeyDown, KeyCode: KeyEnter}
+	InputChannel <- InputEvent{Type: KeyUp, KeyCode: KeyEnter}
+	update(scene, 0)
+	if activated != 1 {
+		t.Errorf("HUD button activated %d times", activated)
+	}
+	if !MoveFocus(scene, 0, -1) || GetFocus() != world {
+		t.Errorf("focus moved back to %v", GetFocus())
+	}
+
+	// the outline of a focused HUD actor is drawn over the HUD, not with the world
+	SetFocus(button)
+	lines := 0
+	shapes := g2d.NewShapeRenderer()
+	shapes.Flush = func(primitive int, vertices []float32, projection *vector.Matrix4) {
+		lines += len(vertices) / g2d.ShapeVertexSize / 2
+	}
+	shapes.Begin(g2d.ShapeLine)
+	drawFocusOutline(scene.Children, shapes)
+	shapes.End()
+	if lines != 0 {
+		t.Errorf("HUD focus outlined with %d lines in the world", lines)
+	}
+	drawHudFocusOutline(scene, shapes)
+	if lines != 4 {
+		t.Errorf("HUD focus outlined with %d lines", lines)
+	}
+	drainInput()
+}
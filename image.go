@@ -0,0 +1,127 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"github.com/pyros2097/spike/utils"
+	"github.com/pyros2097/spike/utils/scaling"
+)
+
+// An Image draws a drawable scaled to its size by its scaling and aligned in it. Its preferred size is the minimum size of
+// the drawable.
+type Image struct {
+	Actor
+	widget
+
+	drawable                                ui.Drawable
+	scaling                                 scaling.Scaling
+	align                                   utils.Alignment
+	imageX, imageY, imageWidth, imageHeight float32
+}
+
+// Creates an image of the drawable stretched to the size of the image, the drawable may be nil
+func NewImage(drawable ui.Drawable) *Image {
+	self := &Image{drawable: drawable, scaling: scaling.Stretch, align: utils.AlignmentCenter}
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.widget.init(&self.Actor, self)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+// Sets the drawable, the image is sized again if its minimum size is different
+func (self *Image) SetDrawable(drawable ui.Drawable) {
+	if drawable == self.drawable {
+		return
+	}
+	if drawable == nil || self.drawable == nil || drawable.GetMinWidth() != self.GetPrefWidth() ||
+		drawable.GetMinHeight() != self.GetPrefHeight() {
+		self.drawable = drawable
+		self.InvalidateHierarchy()
+		return
+	}
+	self.drawable = drawable
+}
+
+func (self *Image) GetDrawable() ui.Drawable {
+	return self.drawable
+}
+
+// Sets how the drawable is scaled to the size of the image. Default is stretch.
+func (self *Image) SetScaling(scaling scaling.Scaling) {
+	self.scaling = scaling
+	self.Invalidate()
+}
+
+// Sets where the drawable is in the image when it is smaller than it. Default is center.
+func (self *Image) SetAlign(align utils.Alignment) {
+	self.align = align
+	self.Invalidate()
+}
+
+// Scales the drawable to the image and aligns it
+func (self *Image) Layout() {
+	if self.drawable == nil {
+		return
+	}
+	size := self.scaling.Apply(self.drawable.GetMinWidth(), self.drawable.GetMinHeight(), self.W, self.H)
+	self.imageWidth, self.imageHeight = size.X, size.Y
+	switch {
+	case self.align&utils.AlignmentLeft != 0:
+		self.imageX = 0
+	case self.align&utils.AlignmentRight != 0:
+		self.imageX = self.W - self.imageWidth
+	default:
+		self.imageX = (self.W - self.imageWidth) / 2
+	}
+	switch {
+	case self.align&utils.AlignmentTop != 0:
+		self.imageY = self.H - self.imageHeight
+	case self.align&utils.AlignmentBottom != 0:
+		self.imageY = 0
+	default:
+		self.imageY = (self.H - self.imageHeight) / 2
+	}
+}
+
+func (self *Image) GetMinWidth() float32 {
+	return 0
+}
+
+func (self *Image) GetMinHeight() float32 {
+	return 0
+}
+
+func (self *Image) GetPrefWidth() float32 {
+	if self.drawable == nil {
+		return 0
+	}
+	return self.drawable.GetMinWidth()
+}
+
+func (self *Image) GetPrefHeight() float32 {
+	if self.drawable == nil {
+		return 0
+	}
+	return self.drawable.GetMinHeight()
+}
+
+func (self *Image) GetMaxWidth() float32 {
+	return 0
+}
+
+func (self *Image) GetMaxHeight() float32 {
+	return 0
+}
+
+func (self *Image) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	setBatchColor(batch, &self.Actor, nil, parentAlpha)
+	drawDrawable(batch, &self.Actor, self.drawable, self.imageX, self.imageY, self.imageWidth, self.imageHeight)
+}
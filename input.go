@@ -93,8 +93,8 @@ const (
 
 	// Called when the user dragged a finger over the screen and lifted it. Reports the last known velocity of the finger in
 	// pixels per second.
-	// param velocityX velocity on x in seconds
-	// param velocityY velocity on y in seconds
+	// param deltaX velocity on x in seconds
+	// param deltaY velocity on y in seconds
 	Fling
 
 	// Called when the user drags a finger over the screen.
@@ -109,9 +109,7 @@ const (
 	LongPress
 
 	// Called when the user performs a pinch zoom gesture. The original distance is the distance in pixels when the gesture
-	// started.
-	// param initialDistance distance between fingers when the gesture started.
-	// param distance current distance between fingers.
+	// started. DeltaX is the distance between the fingers when the gesture started and DeltaY the current distance.
 	Zoom
 
 	// Called when a user performs a pinch zoom gesture. Reports the initial positions of the two involved fingers and their
@@ -127,6 +125,43 @@ const (
 	SwipeRight
 	SwipeUp
 	SwipeDown
+
+	// An axis of a controller, like a thumbstick or a trigger, has moved.
+	// param axis the axis that moved
+	// param value the new value of the axis in the range [-1, 1]
+	AxisMoved
+
+	// A pov (hat switch or d-pad) of a controller has moved.
+	// param button the index of the pov
+	// param pov the new direction of the pov
+	PovMoved
+
+	// A controller was connected.
+	ControllerConnected
+
+	// A controller was disconnected. Its buttons are released before.
+	ControllerDisconnected
+
+	// The text of a text input request was entered.
+	// param text the text
+	TextInput
+
+	// A text input request was canceled.
+	TextInputCanceled
+
+	// The text being composed with an input method or in a text input request has changed. The composed text is not entered
+	// yet, once it is committed its characters are sent as KeyTyped events.
+	// param text the composed text, empty when the composition has ended
+	TextComposed
+
+	// The actor has gained the focus, see SetFocus. It is only sent to the actor.
+	FocusGained
+
+	// The actor has lost the focus. It is only sent to the actor.
+	FocusLost
+
+	// The focused actor was activated with enter or the A button of a controller, like a click. It is only sent to the actor.
+	Activated
 )
 
 // The type of Mouse buttons
@@ -179,27 +214,54 @@ type InputEvent struct {
 	Type InputType
 
 	// The stage x coordinate where the event occurred. Valid for: touchDown, touchDragged, touchUp, mouseMoved, enter, and exit.
+	// The stage coordinates are the world units of the viewport, the origin is in the lower left corner.
 	X float32
 
-	// The stage x coordinate where the event occurred. Valid for: touchDown, touchDragged, touchUp, mouseMoved, enter, and exit.
+	// The stage y coordinate where the event occurred. Valid for: touchDown, touchDragged, touchUp, mouseMoved, enter, and exit.
 	Y float32
 
+	// The screen coordinates in pixels where the event occurred, the origin is in the upper left corner. Valid for: touchDown,
+	// touchDragged, touchUp, tap, longPress, fling, pan, zoom and the swipes.
+	ScreenX, ScreenY float32
+
+	// The velocity in pixels per second. Valid for: fling.
+	// The distance in pixels to the last drag. Valid for: pan.
+	// The distance in pixels between the fingers when the gesture started and now. Valid for: zoom.
+	DeltaX, DeltaY float32
+
 	// The pointer index for the event. The first touch is index 0, second touch is index 1, etc. Always -1 on desktop. Valid for:
 	// touchDown, touchDragged, touchUp, enter, and exit.
 	Pointer uint8
 
 	// The index for the mouse button pressed. Always 0 on Android. Valid for: touchDown and touchUp.
+	// The index of the pov that moved. Valid for: povMoved.
 	Button uint8
 
 	// The key code of the key that was pressed. Valid for: keyDown and keyUp.
-	KeyCode int
+	KeyCode KeyCode
 
 	// The character for the key that was type. Valid for: keyTyped.
-	Character uint8
+	Character rune
+
+	// The text that was entered or composed. Valid for: textInput and textComposed.
+	Text string
 
 	// The amount the mouse was scrolled. Valid for: scrolled.
 	ScrollAmount int
 
+	// The controller axis that moved. Valid for: axisMoved.
+	Axis ControllerAxis
+
+	// The value of the axis in the range [-1, 1]. Valid for: axisMoved.
+	Value float32
+
+	// The direction of the pov. Valid for: povMoved.
+	Pov PovDirection
+
+	// The index of the controller that sent the event plus one, 0 if the event was not sent by a controller. Valid for:
+	// keyDown, keyUp, axisMoved, povMoved, controllerConnected and controllerDisconnected.
+	Controller uint8
+
 	// The actor related to the event. Valid for: enter and exit. For enter, this is the actor being exited, or null.
 	// For exit, this is the actor being entered, or null.
 	// RelatedActor *scene2d.Actor
@@ -245,6 +307,26 @@ func (input InputType) String() string {
 		return "SwipeLeft"
 	case SwipeRight:
 		return "SwipeRight"
+	case AxisMoved:
+		return "AxisMoved"
+	case PovMoved:
+		return "PovMoved"
+	case ControllerConnected:
+		return "ControllerConnected"
+	case ControllerDisconnected:
+		return "ControllerDisconnected"
+	case TextInput:
+		return "TextInput"
+	case TextInputCanceled:
+		return "TextInputCanceled"
+	case TextComposed:
+		return "TextComposed"
+	case FocusGained:
+		return "FocusGained"
+	case FocusLost:
+		return "FocusLost"
+	case Activated:
+		return "Activated"
 	case None:
 		return "None"
 	default:
@@ -269,7 +351,7 @@ var (
 	TapSquareSize                      float32 = 20
 	tapCountInterval                   int64   = 0 //0.4f
 	LongPressSeconds                   float32 = 1.1
-	MaxFlingDelay                      int64   = 0 //0.15
+	MaxFlingDelay                      int64   = 150 * 1000000 // the nanoseconds from the last drag to the release of a fling
 	inTapSquare                                = false
 	tapCount                           int     = 0
 	lastTapTime                        int64   = 0
@@ -279,17 +361,13 @@ var (
 	tapSquareCenterX, tapSquareCenterY float32
 	gestureStartTime                   int64
 	longPressScheduled                 = false
-	longPressTask                      = time.AfterFunc(time.Duration(float32(time.Second)*LongPressSeconds), fireLongPress)
 	fireLongPress                      = func() {
 		if !longPressFired {
 			longPressFired = true
-			InputChannel <- InputEvent{
-				Type: LongPress,
-				X:    pointer1.X,
-				Y:    pointer1.Y,
-			}
+			InputChannel <- gestureEvent(LongPress, touchDownEvent)
 		}
 	}
+	touchDownEvent  InputEvent // the last touch down of the first pointer
 	pointer1        = vector.NewVector2Empty()
 	pointer2        = vector.NewVector2Empty()
 	initialPointer1 = vector.NewVector2Empty()
@@ -298,6 +376,21 @@ var (
 	queue           []InputEvent
 	InputChannel    = make(chan InputEvent, 100)
 
+	// The keys which are down, updated as the key events are processed
+	pressedKeys = map[KeyCode]bool{}
+
+	// The actor which receives the typed text
+	keyboardFocus *Actor
+
+	// Returns the time of the current input event in nanoseconds. While a recording is replayed it returns the recorded
+	// times so that gestures are detected exactly like they were during the recording.
+	inputClock = func() int64 {
+		return time.Now().UnixNano()
+	}
+
+	// The input time at which the current frame started
+	frameTime int64
+
 	// Gesture related
 	gestureStarted                       = false
 	touchDragIntervalRange       float32 = 200 // 200px drag for a gesture event
@@ -345,26 +438,40 @@ func GetEvent() *InputEvent {
 //      Scene.getCurrentScene().onClick(validActor);
 //  }
 
-func doTouchDown(x, y float32, pointer, button int) bool {
-	InputChannel <- InputEvent{
-		Type:    TouchDown,
-		X:       x,
-		Y:       y,
-		Pointer: 0,
-		Button:  0,
+// Creates a touch event at the screen coordinates, which are unprojected to stage coordinates by the viewport
+func newTouchEvent(t InputType, screenX, screenY float32, pointer uint8) InputEvent {
+	e := InputEvent{Type: t, X: screenX, Y: screenY, ScreenX: screenX, ScreenY: screenY, Pointer: pointer}
+	if viewport != nil {
+		e.X, e.Y = viewport.ToWorld(screenX, screenY)
+	}
+	return e
+}
+
+// Creates a gesture event at the coordinates of the touch event
+func gestureEvent(t InputType, e InputEvent) InputEvent {
+	return InputEvent{Type: t, X: e.X, Y: e.Y, ScreenX: e.ScreenX, ScreenY: e.ScreenY}
+}
+
+// Gestures are detected in screen coordinates so that the tap square and the swipe distances are in pixels on every
+// screen.
+func doTouchDown(e InputEvent) bool {
+	InputChannel <- e
+	x, y, pointer := e.ScreenX, e.ScreenY, int(e.Pointer)
+	if pointer > 1 {
+		return false
 	}
 	if pointer == 0 {
+		touchDownEvent = e
 		pointer1.Set(x, y)
-		gestureStartTime = time.Now().UnixNano() //Gdx.input.getCurrentEventTime()
+		gestureStartTime = inputClock() //Gdx.input.getCurrentEventTime()
 		velocityStart(x, y, gestureStartTime)
 		inTapSquare = true
 		pinching = false
 		longPressFired = false
 		tapSquareCenterX = x
 		tapSquareCenterY = y
-		if !longPressScheduled {
-			longPressTask = time.AfterFunc(time.Duration(float32(time.Second)*LongPressSeconds), fireLongPress)
-		}
+		// the long press is fired by checkLongPress once the touch was held long enough
+		longPressScheduled = true
 	} else {
 		// Start pinch.
 		pointer2.Set(x, y)
@@ -372,7 +479,6 @@ func doTouchDown(x, y float32, pointer, button int) bool {
 		pinching = true
 		initialPointer1.SetV(pointer1)
 		initialPointer2.SetV(pointer2)
-		longPressTask.Stop()
 		longPressScheduled = false
 	}
 	//    mouse.set(x, y);
@@ -388,11 +494,11 @@ func doTouchDown(x, y float32, pointer, button int) bool {
 	return false
 }
 
-func doTouchUp(x, y float32, pointer, button int) bool {
-	InputChannel <- InputEvent{
-		Type: TouchUp,
-		X:    x,
-		Y:    y,
+func doTouchUp(e InputEvent) bool {
+	InputChannel <- e
+	x, y, pointer, button := e.ScreenX, e.ScreenY, int(e.Pointer), int(e.Button)
+	if pointer > 1 {
+		return false
 	}
 	// check if we are still tapping.
 	if inTapSquare && !isWithinTapSquare(x, y, tapSquareCenterX, tapSquareCenterY) {
@@ -402,7 +508,6 @@ func doTouchUp(x, y float32, pointer, button int) bool {
 	wasPanning := panning
 	panning = false
 
-	longPressTask.Stop()
 	longPressScheduled = false
 	if longPressFired {
 		return false
@@ -410,12 +515,12 @@ func doTouchUp(x, y float32, pointer, button int) bool {
 
 	if inTapSquare {
 		// handle taps
-		if lastTapButton != button || lastTapPointer != pointer || time.Now().UnixNano()-lastTapTime > tapCountInterval ||
+		if lastTapButton != button || lastTapPointer != pointer || inputClock()-lastTapTime > tapCountInterval ||
 			!isWithinTapSquare(x, y, lastTapX, lastTapY) {
 			tapCount = 0
 		}
 		tapCount++
-		lastTapTime = time.Now().UnixNano()
+		lastTapTime = inputClock()
 		lastTapX = x
 		lastTapY = y
 		lastTapButton = button
@@ -428,11 +533,7 @@ func doTouchUp(x, y float32, pointer, button int) bool {
 		// 	Pointer: 0,
 		// 	Button:  0,
 		// })
-		InputChannel <- InputEvent{
-			Type: Tap,
-			X:    x,
-			Y:    y,
-		}
+		InputChannel <- gestureEvent(Tap, e)
 		return true
 	}
 
@@ -443,10 +544,10 @@ func doTouchUp(x, y float32, pointer, button int) bool {
 		// we are in pan mode again, reset velocity tracker
 		if pointer == 0 {
 			// first pointer has lifted off, set up panning to use the second pointer...
-			velocityStart(pointer2.X, pointer2.Y, time.Now().UnixNano()) //Gdx.input.getCurrentEventTime())
+			velocityStart(pointer2.X, pointer2.Y, inputClock()) //Gdx.input.getCurrentEventTime())
 		} else {
 			// second pointer has lifted off, set up panning to use the first pointer...
-			velocityStart(pointer1.X, pointer1.Y, time.Now().UnixNano()) //Gdx.input.getCurrentEventTime())
+			velocityStart(pointer1.X, pointer1.Y, inputClock()) //Gdx.input.getCurrentEventTime())
 		}
 		return false
 	}
@@ -454,27 +555,18 @@ func doTouchUp(x, y float32, pointer, button int) bool {
 	// handle no longer panning
 	handled := false
 	if wasPanning && !panning {
-		InputChannel <- InputEvent{
-			Type:    PanStop,
-			X:       x,
-			Y:       y,
-			Pointer: 0,
-			Button:  0,
-		}
+		InputChannel <- gestureEvent(PanStop, e)
 		handled = false
 	}
 
 	// handle fling
 	gestureStartTime = 0
-	time := time.Now().UnixNano() //Gdx.input.getCurrentEventTime();
+	time := inputClock() //Gdx.input.getCurrentEventTime();
 	if time-LastTime < MaxFlingDelay {
 		velocityUpdate(x, y, time)
-		InputChannel <- InputEvent{
-			Type:   Fling,
-			X:      getVelocityX(),
-			Y:      getVelocityY(),
-			Button: 0,
-		}
+		fling := gestureEvent(Fling, e)
+		fling.DeltaX, fling.DeltaY = getVelocityX(), getVelocityY()
+		InputChannel <- fling
 	}
 	// reset Gesture
 	difX = 0.0
@@ -494,12 +586,9 @@ func doTouchUp(x, y float32, pointer, button int) bool {
 	return handled
 }
 
-func doTouchDragged(x, y float32, pointer int) bool {
-	InputChannel <- InputEvent{
-		Type: TouchDragged,
-		X:    x,
-		Y:    y,
-	}
+func doTouchDragged(e InputEvent) bool {
+	InputChannel <- e
+	x, y, pointer := e.ScreenX, e.ScreenY, int(e.Pointer)
 	if pointer > 1 {
 		return false
 	}
@@ -513,21 +602,19 @@ func doTouchDragged(x, y float32, pointer int) bool {
 		pointer2.Set(x, y)
 	}
 
-	// // handle pinch zoom
+	// handle pinch zoom
 	if pinching {
-		//  if (listener != null) {
-		//    boolean result = listener.pinch(initialPointer1, initialPointer2, pointer1, pointer2);
-		//    return listener.zoom(initialPointer1.dst(initialPointer2), pointer1.dst(pointer2)) || result;
-		//  }
-		//  return false;
+		zoom := gestureEvent(Zoom, e)
+		zoom.DeltaX, zoom.DeltaY = initialPointer1.DstV(initialPointer2), pointer1.DstV(pointer2)
+		InputChannel <- zoom
+		return false
 	}
 
 	// update tracker
-	velocityUpdate(x, y, time.Now().UnixNano()) //Gdx.input.getCurrentEventTime())
+	velocityUpdate(x, y, inputClock()) //Gdx.input.getCurrentEventTime())
 
 	// // check if we are still tapping.
 	if inTapSquare && !isWithinTapSquare(x, y, tapSquareCenterX, tapSquareCenterY) {
-		longPressTask.Stop()
 		longPressScheduled = false
 		inTapSquare = false
 	}
@@ -535,22 +622,16 @@ func doTouchDragged(x, y float32, pointer int) bool {
 	// if we have left the tap square, we are panning
 	if !inTapSquare {
 		panning = true
-		InputChannel <- InputEvent{
-			Type: Pan,
-			X:    deltaX,
-			Y:    deltaY,
-		}
+		pan := gestureEvent(Pan, e)
+		pan.DeltaX, pan.DeltaY = deltaX, deltaY
+		InputChannel <- pan
 	}
 	if gestureStarted == true {
 		touchCurrentX = x
 		touchCurrentY = y
 		if dir := getGestureDirection(); dir != None {
 			gestureStarted = false
-			InputChannel <- InputEvent{
-				Type: dir,
-				X:    x,
-				Y:    y,
-			}
+			InputChannel <- gestureEvent(dir, e)
 		}
 	}
 	//    if(Camera.useDrag)
@@ -559,9 +640,156 @@ func doTouchDragged(x, y float32, pointer int) bool {
 	return false
 }
 
+// Fires the long press once the first pointer has been held down long enough.
+func checkLongPress() {
+	if longPressScheduled && gestureStartTime != 0 && frameTime-gestureStartTime > int64(LongPressSeconds*1000000000) {
+		longPressScheduled = false
+		fireLongPress()
+	}
+}
+
+// Feeds an input event from the platform to the gesture detector, which sends it along with any gesture it detects to the
+// actors. While a recording is replayed the events from the platform are ignored.
+func feedInput(e InputEvent) {
+	if currentReplay != nil {
+		return
+	}
+	recordInput(e)
+	dispatchInput(e)
+}
+
+func dispatchInput(e InputEvent) {
+	if e.Controller != 0 {
+		doControllerEvent(e)
+		return
+	}
+	switch e.Type {
+	case TouchDown:
+		doTouchDown(e)
+	case TouchUp:
+		doTouchUp(e)
+	case TouchDragged:
+		doTouchDragged(e)
+	case KeyDown:
+		doKeyDown(e.KeyCode)
+	case KeyUp:
+		doKeyUp(e.KeyCode)
+	default:
+		InputChannel <- e
+	}
+}
+
+// Called when a key was pressed
+func doKeyDown(keycode KeyCode) bool {
+	InputChannel <- InputEvent{
+		Type:    KeyDown,
+		KeyCode: keycode,
+	}
+	return false
+}
+
+// Called when a key was released
+func doKeyUp(keycode KeyCode) bool {
+	InputChannel <- InputEvent{
+		Type:    KeyUp,
+		KeyCode: keycode,
+	}
+	return false
+}
+
+// Drains the pending input events, updates the action bindings with them and hands each event to the HUD and then to
+// the actors of the scene.
+func processInput(scene *Scene) {
+	checkLongPress()
+	for len(InputChannel) > 0 {
+		e := <-InputChannel
+		if e.Controller == 0 {
+			switch e.Type {
+			case KeyDown:
+				pressedKeys[e.KeyCode] = true
+			case KeyUp:
+				delete(pressedKeys, e.KeyCode)
+			}
+		}
+		processBinding(e)
+		processTextInput(e)
+		typing := keyboardFocus != nil
+		modal := modalActor(scene)
+		touch := isTouchEvent(e.Type)
+		// the HUD is over the world, the touches on it are not sent to the world actors
+		consumed := false
+		if modal == nil || !touch {
+			consumed = hudInput(scene, e) && touch
+		}
+		for _, child := range scene.Children {
+			if modal == nil && !consumed || child == modal || !touch {
+				child.input(e)
+			}
+		}
+		processFocus(scene, e, typing)
+	}
+	updateBindings()
+}
+
+// Returns the topmost visible modal child of the scene, the touch events are only sent to it. Returns nil if there is none.
+func modalActor(scene *Scene) *Actor {
+	for i := len(scene.Children) - 1; i >= 0; i-- {
+		if child := scene.Children[i]; child.modal && !child.Hidden {
+			return child
+		}
+	}
+	return nil
+}
+
+// Returns whether the event is sent to the actors at its position, which receive it only if they are touchable
+func isTouchEvent(t InputType) bool {
+	switch t {
+	case TouchDown, TouchUp, TouchDragged, MouseMoved, Enter, Exit, Scrolled, Tap, Fling, Pan, PanStop, LongPress, Zoom,
+		Pinch, SwipeLeft, SwipeRight, SwipeUp, SwipeDown:
+		return true
+	}
+	return false
+}
+
+// Returns whether the key is pressed, keys of controllers excluded
+func IsKeyPressed(key KeyCode) bool {
+	return pressedKeys[key]
+}
+
+// Returns whether either shift key is pressed
+func IsShiftPressed() bool {
+	return pressedKeys[KeyShiftLeft] || pressedKeys[KeyShiftRight]
+}
+
+// Returns whether either control key is pressed
+func IsControlPressed() bool {
+	return pressedKeys[KeyControlLeft] || pressedKeys[KeyControlRight]
+}
+
+// Sets the actor which receives the typed text, like a text field, nil to clear it. The actor losing the focus and the
+// actor gaining it are notified with their OnFocus.
+func SetKeyboardFocus(actor *Actor) {
+	if keyboardFocus == actor {
+		return
+	}
+	old := keyboardFocus
+	keyboardFocus = actor
+	if old != nil && old.OnFocus != nil {
+		old.OnFocus(old, false)
+	}
+	if actor != nil && actor.OnFocus != nil {
+		actor.OnFocus(actor, true)
+	}
+}
+
+// Returns the actor which receives the typed text, or nil
+func GetKeyboardFocus() *Actor {
+	return keyboardFocus
+}
+
 // No further gesture events will be triggered for the current touch, if any.
 func Cancel() {
-	longPressTask.Stop()
+	longPressScheduled = false
 	longPressFired = true
 }
 
@@ -576,7 +804,7 @@ func isLongPressedDuration(duration float32) bool {
 	if gestureStartTime == 0 {
 		return false
 	}
-	return time.Now().UnixNano()-gestureStartTime > int64(duration*1000000000)
+	return inputClock()-gestureStartTime > int64(duration*1000000000)
 }
 
 func IsPanning() bool {
@@ -589,6 +817,32 @@ func Reset() {
 	inTapSquare = false
 }
 
+// Discards the pending input events and brings the gesture detector back to its initial state.
+func resetInput() {
+	for len(InputChannel) > 0 {
+		<-InputChannel
+	}
+	Reset()
+	longPressScheduled = false
+	longPressFired = false
+	pinching = false
+	tapCount = 0
+	lastTapTime = 0
+	lastTapX, lastTapY = 0, 0
+	lastTapButton, lastTapPointer = 0, 0
+	pointer1.Set(0, 0)
+	pointer2.Set(0, 0)
+	gestureStarted = false
+	touchInitialX, touchInitialY = 0, 0
+	touchCurrentX, touchCurrentY = 0, 0
+	difX, difY = 0, 0
+	prevDifX, prevDifY = 0, 0
+	touchDifX, touchDifY = 0, 0
+	velocityStart(0, 0, 0)
+	pressedKeys = map[KeyCode]bool{}
+	releaseBindings()
+}
+
 // The tap square will not longer be used for the current touch
 func InvalidateTapSquare() {
 	inTapSquare = false
@@ -641,20 +895,20 @@ func velocityUpdate(x, y float32, timeStamp int64) {
 
 func getVelocityX() float32 {
 	meanX := getAverage(meanX, numSamples)
-	meanTime := getAverageInt(meanTime, numSamples) / 1000000000.0
+	meanTime := float32(getAverageInt(meanTime, numSamples)) / 1000000000
 	if meanTime == 0 {
 		return 0
 	}
-	return meanX / float32(meanTime)
+	return meanX / meanTime
 }
 
 func getVelocityY() float32 {
 	meanY := getAverage(meanY, numSamples)
-	meanTime := getAverageInt(meanTime, numSamples) / 1000000000.0
+	meanTime := float32(getAverageInt(meanTime, numSamples)) / 1000000000
 	if meanTime == 0 {
 		return 0
 	}
-	return meanY / float32(meanTime)
+	return meanY / meanTime
 }
 
 func min(a, b int) int {
@@ -753,10 +1007,6 @@ func getGestureDirection() InputType {
 // 	Portrait  Orientation = 1
 // )
 
-// // Callback interface for {@link Input#getTextInput(TextInputListener, String, String, String)}
-// func TextInputListener(text string) {}
-// func TextInputCanceled()            {}
-
 // // Enumeration of potentially available peripherals. Use with {@link Input#isPeripheralAvailable(Peripheral)}.
 // // public enum Peripheral {
 // //   HardwareKeyboard, OnscreenKeyboard, MultitouchScreen, Accelerometer, Compass, Vibrator
@@ -845,13 +1095,6 @@ func getGestureDirection() InputType {
 // func IsButtonPressed(button int) bool {
 // }
 
-// // Returns whether the key is pressed.
-// //
-// // param key The key code as found in {@link Input.Keys}.
-// // return true or false.
-// func IsKeyPressed(key int) bool {
-// }
-
 // // Returns whether the key has just been pressed.
 // //
 // // param key The key code as found in {@link Input.Keys}.
@@ -859,21 +1102,6 @@ func getGestureDirection() InputType {
 // func IsKeyJustPressed(key int) bool {
 // }
 
-// // System dependent method to input a string of text. A dialog box will be created with the given title and the given text as a
-// // message for the user. Once the dialog has been closed the provided {@link TextInputListener} will be called on the rendering
-// // thread.
-// //
-// // param listener The TextInputListener.
-// // param title The title of the text input dialog.
-// // param text The message presented to the user.
-// // func GetTextInput(TextInputListener listener, title, text, hint string)
-
-// // Sets the on-screen keyboard visible if available.
-// //
-// // param visible visible or not
-// func SetOnscreenKeyboardVisible(visible bool) {
-// }
-
 // // Vibrates for the given amount of time. Note that you'll need the permission
 // // <code> <uses-permission android:name="android.permission.VIBRATE" /></code> in your manifest file in order for this to work.
 // //
@@ -992,454 +1220,437 @@ func getGestureDirection() InputType {
 // The type of Keyboard Keys
 type KeyCode uint8
 
-// const (
-// 	ANY_KEY             KeyCode = -1
-// 	NUM_0                       = 7
-// 	NUM_1                       = 8
-// 	NUM_2                       = 9
-// 	NUM_3                       = 10
-// 	NUM_4                       = 11
-// 	NUM_5                       = 12
-// 	NUM_6                       = 13
-// 	NUM_7                       = 14
-// 	NUM_8                       = 15
-// 	NUM_9                       = 16
-// 	A                           = 29
-// 	ALT_LEFT                    = 57
-// 	ALT_RIGHT                   = 58
-// 	APOSTROPHE                  = 75
-// 	AT                          = 77
-// 	B                           = 30
-// 	BACK                        = 4
-// 	BACKSLASH                   = 73
-// 	C                           = 31
-// 	CALL                        = 5
-// 	CAMERA                      = 27
-// 	CLEAR                       = 28
-// 	COMMA                       = 55
-// 	D                           = 32
-// 	DEL                         = 67
-// 	BACKSPACE                   = 67
-// 	FORWARD_DEL                 = 112
-// 	DPAD_CENTER                 = 23
-// 	DPAD_DOWN                   = 20
-// 	DPAD_LEFT                   = 21
-// 	DPAD_RIGHT                  = 22
-// 	DPAD_UP                     = 19
-// 	CENTER                      = 23
-// 	DOWN                        = 20
-// 	LEFT                        = 21
-// 	RIGHT                       = 22
-// 	UP                          = 19
-// 	E                           = 33
-// 	ENDCALL                     = 6
-// 	ENTER                       = 66
-// 	ENVELOPE                    = 65
-// 	EQUALS                      = 70
-// 	EXPLORER                    = 64
-// 	F                           = 34
-// 	FOCUS                       = 80
-// 	G                           = 35
-// 	GRAVE                       = 68
-// 	H                           = 36
-// 	HEADSETHOOK                 = 79
-// 	HOME                        = 3
-// 	I                           = 37
-// 	J                           = 38
-// 	K                           = 39
-// 	L                           = 40
-// 	LEFT_BRACKET                = 71
-// 	M                           = 41
-// 	MEDIA_FAST_FORWARD          = 90
-// 	MEDIA_NEXT                  = 87
-// 	MEDIA_PLAY_PAUSE            = 85
-// 	MEDIA_PREVIOUS              = 88
-// 	MEDIA_REWIND                = 89
-// 	MEDIA_STOP                  = 86
-// 	MENU                        = 82
-// 	MINUS                       = 69
-// 	MUTE                        = 91
-// 	N                           = 42
-// 	NOTIFICATION                = 83
-// 	NUM                         = 78
-// 	O                           = 43
-// 	P                           = 44
-// 	PERIOD                      = 56
-// 	PLUS                        = 81
-// 	POUND                       = 18
-// 	POWER                       = 26
-// 	Q                           = 45
-// 	R                           = 46
-// 	RIGHT_BRACKET               = 72
-// 	S                           = 47
-// 	SEARCH                      = 84
-// 	SEMICOLON                   = 74
-// 	SHIFT_LEFT                  = 59
-// 	SHIFT_RIGHT                 = 60
-// 	SLASH                       = 76
-// 	SOFT_LEFT                   = 1
-// 	SOFT_RIGHT                  = 2
-// 	SPACE                       = 62
-// 	STAR                        = 17
-// 	SYM                         = 63
-// 	T                           = 48
-// 	TAB                         = 61
-// 	U                           = 49
-// 	UNKNOWN                     = 0
-// 	V                           = 50
-// 	VOLUME_DOWN                 = 25
-// 	VOLUME_UP                   = 24
-// 	W                           = 51
-// 	X                           = 52
-// 	Y                           = 53
-// 	Z                           = 54
-// 	META_ALT_LEFT_ON            = 16
-// 	META_ALT_ON                 = 2
-// 	META_ALT_RIGHT_ON           = 32
-// 	META_SHIFT_LEFT_ON          = 64
-// 	META_SHIFT_ON               = 1
-// 	META_SHIFT_RIGHT_ON         = 128
-// 	META_SYM_ON                 = 4
-// 	CONTROL_LEFT                = 129
-// 	CONTROL_RIGHT               = 130
-// 	ESCAPE                      = 131
-// 	END                         = 132
-// 	INSERT                      = 133
-// 	PAGE_UP                     = 92
-// 	PAGE_DOWN                   = 93
-// 	PICTSYMBOLS                 = 94
-// 	SWITCH_CHARSET              = 95
-// 	BUTTON_CIRCLE               = 255
-// 	BUTTON_A                    = 96
-// 	BUTTON_B                    = 97
-// 	BUTTON_C                    = 98
-// 	BUTTON_X                    = 99
-// 	BUTTON_Y                    = 100
-// 	BUTTON_Z                    = 101
-// 	BUTTON_L1                   = 102
-// 	BUTTON_R1                   = 103
-// 	BUTTON_L2                   = 104
-// 	BUTTON_R2                   = 105
-// 	BUTTON_THUMBL               = 106
-// 	BUTTON_THUMBR               = 107
-// 	BUTTON_START                = 108
-// 	BUTTON_SELECT               = 109
-// 	BUTTON_MODE                 = 110
-
-// 	NUMPAD_0 = 144
-// 	NUMPAD_1 = 145
-// 	NUMPAD_2 = 146
-// 	NUMPAD_3 = 147
-// 	NUMPAD_4 = 148
-// 	NUMPAD_5 = 149
-// 	NUMPAD_6 = 150
-// 	NUMPAD_7 = 151
-// 	NUMPAD_8 = 152
-// 	NUMPAD_9 = 153
-
-// 	COLON = 243
-// 	F1    = 244
-// 	F2    = 245
-// 	F3    = 246
-// 	F4    = 247
-// 	F5    = 248
-// 	F6    = 249
-// 	F7    = 250
-// 	F8    = 251
-// 	F9    = 252
-// 	F10   = 253
-// 	F11   = 254
-// 	F12   = 255
-// )
+// Key codes, matching the Android keycodes used by the original framework.
+const (
+	KeyNum0             KeyCode = 7
+	KeyNum1             KeyCode = 8
+	KeyNum2             KeyCode = 9
+	KeyNum3             KeyCode = 10
+	KeyNum4             KeyCode = 11
+	KeyNum5             KeyCode = 12
+	KeyNum6             KeyCode = 13
+	KeyNum7             KeyCode = 14
+	KeyNum8             KeyCode = 15
+	KeyNum9             KeyCode = 16
+	KeyA                KeyCode = 29
+	KeyAltLeft          KeyCode = 57
+	KeyAltRight         KeyCode = 58
+	KeyApostrophe       KeyCode = 75
+	KeyAt               KeyCode = 77
+	KeyB                KeyCode = 30
+	KeyBack             KeyCode = 4
+	KeyBackslash        KeyCode = 73
+	KeyC                KeyCode = 31
+	KeyCall             KeyCode = 5
+	KeyCamera           KeyCode = 27
+	KeyClear            KeyCode = 28
+	KeyComma            KeyCode = 55
+	KeyD                KeyCode = 32
+	KeyDel              KeyCode = 67
+	KeyBackspace        KeyCode = 67
+	KeyForwardDel       KeyCode = 112
+	KeyDpadCenter       KeyCode = 23
+	KeyDpadDown         KeyCode = 20
+	KeyDpadLeft         KeyCode = 21
+	KeyDpadRight        KeyCode = 22
+	KeyDpadUp           KeyCode = 19
+	KeyE                KeyCode = 33
+	KeyEndcall          KeyCode = 6
+	KeyEnter            KeyCode = 66
+	KeyEnvelope         KeyCode = 65
+	KeyEquals           KeyCode = 70
+	KeyExplorer         KeyCode = 64
+	KeyF                KeyCode = 34
+	KeyFocus            KeyCode = 80
+	KeyG                KeyCode = 35
+	KeyGrave            KeyCode = 68
+	KeyH                KeyCode = 36
+	KeyHeadsethook      KeyCode = 79
+	KeyHome             KeyCode = 3
+	KeyI                KeyCode = 37
+	KeyJ                KeyCode = 38
+	KeyK                KeyCode = 39
+	KeyL                KeyCode = 40
+	KeyLeftBracket      KeyCode = 71
+	KeyM                KeyCode = 41
+	KeyMediaFastForward KeyCode = 90
+	KeyMediaNext        KeyCode = 87
+	KeyMediaPlayPause   KeyCode = 85
+	KeyMediaPrevious    KeyCode = 88
+	KeyMediaRewind      KeyCode = 89
+	KeyMediaStop        KeyCode = 86
+	KeyMenu             KeyCode = 82
+	KeyMinus            KeyCode = 69
+	KeyMute             KeyCode = 91
+	KeyN                KeyCode = 42
+	KeyNotification     KeyCode = 83
+	KeyNum              KeyCode = 78
+	KeyO                KeyCode = 43
+	KeyP                KeyCode = 44
+	KeyPeriod           KeyCode = 56
+	KeyPlus             KeyCode = 81
+	KeyPound            KeyCode = 18
+	KeyPower            KeyCode = 26
+	KeyQ                KeyCode = 45
+	KeyR                KeyCode = 46
+	KeyRightBracket     KeyCode = 72
+	KeyS                KeyCode = 47
+	KeySearch           KeyCode = 84
+	KeySemicolon        KeyCode = 74
+	KeyShiftLeft        KeyCode = 59
+	KeyShiftRight       KeyCode = 60
+	KeySlash            KeyCode = 76
+	KeySoftLeft         KeyCode = 1
+	KeySoftRight        KeyCode = 2
+	KeySpace            KeyCode = 62
+	KeyStar             KeyCode = 17
+	KeySym              KeyCode = 63
+	KeyT                KeyCode = 48
+	KeyTab              KeyCode = 61
+	KeyU                KeyCode = 49
+	KeyUnknown          KeyCode = 0
+	KeyV                KeyCode = 50
+	KeyVolumeDown       KeyCode = 25
+	KeyVolumeUp         KeyCode = 24
+	KeyW                KeyCode = 51
+	KeyX                KeyCode = 52
+	KeyY                KeyCode = 53
+	KeyZ                KeyCode = 54
+	KeyControlLeft      KeyCode = 129
+	KeyControlRight     KeyCode = 130
+	KeyEscape           KeyCode = 131
+	KeyEnd              KeyCode = 132
+	KeyInsert           KeyCode = 133
+	KeyPageUp           KeyCode = 92
+	KeyPageDown         KeyCode = 93
+	KeyPictsymbols      KeyCode = 94
+	KeySwitchCharset    KeyCode = 95
+	KeyButtonCircle     KeyCode = 255
+	KeyButtonA          KeyCode = 96
+	KeyButtonB          KeyCode = 97
+	KeyButtonC          KeyCode = 98
+	KeyButtonX          KeyCode = 99
+	KeyButtonY          KeyCode = 100
+	KeyButtonZ          KeyCode = 101
+	KeyButtonL1         KeyCode = 102
+	KeyButtonR1         KeyCode = 103
+	KeyButtonL2         KeyCode = 104
+	KeyButtonR2         KeyCode = 105
+	KeyButtonThumbl     KeyCode = 106
+	KeyButtonThumbr     KeyCode = 107
+	KeyButtonStart      KeyCode = 108
+	KeyButtonSelect     KeyCode = 109
+	KeyButtonMode       KeyCode = 110
+	KeyNumpad0          KeyCode = 144
+	KeyNumpad1          KeyCode = 145
+	KeyNumpad2          KeyCode = 146
+	KeyNumpad3          KeyCode = 147
+	KeyNumpad4          KeyCode = 148
+	KeyNumpad5          KeyCode = 149
+	KeyNumpad6          KeyCode = 150
+	KeyNumpad7          KeyCode = 151
+	KeyNumpad8          KeyCode = 152
+	KeyNumpad9          KeyCode = 153
+	KeyColon            KeyCode = 243
+	KeyF1               KeyCode = 244
+	KeyF2               KeyCode = 245
+	KeyF3               KeyCode = 246
+	KeyF4               KeyCode = 247
+	KeyF5               KeyCode = 248
+	KeyF6               KeyCode = 249
+	KeyF7               KeyCode = 250
+	KeyF8               KeyCode = 251
+	KeyF9               KeyCode = 252
+	KeyF10              KeyCode = 253
+	KeyF11              KeyCode = 254
+	KeyF12              KeyCode = 255
+)
 
-// // return a human readable representation of the keycode. The returned value can be used in
-// // {@link Input.Keys#valueOf(String)}
-// func ToString(keycode uint8) string {
-// 	switch keycode {
-// 	// META* variables should not be used with this method.
-// 	case UNKNOWN:
-// 		return "Unknown"
-// 	case SOFT_LEFT:
-// 		return "Soft Left"
-// 	case SOFT_RIGHT:
-// 		return "Soft Right"
-// 	case HOME:
-// 		return "Home"
-// 	case BACK:
-// 		return "Back"
-// 	case CALL:
-// 		return "Call"
-// 	case ENDCALL:
-// 		return "End Call"
-// 	case NUM_0:
-// 		return "0"
-// 	case NUM_1:
-// 		return "1"
-// 	case NUM_2:
-// 		return "2"
-// 	case NUM_3:
-// 		return "3"
-// 	case NUM_4:
-// 		return "4"
-// 	case NUM_5:
-// 		return "5"
-// 	case NUM_6:
-// 		return "6"
-// 	case NUM_7:
-// 		return "7"
-// 	case NUM_8:
-// 		return "8"
-// 	case NUM_9:
-// 		return "9"
-// 	case STAR:
-// 		return "*"
-// 	case POUND:
-// 		return "#"
-// 	case UP:
-// 		return "Up"
-// 	case DOWN:
-// 		return "Down"
-// 	case LEFT:
-// 		return "Left"
-// 	case RIGHT:
-// 		return "Right"
-// 	case CENTER:
-// 		return "Center"
-// 	case VOLUME_UP:
-// 		return "Volume Up"
-// 	case VOLUME_DOWN:
-// 		return "Volume Down"
-// 	case POWER:
-// 		return "Power"
-// 	case CAMERA:
-// 		return "Camera"
-// 	case CLEAR:
-// 		return "Clear"
-// 	case A:
-// 		return "A"
-// 	case B:
-// 		return "B"
-// 	case C:
-// 		return "C"
-// 	case D:
-// 		return "D"
-// 	case E:
-// 		return "E"
-// 	case F:
-// 		return "F"
-// 	case G:
-// 		return "G"
-// 	case H:
-// 		return "H"
-// 	case I:
-// 		return "I"
-// 	case J:
-// 		return "J"
-// 	case K:
-// 		return "K"
-// 	case L:
-// 		return "L"
-// 	case M:
-// 		return "M"
-// 	case N:
-// 		return "N"
-// 	case O:
-// 		return "O"
-// 	case P:
-// 		return "P"
-// 	case Q:
-// 		return "Q"
-// 	case R:
-// 		return "R"
-// 	case S:
-// 		return "S"
-// 	case T:
-// 		return "T"
-// 	case U:
-// 		return "U"
-// 	case V:
-// 		return "V"
-// 	case W:
-// 		return "W"
-// 	case X:
-// 		return "X"
-// 	case Y:
-// 		return "Y"
-// 	case Z:
-// 		return "Z"
-// 	case COMMA:
-// 		return ","
-// 	case PERIOD:
-// 		return "."
-// 	case ALT_LEFT:
-// 		return "L-Alt"
-// 	case ALT_RIGHT:
-// 		return "R-Alt"
-// 	case SHIFT_LEFT:
-// 		return "L-Shift"
-// 	case SHIFT_RIGHT:
-// 		return "R-Shift"
-// 	case TAB:
-// 		return "Tab"
-// 	case SPACE:
-// 		return "Space"
-// 	case SYM:
-// 		return "SYM"
-// 	case EXPLORER:
-// 		return "Explorer"
-// 	case ENVELOPE:
-// 		return "Envelope"
-// 	case ENTER:
-// 		return "Enter"
-// 	case DEL:
-// 		return "Delete" // also BACKSPACE
-// 	case GRAVE:
-// 		return "`"
-// 	case MINUS:
-// 		return "-"
-// 	case EQUALS:
-// 		return "="
-// 	case LEFT_BRACKET:
-// 		return "["
-// 	case RIGHT_BRACKET:
-// 		return "]"
-// 	case BACKSLASH:
-// 		return "\\"
-// 	case SEMICOLON:
-// 		return ";"
-// 	case APOSTROPHE:
-// 		return "'"
-// 	case SLASH:
-// 		return "/"
-// 	case AT:
-// 		return "@"
-// 	case NUM:
-// 		return "Num"
-// 	case HEADSETHOOK:
-// 		return "Headset Hook"
-// 	case FOCUS:
-// 		return "Focus"
-// 	case PLUS:
-// 		return "Plus"
-// 	case MENU:
-// 		return "Menu"
-// 	case NOTIFICATION:
-// 		return "Notification"
-// 	case SEARCH:
-// 		return "Search"
-// 	case MEDIA_PLAY_PAUSE:
-// 		return "Play/Pause"
-// 	case MEDIA_STOP:
-// 		return "Stop Media"
-// 	case MEDIA_NEXT:
-// 		return "Next Media"
-// 	case MEDIA_PREVIOUS:
-// 		return "Prev Media"
-// 	case MEDIA_REWIND:
-// 		return "Rewind"
-// 	case MEDIA_FAST_FORWARD:
-// 		return "Fast Forward"
-// 	case MUTE:
-// 		return "Mute"
-// 	case PAGE_UP:
-// 		return "Page Up"
-// 	case PAGE_DOWN:
-// 		return "Page Down"
-// 	case PICTSYMBOLS:
-// 		return "PICTSYMBOLS"
-// 	case SWITCH_CHARSET:
-// 		return "SWITCH_CHARSET"
-// 	case BUTTON_A:
-// 		return "A Button"
-// 	case BUTTON_B:
-// 		return "B Button"
-// 	case BUTTON_C:
-// 		return "C Button"
-// 	case BUTTON_X:
-// 		return "X Button"
-// 	case BUTTON_Y:
-// 		return "Y Button"
-// 	case BUTTON_Z:
-// 		return "Z Button"
-// 	case BUTTON_L1:
-// 		return "L1 Button"
-// 	case BUTTON_R1:
-// 		return "R1 Button"
-// 	case BUTTON_L2:
-// 		return "L2 Button"
-// 	case BUTTON_R2:
-// 		return "R2 Button"
-// 	case BUTTON_THUMBL:
-// 		return "Left Thumb"
-// 	case BUTTON_THUMBR:
-// 		return "Right Thumb"
-// 	case BUTTON_START:
-// 		return "Start"
-// 	case BUTTON_SELECT:
-// 		return "Select"
-// 	case BUTTON_MODE:
-// 		return "Button Mode"
-// 	case FORWARD_DEL:
-// 		return "Forward Delete"
-// 	case CONTROL_LEFT:
-// 		return "L-Ctrl"
-// 	case CONTROL_RIGHT:
-// 		return "R-Ctrl"
-// 	case ESCAPE:
-// 		return "Escape"
-// 	case END:
-// 		return "End"
-// 	case INSERT:
-// 		return "Insert"
-// 	case NUMPAD_0:
-// 		return "Numpad 0"
-// 	case NUMPAD_1:
-// 		return "Numpad 1"
-// 	case NUMPAD_2:
-// 		return "Numpad 2"
-// 	case NUMPAD_3:
-// 		return "Numpad 3"
-// 	case NUMPAD_4:
-// 		return "Numpad 4"
-// 	case NUMPAD_5:
-// 		return "Numpad 5"
-// 	case NUMPAD_6:
-// 		return "Numpad 6"
-// 	case NUMPAD_7:
-// 		return "Numpad 7"
-// 	case NUMPAD_8:
-// 		return "Numpad 8"
-// 	case NUMPAD_9:
-// 		return "Numpad 9"
-// 	case COLON:
-// 		return ":"
-// 	case F1:
-// 		return "F1"
-// 	case F2:
-// 		return "F2"
-// 	case F3:
-// 		return "F3"
-// 	case F4:
-// 		return "F4"
-// 	case F5:
-// 		return "F5"
-// 	case F6:
-// 		return "F6"
-// 	case F7:
-// 		return "F7"
-// 	case F8:
-// 		return "F8"
-// 	case F9:
-// 		return "F9"
-// 	case F10:
-// 		return "F10"
-// 	case F11:
-// 		return "F11"
-// 	case F12:
-// 		return "F12"
-// 		// BUTTON_CIRCLE unhandled, as it conflicts with the more likely to be pressed F12
-// 	default:
-// 		// key name not found
-// 		return ""
-// 	}
-// }
+// Returns a human readable representation of the keycode.
+func (k KeyCode) String() string {
+	switch k {
+	case KeyUnknown:
+		return "Unknown"
+	case KeySoftLeft:
+		return "Soft Left"
+	case KeySoftRight:
+		return "Soft Right"
+	case KeyHome:
+		return "Home"
+	case KeyBack:
+		return "Back"
+	case KeyCall:
+		return "Call"
+	case KeyEndcall:
+		return "End Call"
+	case KeyNum0:
+		return "0"
+	case KeyNum1:
+		return "1"
+	case KeyNum2:
+		return "2"
+	case KeyNum3:
+		return "3"
+	case KeyNum4:
+		return "4"
+	case KeyNum5:
+		return "5"
+	case KeyNum6:
+		return "6"
+	case KeyNum7:
+		return "7"
+	case KeyNum8:
+		return "8"
+	case KeyNum9:
+		return "9"
+	case KeyStar:
+		return "*"
+	case KeyPound:
+		return "#"
+	case KeyDpadUp:
+		return "Up"
+	case KeyDpadDown:
+		return "Down"
+	case KeyDpadLeft:
+		return "Left"
+	case KeyDpadRight:
+		return "Right"
+	case KeyDpadCenter:
+		return "Center"
+	case KeyVolumeUp:
+		return "Volume Up"
+	case KeyVolumeDown:
+		return "Volume Down"
+	case KeyPower:
+		return "Power"
+	case KeyCamera:
+		return "Camera"
+	case KeyClear:
+		return "Clear"
+	case KeyA:
+		return "A"
+	case KeyB:
+		return "B"
+	case KeyC:
+		return "C"
+	case KeyD:
+		return "D"
+	case KeyE:
+		return "E"
+	case KeyF:
+		return "F"
+	case KeyG:
+		return "G"
+	case KeyH:
+		return "H"
+	case KeyI:
+		return "I"
+	case KeyJ:
+		return "J"
+	case KeyK:
+		return "K"
+	case KeyL:
+		return "L"
+	case KeyM:
+		return "M"
+	case KeyN:
+		return "N"
+	case KeyO:
+		return "O"
+	case KeyP:
+		return "P"
+	case KeyQ:
+		return "Q"
+	case KeyR:
+		return "R"
+	case KeyS:
+		return "S"
+	case KeyT:
+		return "T"
+	case KeyU:
+		return "U"
+	case KeyV:
+		return "V"
+	case KeyW:
+		return "W"
+	case KeyX:
+		return "X"
+	case KeyY:
+		return "Y"
+	case KeyZ:
+		return "Z"
+	case KeyComma:
+		return ","
+	case KeyPeriod:
+		return "."
+	case KeyAltLeft:
+		return "L-Alt"
+	case KeyAltRight:
+		return "R-Alt"
+	case KeyShiftLeft:
+		return "L-Shift"
+	case KeyShiftRight:
+		return "R-Shift"
+	case KeyTab:
+		return "Tab"
+	case KeySpace:
+		return "Space"
+	case KeySym:
+		return "SYM"
+	case KeyExplorer:
+		return "Explorer"
+	case KeyEnvelope:
+		return "Envelope"
+	case KeyEnter:
+		return "Enter"
+	case KeyDel:
+		return "Delete"
+	case KeyGrave:
+		return "`"
+	case KeyMinus:
+		return "-"
+	case KeyEquals:
+		return "="
+	case KeyLeftBracket:
+		return "["
+	case KeyRightBracket:
+		return "]"
+	case KeyBackslash:
+		return "\\"
+	case KeySemicolon:
+		return ";"
+	case KeyApostrophe:
+		return "'"
+	case KeySlash:
+		return "/"
+	case KeyAt:
+		return "@"
+	case KeyNum:
+		return "Num"
+	case KeyHeadsethook:
+		return "Headset Hook"
+	case KeyFocus:
+		return "Focus"
+	case KeyPlus:
+		return "Plus"
+	case KeyMenu:
+		return "Menu"
+	case KeyNotification:
+		return "Notification"
+	case KeySearch:
+		return "Search"
+	case KeyMediaPlayPause:
+		return "Play/Pause"
+	case KeyMediaStop:
+		return "Stop Media"
+	case KeyMediaNext:
+		return "Next Media"
+	case KeyMediaPrevious:
+		return "Prev Media"
+	case KeyMediaRewind:
+		return "Rewind"
+	case KeyMediaFastForward:
+		return "Fast Forward"
+	case KeyMute:
+		return "Mute"
+	case KeyPageUp:
+		return "Page Up"
+	case KeyPageDown:
+		return "Page Down"
+	case KeyPictsymbols:
+		return "PICTSYMBOLS"
+	case KeySwitchCharset:
+		return "SWITCH_CHARSET"
+	case KeyButtonA:
+		return "A Button"
+	case KeyButtonB:
+		return "B Button"
+	case KeyButtonC:
+		return "C Button"
+	case KeyButtonX:
+		return "X Button"
+	case KeyButtonY:
+		return "Y Button"
+	case KeyButtonZ:
+		return "Z Button"
+	case KeyButtonL1:
+		return "L1 Button"
+	case KeyButtonR1:
+		return "R1 Button"
+	case KeyButtonL2:
+		return "L2 Button"
+	case KeyButtonR2:
+		return "R2 Button"
+	case KeyButtonThumbl:
+		return "Left Thumb"
+	case KeyButtonThumbr:
+		return "Right Thumb"
+	case KeyButtonStart:
+		return "Start"
+	case KeyButtonSelect:
+		return "Select"
+	case KeyButtonMode:
+		return "Button Mode"
+	case KeyForwardDel:
+		return "Forward Delete"
+	case KeyControlLeft:
+		return "L-Ctrl"
+	case KeyControlRight:
+		return "R-Ctrl"
+	case KeyEscape:
+		return "Escape"
+	case KeyEnd:
+		return "End"
+	case KeyInsert:
+		return "Insert"
+	case KeyNumpad0:
+		return "Numpad 0"
+	case KeyNumpad1:
+		return "Numpad 1"
+	case KeyNumpad2:
+		return "Numpad 2"
+	case KeyNumpad3:
+		return "Numpad 3"
+	case KeyNumpad4:
+		return "Numpad 4"
+	case KeyNumpad5:
+		return "Numpad 5"
+	case KeyNumpad6:
+		return "Numpad 6"
+	case KeyNumpad7:
+		return "Numpad 7"
+	case KeyNumpad8:
+		return "Numpad 8"
+	case KeyNumpad9:
+		return "Numpad 9"
+	case KeyColon:
+		return ":"
+	case KeyF1:
+		return "F1"
+	case KeyF2:
+		return "F2"
+	case KeyF3:
+		return "F3"
+	case KeyF4:
+		return "F4"
+	case KeyF5:
+		return "F5"
+	case KeyF6:
+		return "F6"
+	case KeyF7:
+		return "F7"
+	case KeyF8:
+		return "F8"
+	case KeyF9:
+		return "F9"
+	case KeyF10:
+		return "F10"
+	case KeyF11:
+		return "F11"
+	case KeyF12:
+		return "F12"
+	default:
+		// key name not found
+		return ""
+	}
+}
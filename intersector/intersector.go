@@ -429,7 +429,7 @@ func IntersectRayBounds(ray *Ray, box *BoundingBox, intersection *Vector3) bool
 		t = (box.Min.X - ray.Origin.X) / ray.Direction.X
 		if t >= 0 {
 			v2.SetV(ray.Direction).SclScalar(t).AddV(ray.Origin)
-			if v2.Y >= box.Min.X && v2.Y <= box.Max.Y && v2.Z >= box.Min.X && v2.Z <= box.Max.Z && (!hit || t < lowest) {
+			if v2.Y >= box.Min.Y && v2.Y <= box.Max.Y && v2.Z >= box.Min.Z && v2.Z <= box.Max.Z && (!hit || t < lowest) {
 				hit = true
 				lowest = t
 			}
@@ -440,18 +440,18 @@ func IntersectRayBounds(ray *Ray, box *BoundingBox, intersection *Vector3) bool
 		t = (box.Max.X - ray.Origin.X) / ray.Direction.X
 		if t >= 0 {
 			v2.SetV(ray.Direction).SclScalar(t).AddV(ray.Origin)
-			if v2.Y >= box.Min.X && v2.Y <= box.Max.Y && v2.Z >= box.Min.X && v2.Z <= box.Max.Z && (!hit || t < lowest) {
+			if v2.Y >= box.Min.Y && v2.Y <= box.Max.Y && v2.Z >= box.Min.Z && v2.Z <= box.Max.Z && (!hit || t < lowest) {
 				hit = true
 				lowest = t
 			}
 		}
 	}
 	// min y
-	if ray.Origin.Y <= box.Min.X && ray.Direction.Y > 0 {
-		t = (box.Min.X - ray.Origin.Y) / ray.Direction.Y
+	if ray.Origin.Y <= box.Min.Y && ray.Direction.Y > 0 {
+		t = (box.Min.Y - ray.Origin.Y) / ray.Direction.Y
 		if t >= 0 {
 			v2.SetV(ray.Direction).SclScalar(t).AddV(ray.Origin)
-			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Z >= box.Min.X && v2.Z <= box.Max.Z && (!hit || t < lowest) {
+			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Z >= box.Min.Z && v2.Z <= box.Max.Z && (!hit || t < lowest) {
 				hit = true
 				lowest = t
 			}
@@ -462,29 +462,29 @@ func IntersectRayBounds(ray *Ray, box *BoundingBox, intersection *Vector3) bool
 		t = (box.Max.Y - ray.Origin.Y) / ray.Direction.Y
 		if t >= 0 {
 			v2.SetV(ray.Direction).SclScalar(t).AddV(ray.Origin)
-			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Z >= box.Min.X && v2.Z <= box.Max.Z && (!hit || t < lowest) {
+			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Z >= box.Min.Z && v2.Z <= box.Max.Z && (!hit || t < lowest) {
 				hit = true
 				lowest = t
 			}
 		}
 	}
 	// min z
-	if ray.Origin.Z <= box.Min.X && ray.Direction.Z > 0 {
-		t = (box.Min.X - ray.Origin.Z) / ray.Direction.Z
+	if ray.Origin.Z <= box.Min.Z && ray.Direction.Z > 0 {
+		t = (box.Min.Z - ray.Origin.Z) / ray.Direction.Z
 		if t >= 0 {
 			v2.SetV(ray.Direction).SclScalar(t).AddV(ray.Origin)
-			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Y >= box.Min.X && v2.Y <= box.Max.Y && (!hit || t < lowest) {
+			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Y >= box.Min.Y && v2.Y <= box.Max.Y && (!hit || t < lowest) {
 				hit = true
 				lowest = t
 			}
 		}
 	}
-	// max y
+	// max z
 	if ray.Origin.Z >= box.Max.Z && ray.Direction.Z < 0 {
 		t = (box.Max.Z - ray.Origin.Z) / ray.Direction.Z
 		if t >= 0 {
 			v2.SetV(ray.Direction).SclScalar(t).AddV(ray.Origin)
-			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Y >= box.Min.X && v2.Y <= box.Max.Y && (!hit || t < lowest) {
+			if v2.X >= box.Min.X && v2.X <= box.Max.X && v2.Y >= box.Min.Y && v2.Y <= box.Max.Y && (!hit || t < lowest) {
 				hit = true
 				lowest = t
 			}
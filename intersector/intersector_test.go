@@ -112,3 +112,41 @@ package intersector
 //     }
 //   }
 // }
+
+import (
+	"testing"
+
+	. "github.com/pyros2097/spike/math/collision"
+	. "github.com/pyros2097/spike/math/vector"
+)
+
+func TestIntersectRayBounds(t *testing.T) {
+	// the bounds of the boxes differ on each axis, so that mixing up the axes of their bounds misses them
+	tests := []struct {
+		name                  string
+		min, max, origin, dir *Vector3
+		hit                   bool
+		x, y, z               float32
+	}{
+		{"min x", NewVector3(10, 0, 0), NewVector3(11, 1, 1), NewVector3(0, 0.5, 0.5), NewVector3(1, 0, 0), true, 10, 0.5, 0.5},
+		{"max x", NewVector3(10, 0, 0), NewVector3(11, 1, 1), NewVector3(20, 0.5, 0.5), NewVector3(-1, 0, 0), true, 11, 0.5, 0.5},
+		{"min y", NewVector3(-5, 10, -5), NewVector3(5, 11, 5), NewVector3(0, 0, 0), NewVector3(0, 1, 0), true, 0, 10, 0},
+		{"max y", NewVector3(10, -11, 0), NewVector3(11, -10, 1), NewVector3(10.5, 0, 0.5), NewVector3(0, -1, 0), true, 10.5, -10, 0.5},
+		{"min z", NewVector3(-5, -5, 10), NewVector3(5, 5, 11), NewVector3(0, 0, 0), NewVector3(0, 0, 1), true, 0, 0, 10},
+		{"max z", NewVector3(10, 0, -11), NewVector3(11, 1, -10), NewVector3(10.5, 0.5, 0), NewVector3(0, 0, -1), true, 10.5, 0.5, -10},
+		{"beside", NewVector3(10, 0, 0), NewVector3(11, 1, 1), NewVector3(0, 5, 0.5), NewVector3(1, 0, 0), false, 0, 0, 0},
+		{"away", NewVector3(10, 0, 0), NewVector3(11, 1, 1), NewVector3(0, 0.5, 0.5), NewVector3(-1, 0, 0), false, 0, 0, 0},
+		{"inside", NewVector3(0, 0, 0), NewVector3(2, 2, 2), NewVector3(1, 1, 1), NewVector3(1, 0, 0), true, 1, 1, 1},
+	}
+	for _, test := range tests {
+		// the corners are given the wrong way around, the box sorts them
+		box := NewBoundingBoxVector3(test.max, test.min)
+		intersection := NewVector3Empty()
+		hit := IntersectRayBounds(NewRay(test.origin, test.dir), box, intersection)
+		if hit != test.hit {
+			t.Errorf("%s: hit %v", test.name, hit)
+		} else if hit && (intersection.X != test.x || intersection.Y != test.y || intersection.Z != test.z) {
+			t.Errorf("%s: intersection %v %v %v", test.name, intersection.X, intersection.Y, intersection.Z)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"unicode"
+
+	"golang.org/x/mobile/event/key"
+)
+
+// Translates the physical keys reported by golang mobile (USB HID codes) to the framework's KeyCodes.
+var keyCodes = map[key.Code]KeyCode{
+	key.CodeA: KeyA, key.CodeB: KeyB, key.CodeC: KeyC, key.CodeD: KeyD, key.CodeE: KeyE, key.CodeF: KeyF,
+	key.CodeG: KeyG, key.CodeH: KeyH, key.CodeI: KeyI, key.CodeJ: KeyJ, key.CodeK: KeyK, key.CodeL: KeyL,
+	key.CodeM: KeyM, key.CodeN: KeyN, key.CodeO: KeyO, key.CodeP: KeyP, key.CodeQ: KeyQ, key.CodeR: KeyR,
+	key.CodeS: KeyS, key.CodeT: KeyT, key.CodeU: KeyU, key.CodeV: KeyV, key.CodeW: KeyW, key.CodeX: KeyX,
+	key.CodeY: KeyY, key.CodeZ: KeyZ,
+
+	key.Code0: KeyNum0, key.Code1: KeyNum1, key.Code2: KeyNum2, key.Code3: KeyNum3, key.Code4: KeyNum4,
+	key.Code5: KeyNum5, key.Code6: KeyNum6, key.Code7: KeyNum7, key.Code8: KeyNum8, key.Code9: KeyNum9,
+
+	key.CodeKeypad0: KeyNumpad0, key.CodeKeypad1: KeyNumpad1, key.CodeKeypad2: KeyNumpad2,
+	key.CodeKeypad3: KeyNumpad3, key.CodeKeypad4: KeyNumpad4, key.CodeKeypad5: KeyNumpad5,
+	key.CodeKeypad6: KeyNumpad6, key.CodeKeypad7: KeyNumpad7, key.CodeKeypad8: KeyNumpad8,
+	key.CodeKeypad9: KeyNumpad9, key.CodeKeypadEnter: KeyEnter,
+
+	key.CodeF1: KeyF1, key.CodeF2: KeyF2, key.CodeF3: KeyF3, key.CodeF4: KeyF4, key.CodeF5: KeyF5,
+	key.CodeF6: KeyF6, key.CodeF7: KeyF7, key.CodeF8: KeyF8, key.CodeF9: KeyF9, key.CodeF10: KeyF10,
+	key.CodeF11: KeyF11, key.CodeF12: KeyF12,
+
+	key.CodeUpArrow:    KeyDpadUp,
+	key.CodeDownArrow:  KeyDpadDown,
+	key.CodeLeftArrow:  KeyDpadLeft,
+	key.CodeRightArrow: KeyDpadRight,
+
+	key.CodeReturnEnter:        KeyEnter,
+	key.CodeEscape:             KeyEscape,
+	key.CodeSpacebar:           KeySpace,
+	key.CodeTab:                KeyTab,
+	key.CodeDeleteBackspace:    KeyDel,
+	key.CodeDeleteForward:      KeyForwardDel,
+	key.CodeInsert:             KeyInsert,
+	key.CodeHome:               KeyHome,
+	key.CodeEnd:                KeyEnd,
+	key.CodePageUp:             KeyPageUp,
+	key.CodePageDown:           KeyPageDown,
+	key.CodeLeftShift:          KeyShiftLeft,
+	key.CodeRightShift:         KeyShiftRight,
+	key.CodeLeftControl:        KeyControlLeft,
+	key.CodeRightControl:       KeyControlRight,
+	key.CodeLeftAlt:            KeyAltLeft,
+	key.CodeRightAlt:           KeyAltRight,
+	key.CodeComma:              KeyComma,
+	key.CodeFullStop:           KeyPeriod,
+	key.CodeSlash:              KeySlash,
+	key.CodeBackslash:          KeyBackslash,
+	key.CodeSemicolon:          KeySemicolon,
+	key.CodeApostrophe:         KeyApostrophe,
+	key.CodeGraveAccent:        KeyGrave,
+	key.CodeHyphenMinus:        KeyMinus,
+	key.CodeEqualSign:          KeyEquals,
+	key.CodeLeftSquareBracket:  KeyLeftBracket,
+	key.CodeRightSquareBracket: KeyRightBracket,
+	key.CodeKeypadPlusSign:     KeyPlus,
+	key.CodeKeypadHyphenMinus:  KeyMinus,
+	key.CodeKeypadAsterisk:     KeyStar,
+	key.CodeKeypadSlash:        KeySlash,
+	key.CodeKeypadFullStop:     KeyPeriod,
+	key.CodeMute:               KeyMute,
+	key.CodeVolumeUp:           KeyVolumeUp,
+	key.CodeVolumeDown:         KeyVolumeDown,
+}
+
+// Returns the KeyCode for a golang mobile key code or KeyUnknown if there is none.
+func toKeyCode(code key.Code) KeyCode {
+	if keycode, ok := keyCodes[code]; ok {
+		return keycode
+	}
+	return KeyUnknown
+}
+
+// Sends the key events for a golang mobile key event. Key repeats are reported as new key downs. Keys that produce a
+// character also send a KeyTyped event. Events without a direction only type their character, as no key up follows them.
+func doKeyEvent(e key.Event) {
+	keycode := toKeyCode(e.Code)
+	switch e.Direction {
+	case key.DirPress:
+		feedInput(InputEvent{Type: KeyDown, KeyCode: keycode})
+		feedKeyTyped(e, keycode)
+	case key.DirNone:
+		feedKeyTyped(e, keycode)
+	case key.DirRelease:
+		feedInput(InputEvent{Type: KeyUp, KeyCode: keycode})
+	}
+}
+
+func feedKeyTyped(e key.Event, keycode KeyCode) {
+	if e.Rune > 0 && unicode.IsPrint(e.Rune) && e.Modifiers&(key.ModControl|key.ModMeta) == 0 {
+		feedInput(InputEvent{Type: KeyTyped, KeyCode: keycode, Character: e.Rune})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	mathutils "github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"github.com/pyros2097/spike/utils"
+)
+
+// The style of a label
+type LabelStyle struct {
+	Font *g2d.BitmapFont
+
+	// The color of the text, nil for white
+	FontColor *Color
+
+	// Drawn behind the text, may be nil
+	Background ui.Drawable
+}
+
+// A Label draws text with a bitmap font. Its preferred size is the size of the text, which is broken at newlines. A label
+// which wraps breaks the text into lines which fit its width instead, its preferred width is 0 so that it is sized by its
+// container.
+type Label struct {
+	Actor
+	widget
+
+	style                  *LabelStyle
+	text                   string
+	glyphs                 g2d.GlyphLayout
+	labelAlign, lineAlign  utils.Alignment
+	wrap                   bool
+	ellipsis               string
+	fontScaleX, fontScaleY float32
+
+	prefSizeInvalid       bool
+	prefWidth, prefHeight float32
+
+	// the width the text was wrapped to for the preferred size
+	wrapWidth float32
+
+	// the top left corner of the text
+	textX, textY float32
+}
+
+// Creates a label with the text and style
+func NewLabel(text string, style *LabelStyle) *Label {
+	self := &Label{text: text, style: style, fontScaleX: 1, fontScaleY: 1, prefSizeInvalid: true}
+	self.labelAlign, self.lineAlign = utils.AlignmentLeft, utils.AlignmentLeft
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.widget.init(&self.Actor, self)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+func (self *Label) SetText(text string) {
+	if text == self.text {
+		return
+	}
+	self.text = text
+	self.InvalidateHierarchy()
+}
+
+func (self *Label) GetText() string {
+	return self.text
+}
+
+func (self *Label) SetStyle(style *LabelStyle) {
+	self.style = style
+	self.InvalidateHierarchy()
+}
+
+func (self *Label) GetStyle() *LabelStyle {
+	return self.style
+}
+
+// If set the text is broken into lines which fit the width of the label. Default is false.
+func (self *Label) SetWrap(wrap bool) {
+	self.wrap = wrap
+	self.InvalidateHierarchy()
+}
+
+func (self *Label) GetWrap() bool {
+	return self.wrap
+}
+
+// Sets the text the lines which do not fit the width of the label end with, they are cut to fit it. It is empty by
+// default, which does not cut the lines. The minimum width of the label is then the width of the ellipsis.
+func (self *Label) SetEllipsis(ellipsis string) {
+	self.ellipsis = ellipsis
+	self.InvalidateHierarchy()
+}
+
+// Sets where the text is in the label when the label is larger than it and how its lines are aligned to each other.
+// Default is left for both.
+func (self *Label) SetAlignment(labelAlign, lineAlign utils.Alignment) {
+	self.labelAlign, self.lineAlign = labelAlign, lineAlign
+	self.Invalidate()
+}
+
+// Sets the scale of the font the text is drawn with
+func (self *Label) SetFontScale(scaleX, scaleY float32) {
+	self.fontScaleX, self.fontScaleY = scaleX, scaleY
+	self.InvalidateHierarchy()
+}
+
+// Returns the lines of the text as they were laid out to fit the label
+func (self *Label) GetGlyphLayout() *g2d.GlyphLayout {
+	return &self.glyphs
+}
+
+func (self *Label) Invalidate() {
+	self.prefSizeInvalid = true
+	self.widget.Invalidate()
+}
+
+// Lays the text out with the font scaled by the font scale of the label
+func (self *Label) layoutText(width float32, wrap bool, ellipsis string) {
+	font := self.style.Font
+	scaleX, scaleY := font.ScaleX, font.ScaleY
+	font.SetScale(scaleX*self.fontScaleX, scaleY*self.fontScaleY)
+	self.glyphs.SetText(font, self.text, width, self.lineAlign, wrap, ellipsis)
+	font.SetScale(scaleX, scaleY)
+}
+
+func (self *Label) computePrefSize() {
+	if !self.prefSizeInvalid {
+		return
+	}
+	self.prefSizeInvalid = false
+	if self.wrap {
+		// the height of the wrapped text depends on the width
+		self.wrapWidth = self.W - self.padLeft() - self.padRight()
+		self.layoutText(self.wrapWidth, true, "")
+		self.prefWidth = 0
+	} else {
+		self.layoutText(0, false, "")
+		self.prefWidth = self.glyphs.Width
+	}
+	self.prefWidth += self.padLeft() + self.padRight()
+	self.prefHeight = self.glyphs.Height + self.padTop() + self.padBottom()
+	if self.style.Background != nil {
+		self.prefWidth = mathutils.MaxFloat32(self.prefWidth, self.style.Background.GetMinWidth())
+		self.prefHeight = mathutils.MaxFloat32(self.prefHeight, self.style.Background.GetMinHeight())
+	}
+}
+
+func (self *Label) padLeft() float32 {
+	if self.style.Background == nil {
+		return 0
+	}
+	return self.style.Background.GetPadLeft()
+}
+
+func (self *Label) padRight() float32 {
+	if self.style.Background == nil {
+		return 0
+	}
+	return self.style.Background.GetPadRight()
+}
+
+func (self *Label) padTop() float32 {
+	if self.style.Background == nil {
+		return 0
+	}
+	return self.style.Background.GetPadTop()
+}
+
+func (self *Label) padBottom() float32 {
+	if self.style.Background == nil {
+		return 0
+	}
+	return self.style.Background.GetPadBottom()
+}
+
+// Lays the text out in the label and aligns it
+func (self *Label) Layout() {
+	left, bottom := self.padLeft(), self.padBottom()
+	width := self.W - left - self.padRight()
+	height := self.H - bottom - self.padTop()
+	if self.wrap && width != self.wrapWidth {
+		// the preferred height changes with the width, the parent lays the label out again with it
+		self.prefSizeInvalid = true
+		if parent := self.Parent; parent != nil && parent.Widget != nil {
+			parent.Widget.InvalidateHierarchy()
+		}
+	}
+	self.layoutText(width, self.wrap, self.ellipsis)
+	x, y := left, bottom
+	if self.labelAlign&utils.AlignmentRight != 0 {
+		x += width - self.glyphs.Width
+	} else if self.labelAlign&utils.AlignmentLeft == 0 {
+		x += (width - self.glyphs.Width) / 2
+	}
+	if self.labelAlign&utils.AlignmentTop != 0 {
+		y += height
+	} else if self.labelAlign&utils.AlignmentBottom != 0 {
+		y += self.glyphs.Height
+	} else {
+		y += (height + self.glyphs.Height) / 2
+	}
+	self.textX, self.textY = x, y
+}
+
+func (self *Label) GetMinWidth() float32 {
+	if self.ellipsis != "" {
+		font := self.style.Font
+		return font.TextWidth([]rune(self.ellipsis))*self.fontScaleX + self.padLeft() + self.padRight()
+	}
+	return self.GetPrefWidth()
+}
+
+func (self *Label) GetMinHeight() float32 {
+	return self.GetPrefHeight()
+}
+
+func (self *Label) GetPrefWidth() float32 {
+	self.computePrefSize()
+	return self.prefWidth
+}
+
+func (self *Label) GetPrefHeight() float32 {
+	self.computePrefSize()
+	return self.prefHeight
+}
+
+func (self *Label) GetMaxWidth() float32 {
+	return 0
+}
+
+func (self *Label) GetMaxHeight() float32 {
+	return 0
+}
+
+func (self *Label) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	setBatchColor(batch, &self.Actor, nil, parentAlpha)
+	drawDrawable(batch, &self.Actor, self.style.Background, 0, 0, self.W, self.H)
+	setBatchColor(batch, &self.Actor, self.style.FontColor, parentAlpha)
+	font := self.style.Font
+	scaleX, scaleY := font.ScaleX, font.ScaleY
+	font.SetScale(scaleX*self.fontScaleX, scaleY*self.fontScaleY)
+	font.DrawLayout(batch, &self.glyphs, self.textX, self.textY, self.computeLocalTransform())
+	font.SetScale(scaleX, scaleY)
+}
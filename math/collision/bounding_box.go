@@ -129,40 +129,22 @@ func (self *BoundingBox) SetB(bounds *BoundingBox) *BoundingBox {
 // param minimum The minimum vector
 // param maximum The maximum vector
 // return This bounding box for chaining.
-func (self *BoundingBox) Set(min, max *Vector3) *BoundingBox {
-	var mx, my, mz, mmx, mmy, mmz float32
-	switch {
-	case min.X < max.X:
-		mx = min.X
-	case min.X > max.X:
-		mx = max.X
-	case min.Y < max.Y:
-		my = min.Y
-	case min.Y > max.Y:
-		my = max.Y
-	case min.Z < max.Z:
-		mz = min.Z
-	case min.Z > max.Z:
-		mz = max.Z
+func (self *BoundingBox) Set(minimum, maximum *Vector3) *BoundingBox {
+	x0, y0, z0 := minimum.X, minimum.Y, minimum.Z
+	x1, y1, z1 := maximum.X, maximum.Y, maximum.Z
+	if x0 > x1 {
+		x0, x1 = x1, x0
 	}
-	self.Min.Set(mx, my, mz)
-	switch {
-	case min.X < max.X:
-		mmx = max.X
-	case min.X > max.X:
-		mmx = min.X
-	case min.Y < max.Y:
-		mmy = max.Y
-	case min.Y > max.Y:
-		mmy = min.Y
-	case min.Z < max.Z:
-		mmz = max.Z
-	case min.Z > max.Z:
-		mmz = min.Z
+	if y0 > y1 {
+		y0, y1 = y1, y0
 	}
-	self.Max.Set(mmx, mmy, mmz)
-	self.Cnt.SetV(min).AddV(max).SclScalar(0.5)
-	self.Dim.SetV(max).SubV(min)
+	if z0 > z1 {
+		z0, z1 = z1, z0
+	}
+	self.Min.Set(x0, y0, z0)
+	self.Max.Set(x1, y1, z1)
+	self.Cnt.SetV(self.Min).AddV(self.Max).SclScalar(0.5)
+	self.Dim.SetV(self.Max).SubV(self.Min)
 	return self
 }
 
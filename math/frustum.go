@@ -44,7 +44,7 @@ func NewFrustumEmpty() *Frustum {
 	self := &Frustum{}
 	self.PlanePoints = []*Vector3{
 		NewVector3Empty(), NewVector3Empty(), NewVector3Empty(), NewVector3Empty(),
-		NewVector3Empty(), NewVector3Empty(), NewVector3Empty(),
+		NewVector3Empty(), NewVector3Empty(), NewVector3Empty(), NewVector3Empty(),
 	}
 	for i := 0; i < 6; i++ {
 		self.Planes[i] = NewPlane(NewVector3Empty(), 0)
@@ -56,15 +56,14 @@ func NewFrustumEmpty() *Frustum {
 // OrthographicCamera or PerspectiveCamera
 // inverseProjectionView the combined projection and view matrices.
 func (self *Frustum) Update(inverseProjectionView *Matrix4) {
-	// System.arraycopy(clipSpacePlanePointsArray, 0, planePointsArray, 0, clipSpacePlanePointsArray.length);
-	// Matrix4.prj(inverseProjectionView.val, planePointsArray, 0, 8, 3);
 	j := 0
 	for i := 0; i < 8; i++ {
-		self.PlanePoints[i].X = self.PlanePointsArray[j]
+		point := self.PlanePoints[i].SetV(clipSpacePlanePoints[i]).Prj(inverseProjectionView)
+		self.PlanePointsArray[j] = point.X
 		j++
-		self.PlanePoints[i].Y = self.PlanePointsArray[j]
+		self.PlanePointsArray[j] = point.Y
 		j++
-		self.PlanePoints[i].Z = self.PlanePointsArray[j]
+		self.PlanePointsArray[j] = point.Z
 		j++
 	}
 	self.Planes[0].SetP3(self.PlanePoints[1], self.PlanePoints[0], self.PlanePoints[2])
@@ -238,6 +237,40 @@ func (self *Frustum) BoundsInFrustum(x, y, z, halfWidth, halfHeight, halfDepth f
 	return true
 }
 
+// Returns whether the given bounding box is in the frustum not checking whether it is behind the near and far clipping plane.
+// return Whether the bounding box is in the frustum
+func (self *Frustum) BoundsInFrustumWithoutNearFar(x, y, z, halfWidth, halfHeight, halfDepth float32) bool {
+	for i := 2; i < len(self.Planes); i++ {
+		if self.Planes[i].TestPoint(x+halfWidth, y+halfHeight, z+halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x+halfWidth, y+halfHeight, z-halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x+halfWidth, y-halfHeight, z+halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x+halfWidth, y-halfHeight, z-halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x-halfWidth, y+halfHeight, z+halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x-halfWidth, y+halfHeight, z-halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x-halfWidth, y-halfHeight, z+halfDepth) != PlaneBack {
+			continue
+		}
+		if self.Planes[i].TestPoint(x-halfWidth, y-halfHeight, z-halfDepth) != PlaneBack {
+			continue
+		}
+		return false
+	}
+
+	return true
+}
+
 // COMMENTED
 // Calculates the pick ray for the given window coordinates. Assumes the window coordinate system has it's y downwards. The
 // returned Ray is a member of this instance so don't reuse it outside this class.
@@ -51,14 +51,12 @@ func (self *Polygon) GetTransformedVertices() []float32 {
 		return self.worldVertices
 	}
 	self.dirty = false
-	var localVertices []float32
-	copy(localVertices, self.localVertices)
+	localVertices := self.localVertices
 	if self.worldVertices == nil || len(self.worldVertices) != len(localVertices) {
 		self.worldVertices = make([]float32, len(localVertices))
 	}
 
-	var worldVertices []float32
-	copy(worldVertices, self.worldVertices)
+	worldVertices := self.worldVertices
 	positionX := self.X
 	positionY := self.Y
 	originX := self.OriginX
@@ -46,14 +46,12 @@ func (self *Polyline) GetTransformedVertices() []float32 {
 		return self.worldVertices
 	}
 	self.dirty = false
-	var localVertices []float32
-	copy(localVertices, self.localVertices)
+	localVertices := self.localVertices
 	if self.worldVertices == nil || len(self.worldVertices) != len(self.localVertices) {
 		self.worldVertices = make([]float32, len(self.localVertices))
 	}
 
-	var worldVertices []float32
-	copy(worldVertices, self.worldVertices)
+	worldVertices := self.worldVertices
 	positionX := self.x
 	positionY := self.y
 	originX := self.originX
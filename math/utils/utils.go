@@ -8,6 +8,7 @@ package utils
 import (
 	"math"
 	"math/rand"
+	"time"
 )
 
 var (
@@ -43,39 +44,35 @@ var (
 	BIG_ENOUGH_ROUND float32 = BIG_ENOUGH_INT + 0.5
 )
 
-// var SinTable [SIN_COUNT]float32
+var SinTable = make([]float32, SIN_COUNT)
 
-// func init() {
-// 	for i := 0; i < SIN_COUNT; i++ {
-// 		SinTable[i] = float32(math.Sin(float64((i + 0.5) / SIN_COUNT * RadFull)))
-// 	}
-// 	for i := 0; i < 360; i += 90 {
-// 		SinTable[int(int(i*DegToIndex)&SIN_MASK)] = float32(math.Sin(float64(i * DegreesToRadians)))
-// 	}
-// }
+func init() {
+	for i := 0; i < SIN_COUNT; i++ {
+		SinTable[i] = float32(math.Sin(float64((float32(i) + 0.5) / float32(SIN_COUNT) * RadFull)))
+	}
+	for i := 0; i < 360; i += 90 {
+		SinTable[int(float32(i)*DegToIndex)&SIN_MASK] = float32(math.Sin(float64(float32(i) * DegreesToRadians)))
+	}
+}
 
 // Returns the sine in radians from a lookup table.
 func Sin(radians float32) float32 {
-	return 0
-	// return SinTable[int((radians*RadToIndex)&SIN_MASK)]
+	return SinTable[int(radians*RadToIndex)&SIN_MASK]
 }
 
 // Returns the cosine in radians from a lookup table.
 func Cos(radians float32) float32 {
-	return 0
-	// return SinTable[int(((radians+PI/2)*RadToIndex)&SIN_MASK)]
+	return SinTable[int((radians+PI/2)*RadToIndex)&SIN_MASK]
 }
 
 // Returns the sine in radians from a lookup table.
 func SinDeg(degrees float32) float32 {
-	return 0
-	// return SinTable[int((degrees*DegToIndex)&SIN_MASK)]
+	return SinTable[int(degrees*DegToIndex)&SIN_MASK]
 }
 
 // Returns the cosine in radians from a lookup table.
 func CosDeg(degrees float32) float32 {
-	return 0
-	// return SinTable[int(((degrees+90)*DegToIndex)&SIN_MASK)]
+	return SinTable[int((degrees+90)*DegToIndex)&SIN_MASK]
 }
 
 /** Returns atan2 in radians, faster but less accurate than math.atan2. Average error of 0.00231 radians (0.1323 degrees),
@@ -177,6 +174,27 @@ func ClampFloat64(value float64, min float64, max float64) float64 {
 	return value
 }
 
+func MinFloat32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func MaxFloat32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func AbsFloat32(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
 // Linearly interpolates between fromValue to toValue on progress position.
 func Lerp(fromValue, toValue, progress float32) float32 {
 	return fromValue + (toValue-fromValue)*progress
@@ -277,18 +295,26 @@ func Log2(value float32) float32 {
 
 // TODO int64 and float
 
+// The random number generator used by all the Random functions
+var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// Reseeds the random number generator so that the Random functions return the same sequence of numbers again.
+func SetSeed(seed int64) {
+	random.Seed(seed)
+}
+
 /** Returns a random number between 0 (inclusive) and the specified value (inclusive). */
 func Random(n int) int {
-	return rand.Intn(n)
+	return random.Intn(n)
 }
 
 // Returns a random number between start (inclusive) and end (inclusive).
 func RandomRange(start, end int) int {
-	return rand.Intn(end - start + 1)
+	return random.Intn(end - start + 1)
 }
 
 func RandomFloat() float32 {
-	return rand.Float32()
+	return random.Float32()
 }
 
 //   /** Returns a random boolean value. */
@@ -311,7 +337,7 @@ func RandomFloat() float32 {
  * <p>
  * This is an optimized version of {@link #randomTriangular(float, float, float) randomTriangular(-1, 1, 0)} */
 func RandomTriangular() float32 {
-	return rand.Float32() - rand.Float32()
+	return random.Float32() - random.Float32()
 }
 
 /* Returns a triangularly distributed random number between {@code -max} (exclusive) and {@code max} (exclusive), where values
@@ -320,7 +346,7 @@ func RandomTriangular() float32 {
  * This is an optimized version of {@link #randomTriangular(float, float, float) randomTriangular(-max, max, 0)}
  * @param max the upper limit */
 func RandomTriangularMax(max float32) float32 {
-	return (rand.Float32() - rand.Float32()) * max
+	return (random.Float32() - random.Float32()) * max
 }
 
 /** Returns a triangularly distributed random number between {@code min} (inclusive) and {@code max} (exclusive), where the
@@ -339,7 +365,7 @@ func RandomTriangularMinMax(min, max float32) float32 {
  * @param max the upper limit
  * @param mode the point around which the values are more likely */
 func RandomTriangularMinMaxMode(min, max, mode float32) float32 {
-	u := rand.Float32()
+	u := random.Float32()
 	d := max - min
 	if u <= (mode-min)/d {
 		return min + float32(math.Sqrt(float64(u*d*(mode-min))))
@@ -54,6 +54,23 @@ func (self *Affine2) Set(other *Affine2) *Affine2 {
 	return self
 }
 
+// Sets the values of the first two rows of this matrix, the last row is always (0, 0, 1).
+// return This matrix for the purposes of chaining.
+func (self *Affine2) SetValues(m00, m01, m02, m10, m11, m12 float32) *Affine2 {
+	self.m00 = m00
+	self.m01 = m01
+	self.m02 = m02
+	self.m10 = m10
+	self.m11 = m11
+	self.m12 = m12
+	return self
+}
+
+// Returns the values of the first two rows of this matrix in the order m00, m01, m02, m10, m11, m12.
+func (self *Affine2) GetValues() [6]float32 {
+	return [6]float32{self.m00, self.m01, self.m02, self.m10, self.m11, self.m12}
+}
+
 // Copies the values from the provided matrix to this matrix.
 // param matrix The matrix to copy, assumed to be an affine transformation.
 // return This matrix for the purposes of chaining.
@@ -321,7 +321,7 @@ func (self *Matrix4) GetValues() [16]float32 {
 //
 // param matrix The other matrix to multiply by.
 func (self *Matrix4) MulM4(matrix *Matrix4) *Matrix4 {
-	MulM4(self.val, matrix.val)
+	MulM4(&self.val, matrix.val)
 	return self
 }
 
@@ -332,7 +332,7 @@ func (self *Matrix4) MulM4(matrix *Matrix4) *Matrix4 {
 // param matrix The other matrix to multiply by.
 func (self *Matrix4) MulLeft(matrix *Matrix4) *Matrix4 {
 	tmpMat.SetM4(matrix)
-	MulM4(tmpMat.val, self.val)
+	MulM4(&tmpMat.val, self.val)
 	return self.SetM4(tmpMat)
 }
 
@@ -1091,7 +1091,7 @@ func (self *Matrix4) ToNormalMatrix() *Matrix4 {
 // param matb the second matrix.
 // public static native void mul (float[] mata, float[] matb) /*-{ }; /*
 // matrix4_mul(mata, matb);
-func MulM4(mata [16]float32, matb [16]float32) {
+func MulM4(mata *[16]float32, matb [16]float32) {
 	var tmp [16]float32
 	tmp[M4_00] = mata[M4_00]*matb[M4_00] + mata[M4_01]*matb[M4_10] + mata[M4_02]*matb[M4_20] + mata[M4_03]*matb[M4_30]
 	tmp[M4_01] = mata[M4_00]*matb[M4_01] + mata[M4_01]*matb[M4_11] + mata[M4_02]*matb[M4_21] + mata[M4_03]*matb[M4_31]
@@ -1390,7 +1390,7 @@ func (self *Matrix4) Translate(x, y, z float32) *Matrix4 {
 	tmp[M4_32] = 0
 	tmp[M4_33] = 1
 
-	MulM4(self.val, tmp)
+	MulM4(&self.val, tmp)
 	return self
 }
 
@@ -1454,7 +1454,7 @@ func (self *Matrix4) RotateRadAxis(axisX, axisY, axisZ, radians float32) *Matrix
 // param rotation
 func (self *Matrix4) RotateQ(rotation *Quaternion) *Matrix4 {
 	rotation.ToMatrix(tmp)
-	MulM4(self.val, tmp)
+	MulM4(&self.val, tmp)
 	return self
 }
 
@@ -1488,7 +1488,7 @@ func (self *Matrix4) Scale(scaleX, scaleY, scaleZ float32) *Matrix4 {
 	tmp[M4_32] = 0
 	tmp[M4_33] = 1
 
-	MulM4(self.val, tmp)
+	MulM4(&self.val, tmp)
 	return self
 }
 
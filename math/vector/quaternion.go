@@ -193,7 +193,7 @@ func (self *Quaternion) Len2() float32 {
 // Normalizes this quaternion to unit length
 func (self *Quaternion) Nor() *Quaternion {
 	length := self.Len2()
-	if length != 0 && utils.IsEqual(length, 1) {
+	if length != 0 && !utils.IsEqual(length, 1) {
 		length = float32(math.Sqrt(float64(length)))
 		self.w /= length
 		self.x /= length
@@ -276,10 +276,10 @@ func (self *Quaternion) MulLeftQ(other *Quaternion) *Quaternion {
 // param z the z component of the other quaternion to multiply with
 // param w the w component of the other quaternion to multiply with
 func (self *Quaternion) MulLeft(x, y, z, w float32) *Quaternion {
-	newX := w*self.x + x*self.w + y*self.z - z*y
-	newY := w*self.y + y*self.w + z*self.x - x*z
-	newZ := w*self.z + z*self.w + x*self.y - y*x
-	newW := w*self.w - x*self.x - y*self.y - z*z
+	newX := w*self.x + x*self.w + y*self.z - z*self.y
+	newY := w*self.y + y*self.w + z*self.x - x*self.z
+	newZ := w*self.z + z*self.w + x*self.y - y*self.x
+	newW := w*self.w - x*self.x - y*self.y - z*self.z
 	self.x = newX
 	self.y = newY
 	self.z = newZ
@@ -393,9 +393,9 @@ func (self *Quaternion) SetFromAxisRad(x, y, z, radians float32) *Quaternion {
 	d = 1 / d
 	var l_ang float32
 	if radians < 0 {
-		l_ang = utils.PI2 // - (-radians % utils.PI2) // TODO FIX THIS
+		l_ang = utils.PI2 - float32(math.Mod(float64(-radians), float64(utils.PI2)))
 	} else {
-		l_ang = radians // % utils.PI2 // TODO FIX THIS
+		l_ang = float32(math.Mod(float64(radians), float64(utils.PI2)))
 	}
 	l_sin := float32(math.Sin(float64(l_ang / 2)))
 	l_cos := float32(math.Cos(float64(l_ang / 2)))
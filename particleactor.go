@@ -0,0 +1,109 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/g2d/particle"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// A ParticleEffectActor runs a particle effect which is emitted from the origin of the actor. The particles are tinted
+// with the alpha of the actor's color.
+//
+//	explosion := spike.NewParticleEffectActor(spike.Particle("explosion"), true)
+//	explosion.OnComplete = func(self *spike.ParticleEffectActor) {
+//		scene.RemoveActor(&self.Actor)
+//	}
+//	scene.AddActor(&explosion.Actor)
+//
+// The particles may be drawn outside of the actor's rectangle, its size should cover them so that the effect is not culled
+// while it is in view.
+type ParticleEffectActor struct {
+	Actor
+
+	Effect *particle.Effect
+
+	// If set the particles are emitted in stage coordinates and stay where they are when the actor moves, else they are in
+	// the coordinates of the actor and move, rotate and scale with it
+	WorldSpace bool
+
+	// Called once when the effect is complete
+	OnComplete func(self *ParticleEffectActor)
+
+	completed bool
+	transform *vector.Affine2
+	position  *vector.Vector2
+}
+
+// Creates an actor running the effect, which is started
+func NewParticleEffectActor(effect *particle.Effect, worldSpace bool) *ParticleEffectActor {
+	self := &ParticleEffectActor{Effect: effect, WorldSpace: worldSpace, transform: vector.NewAffine2Empty(),
+		position: vector.NewVector2Empty()}
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update(delta)
+	}
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.Start()
+	return self
+}
+
+// Starts the effect again, the particles alive are removed
+func (self *ParticleEffectActor) Start() {
+	self.completed = false
+	self.Effect.SetPosition(self.emitterPosition())
+	self.Effect.Reset()
+}
+
+// Lets the continuous emitters of the effect complete
+func (self *ParticleEffectActor) AllowCompletion() {
+	self.Effect.AllowCompletion()
+}
+
+func (self *ParticleEffectActor) IsComplete() bool {
+	return self.Effect.IsComplete()
+}
+
+// Moves the effect to the actor and advances it by the time
+func (self *ParticleEffectActor) Update(delta float32) {
+	self.Effect.SetPosition(self.emitterPosition())
+	self.Effect.Update(delta)
+	if !self.completed && self.Effect.IsComplete() {
+		self.completed = true
+		if self.OnComplete != nil {
+			self.OnComplete(self)
+		}
+	}
+}
+
+// Returns the origin of the actor in the coordinates the particles are emitted in
+func (self *ParticleEffectActor) emitterPosition() (x, y float32) {
+	if !self.WorldSpace {
+		return self.OX, self.OY
+	}
+	self.LocalToStageCoordinates(self.position.Set(self.OX, self.OY))
+	return self.position.X, self.position.Y
+}
+
+func (self *ParticleEffectActor) draw(batch g2d.Batch, parentAlpha float32) {
+	alpha := parentAlpha
+	if self.Color != nil {
+		alpha *= self.Color.A
+	}
+	self.computeTransform()
+	if !self.WorldSpace {
+		self.Effect.Draw(batch, self.localTransform, alpha)
+		return
+	}
+	// the batch draws in the coordinates of the parent, the particles are taken back there from stage coordinates
+	self.transform.Idt()
+	if self.Parent != nil && self.Parent.GetWorldTransform().Det() != 0 {
+		self.transform.Set(self.Parent.GetWorldTransform()).Inv()
+	}
+	self.Effect.Draw(batch, self.transform, alpha)
+}
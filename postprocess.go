@@ -0,0 +1,478 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/gl"
+)
+
+// The post processing of a scene draws the scene into a frame buffer and then draws it to the screen through a chain of
+// shader passes, like a blur, a bloom, a vignette, color grading or CRT scanlines. Each pass draws the output of the pass
+// before it into a frame buffer, the two buffers are used in turn, and the last pass draws to the screen bounds of the
+// viewport. The background and the actors of the scene are post processed, the debug lines and the HUD are not.
+//
+// A pass is a fragment shader drawn over a quad covering its target. It samples u_texture, the output of the pass before
+// or the scene for the first pass, and u_scene, the scene as it was drawn, at v_texCoords. u_resolution is the size of its
+// target in pixels. Its own uniforms are set with SetUniform, also while it is shown.
+//
+//	post := spike.NewPostProcessor(spike.NewBloomPasses(0.7, 1.2, 2)...)
+//	vignette := spike.NewVignettePass(0.75, 0.45)
+//	post.AddPass(vignette, spike.NewScanlinesPass(240, 0.15))
+//	scene.PostProcessor = post
+//	...
+//	vignette.SetUniform("u_radius", 0.6)
+
+// The vertex shader of the passes, it maps the quad covering the target to the texture coordinates
+const passVertexShader = `#version 100
+attribute vec2 a_position;
+varying vec2 v_texCoords;
+void main() {
+	v_texCoords = a_position * 0.5 + 0.5;
+	gl_Position = vec4(a_position, 0.0, 1.0);
+}`
+
+// The quad covering the target, drawn as a triangle strip
+var passQuad = f32.Bytes(binary.LittleEndian,
+	-1, -1,
+	1, -1,
+	-1, 1,
+	1, 1,
+)
+
+// A ShaderPass is a fragment shader the post processor draws the scene through
+type ShaderPass struct {
+	Name string
+
+	// The GLSL ES source of the fragment shader
+	Fragment string
+
+	// If set the pass is skipped
+	Disabled bool
+
+	uniforms []*passUniform
+
+	ctx        GLContext
+	program    gl.Program
+	position   gl.Attrib
+	texture    gl.Uniform
+	scene      gl.Uniform
+	resolution gl.Uniform
+}
+
+// A uniform of a pass and its values, the location is looked up when it is first set after the program was compiled
+type passUniform struct {
+	name     string
+	values   []float32
+	location gl.Uniform
+	located  bool
+}
+
+// Creates a pass drawing the fragment shader, the name is used in its compile errors
+func NewShaderPass(name, fragment string) *ShaderPass {
+	return &ShaderPass{Name: name, Fragment: fragment}
+}
+
+// Sets the uniform of the fragment shader to the values, 1 to 4 values for a float to a vec4 and 16 for a mat4. It is set
+// each time the pass is drawn.
+func (self *ShaderPass) SetUniform(name string, values ...float32) *ShaderPass {
+	switch len(values) {
+	case 1, 2, 3, 4, 16:
+	default:
+		panic("postprocess: uniform " + name + " needs 1 to 4 or 16 values, got " + strconv.Itoa(len(values)))
+	}
+	for _, uniform := range self.uniforms {
+		if uniform.name == name {
+			uniform.values = append(uniform.values[:0], values...)
+			return self
+		}
+	}
+	self.uniforms = append(self.uniforms, &passUniform{name: name, values: append([]float32(nil), values...)})
+	return self
+}
+
+// Returns the values of the uniform, nil if it is not set
+func (self *ShaderPass) GetUniform(name string) []float32 {
+	for _, uniform := range self.uniforms {
+		if uniform.name == name {
+			return uniform.values
+		}
+	}
+	return nil
+}
+
+// Compiles and links the program of the pass with the context
+func (self *ShaderPass) compile(ctx GLContext) error {
+	program, err := compileProgram(ctx, passVertexShader, self.Fragment)
+	if err != nil {
+		return errors.New("postprocess: " + self.Name + ": " + err.Error())
+	}
+	self.ctx = ctx
+	self.program = program
+	self.position = ctx.GetAttribLocation(program, "a_position")
+	self.texture = ctx.GetUniformLocation(program, "u_texture")
+	self.scene = ctx.GetUniformLocation(program, "u_scene")
+	self.resolution = ctx.GetUniformLocation(program, "u_resolution")
+	for _, uniform := range self.uniforms {
+		uniform.located = false
+	}
+	return nil
+}
+
+// Compiles the shaders and links them in a program, returns the info log as the error if it fails
+func compileProgram(ctx GLContext, vertexSource, fragmentSource string) (gl.Program, error) {
+	vertex, err := compileShader(ctx, gl.VERTEX_SHADER, vertexSource)
+	if err != nil {
+		return gl.Program{}, err
+	}
+	fragment, err := compileShader(ctx, gl.FRAGMENT_SHADER, fragmentSource)
+	if err != nil {
+		ctx.DeleteShader(vertex)
+		return gl.Program{}, err
+	}
+	program := ctx.CreateProgram()
+	ctx.AttachShader(program, vertex)
+	ctx.AttachShader(program, fragment)
+	ctx.LinkProgram(program)
+	// the shaders are freed with the program
+	ctx.DeleteShader(vertex)
+	ctx.DeleteShader(fragment)
+	if ctx.GetProgrami(program, gl.LINK_STATUS) == 0 {
+		log := ctx.GetProgramInfoLog(program)
+		ctx.DeleteProgram(program)
+		return gl.Program{}, errors.New("link failed: " + log)
+	}
+	return program, nil
+}
+
+// Compiles a shader of the type, returns the info log as the error if it fails
+func compileShader(ctx GLContext, ty gl.Enum, source string) (gl.Shader, error) {
+	shader := ctx.CreateShader(ty)
+	ctx.ShaderSource(shader, source)
+	ctx.CompileShader(shader)
+	if ctx.GetShaderi(shader, gl.COMPILE_STATUS) == 0 {
+		log := ctx.GetShaderInfoLog(shader)
+		ctx.DeleteShader(shader)
+		kind := "fragment"
+		if ty == gl.VERTEX_SHADER {
+			kind = "vertex"
+		}
+		return gl.Shader{}, errors.New(kind + " shader compile failed: " + log)
+	}
+	return shader, nil
+}
+
+// Sets the uniforms of the pass, its program must be in use
+func (self *ShaderPass) applyUniforms() {
+	ctx := self.ctx
+	for _, uniform := range self.uniforms {
+		if !uniform.located {
+			uniform.location = ctx.GetUniformLocation(self.program, uniform.name)
+			uniform.located = true
+		}
+		v := uniform.values
+		switch len(v) {
+		case 1:
+			ctx.Uniform1f(uniform.location, v[0])
+		case 2:
+			ctx.Uniform2f(uniform.location, v[0], v[1])
+		case 3:
+			ctx.Uniform3f(uniform.location, v[0], v[1], v[2])
+		case 4:
+			ctx.Uniform4f(uniform.location, v[0], v[1], v[2], v[3])
+		case 16:
+			ctx.UniformMatrix4fv(uniform.location, v)
+		}
+	}
+}
+
+// Deletes the program of the pass, it is compiled again when it is next drawn
+func (self *ShaderPass) dispose() {
+	if self.ctx != nil {
+		self.ctx.DeleteProgram(self.program)
+		self.ctx = nil
+	}
+}
+
+// A PostProcessor draws a scene through a chain of shader passes, see Scene.PostProcessor
+type PostProcessor struct {
+	passes []*ShaderPass
+
+	ctx               GLContext
+	scene, ping, pong *FrameBuffer
+	quad              gl.Buffer
+
+	// The passes drawn between Begin and End
+	frame []*ShaderPass
+}
+
+// The post processor which failed to begin, it is not used again so that its error is printed once
+var failedPostProcessor *PostProcessor
+
+// Creates a post processor drawing through the passes in order
+func NewPostProcessor(passes ...*ShaderPass) *PostProcessor {
+	return &PostProcessor{passes: passes}
+}
+
+// Adds the passes to the end of the chain
+func (self *PostProcessor) AddPass(passes ...*ShaderPass) {
+	self.passes = append(self.passes, passes...)
+}
+
+// Removes the pass from the chain
+func (self *PostProcessor) RemovePass(pass *ShaderPass) {
+	for i, p := range self.passes {
+		if p == pass {
+			self.passes = append(self.passes[:i], self.passes[i+1:]...)
+			pass.dispose()
+			return
+		}
+	}
+}
+
+// Returns the passes of the chain in the order they are drawn
+func (self *PostProcessor) GetPasses() []*ShaderPass {
+	return self.passes
+}
+
+// Starts drawing the scene into the scene buffer, cleared to the color, and compiles the passes which are not compiled yet.
+// The frame buffers are created for the size and made again when it changes. If no pass is enabled nothing is done and
+// the scene is drawn to the screen as usual.
+func (self *PostProcessor) Begin(ctx GLContext, width, height int, clearColor Color) error {
+	self.frame = self.frame[:0]
+	frame := self.frame
+	if ctx != self.ctx {
+		// the resources of a lost context are gone with it
+		self.ctx, self.scene, self.ping, self.pong = ctx, nil, nil, nil
+		self.quad = ctx.CreateBuffer()
+		ctx.BindBuffer(gl.ARRAY_BUFFER, self.quad)
+		ctx.BufferData(gl.ARRAY_BUFFER, passQuad, gl.STATIC_DRAW)
+		for _, pass := range self.passes {
+			pass.ctx = nil
+		}
+	}
+	for _, pass := range self.passes {
+		if pass.Disabled {
+			continue
+		}
+		if pass.ctx != ctx {
+			if err := pass.compile(ctx); err != nil {
+				return err
+			}
+		}
+		frame = append(frame, pass)
+	}
+	if len(frame) == 0 {
+		return nil
+	}
+	if self.scene == nil || self.scene.Width != width || self.scene.Height != height {
+		self.disposeBuffers()
+		var err error
+		if self.scene, err = NewFrameBuffer(ctx, width, height); err != nil {
+			return err
+		}
+		if self.ping, err = NewFrameBuffer(ctx, width, height); err != nil {
+			self.disposeBuffers()
+			return err
+		}
+		if self.pong, err = NewFrameBuffer(ctx, width, height); err != nil {
+			self.disposeBuffers()
+			return err
+		}
+	}
+	self.frame = frame
+	self.scene.Begin()
+	self.scene.Clear(clearColor)
+	return nil
+}
+
+// Begins the post processor of the scene, returns it or nil if the scene has none or it failed. The scene is then drawn
+// without the passes rather than stopping the app, the error is printed the first time.
+func beginPostProcessor(scene *Scene, ctx GLContext, width, height int) *PostProcessor {
+	post := scene.PostProcessor
+	if post == nil || post == failedPostProcessor {
+		return nil
+	}
+	if err := post.Begin(ctx, width, height, scene.BGColor); err != nil {
+		println("PostProcessor: " + err.Error())
+		failedPostProcessor = post
+		return nil
+	}
+	return post
+}
+
+// Stops drawing into the scene buffer and draws it through the passes, the last pass draws to the screen bounds. Blending
+// is disabled while they are drawn, the passes replace what is under them.
+func (self *PostProcessor) End(screenX, screenY, screenWidth, screenHeight int) {
+	if len(self.frame) == 0 {
+		return
+	}
+	self.scene.End()
+	ctx := self.ctx
+	blending := ctx.IsEnabled(gl.BLEND)
+	ctx.Disable(gl.BLEND)
+	source, target := self.scene, self.ping
+	for i, pass := range self.frame {
+		if i == len(self.frame)-1 {
+			ctx.Viewport(screenX, screenY, screenWidth, screenHeight)
+			self.drawPass(pass, source, screenWidth, screenHeight)
+			break
+		}
+		target.Begin()
+		self.drawPass(pass, source, target.Width, target.Height)
+		target.End()
+		source = target
+		if target == self.ping {
+			target = self.pong
+		} else {
+			target = self.ping
+		}
+	}
+	if blending {
+		ctx.Enable(gl.BLEND)
+	}
+	self.frame = self.frame[:0]
+}
+
+// Draws the pass over its target, which is width by height pixels, sampling the source
+func (self *PostProcessor) drawPass(pass *ShaderPass, source *FrameBuffer, width, height int) {
+	ctx := self.ctx
+	ctx.UseProgram(pass.program)
+	ctx.ActiveTexture(gl.TEXTURE1)
+	ctx.BindTexture(gl.TEXTURE_2D, self.scene.texture)
+	ctx.Uniform1i(pass.scene, 1)
+	ctx.ActiveTexture(gl.TEXTURE0)
+	ctx.BindTexture(gl.TEXTURE_2D, source.texture)
+	ctx.Uniform1i(pass.texture, 0)
+	ctx.Uniform2f(pass.resolution, float32(width), float32(height))
+	pass.applyUniforms()
+	ctx.BindBuffer(gl.ARRAY_BUFFER, self.quad)
+	ctx.EnableVertexAttribArray(pass.position)
+	ctx.VertexAttribPointer(pass.position, 2, gl.FLOAT, false, 0, 0)
+	ctx.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	ctx.DisableVertexAttribArray(pass.position)
+}
+
+func (self *PostProcessor) disposeBuffers() {
+	for _, buffer := range []*FrameBuffer{self.scene, self.ping, self.pong} {
+		if buffer != nil {
+			buffer.Dispose()
+		}
+	}
+	self.scene, self.ping, self.pong = nil, nil, nil
+}
+
+// Deletes the frame buffers and the programs of the passes, they are made again when it is next drawn
+func (self *PostProcessor) Dispose() {
+	if self.ctx == nil {
+		return
+	}
+	self.disposeBuffers()
+	for _, pass := range self.passes {
+		pass.dispose()
+	}
+	self.ctx.DeleteBuffer(self.quad)
+	self.ctx = nil
+}
+
+// The header of the fragment shaders of the passes
+const passHeader = `#version 100
+precision mediump float;
+uniform sampler2D u_texture;
+uniform sampler2D u_scene;
+uniform vec2 u_resolution;
+varying vec2 v_texCoords;
+`
+
+// Creates a gaussian blur pass in one direction, blurring both ways takes a horizontal and a vertical pass. The radius
+// is the spread in pixels, in the uniform u_direction.
+func NewBlurPass(horizontal bool, radius float32) *ShaderPass {
+	name := "vertical blur"
+	direction := []float32{0, radius}
+	if horizontal {
+		name = "horizontal blur"
+		direction = []float32{radius, 0}
+	}
+	return NewShaderPass(name, passHeader+`uniform vec2 u_direction;
+void main() {
+	vec2 offset = u_direction / u_resolution;
+	vec4 sum = texture2D(u_texture, v_texCoords) * 0.2270270;
+	sum += texture2D(u_texture, v_texCoords + offset * 1.3846154) * 0.3162162;
+	sum += texture2D(u_texture, v_texCoords - offset * 1.3846154) * 0.3162162;
+	sum += texture2D(u_texture, v_texCoords + offset * 3.2307692) * 0.0702703;
+	sum += texture2D(u_texture, v_texCoords - offset * 3.2307692) * 0.0702703;
+	gl_FragColor = sum;
+}`).SetUniform("u_direction", direction...)
+}
+
+// Creates a pass darkening the corners. The screen is darkened from the radius to radius - softness away from its center,
+// in the uniforms u_radius and u_softness, where 0.5 is the middle of its edges.
+func NewVignettePass(radius, softness float32) *ShaderPass {
+	return NewShaderPass("vignette", passHeader+`uniform float u_radius;
+uniform float u_softness;
+void main() {
+	vec4 color = texture2D(u_texture, v_texCoords);
+	float vignette = smoothstep(u_radius, u_radius - u_softness, distance(v_texCoords, vec2(0.5)));
+	gl_FragColor = vec4(color.rgb * vignette, color.a);
+}`).SetUniform("u_radius", radius).SetUniform("u_softness", softness)
+}
+
+// Creates a color grading pass. The brightness is added to the colors, the contrast scales them away from the middle gray
+// and the saturation mixes them with their gray, 0, 1 and 1 keep the colors. The colors are multiplied by u_tint, which is
+// white. The uniforms are u_brightness, u_contrast, u_saturation and u_tint.
+func NewColorGradePass(brightness, contrast, saturation float32) *ShaderPass {
+	return NewShaderPass("color grading", passHeader+`uniform float u_brightness;
+uniform float u_contrast;
+uniform float u_saturation;
+uniform vec3 u_tint;
+void main() {
+	vec4 color = texture2D(u_texture, v_texCoords);
+	vec3 rgb = (color.rgb + u_brightness - 0.5) * u_contrast + 0.5;
+	float gray = dot(rgb, vec3(0.299, 0.587, 0.114));
+	gl_FragColor = vec4(clamp(mix(vec3(gray), rgb, u_saturation) * u_tint, 0.0, 1.0), color.a);
+}`).SetUniform("u_brightness", brightness).SetUniform("u_contrast", contrast).SetUniform("u_saturation", saturation).
+		SetUniform("u_tint", 1, 1, 1)
+}
+
+// Creates a pass darkening the lines of a CRT screen, with the number of lines over the height of the screen and how much
+// they are darkened from 0 to 1, in the uniforms u_count and u_intensity
+func NewScanlinesPass(count, intensity float32) *ShaderPass {
+	return NewShaderPass("scanlines", passHeader+`uniform float u_count;
+uniform float u_intensity;
+void main() {
+	vec4 color = texture2D(u_texture, v_texCoords);
+	float line = sin(v_texCoords.y * u_count * 3.14159265) * 0.5 + 0.5;
+	gl_FragColor = vec4(color.rgb * (1.0 - u_intensity * line), color.a);
+}`).SetUniform("u_count", count).SetUniform("u_intensity", intensity)
+}
+
+// Creates a pass keeping the parts of the colors brighter than the threshold, in the uniform u_threshold
+func NewBrightPass(threshold float32) *ShaderPass {
+	return NewShaderPass("bright", passHeader+`uniform float u_threshold;
+void main() {
+	vec4 color = texture2D(u_texture, v_texCoords);
+	gl_FragColor = vec4(max(color.rgb - u_threshold, 0.0) / max(1.0 - u_threshold, 0.0001), 1.0);
+}`).SetUniform("u_threshold", threshold)
+}
+
+// Creates a pass adding its input times the intensity to the scene, in the uniform u_intensity
+func NewBloomCombinePass(intensity float32) *ShaderPass {
+	return NewShaderPass("bloom combine", passHeader+`uniform float u_intensity;
+void main() {
+	vec4 scene = texture2D(u_scene, v_texCoords);
+	gl_FragColor = vec4(scene.rgb + texture2D(u_texture, v_texCoords).rgb * u_intensity, scene.a);
+}`).SetUniform("u_intensity", intensity)
+}
+
+// Creates the passes of a bloom, the parts brighter than the threshold are blurred by the radius in pixels and added to
+// the scene times the intensity. They add to the scene as it was drawn, so they go first in the chain.
+func NewBloomPasses(threshold, intensity, radius float32) []*ShaderPass {
+	return []*ShaderPass{NewBrightPass(threshold), NewBlurPass(true, radius), NewBlurPass(false, radius),
+		NewBloomCombinePass(intensity)}
+}
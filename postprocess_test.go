@@ -0,0 +1,244 @@
+package spike
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/mobile/gl"
+)
+
+func TestFrameBuffer(t *testing.T) {
+	oldViewport := viewport
+	viewport = &Viewport{ScreenX: 10, ScreenY: 20, ScreenWidth: 300, ScreenHeight: 200}
+	defer func() { viewport = oldViewport }()
+	fake := NewFakeGL()
+	outer, err := NewFrameBuffer(fake, 64, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, _ := NewFrameBuffer(fake, 16, 8)
+	if outer.Texture.Width != 64 || outer.Texture.Height != 32 || outer.GetRegion().RegionHeight != 32 {
+		t.Errorf("texture %+v", outer.Texture)
+	}
+	if calls := fake.Filter("FramebufferTexture2D"); len(calls) != 2 ||
+		calls[0] != "FramebufferTexture2D FRAMEBUFFER COLOR_ATTACHMENT0 TEXTURE_2D "+handle(outer.texture.Value)+" 0" {
+		t.Errorf("attached %v", calls)
+	}
+	// the default frame buffer is bound again after they are made
+	if calls := fake.Filter("BindFramebuffer"); calls[len(calls)-1] != "BindFramebuffer FRAMEBUFFER 0" {
+		t.Errorf("bound %v", calls)
+	}
+
+	fake.Reset()
+	outer.Begin()
+	inner.Begin()
+	inner.End()
+	outer.End()
+	expected := []string{
+		"BindFramebuffer FRAMEBUFFER " + handle(outer.framebuffer.Value), "Viewport 0 0 64 32",
+		"BindFramebuffer FRAMEBUFFER " + handle(inner.framebuffer.Value), "Viewport 0 0 16 8",
+		"BindFramebuffer FRAMEBUFFER " + handle(outer.framebuffer.Value), "Viewport 0 0 64 32",
+		"BindFramebuffer FRAMEBUFFER 0", "Viewport 10 20 300 200",
+	}
+	if !reflect.DeepEqual(fake.Calls, expected) {
+		t.Errorf("nested calls\n%v\nexpected\n%v", fake.Calls, expected)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("End without Begin did not panic")
+			}
+		}()
+		outer.End()
+	}()
+
+	fake.FramebufferStatus = 0x8cd6
+	if _, err := NewFrameBuffer(fake, 1<<20, 1); err == nil || !strings.Contains(err.Error(), "0x8cd6") {
+		t.Errorf("incomplete frame buffer error %v", err)
+	}
+	if calls := fake.Filter("Delete"); len(calls) != 2 {
+		t.Errorf("incomplete frame buffer not deleted %v", calls)
+	}
+}
+
+func TestPostProcessorPasses(t *testing.T) {
+	fake := NewFakeGL()
+	first := NewShaderPass("first", "void main() {}")
+	disabled := NewShaderPass("disabled", "void main() {}")
+	disabled.Disabled = true
+	second := NewShaderPass("second", "void main() {}")
+	last := NewShaderPass("last", "void main() {}")
+	post := NewPostProcessor(first, disabled, second)
+	post.AddPass(last)
+	if err := post.Begin(fake, 320, 240, Color{0.5, 0, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if disabled.ctx != nil {
+		t.Error("disabled pass compiled")
+	}
+	sceneFB, ping, pong := handle(post.scene.framebuffer.Value), handle(post.ping.framebuffer.Value),
+		handle(post.pong.framebuffer.Value)
+	sceneTex, pingTex, pongTex := handle(post.scene.texture.Value), handle(post.ping.texture.Value),
+		handle(post.pong.texture.Value)
+	if calls := fake.Calls[len(fake.Calls)-4:]; !reflect.DeepEqual(calls, []string{"BindFramebuffer FRAMEBUFFER " + sceneFB,
+		"Viewport 0 0 320 240", "ClearColor 0.5 0 0 1", "Clear COLOR_BUFFER_BIT|DEPTH_BUFFER_BIT"}) {
+		t.Errorf("capture %v", calls)
+	}
+
+	fake.Reset()
+	post.End(8, 4, 320, 240)
+	var drawn []string
+	for _, call := range fake.Calls {
+		if strings.HasPrefix(call, "UseProgram") || strings.HasPrefix(call, "BindFramebuffer") ||
+			strings.HasPrefix(call, "Viewport") || strings.HasPrefix(call, "BindTexture") ||
+			strings.HasPrefix(call, "DrawArrays") {
+			drawn = append(drawn, call)
+		}
+	}
+	expected := []string{
+		"BindFramebuffer FRAMEBUFFER 0", "Viewport 0 0 0 0",
+		// the first pass reads the scene into ping
+		"BindFramebuffer FRAMEBUFFER " + ping, "Viewport 0 0 320 240",
+		"UseProgram " + handle(first.program.Value), "BindTexture TEXTURE_2D " + sceneTex,
+		"BindTexture TEXTURE_2D " + sceneTex, "DrawArrays TRIANGLE_STRIP 0 4",
+		"BindFramebuffer FRAMEBUFFER 0", "Viewport 0 0 0 0",
+		// the second reads ping into pong
+		"BindFramebuffer FRAMEBUFFER " + pong, "Viewport 0 0 320 240",
+		"UseProgram " + handle(second.program.Value), "BindTexture TEXTURE_2D " + sceneTex,
+		"BindTexture TEXTURE_2D " + pingTex, "DrawArrays TRIANGLE_STRIP 0 4",
+		"BindFramebuffer FRAMEBUFFER 0", "Viewport 0 0 0 0",
+		// the last reads pong into the screen bounds
+		"Viewport 8 4 320 240",
+		"UseProgram " + handle(last.program.Value), "BindTexture TEXTURE_2D " + sceneTex,
+		"BindTexture TEXTURE_2D " + pongTex, "DrawArrays TRIANGLE_STRIP 0 4",
+	}
+	if !reflect.DeepEqual(drawn, expected) {
+		t.Errorf("passes drawn\n%v\nexpected\n%v", drawn, expected)
+	}
+	if len(fake.Filter("Disable BLEND")) != 1 || len(fake.Filter("Enable BLEND")) != 0 {
+		t.Error("blending not disabled")
+	}
+
+	// the buffers are made again when the size changes, the programs are kept
+	fake.Reset()
+	second.Disabled = true
+	if err := post.Begin(fake, 160, 120, Color{}); err != nil {
+		t.Fatal(err)
+	}
+	fake.Enable(gl.BLEND)
+	post.End(0, 0, 160, 120)
+	// blending is enabled again after the passes
+	if !fake.enabled[gl.BLEND] || fake.Calls[len(fake.Calls)-1] != "Enable BLEND" {
+		t.Errorf("blending not restored %v", fake.Calls)
+	}
+	if len(fake.Filter("CreateProgram")) != 0 || len(fake.Filter("DeleteFramebuffer")) != 3 ||
+		len(fake.Filter("CreateFramebuffer")) != 3 {
+		t.Errorf("resized %v", fake.Calls)
+	}
+	if programs := fake.Filter("UseProgram"); !reflect.DeepEqual(programs, []string{"UseProgram " +
+		handle(first.program.Value), "UseProgram " + handle(last.program.Value)}) {
+		t.Errorf("drawn without the disabled pass %v", programs)
+	}
+
+	post.RemovePass(first)
+	if len(post.GetPasses()) != 3 || first.ctx != nil {
+		t.Errorf("removed pass %v", post.GetPasses())
+	}
+	post.Dispose()
+	if post.scene != nil || last.ctx != nil {
+		t.Error("not disposed")
+	}
+}
+
+func TestPostProcessorUniforms(t *testing.T) {
+	fake := NewFakeGL()
+	pass := NewVignettePass(0.75, 0.45)
+	pass.SetUniform("u_offset", 1, 2).SetUniform("u_tint", 1, 0.5, 0.25).SetUniform("u_color", 1, 1, 1, 0.5)
+	pass.SetUniform("u_matrix", 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1)
+	post := NewPostProcessor(pass)
+	post.Begin(fake, 100, 50, Color{})
+	fake.Reset()
+	post.End(0, 0, 100, 50)
+	var uniforms []string
+	for _, call := range fake.Calls {
+		if strings.HasPrefix(call, "Uniform") {
+			uniforms = append(uniforms, call)
+		}
+	}
+	expected := []string{
+		"Uniform1i u_scene 1", "Uniform1i u_texture 0", "Uniform2f u_resolution 100 50",
+		"Uniform1f u_radius 0.75", "Uniform1f u_softness 0.45", "Uniform2f u_offset 1 2",
+		"Uniform3f u_tint 1 0.5 0.25", "Uniform4f u_color 1 1 1 0.5",
+		"UniformMatrix4fv u_matrix 1 0 0 0 0 1 0 0 0 0 1 0 0 0 0 1",
+	}
+	if !reflect.DeepEqual(uniforms, expected) {
+		t.Errorf("uniforms\n%v\nexpected\n%v", uniforms, expected)
+	}
+
+	// the uniforms are looked up once and can be changed between frames
+	pass.SetUniform("u_radius", 0.5)
+	fake.Reset()
+	post.Begin(fake, 100, 50, Color{})
+	post.End(0, 0, 100, 50)
+	if len(fake.Filter("GetUniformLocation")) != 0 || len(fake.Filter("Uniform1f u_radius 0.5")) != 1 {
+		t.Errorf("changed uniform %v", fake.Calls)
+	}
+	if v := pass.GetUniform("u_radius"); len(v) != 1 || v[0] != 0.5 {
+		t.Errorf("uniform values %v", v)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("uniform with 5 values did not panic")
+			}
+		}()
+		pass.SetUniform("u_bad", 1, 2, 3, 4, 5)
+	}()
+}
+
+func TestPostProcessorErrors(t *testing.T) {
+	fake := NewFakeGL()
+	post := NewPostProcessor()
+	if err := post.Begin(fake, 100, 50, Color{}); err != nil {
+		t.Fatal(err)
+	}
+	post.End(0, 0, 100, 50)
+	if len(fake.Filter("BindFramebuffer")) != 0 || len(fake.Filter("DrawArrays")) != 0 {
+		t.Errorf("drawn without passes %v", fake.Calls)
+	}
+
+	fake.ShaderError = "broken"
+	post.AddPass(NewShaderPass("glitch", "void main() { broken }"))
+	err := post.Begin(fake, 100, 50, Color{})
+	if err == nil || err.Error() != "postprocess: glitch: fragment shader compile failed: broken" {
+		t.Errorf("compile error %v", err)
+	}
+	fake.Reset()
+	post.End(0, 0, 100, 50)
+	if len(fake.Calls) != 0 {
+		t.Errorf("drawn after an error %v", fake.Calls)
+	}
+
+	// the scene is drawn without a post processor which failed, it is not tried again
+	defer func() { failedPostProcessor = nil }()
+	scene := &Scene{PostProcessor: post}
+	if beginPostProcessor(scene, fake, 100, 50) != nil || failedPostProcessor != post {
+		t.Error("began a broken post processor")
+	}
+	fake.Reset()
+	if beginPostProcessor(scene, fake, 100, 50) != nil || len(fake.Calls) != 0 {
+		t.Errorf("tried a broken post processor again %v", fake.Calls)
+	}
+	working := NewPostProcessor(NewShaderPass("working", "void main() {}"))
+	scene.PostProcessor = working
+	if beginPostProcessor(scene, fake, 100, 50) != working || beginPostProcessor(&Scene{}, fake, 100, 50) != nil {
+		t.Error("did not begin the working post processor")
+	}
+	working.End(0, 0, 100, 50)
+}
+
+func handle(value uint32) string {
+	return strconv.Itoa(int(value))
+}
@@ -0,0 +1,459 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"math"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/interpolation"
+	"github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+// The style of a progress bar. The background is drawn along the bar, the knob before and after the knob fill the parts
+// of the bar before and after it. All the drawables may be nil, the disabled ones are used by disabled bars if they are
+// set.
+type ProgressBarStyle struct {
+	Background, DisabledBackground ui.Drawable
+	Knob, DisabledKnob             ui.Drawable
+	KnobBefore, DisabledKnobBefore ui.Drawable
+	KnobAfter, DisabledKnobAfter   ui.Drawable
+}
+
+// A ProgressBar shows a value in a range with the position of a knob on a horizontal or vertical bar. Changes of the value
+// can be animated, the knob then moves to the new value over the animate duration.
+type ProgressBar struct {
+	Actor
+	widget
+
+	style                     *ProgressBarStyle
+	min, max, stepSize, value float32
+	vertical, disabled        bool
+	animateFromValue          float32
+	animateDuration           float32
+	animateTime               float32
+	animateInterpolation      interpolation.Interpolation
+	knobPosition              float32
+
+	// returns the knob of the state of the bar, it is set by the widgets which embed the bar
+	knobDrawable func() ui.Drawable
+
+	// If set SetValue calls OnChange. Default is true.
+	ProgrammaticChangeEvents bool
+
+	// Called when the value changes
+	OnChange func(self *ProgressBar)
+}
+
+// Creates a progress bar for the values from min to max, the values are rounded to the step size if it is not 0. The
+// value starts at min.
+func NewProgressBar(min, max, stepSize float32, vertical bool, style *ProgressBarStyle) *ProgressBar {
+	self := &ProgressBar{}
+	self.initProgressBar(min, max, stepSize, vertical, style)
+	return self
+}
+
+// Initializes the bar, the widgets which embed a bar call it in their constructors
+func (self *ProgressBar) initProgressBar(min, max, stepSize float32, vertical bool, style *ProgressBarStyle) {
+	if min > max {
+		panic("progress bar: max must be larger than min")
+	}
+	self.style, self.min, self.max, self.stepSize, self.vertical = style, min, max, stepSize, vertical
+	self.value, self.ProgrammaticChangeEvents = min, true
+	self.animateInterpolation = interpolation.Linear()
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.widget.init(&self.Actor, self)
+	self.Act = func(a *Actor, delta float32) {
+		if self.animateTime > 0 {
+			self.animateTime -= delta
+		}
+	}
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+}
+
+func (self *ProgressBar) GetStyle() *ProgressBarStyle {
+	return self.style
+}
+
+func (self *ProgressBar) SetStyle(style *ProgressBarStyle) {
+	self.style = style
+	self.InvalidateHierarchy()
+}
+
+func (self *ProgressBar) GetValue() float32 {
+	return self.value
+}
+
+// Returns the value the knob is drawn at, between the old and the new value while a change is animated
+func (self *ProgressBar) GetVisualValue() float32 {
+	if self.animateTime > 0 {
+		alpha := 1 - self.animateTime/self.animateDuration
+		return interpolation.StartEnd(self.animateFromValue, self.value, alpha, self.animateInterpolation)
+	}
+	return self.value
+}
+
+// Returns the value as a fraction of the range from 0 to 1
+func (self *ProgressBar) GetPercent() float32 {
+	if self.min == self.max {
+		return 0
+	}
+	return (self.value - self.min) / (self.max - self.min)
+}
+
+// Returns the visual value as a fraction of the range from 0 to 1
+func (self *ProgressBar) GetVisualPercent() float32 {
+	if self.min == self.max {
+		return 0
+	}
+	return (self.GetVisualValue() - self.min) / (self.max - self.min)
+}
+
+// Sets the value, which is rounded to the step size and clamped to the range. It returns false if the value did not
+// change. OnChange is called if ProgrammaticChangeEvents is set.
+func (self *ProgressBar) SetValue(value float32) bool {
+	return self.setValue(value, self.ProgrammaticChangeEvents)
+}
+
+func (self *ProgressBar) setValue(value float32, fire bool) bool {
+	value = self.clamp(self.round(value))
+	if value == self.value {
+		return false
+	}
+	visualValue := self.GetVisualValue()
+	self.value = value
+	if self.animateDuration > 0 {
+		self.animateFromValue = visualValue
+		self.animateTime = self.animateDuration
+	}
+	if fire && self.OnChange != nil {
+		self.OnChange(self)
+	}
+	return true
+}
+
+// Rounds the value to the nearest step from min
+func (self *ProgressBar) round(value float32) float32 {
+	if self.stepSize <= 0 {
+		return value
+	}
+	steps := math.Floor(float64((value-self.min)/self.stepSize) + 0.5)
+	return self.min + float32(steps)*self.stepSize
+}
+
+func (self *ProgressBar) clamp(value float32) float32 {
+	if value < self.min {
+		return self.min
+	}
+	if value > self.max {
+		return self.max
+	}
+	return value
+}
+
+// Sets the range of the values, the value is clamped to it
+func (self *ProgressBar) SetRange(min, max float32) {
+	if min > max {
+		panic("progress bar: max must be larger than min")
+	}
+	self.min, self.max = min, max
+	self.SetValue(self.value)
+}
+
+func (self *ProgressBar) GetMinValue() float32 {
+	return self.min
+}
+
+func (self *ProgressBar) GetMaxValue() float32 {
+	return self.max
+}
+
+func (self *ProgressBar) SetStepSize(stepSize float32) {
+	self.stepSize = stepSize
+}
+
+func (self *ProgressBar) GetStepSize() float32 {
+	return self.stepSize
+}
+
+// Sets the time in seconds the knob takes to move to a new value, 0 moves it at once. Default is 0.
+func (self *ProgressBar) SetAnimateDuration(duration float32) {
+	self.animateDuration = duration
+}
+
+// Sets how the knob moves to a new value. Default is linear.
+func (self *ProgressBar) SetAnimateInterpolation(interp interpolation.Interpolation) {
+	self.animateInterpolation = interp
+}
+
+// Returns whether the knob is moving to a new value
+func (self *ProgressBar) IsAnimating() bool {
+	return self.animateTime > 0
+}
+
+// Moves the knob to the value at once if it is animating
+func (self *ProgressBar) UpdateVisualValue() {
+	self.animateTime = 0
+}
+
+// A disabled bar is drawn with the disabled drawables of its style, sliders cannot be dragged while they are disabled
+func (self *ProgressBar) SetDisabled(disabled bool) {
+	self.disabled = disabled
+}
+
+func (self *ProgressBar) IsDisabled() bool {
+	return self.disabled
+}
+
+func (self *ProgressBar) IsVertical() bool {
+	return self.vertical
+}
+
+// Returns the position of the knob from the start of the bar as it was last drawn
+func (self *ProgressBar) GetKnobPosition() float32 {
+	return self.knobPosition
+}
+
+// Returns the drawable of the state of the bar, the disabled one if the bar is disabled and it is set
+func (self *ProgressBar) stateDrawable(drawable, disabled ui.Drawable) ui.Drawable {
+	if self.disabled && disabled != nil {
+		return disabled
+	}
+	return drawable
+}
+
+func (self *ProgressBar) getKnob() ui.Drawable {
+	if self.knobDrawable != nil {
+		return self.knobDrawable()
+	}
+	return self.stateDrawable(self.style.Knob, self.style.DisabledKnob)
+}
+
+// Returns the size of the drawable along the bar, 0 if it is nil
+func (self *ProgressBar) lengthOf(drawable ui.Drawable) float32 {
+	switch {
+	case drawable == nil:
+		return 0
+	case self.vertical:
+		return drawable.GetMinHeight()
+	}
+	return drawable.GetMinWidth()
+}
+
+// Returns the size of the drawable across the bar, 0 if it is nil
+func (self *ProgressBar) thicknessOf(drawable ui.Drawable) float32 {
+	switch {
+	case drawable == nil:
+		return 0
+	case self.vertical:
+		return drawable.GetMinWidth()
+	}
+	return drawable.GetMinHeight()
+}
+
+// Returns the padding of the background at the start and at the end of the bar
+func (self *ProgressBar) backgroundPads(background ui.Drawable) (start, end float32) {
+	switch {
+	case background == nil:
+		return 0, 0
+	case self.vertical:
+		return background.GetPadBottom(), background.GetPadTop()
+	}
+	return background.GetPadLeft(), background.GetPadRight()
+}
+
+// Draws the drawable centered across the bar at the position along the bar with the length
+func (self *ProgressBar) drawAlong(batch g2d.Batch, drawable ui.Drawable, position, length float32) {
+	if drawable == nil || length <= 0 {
+		return
+	}
+	thickness := self.thicknessOf(drawable)
+	if self.vertical {
+		drawDrawable(batch, &self.Actor, drawable, (self.W-thickness)/2, position, thickness, length)
+	} else {
+		drawDrawable(batch, &self.Actor, drawable, position, (self.H-thickness)/2, length, thickness)
+	}
+}
+
+func (self *ProgressBar) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	style := self.style
+	background := self.stateDrawable(style.Background, style.DisabledBackground)
+	knob := self.getKnob()
+	knobBefore := self.stateDrawable(style.KnobBefore, style.DisabledKnobBefore)
+	knobAfter := self.stateDrawable(style.KnobAfter, style.DisabledKnobAfter)
+	size := self.W
+	if self.vertical {
+		size = self.H
+	}
+	start, end := self.backgroundPads(background)
+	knobSize := self.lengthOf(knob)
+	position := start
+	if self.min != self.max {
+		position += (size - start - end - knobSize) * self.GetVisualPercent()
+	}
+	self.knobPosition = position
+	half := knobSize / 2
+	setBatchColor(batch, &self.Actor, nil, parentAlpha)
+	self.drawAlong(batch, background, 0, size)
+	self.drawAlong(batch, knobBefore, start, position+half-start)
+	self.drawAlong(batch, knobAfter, position+half, size-end-position-half)
+	self.drawAlong(batch, knob, position, knobSize)
+}
+
+// The bar has nothing to lay out, the knob is positioned when it is drawn
+func (self *ProgressBar) Layout() {
+}
+
+// Returns the thickness of the widest drawable of the bar
+func (self *ProgressBar) prefThickness() float32 {
+	style := self.style
+	thickness := float32(0)
+	for _, drawable := range []ui.Drawable{self.getKnob(), style.Background, style.KnobBefore, style.KnobAfter} {
+		thickness = utils.MaxFloat32(thickness, self.thicknessOf(drawable))
+	}
+	return thickness
+}
+
+func (self *ProgressBar) GetPrefWidth() float32 {
+	if self.vertical {
+		return self.prefThickness()
+	}
+	return 140
+}
+
+func (self *ProgressBar) GetPrefHeight() float32 {
+	if self.vertical {
+		return 140
+	}
+	return self.prefThickness()
+}
+
+func (self *ProgressBar) GetMinWidth() float32 {
+	return self.GetPrefWidth()
+}
+
+func (self *ProgressBar) GetMinHeight() float32 {
+	return self.GetPrefHeight()
+}
+
+func (self *ProgressBar) GetMaxWidth() float32 {
+	return 0
+}
+
+func (self *ProgressBar) GetMaxHeight() float32 {
+	return 0
+}
+
+// The style of a slider, the knob is drawn with the knob over and knob down drawables while the mouse is over it and
+// while it is dragged, if they are set
+type SliderStyle struct {
+	ProgressBarStyle
+
+	KnobOver, KnobDown ui.Drawable
+}
+
+// A Slider is a progress bar whose value is set by dragging its knob, or by touching the bar
+type Slider struct {
+	ProgressBar
+
+	style    *SliderStyle
+	dragging bool
+	over     bool
+	pointer  uint8
+
+	// Called when the knob is grabbed and when it is released
+	OnDragStart, OnDragStop func(self *Slider)
+}
+
+func NewSlider(min, max, stepSize float32, vertical bool, style *SliderStyle) *Slider {
+	self := &Slider{style: style}
+	self.initProgressBar(min, max, stepSize, vertical, &style.ProgressBarStyle)
+	self.knobDrawable = self.getKnob
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	return self
+}
+
+func (self *Slider) GetStyle() *SliderStyle {
+	return self.style
+}
+
+// Returns whether the knob is being dragged
+func (self *Slider) IsDragging() bool {
+	return self.dragging
+}
+
+// Returns whether the mouse is over the slider
+func (self *Slider) IsOver() bool {
+	return self.over
+}
+
+func (self *Slider) getKnob() ui.Drawable {
+	style := self.style
+	switch {
+	case self.disabled && style.DisabledKnob != nil:
+		return style.DisabledKnob
+	case self.dragging && style.KnobDown != nil:
+		return style.KnobDown
+	case self.over && style.KnobOver != nil:
+		return style.KnobOver
+	}
+	return style.Knob
+}
+
+// Sets the value to the position of the touch, the knob is centered on it
+func (self *Slider) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if self.disabled || self.dragging || !self.Hit(e.X, e.Y, widgetPoint) {
+			return
+		}
+		self.dragging, self.pointer = true, e.Pointer
+		if self.OnDragStart != nil {
+			self.OnDragStart(self)
+		}
+		self.valueAt(widgetPoint.X, widgetPoint.Y)
+	case TouchDragged:
+		if self.dragging && e.Pointer == self.pointer {
+			self.Hit(e.X, e.Y, widgetPoint)
+			self.valueAt(widgetPoint.X, widgetPoint.Y)
+		}
+	case TouchUp:
+		if !self.dragging || e.Pointer != self.pointer {
+			return
+		}
+		self.Hit(e.X, e.Y, widgetPoint)
+		self.valueAt(widgetPoint.X, widgetPoint.Y)
+		self.dragging = false
+		if self.OnDragStop != nil {
+			self.OnDragStop(self)
+		}
+	case MouseMoved:
+		self.over = self.Hit(e.X, e.Y, widgetPoint)
+	}
+}
+
+// Sets the value to the one whose knob is centered at the point in the coordinates of the slider
+func (self *Slider) valueAt(x, y float32) {
+	background := self.stateDrawable(self.style.Background, self.style.DisabledBackground)
+	start, end := self.backgroundPads(background)
+	knobSize := self.lengthOf(self.getKnob())
+	size, position := self.W, x
+	if self.vertical {
+		size, position = self.H, y
+	}
+	track := size - start - end - knobSize
+	if track <= 0 {
+		return
+	}
+	percent := (position - start - knobSize/2) / track
+	self.setValue(self.min+(self.max-self.min)*percent, true)
+}
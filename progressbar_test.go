@@ -0,0 +1,120 @@
+package spike
+
+import (
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+// Returns a slider 110 pixels long with a knob 10 pixels long, so the value goes along 100 pixels
+func newTestSlider(min, max, stepSize float32, vertical bool) *Slider {
+	knob := ui.NewRegionDrawable(&g2d.AtlasRegion{OriginalWidth: 10, OriginalHeight: 10})
+	slider := NewSlider(min, max, stepSize, vertical, &SliderStyle{ProgressBarStyle: ProgressBarStyle{Knob: knob}})
+	if vertical {
+		slider.SetSize(10, 110)
+	} else {
+		slider.SetSize(110, 10)
+	}
+	return slider
+}
+
+func TestSliderSteps(t *testing.T) {
+	slider := newTestSlider(0, 10, 2, false)
+	var changes []float32
+	slider.OnChange = func(bar *ProgressBar) {
+		changes = append(changes, bar.GetValue())
+	}
+	dragging := 0
+	slider.OnDragStart = func(s *Slider) { dragging++ }
+	slider.OnDragStop = func(s *Slider) { dragging-- }
+	// the knob is centered on the touch and the value is rounded to the nearest step
+	slider.input(InputEvent{Type: TouchDown, X: 38, Y: 5})
+	if slider.GetValue() != 4 || !slider.IsDragging() || dragging != 1 {
+		t.Errorf("touched at %v dragging %v", slider.GetValue(), slider.IsDragging())
+	}
+	slider.input(InputEvent{Type: TouchDragged, X: 100, Y: 50, Pointer: 1})
+	slider.input(InputEvent{Type: TouchDragged, X: 100, Y: 50})
+	if slider.GetValue() != 10 {
+		t.Errorf("dragged to %v", slider.GetValue())
+	}
+	slider.input(InputEvent{Type: TouchUp, X: -40, Y: 5})
+	if slider.GetValue() != 0 || slider.IsDragging() || dragging != 0 {
+		t.Errorf("released at %v dragging %v", slider.GetValue(), slider.IsDragging())
+	}
+	if len(changes) != 3 || changes[0] != 4 || changes[1] != 10 || changes[2] != 0 {
+		t.Errorf("changes %v", changes)
+	}
+
+	// the steps start at min
+	stepped := NewProgressBar(1, 10, 3, false, &ProgressBarStyle{})
+	for _, step := range [][2]float32{{5, 4}, {5.6, 7}, {9, 10}, {-20, 1}, {20, 10}} {
+		stepped.SetValue(step[0])
+		if stepped.GetValue() != step[1] {
+			t.Errorf("set to %v is %v, expected %v", step[0], stepped.GetValue(), step[1])
+		}
+	}
+	if stepped.SetValue(10.5) {
+		t.Error("changed to the same step")
+	}
+	// the range is kept also when its end is not a step
+	stepped.SetRange(1, 5)
+	if stepped.GetValue() != 5 {
+		t.Errorf("clamped to the range at %v", stepped.GetValue())
+	}
+
+	// vertical sliders go up, disabled sliders are not dragged
+	vertical := newTestSlider(0, 100, 0, true)
+	vertical.input(InputEvent{Type: TouchDown, X: 5, Y: 30})
+	vertical.input(InputEvent{Type: TouchUp, X: 5, Y: 30})
+	if vertical.GetValue() != 25 {
+		t.Errorf("vertical slider at %v", vertical.GetValue())
+	}
+	vertical.SetDisabled(true)
+	vertical.input(InputEvent{Type: TouchDown, X: 5, Y: 80})
+	if vertical.GetValue() != 25 || vertical.IsDragging() {
+		t.Errorf("disabled slider dragged to %v", vertical.GetValue())
+	}
+}
+
+func TestProgressBarAnimation(t *testing.T) {
+	bar := NewProgressBar(0, 100, 0, false, &ProgressBarStyle{})
+	bar.SetValue(40)
+	if bar.IsAnimating() || bar.GetVisualValue() != 40 {
+		t.Errorf("not animated bar at %v", bar.GetVisualValue())
+	}
+
+	bar.SetAnimateDuration(1)
+	bar.SetValue(80)
+	if !bar.IsAnimating() || bar.GetValue() != 80 || bar.GetVisualValue() != 40 {
+		t.Errorf("animation started at %v", bar.GetVisualValue())
+	}
+	bar.Act(&bar.Actor, 0.25)
+	if !near(bar.GetVisualValue(), 50, 0.001) || !near(bar.GetVisualPercent(), 0.5, 0.001) || bar.GetPercent() != 0.8 {
+		t.Errorf("a quarter of the way at %v", bar.GetVisualValue())
+	}
+	// a new value is animated from where the knob is
+	bar.SetValue(0)
+	bar.Act(&bar.Actor, 0.5)
+	if !near(bar.GetVisualValue(), 25, 0.001) {
+		t.Errorf("halfway back at %v", bar.GetVisualValue())
+	}
+	bar.Act(&bar.Actor, 0.6)
+	if bar.IsAnimating() || bar.GetVisualValue() != 0 {
+		t.Errorf("animation ended at %v", bar.GetVisualValue())
+	}
+
+	// the knob is drawn at the visual value
+	slider := newTestSlider(0, 100, 0, false)
+	slider.SetAnimateDuration(2)
+	slider.SetValue(100)
+	slider.Act(&slider.Actor, 0.5)
+	slider.draw(tempBatch, 1)
+	if !near(slider.GetKnobPosition(), 25, 0.001) {
+		t.Errorf("knob drawn at %v", slider.GetKnobPosition())
+	}
+	slider.UpdateVisualValue()
+	if slider.IsAnimating() || slider.GetVisualValue() != 100 {
+		t.Errorf("visual value updated to %v", slider.GetVisualValue())
+	}
+}
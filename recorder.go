@@ -0,0 +1,318 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/pyros2097/spike/math/utils"
+)
+
+// Sessions can be recorded for bug reports and replayed later to reproduce them exactly. A recording holds every input
+// event from the platform along with the frame it was processed in and its time, the time and delta of every frame and
+// the seed of the random number generator. When replayed the events go through the gesture detector and the actors again,
+// so as long as the game only uses the Random functions from math/utils the actors end up in the same state.
+//
+//   spike.StartRecording(time.Now().UnixNano())
+//   ...
+//   recording := spike.StopRecording()
+//   recording.WriteBinary(file)
+//
+//   recording, err := spike.ReadRecording(file)
+//   spike.PlayRecording(recording)
+
+// An input event as it was received from the platform
+type RecordedEvent struct {
+	// The frame in which the event was processed
+	Frame uint32
+
+	// The time of the event in nanoseconds
+	Time int64
+
+	Event InputEvent
+}
+
+// A frame of a recording
+type RecordedFrame struct {
+	// The time at which the frame started in nanoseconds
+	Time int64
+
+	// The delta the actors were updated with
+	Delta float32
+}
+
+// A Recording holds everything needed to replay a session
+type Recording struct {
+	Seed   int64
+	Frames []RecordedFrame
+	Events []RecordedEvent
+}
+
+var (
+	recording     *Recording
+	currentReplay *Replay
+
+	// Identifies the binary recording format, followed by its version
+	recordingMagic   = []byte("SPKR")
+	recordingVersion = uint8(1)
+)
+
+// StartRecording starts recording the input. The random number generator is seeded with seed, which is saved along with
+// the recording.
+func StartRecording(seed int64) {
+	utils.SetSeed(seed)
+	resetInput()
+	recording = &Recording{Seed: seed}
+}
+
+// StopRecording stops recording the input and returns the recording
+func StopRecording() *Recording {
+	r := recording
+	recording = nil
+	return r
+}
+
+// Returns true if the input is being recorded
+func IsRecording() bool {
+	return recording != nil
+}
+
+func recordInput(e InputEvent) {
+	if recording != nil {
+		recording.Events = append(recording.Events, RecordedEvent{
+			Frame: uint32(len(recording.Frames)),
+			Time:  inputClock(),
+			Event: e,
+		})
+	}
+}
+
+func recordFrame(delta float32) {
+	if recording != nil {
+		recording.Frames = append(recording.Frames, RecordedFrame{
+			Time:  frameTime,
+			Delta: delta,
+		})
+	}
+}
+
+// Writes the recording in the compact binary format
+func (self *Recording) WriteBinary(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	buf.Write(recordingMagic)
+	buf.WriteByte(recordingVersion)
+	binary.Write(buf, binary.LittleEndian, self.Seed)
+	binary.Write(buf, binary.LittleEndian, uint32(len(self.Frames)))
+	for _, frame := range self.Frames {
+		binary.Write(buf, binary.LittleEndian, frame.Time)
+		binary.Write(buf, binary.LittleEndian, frame.Delta)
+	}
+	binary.Write(buf, binary.LittleEndian, uint32(len(self.Events)))
+	for _, recorded := range self.Events {
+		e := recorded.Event
+		binary.Write(buf, binary.LittleEndian, recorded.Frame)
+		binary.Write(buf, binary.LittleEndian, recorded.Time)
+		buf.Write([]byte{byte(e.Type), e.Pointer, e.Button, byte(e.KeyCode), byte(e.Axis), byte(e.Pov), e.Controller})
+		binary.Write(buf, binary.LittleEndian, e.X)
+		binary.Write(buf, binary.LittleEndian, e.Y)
+		binary.Write(buf, binary.LittleEndian, e.ScreenX)
+		binary.Write(buf, binary.LittleEndian, e.ScreenY)
+		binary.Write(buf, binary.LittleEndian, e.DeltaX)
+		binary.Write(buf, binary.LittleEndian, e.DeltaY)
+		binary.Write(buf, binary.LittleEndian, int32(e.ScrollAmount))
+		binary.Write(buf, binary.LittleEndian, e.Value)
+		binary.Write(buf, binary.LittleEndian, int32(e.Character))
+		if len(e.Text) > math.MaxUint16 {
+			return errors.New("recording: the text of an event is longer than " + strconv.Itoa(math.MaxUint16) + " bytes")
+		}
+		binary.Write(buf, binary.LittleEndian, uint16(len(e.Text)))
+		buf.WriteString(e.Text)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Writes the recording as JSON
+func (self *Recording) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(self)
+}
+
+// ReadRecording reads a recording written with WriteBinary or WriteJSON
+func ReadRecording(r io.Reader) (*Recording, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(recordingMagic))
+	if err == nil && bytes.Equal(magic, recordingMagic) {
+		return readBinaryRecording(br)
+	}
+	rec := &Recording{}
+	if err := json.NewDecoder(br).Decode(rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func readBinaryRecording(r io.Reader) (*Recording, error) {
+	header := make([]byte, len(recordingMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if version := header[len(recordingMagic)]; version != recordingVersion {
+		return nil, errors.New("recording: unsupported version " + strconv.Itoa(int(version)) + ", expected " +
+			strconv.Itoa(int(recordingVersion)))
+	}
+	rec := &Recording{}
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &rec.Seed); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+	// the counts are not used to allocate, a damaged file could ask for gigabytes before it fails to read
+	for i := uint32(0); i < count; i++ {
+		var frame RecordedFrame
+		if err := binary.Read(r, binary.LittleEndian, &frame); err != nil {
+			return nil, err
+		}
+		rec.Frames = append(rec.Frames, frame)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < count; i++ {
+		var raw struct {
+			Frame                          uint32
+			Time                           int64
+			Type, Pointer, Button, KeyCode uint8
+			Axis, Pov, Controller          uint8
+			X, Y, ScreenX, ScreenY         float32
+			DeltaX, DeltaY                 float32
+			ScrollAmount                   int32
+			Value                          float32
+			Character                      int32
+			TextLength                     uint16
+		}
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			return nil, err
+		}
+		text := make([]byte, raw.TextLength)
+		if _, err := io.ReadFull(r, text); err != nil {
+			return nil, err
+		}
+		rec.Events = append(rec.Events, RecordedEvent{
+			Frame: raw.Frame,
+			Time:  raw.Time,
+			Event: InputEvent{
+				Type:         InputType(raw.Type),
+				X:            raw.X,
+				Y:            raw.Y,
+				ScreenX:      raw.ScreenX,
+				ScreenY:      raw.ScreenY,
+				DeltaX:       raw.DeltaX,
+				DeltaY:       raw.DeltaY,
+				Pointer:      raw.Pointer,
+				Button:       raw.Button,
+				KeyCode:      KeyCode(raw.KeyCode),
+				Character:    rune(raw.Character),
+				Text:         string(text),
+				ScrollAmount: int(raw.ScrollAmount),
+				Axis:         ControllerAxis(raw.Axis),
+				Value:        raw.Value,
+				Pov:          PovDirection(raw.Pov),
+				Controller:   raw.Controller,
+			},
+		})
+	}
+	return rec, nil
+}
+
+// A Replay feeds a recording to the gesture detector and the actors of a scene, one frame at a time.
+type Replay struct {
+	recording *Recording
+	frame     int
+	event     int
+	time      int64
+	liveClock func() int64
+}
+
+// Creates a replay of the recording. Nothing happens until the first Step.
+func NewReplay(rec *Recording) *Replay {
+	return &Replay{recording: rec}
+}
+
+// PlayRecording replays the recording in the running game. The input from the platform is ignored until the replay
+// is done.
+func PlayRecording(rec *Recording) {
+	currentReplay = NewReplay(rec)
+}
+
+// Returns true if a recording is being replayed in the running game
+func IsReplaying() bool {
+	return currentReplay != nil
+}
+
+func (self *Replay) begin() {
+	utils.SetSeed(self.recording.Seed)
+	resetInput()
+	self.liveClock = inputClock
+	inputClock = func() int64 {
+		return self.time
+	}
+}
+
+func (self *Replay) end() {
+	if self.liveClock != nil {
+		inputClock = self.liveClock
+		self.liveClock = nil
+	}
+	if currentReplay == self {
+		currentReplay = nil
+	}
+}
+
+// Returns true once all the frames have been replayed
+func (self *Replay) Done() bool {
+	return self.frame >= len(self.recording.Frames)
+}
+
+// Step feeds the events of the next frame and updates the scene with the recorded delta. It returns false once the
+// replay is done.
+func (self *Replay) Step(scene *Scene) bool {
+	if self.Done() {
+		self.end()
+		return false
+	}
+	if self.frame == 0 && self.event == 0 {
+		self.begin()
+	}
+	events := self.recording.Events
+	for self.event < len(events) && int(events[self.event].Frame) <= self.frame {
+		self.time = events[self.event].Time
+		dispatchInput(events[self.event].Event)
+		self.event++
+	}
+	frame := self.recording.Frames[self.frame]
+	self.time = frame.Time
+	update(scene, frame.Delta)
+	self.frame++
+	if self.Done() {
+		self.end()
+		return false
+	}
+	return true
+}
+
+// Run replays all the frames on the scene without drawing it
+func (self *Replay) Run(scene *Scene) {
+	for self.Step(scene) {
+	}
+}
@@ -0,0 +1,178 @@
+package spike
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pyros2097/spike/math/utils"
+)
+
+// A scene with a single actor which moves with the input and jitters randomly
+func newRecorderScene() (*Scene, *Actor) {
+	player := &Actor{Name: "player"}
+	player.Input = func(a *Actor, e InputEvent) {
+		switch e.Type {
+		case TouchDown, TouchDragged:
+			a.X, a.Y = e.X, e.Y
+		case Tap, LongPress, SwipeLeft, SwipeRight, SwipeUp, SwipeDown:
+			a.Z += uint32(e.Type)
+		case KeyDown:
+			a.Rotation += float32(e.KeyCode)
+		}
+	}
+	player.Act = func(a *Actor, delta float32) {
+		if ActionPressed("jump") {
+			a.H += 10 * delta
+		}
+		a.W += utils.RandomFloat() * delta
+	}
+	scene := &Scene{Name: "recorder"}
+	scene.Children = []*Actor{player}
+	return scene, player
+}
+
+func recordSession(t *testing.T) (*Recording, Actor) {
+	now := int64(0)
+	inputClock = func() int64 {
+		return now
+	}
+	scene, player := newRecorderScene()
+	StartRecording(42)
+	delta := float32(1.0 / 60)
+	for frame := 0; frame < 120; frame++ {
+		switch frame {
+		case 5:
+			feedInput(newTouchEvent(TouchDown, 10, 10, 0))
+		case 6:
+			feedInput(newTouchEvent(TouchUp, 10, 10, 0))
+		case 20:
+			feedInput(InputEvent{Type: KeyDown, KeyCode: KeySpace})
+		case 40:
+			feedInput(InputEvent{Type: KeyUp, KeyCode: KeySpace})
+		case 50:
+			feedInput(newTouchEvent(TouchDown, 100, 100, 0))
+		case 90:
+			feedInput(newTouchEvent(TouchDragged, 120, 100, 0))
+			feedInput(newTouchEvent(TouchUp, 120, 100, 0))
+		}
+		update(scene, delta)
+		now += int64(delta * 1000000000)
+	}
+	rec := StopRecording()
+	if len(rec.Frames) != 120 || len(rec.Events) != 7 {
+		t.Fatalf("recorded %d frames and %d events", len(rec.Frames), len(rec.Events))
+	}
+	return rec, *player
+}
+
+func replaySession(rec *Recording) Actor {
+	scene, player := newRecorderScene()
+	NewReplay(rec).Run(scene)
+	return *player
+}
+
+func sameState(a, b Actor) bool {
+	return a.X == b.X && a.Y == b.Y && a.W == b.W && a.H == b.H && a.Z == b.Z && a.Rotation == b.Rotation
+}
+
+func TestReplay(t *testing.T) {
+	liveClock := inputClock
+	defer func() {
+		inputClock = liveClock
+		delete(actionBindings, "jump")
+		resetInput()
+	}()
+	BindAction("jump", NewKeyBinding(KeySpace))
+
+	rec, recorded := recordSession(t)
+	if recorded.Z == 0 || recorded.H == 0 || recorded.Rotation == 0 {
+		t.Errorf("input was not processed while recording %+v", recorded)
+	}
+
+	if replayed := replaySession(rec); !sameState(recorded, replayed) {
+		t.Errorf("replay diverged: recorded %+v, replayed %+v", recorded, replayed)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := rec.WriteBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	binaryRec, err := ReadRecording(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed := replaySession(binaryRec); !sameState(recorded, replayed) {
+		t.Errorf("binary replay diverged: recorded %+v, replayed %+v", recorded, replayed)
+	}
+
+	buf.Reset()
+	if err := rec.WriteJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	jsonRec, err := ReadRecording(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed := replaySession(jsonRec); !sameState(recorded, replayed) {
+		t.Errorf("json replay diverged: recorded %+v, replayed %+v", recorded, replayed)
+	}
+}
+
+func TestRecordingVersion(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := (&Recording{Seed: 7}).WriteBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if data[len(recordingMagic)] != recordingVersion {
+		t.Fatalf("written with version %d", data[len(recordingMagic)])
+	}
+	for _, version := range []uint8{0, recordingVersion + 1} {
+		data[len(recordingMagic)] = version
+		if _, err := ReadRecording(bytes.NewReader(data)); err == nil ||
+			!strings.Contains(err.Error(), "unsupported version") {
+			t.Errorf("version %d read with error %v", version, err)
+		}
+	}
+}
+
+func TestRecordingLimits(t *testing.T) {
+	long := &Recording{Events: []RecordedEvent{{Event: InputEvent{Type: TextInput, Text: strings.Repeat("a", 70000)}}}}
+	if err := long.WriteBinary(&bytes.Buffer{}); err == nil {
+		t.Error("text longer than the binary format allows written")
+	}
+
+	// a damaged frame count fails when the frames run out
+	buf := &bytes.Buffer{}
+	if err := (&Recording{Seed: 7}).WriteBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint32(data[len(recordingMagic)+1+8:], math.MaxUint32)
+	if _, err := ReadRecording(bytes.NewReader(data)); err == nil {
+		t.Error("recording with a damaged frame count read")
+	}
+}
+
+func TestRecordingFields(t *testing.T) {
+	event := InputEvent{Type: Fling, X: 1, Y: 2, ScreenX: 3, ScreenY: 4, DeltaX: 5, DeltaY: 6, Pointer: 7, Button: 8,
+		KeyCode: KeyA, Character: 'x', Text: "text", ScrollAmount: -9, Axis: AxisRightY, Value: 0.5, Pov: PovNorth,
+		Controller: 2}
+	rec := &Recording{Seed: 7, Frames: []RecordedFrame{{Time: 10, Delta: 0.25}},
+		Events: []RecordedEvent{{Frame: 0, Time: 11, Event: event}}}
+	buf := &bytes.Buffer{}
+	if err := rec.WriteBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadRecording(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Seed != 7 || len(read.Frames) != 1 || read.Frames[0] != rec.Frames[0] || len(read.Events) != 1 ||
+		read.Events[0] != rec.Events[0] {
+		t.Errorf("read %+v", read)
+	}
+}
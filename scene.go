@@ -5,7 +5,11 @@
 package spike
 
 import (
+	"math"
 	"time"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/collision"
 )
 
 var (
@@ -42,6 +46,27 @@ type Scene struct {
 	Name    string
 	BGColor Color
 
+	// If not nil the background is a vertical gradient from BGColor at the bottom to it at the top, see SetBackgroundGradient
+	BGTopColor *Color
+
+	// If not nil the background and the actors are drawn through its shader passes, see NewPostProcessor
+	PostProcessor *PostProcessor
+
+	// If set a grid of GridSize world units is drawn over the scene, with the axes in GridColor
+	ShowGrid  bool
+	GridSize  float32
+	GridColor Color
+
+	// The actor outlined in SelectionColor, if not nil
+	Selection *Actor
+
+	// The root of the HUD actors and how they are anchored to the screen, see AddHud
+	hud        *Actor
+	hudAnchors []hudAnchor
+
+	// The images drawn behind the actors, from the back to the front
+	backgroundLayers []*BackgroundLayer
+
 	OnPause  func(self *Scene)
 	OnResume func(self *Scene)
 
@@ -53,26 +78,49 @@ type Scene struct {
 	TransitionOut func(scene *Scene)
 }
 
-func (self *Scene) SetBackground(texName string) {
-	// 		if(Asset.tex(texName) != null){
-	// 			imgbg = new Image(new TextureRegionDrawable(Asset.tex(texName)), Scaling.stretch);
-	// 			imgbg.setFillParent(true);
-	// 			stage2d.addActor(imgbg);
-	// 			imgbg.toBack();
-	// 		}
-}
-
-func (self *Scene) RemoveBackground() {
-	// self.RemoveActor()
-}
-
-func (self *Scene) AddHud() {
+// Draws the lines of the grid across the view of the camera, the axes are brighter than the other lines. It draws between
+// Begin and End of the shape renderer.
+func (self *Scene) DrawGrid(shapes *g2d.ShapeRenderer) {
+	size := self.GridSize
+	if size <= 0 {
+		size = 32
+	}
+	camera := currentCamera()
+	halfW, halfH := camera.ViewportWidth*camera.Zoom/2, camera.ViewportHeight*camera.Zoom/2
+	left, right := camera.Position.X-halfW, camera.Position.X+halfW
+	bottom, top := camera.Position.Y-halfH, camera.Position.Y+halfH
+	shapes.SetTransformMatrix(identityMatrix)
+	color := self.GridColor
+	if color == (Color{}) {
+		color = Color{0.5, 0.5, 0.5, 1}
+	}
+	for x := float32(math.Floor(float64(left/size))) * size; x <= right; x += size {
+		self.setGridColor(shapes, color, x == 0)
+		shapes.Line(x, bottom, x, top)
+	}
+	for y := float32(math.Floor(float64(bottom/size))) * size; y <= top; y += size {
+		self.setGridColor(shapes, color, y == 0)
+		shapes.Line(left, y, right, y)
+	}
 }
 
-func (self *Scene) DrawGrid() {
+func (self *Scene) setGridColor(shapes *g2d.ShapeRenderer, color Color, axis bool) {
+	if axis {
+		shapes.SetColor(color.R, color.G, color.B, color.A)
+	} else {
+		shapes.SetColor(color.R, color.G, color.B, color.A*0.4)
+	}
 }
 
-func (self *Scene) DrawSelection() {
+// Outlines the bounds of the selected actor and marks its origin. It draws between Begin and End of the shape renderer.
+func (self *Scene) DrawSelection(shapes *g2d.ShapeRenderer) {
+	if self.Selection == nil || self.Selection.Hidden {
+		return
+	}
+	shapes.SetTransformMatrix(self.Selection.ComputeTransform())
+	shapes.SetColor(SelectionColor.R, SelectionColor.G, SelectionColor.B, SelectionColor.A)
+	shapes.Rect(0, 0, self.Selection.W, self.Selection.H)
+	shapes.X(self.Selection.OX, self.Selection.OY, 8)
 }
 
 func init() {
@@ -160,8 +208,12 @@ func (self *Scene) AddActorWithDelay(actor *Actor, duration time.Duration) {
 }
 
 func (self *Scene) RemoveActor(actor *Actor) {
-	i := actor.Z
-	self.Children, self.Children[len(self.Children)-1] = append(self.Children[:i], self.Children[i+1:]...), nil
+	for i, child := range self.Children {
+		if child == actor {
+			self.Children, self.Children[len(self.Children)-1] = append(self.Children[:i], self.Children[i+1:]...), nil
+			return
+		}
+	}
 }
 
 func (self *Scene) RemoveActorWithDelay(actor *Actor, duration time.Duration) {
@@ -172,44 +224,88 @@ func (self *Scene) RemoveActorWithName(name string) {
 	// return removeActor(findActor(actorName));
 }
 
-// func (self *Scene) Hit(x, y float32) *Actor {
-// }
+// HitRay returns the visible and touchable actor whose bounds are hit by the pick ray closest to its origin, or nil. Actors
+// without Bounds3d are hit as their rectangle at z 0, so with a 2D camera the actor on top is returned.
+func (self *Scene) HitRay(ray *collision.Ray) *Actor {
+	hit, _ := self.hitRay(ray, nil, 0)
+	return hit
+}
 
-// public Actor hit(float x, float y){
-// 	return hit(x, y, true);
-// }
+// Pick returns the actor at the screen coordinates using a pick ray of the viewport camera, or nil. The screen origin is
+// the upper left corner like the ScreenX and ScreenY of the input events.
+//
+//	if e.Type == spike.TouchDown {
+//	  selected = scene.Pick(e.ScreenX, e.ScreenY)
+//	}
+func (self *Scene) Pick(screenX, screenY float32) *Actor {
+	return self.HitRay(viewport.GetPickRay(screenX, screenY))
+}
 
 // func AddActor3d() {
 // }
 
-// 	public void showToast(String message, float duration){
-// 		Table table = new Table(Asset.skin);
-// 		table.add("   "+message+"   ");
-// 		table.setBackground(Asset.skin.getDrawable("dialogDim"));
-// 		table.pack();
-// 		table.setPosition(Scene.targetWidth/2 - table.getWidth(), Scene.targetHeight/2 - table.getHeight());
-// 		addActor(table);
-// 		table.addAction(Actions.sequence(Actions.delay(duration), Actions.removeActor(table)));
-// 	}
+// How many seconds a toast takes to fade out at the end of its duration
+var ToastFadeTime float32 = 0.3
 
-// 	public void showMessageDialog(String title, String message){
-// 		Dialog dialog = new Dialog(title, Asset.skin);
-// 		dialog.getContentTable().add(message);
-// 		dialog.button("OK", "OK");
-// 		dialog.pack();
-// 		dialog.show(getStage());
-// 	}
+// Shows the message in the center of the scene for the duration in seconds, then it fades out and is removed. It is
+// drawn with the default LabelStyle of the skin over its dialogDim drawable, if it has one.
+func (self *Scene) ShowToast(skin *Skin, message string, duration float32) *Table {
+	table := NewTable()
+	if skin.Has("Drawable", "dialogDim") {
+		table.SetBackground(skin.GetDrawable("dialogDim"))
+	}
+	label := skin.NewLabel(message, "default")
+	table.Add(&label.Actor).Pad(8)
+	table.Pack()
+	width, height := worldSize()
+	table.SetPosition(float32(int((width-table.W)/2)), float32(int((height-table.H)/2)))
+	// the label shares the color of the table so that they fade together
+	table.Color = NewColor(1, 1, 1, 1)
+	label.Color = table.Color
+	time := float32(0)
+	table.Act = func(a *Actor, delta float32) {
+		time += delta
+		if time >= duration {
+			self.RemoveActor(a)
+		} else if remaining := duration - time; remaining < ToastFadeTime {
+			a.Color.A = remaining / ToastFadeTime
+		}
+	}
+	self.AddActor(&table.Actor)
+	return table
+}
 
-// 	public boolean showConfirmDialog(String title, String message){
-// 		Dialog dialog = new Dialog(title, Asset.skin);
-// 		dialog.button("Yes", "Yes");
-// 		dialog.button("No", "No");
-// 		dialog.pack();
-// 		dialog.show(getStage());
-// 		//if(dialog.result().equals("Yes")) FIXME update Gdx
-// 		//	return true;
-// 		return false;
-// 	}
+// Shows a dialog with the message and an OK button, onClose is called when it is closed and may be nil. The dialog is
+// made with the default styles of the skin and is also closed with the enter and escape keys.
+func (self *Scene) ShowMessageDialog(skin *Skin, title, message string, onClose func()) *Dialog {
+	dialog := skin.NewDialog(title, "default")
+	dialog.Text(message, skin.GetLabelStyle("default"))
+	dialog.Button("OK", nil, skin.GetTextButtonStyle("default"))
+	dialog.Key(KeyEnter, nil).Key(KeyEscape, nil)
+	dialog.OnResult = func(d *Dialog, result interface{}) {
+		if onClose != nil {
+			onClose()
+		}
+	}
+	return dialog.Show(self)
+}
+
+// Shows a dialog with the message and Yes and No buttons, onResult is called with whether Yes was clicked once it is
+// closed. The scene keeps running while the dialog is shown, so the answer is only known in onResult. The dialog is made
+// with the default styles of the skin, the enter key answers yes and the escape key no.
+func (self *Scene) ShowConfirmDialog(skin *Skin, title, message string, onResult func(confirmed bool)) *Dialog {
+	dialog := skin.NewDialog(title, "default")
+	dialog.Text(message, skin.GetLabelStyle("default"))
+	buttonStyle := skin.GetTextButtonStyle("default")
+	dialog.Button("Yes", true, buttonStyle).Button("No", false, buttonStyle)
+	dialog.Key(KeyEnter, true).Key(KeyEscape, false)
+	dialog.OnResult = func(d *Dialog, result interface{}) {
+		if onResult != nil {
+			onResult(result == true)
+		}
+	}
+	return dialog.Show(self)
+}
 
 // 	public void outline(Actor actor){
 // 		selectionBox.setPosition(actor.getX(), actor.getY());
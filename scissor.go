@@ -0,0 +1,83 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/utils"
+	"github.com/pyros2097/spike/math/vector"
+	"golang.org/x/mobile/gl"
+)
+
+var (
+	// The scissor rectangles in screen pixels, each is within the one before it and the last one is applied
+	scissors []shape.Rectangle
+
+	scissorPoint = vector.NewVector2Empty()
+)
+
+// Pushes the rectangle in screen pixels to the scissor stack, it is clipped to the rectangle on top of the stack. Returns
+// false without pushing it if the clipped rectangle is empty.
+func pushScissors(rect shape.Rectangle) bool {
+	x1, y1 := float32(utils.Round(rect.X)), float32(utils.Round(rect.Y))
+	x2, y2 := float32(utils.Round(rect.X+rect.W)), float32(utils.Round(rect.Y+rect.H))
+	if n := len(scissors); n > 0 {
+		top := &scissors[n-1]
+		x1, y1 = utils.MaxFloat32(x1, top.X), utils.MaxFloat32(y1, top.Y)
+		x2, y2 = utils.MinFloat32(x2, top.X+top.W), utils.MinFloat32(y2, top.Y+top.H)
+	}
+	if x2-x1 < 1 || y2-y1 < 1 {
+		return false
+	}
+	scissors = append(scissors, shape.Rectangle{X: x1, Y: y1, W: x2 - x1, H: y2 - y1})
+	applyScissors()
+	return true
+}
+
+// Pops the rectangle on top of the scissor stack, the one below it is applied again
+func popScissors() {
+	scissors = scissors[:len(scissors)-1]
+	applyScissors()
+}
+
+// Sets the scissor test of the GL context to the rectangle on top of the stack, it is disabled if the stack is empty
+func applyScissors() {
+	if drawContext == nil {
+		return
+	}
+	if len(scissors) == 0 {
+		drawContext.Disable(gl.SCISSOR_TEST)
+		return
+	}
+	rect := &scissors[len(scissors)-1]
+	drawContext.Enable(gl.SCISSOR_TEST)
+	drawContext.Scissor(int32(rect.X), int32(rect.Y), int32(rect.W), int32(rect.H))
+}
+
+// Returns the rectangle in screen pixels of the rectangle in the actor's coordinates, the origin of the screen is the lower
+// left corner like for the scissor test. The actor and the camera must not be rotated.
+func calculateScissors(actor *Actor, x, y, width, height float32) shape.Rectangle {
+	x1, y1 := scissorPosition(actor, x, y)
+	x2, y2 := scissorPosition(actor, x+width, y+height)
+	left, bottom := utils.MinFloat32(x1, x2), utils.MinFloat32(y1, y2)
+	return shape.Rectangle{X: left, Y: bottom, W: utils.MaxFloat32(x1, x2) - left, H: utils.MaxFloat32(y1, y2) - bottom}
+}
+
+// Returns the screen position of the point in the actor's coordinates
+func scissorPosition(actor *Actor, x, y float32) (float32, float32) {
+	actor.LocalToStageCoordinates(scissorPoint.Set(x, y))
+	if inHud {
+		return scissorPoint.X / HudUnitsPerPixel, scissorPoint.Y / HudUnitsPerPixel
+	}
+	if viewport != nil {
+		viewport.Project(scissorPoint)
+		if len(boundFrameBuffers) > 0 {
+			// a frame buffer starts at its lower left corner instead of at the screen bounds of the viewport
+			scissorPoint.X -= float32(viewport.ScreenX)
+			scissorPoint.Y -= float32(viewport.ScreenY)
+		}
+	}
+	return scissorPoint.X, scissorPoint.Y
+}
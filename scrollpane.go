@@ -0,0 +1,691 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/interpolation"
+	"github.com/pyros2097/spike/math/shape"
+	"github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+// The style of a scroll pane, all its drawables are optional. The scroll bars take no space without their drawables.
+type ScrollPaneStyle struct {
+	Background ui.Drawable
+
+	// Drawn where the scroll bars meet when both are shown
+	Corner ui.Drawable
+
+	HScroll, HScrollKnob ui.Drawable
+	VScroll, VScrollKnob ui.Drawable
+}
+
+// A ScrollPane shows a part of an actor which is larger than it and scrolls it. The actor is clipped to the pane and its
+// children outside of the visible part are culled. It is scrolled by dragging and flinging it, with the mouse wheel, by
+// dragging the knobs of the scroll bars and with ScrollTo. It can be dragged and flung past its edges, it then bounces
+// back to them.
+//
+//	list := spike.NewTable()
+//	for _, level := range levels {
+//	  list.Add(&spike.NewTextButton(level.Name, buttonStyle).Actor).Row()
+//	}
+//	pane := spike.NewScrollPane(&list.Actor, paneStyle)
+//	pane.SetScrollingDisabled(true, false)
+type ScrollPane struct {
+	Actor
+	widget
+
+	style    *ScrollPaneStyle
+	content  *Actor
+	cullable ui.Cullable
+
+	// the part of the pane the actor is shown in and the visible part of the actor in its coordinates
+	widgetArea, cullingArea shape.Rectangle
+
+	hScrollBounds, hKnobBounds shape.Rectangle
+	vScrollBounds, vKnobBounds shape.Rectangle
+
+	scrollX, scrollY             bool
+	disableX, disableY           bool
+	forceScrollX, forceScrollY   bool
+	amountX, amountY             float32
+	visualAmountX, visualAmountY float32
+	maxX, maxY                   float32
+
+	touched, dragged, flingable  bool
+	pointer                      uint8
+	draggingKnobX, draggingKnobY bool
+	lastX, lastY                 float32
+	over                         bool
+
+	velocityX, velocityY float32
+	flingTimer           float32
+	bounceX, bounceY     scrollBounce
+
+	// Whether the actor can be dragged and flung. Default is true.
+	FlickScroll bool
+
+	// How many seconds a fling scrolls for, its velocity slows down to 0 over them. Default is 1.
+	FlingTime float32
+
+	// Whether the actor can be dragged and flung past its edges, up to OverscrollDistance. Default is true for both.
+	OverscrollX, OverscrollY bool
+	OverscrollDistance       float32
+
+	// How many seconds the actor takes to bounce back to its edge after it was released past it and the curve it moves
+	// along. Default is 0.3 and Pow2Out.
+	OverscrollDuration      float32
+	OverscrollInterpolation interpolation.Interpolation
+
+	// If set the mouse wheel and ScrollTo scroll smoothly to the new position instead of jumping to it. Default is true.
+	SmoothScrolling bool
+
+	// If set the knobs are as long as the visible part of the actor is of all of it, with the minimum size of their
+	// drawables. Default is true.
+	VariableSizeKnobs bool
+}
+
+// The return of an axis of a scroll pane to its edge after it was released past it
+type scrollBounce struct {
+	active         bool
+	from, to, time float32
+}
+
+// Creates a scroll pane showing the actor, which may be nil
+func NewScrollPane(actor *Actor, style *ScrollPaneStyle) *ScrollPane {
+	self := &ScrollPane{style: style}
+	self.FlickScroll, self.FlingTime = true, 1
+	self.OverscrollX, self.OverscrollY, self.OverscrollDistance = true, true, 50
+	self.OverscrollDuration, self.OverscrollInterpolation = 0.3, interpolation.Pow2Out
+	self.SmoothScrolling, self.VariableSizeKnobs = true, true
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.widget.init(&self.Actor, self)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.Act = func(a *Actor, delta float32) {
+		self.act(delta)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	self.SetActor(actor)
+	self.SetSize(150, 150)
+	return self
+}
+
+// Sets the actor which is scrolled, it replaces the actor shown before. The actor may be nil.
+func (self *ScrollPane) SetActor(actor *Actor) {
+	if self.content != nil {
+		self.content.SetCullingArea(nil)
+		self.RemoveActor(self.content)
+	}
+	self.content, self.cullable = actor, nil
+	if actor != nil {
+		// widgets which cull their own drawing implement ui.Cullable, the actor culls its children otherwise
+		self.cullable = actor
+		if cullable, ok := actor.Widget.(ui.Cullable); ok {
+			self.cullable = cullable
+		}
+		self.AddActor(actor)
+	}
+	self.InvalidateHierarchy()
+}
+
+func (self *ScrollPane) GetActor() *Actor {
+	return self.content
+}
+
+func (self *ScrollPane) SetStyle(style *ScrollPaneStyle) {
+	self.style = style
+	self.InvalidateHierarchy()
+}
+
+func (self *ScrollPane) GetStyle() *ScrollPaneStyle {
+	return self.style
+}
+
+// Disables scrolling in the directions, the actor is then sized to the pane in them. Default is false for both.
+func (self *ScrollPane) SetScrollingDisabled(x, y bool) {
+	self.disableX, self.disableY = x, y
+	self.InvalidateHierarchy()
+}
+
+// Shows the scroll bars even if the actor fits the pane, unless scrolling is disabled. Default is false for both.
+func (self *ScrollPane) SetForceScroll(x, y bool) {
+	self.forceScrollX, self.forceScrollY = x, y
+	self.InvalidateHierarchy()
+}
+
+// Returns how far the actor is scrolled from its left edge
+func (self *ScrollPane) GetScrollX() float32 {
+	return self.amountX
+}
+
+// Returns how far the actor is scrolled from its top edge
+func (self *ScrollPane) GetScrollY() float32 {
+	return self.amountY
+}
+
+// Scrolls the actor to the distance from its left edge, the pane scrolls there smoothly if SmoothScrolling is set
+func (self *ScrollPane) SetScrollX(amount float32) {
+	self.amountX = utils.ClampFloat32(amount, 0, self.maxX)
+	self.bounceX.active = false
+}
+
+// Scrolls the actor to the distance from its top edge, the pane scrolls there smoothly if SmoothScrolling is set
+func (self *ScrollPane) SetScrollY(amount float32) {
+	self.amountY = utils.ClampFloat32(amount, 0, self.maxY)
+	self.bounceY.active = false
+}
+
+// Returns how far the actor is shown scrolled from its left edge, it differs from GetScrollX while it scrolls smoothly
+func (self *ScrollPane) GetVisualScrollX() float32 {
+	return self.visualAmountX
+}
+
+// Returns how far the actor is shown scrolled from its top edge, it differs from GetScrollY while it scrolls smoothly
+func (self *ScrollPane) GetVisualScrollY() float32 {
+	return self.visualAmountY
+}
+
+// Returns how far the actor can be scrolled horizontally
+func (self *ScrollPane) GetMaxX() float32 {
+	return self.maxX
+}
+
+// Returns how far the actor can be scrolled vertically
+func (self *ScrollPane) GetMaxY() float32 {
+	return self.maxY
+}
+
+// Returns how far the actor is scrolled horizontally, from 0 at its left edge to 1 at its right edge
+func (self *ScrollPane) GetScrollPercentX() float32 {
+	if self.maxX == 0 {
+		return 0
+	}
+	return utils.ClampFloat32(self.amountX/self.maxX, 0, 1)
+}
+
+// Returns how far the actor is scrolled vertically, from 0 at its top edge to 1 at its bottom edge
+func (self *ScrollPane) GetScrollPercentY() float32 {
+	if self.maxY == 0 {
+		return 0
+	}
+	return utils.ClampFloat32(self.amountY/self.maxY, 0, 1)
+}
+
+func (self *ScrollPane) SetScrollPercentX(percent float32) {
+	self.SetScrollX(self.maxX * percent)
+}
+
+func (self *ScrollPane) SetScrollPercentY(percent float32) {
+	self.SetScrollY(self.maxY * percent)
+}
+
+// Shows the actor as it is scrolled at once, ending a smooth scroll
+func (self *ScrollPane) UpdateVisualScroll() {
+	self.visualAmountX, self.visualAmountY = self.amountX, self.amountY
+	self.updateActorPosition()
+}
+
+// Scrolls the actor as little as needed to show the rectangle in its coordinates. If the rectangle is larger than the
+// pane its top left corner is shown.
+func (self *ScrollPane) ScrollTo(rect *shape.Rectangle) {
+	self.Validate()
+	amountX := self.amountX
+	if rect.X+rect.W > amountX+self.widgetArea.W {
+		amountX = rect.X + rect.W - self.widgetArea.W
+	}
+	if rect.X < amountX {
+		amountX = rect.X
+	}
+	self.SetScrollX(amountX)
+
+	// the visible part of the actor is from maxY - amountY to maxY - amountY + the area height
+	amountY := self.amountY
+	if rect.Y < self.maxY-amountY {
+		amountY = self.maxY - rect.Y
+	}
+	if rect.Y+rect.H > self.maxY-amountY+self.widgetArea.H {
+		amountY = self.maxY - rect.Y - rect.H + self.widgetArea.H
+	}
+	self.SetScrollY(amountY)
+}
+
+// Returns whether the actor is being dragged
+func (self *ScrollPane) IsPanning() bool {
+	return self.touched && self.dragged
+}
+
+// Returns whether the actor is moving after it was flung
+func (self *ScrollPane) IsFlinging() bool {
+	return self.flingTimer > 0
+}
+
+// Returns whether the actor is beyond its edges
+func (self *ScrollPane) IsOverscrolling() bool {
+	return self.amountX < 0 || self.amountX > self.maxX || self.amountY < 0 || self.amountY > self.maxY
+}
+
+// Stops the fling and the smooth scrolling, the actor is shown where it is scrolled
+func (self *ScrollPane) Cancel() {
+	self.touched, self.flingable, self.draggingKnobX, self.draggingKnobY = false, false, false, false
+	self.flingTimer, self.velocityX, self.velocityY = 0, 0, 0
+	self.UpdateVisualScroll()
+}
+
+// Returns whether the actor is in a scroll pane which was dragged by the current touch, the touch then does not click it
+func scrollPaneDragged(actor *Actor) bool {
+	for parent := actor.Parent; parent != nil; parent = parent.Parent {
+		if pane, ok := parent.Widget.(*ScrollPane); ok && pane.dragged {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the stage units per screen pixel, the gestures are measured in pixels
+func stageUnitsPerPixel() float32 {
+	if inHud {
+		return HudUnitsPerPixel
+	}
+	if viewport != nil && viewport.ScreenWidth > 0 {
+		return viewport.Camera.ViewportWidth * viewport.Camera.Zoom / float32(viewport.ScreenWidth)
+	}
+	return 1
+}
+
+func (self *ScrollPane) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		self.dragged, self.flingable = false, false
+		if self.touched || self.draggingKnobX || self.draggingKnobY || !self.Hit(e.X, e.Y, widgetPoint) {
+			return
+		}
+		self.flingTimer, self.velocityX, self.velocityY = 0, 0, 0
+		x, y := widgetPoint.X, widgetPoint.Y
+		if self.scrollX && self.hScrollBounds.Contains(x, y) {
+			if self.hKnobBounds.Contains(x, y) {
+				self.draggingKnobX, self.pointer, self.lastX = true, e.Pointer, x
+			} else if x < self.hKnobBounds.X {
+				self.SetScrollX(self.amountX - self.widgetArea.W)
+			} else {
+				self.SetScrollX(self.amountX + self.widgetArea.W)
+			}
+			return
+		}
+		if self.scrollY && self.vScrollBounds.Contains(x, y) {
+			if self.vKnobBounds.Contains(x, y) {
+				self.draggingKnobY, self.pointer, self.lastY = true, e.Pointer, y
+			} else if y < self.vKnobBounds.Y {
+				self.SetScrollY(self.amountY + self.widgetArea.H)
+			} else {
+				self.SetScrollY(self.amountY - self.widgetArea.H)
+			}
+			return
+		}
+		if self.FlickScroll && self.widgetArea.Contains(x, y) {
+			self.touched, self.pointer = true, e.Pointer
+			self.bounceX.active, self.bounceY.active = false, false
+		}
+	case TouchDragged:
+		if e.Pointer != self.pointer || !(self.draggingKnobX || self.draggingKnobY) {
+			return
+		}
+		self.StageToLocalCoordinates(widgetPoint.Set(e.X, e.Y))
+		if self.draggingKnobX {
+			if track := self.hScrollBounds.W - self.hKnobBounds.W; track > 0 {
+				self.SetScrollX(self.amountX + (widgetPoint.X-self.lastX)*self.maxX/track)
+			}
+			self.lastX = widgetPoint.X
+		} else {
+			if track := self.vScrollBounds.H - self.vKnobBounds.H; track > 0 {
+				self.SetScrollY(self.amountY - (widgetPoint.Y-self.lastY)*self.maxY/track)
+			}
+			self.lastY = widgetPoint.Y
+		}
+		self.UpdateVisualScroll()
+	case TouchUp:
+		if e.Pointer != self.pointer {
+			return
+		}
+		// the fling of the touch follows its release
+		self.flingable = self.touched
+		self.touched, self.draggingKnobX, self.draggingKnobY = false, false, false
+	case Pan:
+		if !self.touched {
+			return
+		}
+		// the screen y axis points down, the amount is measured from the top of the actor
+		scale := stageUnitsPerPixel()
+		if self.scrollX {
+			self.amountX = self.overscrollClamp(self.amountX-e.DeltaX*scale, self.maxX, self.OverscrollX)
+		}
+		if self.scrollY {
+			self.amountY = self.overscrollClamp(self.amountY-e.DeltaY*scale, self.maxY, self.OverscrollY)
+		}
+		self.dragged = true
+		self.UpdateVisualScroll()
+	case Fling:
+		if !self.flingable {
+			return
+		}
+		self.flingable = false
+		// the velocity is in pixels per second, slow flings do not scroll
+		scale := stageUnitsPerPixel()
+		if self.scrollX && utils.AbsFloat32(e.DeltaX) > 150 {
+			self.velocityX, self.flingTimer = -e.DeltaX*scale, self.FlingTime
+		}
+		if self.scrollY && utils.AbsFloat32(e.DeltaY) > 150 {
+			self.velocityY, self.flingTimer = -e.DeltaY*scale, self.FlingTime
+		}
+	case MouseMoved:
+		self.over = self.Hit(e.X, e.Y, widgetPoint)
+	case Scrolled:
+		if !self.over {
+			return
+		}
+		if self.scrollY {
+			step := utils.MinFloat32(self.widgetArea.H, utils.MaxFloat32(self.widgetArea.H*0.9, self.maxY*0.1)/4)
+			self.SetScrollY(self.amountY + step*float32(e.ScrollAmount))
+		} else if self.scrollX {
+			step := utils.MinFloat32(self.widgetArea.W, utils.MaxFloat32(self.widgetArea.W*0.9, self.maxX*0.1)/4)
+			self.SetScrollX(self.amountX + step*float32(e.ScrollAmount))
+		}
+	}
+}
+
+// Clamps the amount to the range it can be scrolled in, which reaches OverscrollDistance past the edges if overscroll is
+// set
+func (self *ScrollPane) overscrollClamp(amount, max float32, overscroll bool) float32 {
+	if !overscroll {
+		return utils.ClampFloat32(amount, 0, max)
+	}
+	return utils.ClampFloat32(amount, -self.OverscrollDistance, max+self.OverscrollDistance)
+}
+
+// Moves the actor with the velocity of the fling, bounces it back to its edges and scrolls it smoothly
+func (self *ScrollPane) act(delta float32) {
+	if self.flingTimer > 0 {
+		alpha := self.flingTimer / self.FlingTime
+		amountX := self.overscrollClamp(self.amountX+self.velocityX*alpha*delta, self.maxX, self.OverscrollX)
+		amountY := self.overscrollClamp(self.amountY+self.velocityY*alpha*delta, self.maxY, self.OverscrollY)
+		// the fling stops at the end of the overscroll
+		if amountX != self.amountX+self.velocityX*alpha*delta {
+			self.velocityX = 0
+		}
+		if amountY != self.amountY+self.velocityY*alpha*delta {
+			self.velocityY = 0
+		}
+		self.amountX, self.amountY = amountX, amountY
+		self.flingTimer -= delta
+		if self.flingTimer <= 0 || self.velocityX == 0 && self.velocityY == 0 {
+			self.flingTimer, self.velocityX, self.velocityY = 0, 0, 0
+		}
+		self.UpdateVisualScroll()
+	}
+	if self.touched || self.flingTimer > 0 {
+		return
+	}
+	if self.amountX < 0 || self.amountX > self.maxX || self.bounceX.active {
+		self.amountX = self.bounceX.update(self.amountX, self.maxX, self, delta)
+		self.visualAmountX = self.amountX
+	}
+	if self.amountY < 0 || self.amountY > self.maxY || self.bounceY.active {
+		self.amountY = self.bounceY.update(self.amountY, self.maxY, self, delta)
+		self.visualAmountY = self.amountY
+	}
+	if self.SmoothScrolling && !self.draggingKnobX && !self.draggingKnobY {
+		self.visualAmountX = smoothScroll(self.visualAmountX, self.amountX, delta)
+		self.visualAmountY = smoothScroll(self.visualAmountY, self.amountY, delta)
+	} else {
+		self.visualAmountX, self.visualAmountY = self.amountX, self.amountY
+	}
+	self.updateActorPosition()
+}
+
+// Returns the amount an axis shows moved towards the amount it is scrolled to, faster the farther it is from it
+func smoothScroll(visual, amount, delta float32) float32 {
+	if visual < amount {
+		return utils.MinFloat32(amount, visual+utils.MaxFloat32(200*delta, (amount-visual)*7*delta))
+	}
+	if visual > amount {
+		return utils.MaxFloat32(amount, visual-utils.MaxFloat32(200*delta, (visual-amount)*7*delta))
+	}
+	return visual
+}
+
+// Returns the amount of the axis moved back towards the edge it is past along the overscroll interpolation
+func (self *scrollBounce) update(amount, max float32, pane *ScrollPane, delta float32) float32 {
+	if !self.active {
+		self.active, self.from, self.time = true, amount, 0
+		self.to = utils.ClampFloat32(amount, 0, max)
+	}
+	self.time += delta
+	if self.time >= pane.OverscrollDuration {
+		self.active = false
+		return self.to
+	}
+	return interpolation.StartEnd(self.from, self.to, self.time/pane.OverscrollDuration, pane.OverscrollInterpolation)
+}
+
+// Moves the actor to show the part it is scrolled to and sets the culling area of its children to it
+func (self *ScrollPane) updateActorPosition() {
+	if self.content == nil {
+		return
+	}
+	area := &self.widgetArea
+	self.content.SetPosition(area.X-self.visualAmountX, area.Y-(self.maxY-self.visualAmountY))
+	self.cullingArea.Set(self.visualAmountX, self.maxY-self.visualAmountY, area.W, area.H)
+	self.cullable.SetCullingArea(&self.cullingArea)
+}
+
+// Sizes the actor to at least the area it is shown in and places the scroll bars
+func (self *ScrollPane) Layout() {
+	style := self.style
+	var left, right, top, bottom float32
+	if style.Background != nil {
+		left, right = style.Background.GetPadLeft(), style.Background.GetPadRight()
+		top, bottom = style.Background.GetPadTop(), style.Background.GetPadBottom()
+	}
+	areaWidth, areaHeight := self.W-left-right, self.H-top-bottom
+	self.scrollX, self.scrollY = false, false
+	if self.content == nil {
+		self.widgetArea.Set(left, bottom, areaWidth, areaHeight)
+		self.clipBounds = self.widgetArea
+		return
+	}
+	widgetWidth, widgetHeight := widgetPrefWidth(self.content), widgetPrefHeight(self.content)
+	if self.disableX {
+		widgetWidth = areaWidth
+	}
+	if self.disableY {
+		widgetHeight = areaHeight
+	}
+
+	// a scroll bar takes space from the area, the actor may not fit the other way then
+	hScrollHeight, vScrollWidth := self.hScrollHeight(), self.vScrollWidth()
+	self.scrollX = !self.disableX && (self.forceScrollX || widgetWidth > areaWidth)
+	self.scrollY = !self.disableY && (self.forceScrollY || widgetHeight > areaHeight)
+	if self.scrollX {
+		areaHeight -= hScrollHeight
+		if !self.scrollY && !self.disableY && widgetHeight > areaHeight {
+			self.scrollY = true
+		}
+	}
+	if self.scrollY {
+		areaWidth -= vScrollWidth
+		if !self.scrollX && !self.disableX && widgetWidth > areaWidth {
+			self.scrollX = true
+			areaHeight -= hScrollHeight
+		}
+	}
+	areaY := bottom
+	if self.scrollX {
+		areaY += hScrollHeight
+	}
+	self.widgetArea.Set(left, areaY, areaWidth, areaHeight)
+	self.clipBounds = self.widgetArea
+
+	// the actor fills the area if it is smaller
+	if self.disableX {
+		widgetWidth = areaWidth
+	} else {
+		widgetWidth = utils.MaxFloat32(widgetWidth, areaWidth)
+	}
+	if self.disableY {
+		widgetHeight = areaHeight
+	} else {
+		widgetHeight = utils.MaxFloat32(widgetHeight, areaHeight)
+	}
+	self.maxX, self.maxY = widgetWidth-areaWidth, widgetHeight-areaHeight
+	if self.scrollX {
+		self.hScrollBounds.Set(left, bottom, areaWidth, hScrollHeight)
+	}
+	if self.scrollY {
+		self.vScrollBounds.Set(left+areaWidth, areaY, vScrollWidth, areaHeight)
+	}
+	self.content.SetSize(widgetWidth, widgetHeight)
+	if self.content.Widget != nil {
+		self.content.Widget.Validate()
+	}
+	if !self.touched && self.flingTimer <= 0 {
+		self.amountX = utils.ClampFloat32(self.amountX, 0, self.maxX)
+		self.amountY = utils.ClampFloat32(self.amountY, 0, self.maxY)
+		self.UpdateVisualScroll()
+	} else {
+		self.updateActorPosition()
+	}
+}
+
+// Returns the height of the horizontal scroll bar
+func (self *ScrollPane) hScrollHeight() float32 {
+	var height float32
+	if self.style.HScroll != nil {
+		height = self.style.HScroll.GetMinHeight()
+	}
+	if self.style.HScrollKnob != nil {
+		height = utils.MaxFloat32(height, self.style.HScrollKnob.GetMinHeight())
+	}
+	return height
+}
+
+// Returns the width of the vertical scroll bar
+func (self *ScrollPane) vScrollWidth() float32 {
+	var width float32
+	if self.style.VScroll != nil {
+		width = self.style.VScroll.GetMinWidth()
+	}
+	if self.style.VScrollKnob != nil {
+		width = utils.MaxFloat32(width, self.style.VScrollKnob.GetMinWidth())
+	}
+	return width
+}
+
+// Places the knobs where the actor is shown scrolled to
+func (self *ScrollPane) updateKnobs() {
+	style := self.style
+	if self.scrollX {
+		bounds := &self.hScrollBounds
+		width := bounds.W
+		if style.HScrollKnob != nil {
+			width = style.HScrollKnob.GetMinWidth()
+			if self.VariableSizeKnobs && self.maxX > 0 {
+				width = utils.MaxFloat32(width, bounds.W*bounds.W/(bounds.W+self.maxX))
+			}
+		}
+		var percent float32
+		if self.maxX > 0 {
+			percent = utils.ClampFloat32(self.visualAmountX/self.maxX, 0, 1)
+		}
+		self.hKnobBounds.Set(bounds.X+(bounds.W-width)*percent, bounds.Y, width, bounds.H)
+	}
+	if self.scrollY {
+		bounds := &self.vScrollBounds
+		height := bounds.H
+		if style.VScrollKnob != nil {
+			height = style.VScrollKnob.GetMinHeight()
+			if self.VariableSizeKnobs && self.maxY > 0 {
+				height = utils.MaxFloat32(height, bounds.H*bounds.H/(bounds.H+self.maxY))
+			}
+		}
+		var percent float32
+		if self.maxY > 0 {
+			percent = utils.ClampFloat32(self.visualAmountY/self.maxY, 0, 1)
+		}
+		self.vKnobBounds.Set(bounds.X, bounds.Y+(bounds.H-height)*(1-percent), bounds.W, height)
+	}
+}
+
+func (self *ScrollPane) GetMinWidth() float32 {
+	return 0
+}
+
+func (self *ScrollPane) GetMinHeight() float32 {
+	return 0
+}
+
+// Returns the preferred width of the actor with the background and the vertical scroll bar if it is forced
+func (self *ScrollPane) GetPrefWidth() float32 {
+	var width float32
+	if self.content != nil {
+		width = widgetPrefWidth(self.content)
+	}
+	if self.style.Background != nil {
+		width += self.style.Background.GetPadLeft() + self.style.Background.GetPadRight()
+	}
+	if self.forceScrollY && !self.disableY {
+		width += self.vScrollWidth()
+	}
+	return width
+}
+
+// Returns the preferred height of the actor with the background and the horizontal scroll bar if it is forced
+func (self *ScrollPane) GetPrefHeight() float32 {
+	var height float32
+	if self.content != nil {
+		height = widgetPrefHeight(self.content)
+	}
+	if self.style.Background != nil {
+		height += self.style.Background.GetPadTop() + self.style.Background.GetPadBottom()
+	}
+	if self.forceScrollX && !self.disableX {
+		height += self.hScrollHeight()
+	}
+	return height
+}
+
+func (self *ScrollPane) GetMaxWidth() float32 {
+	return 0
+}
+
+func (self *ScrollPane) GetMaxHeight() float32 {
+	return 0
+}
+
+// Draws the background and the scroll bars, the actor is drawn after them clipped to its area
+func (self *ScrollPane) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	self.updateKnobs()
+	style := self.style
+	setBatchColor(batch, &self.Actor, nil, parentAlpha)
+	drawDrawable(batch, &self.Actor, style.Background, 0, 0, self.W, self.H)
+	if self.scrollX {
+		bounds, knob := &self.hScrollBounds, &self.hKnobBounds
+		drawDrawable(batch, &self.Actor, style.HScroll, bounds.X, bounds.Y, bounds.W, bounds.H)
+		drawDrawable(batch, &self.Actor, style.HScrollKnob, knob.X, knob.Y, knob.W, knob.H)
+	}
+	if self.scrollY {
+		bounds, knob := &self.vScrollBounds, &self.vKnobBounds
+		drawDrawable(batch, &self.Actor, style.VScroll, bounds.X, bounds.Y, bounds.W, bounds.H)
+		drawDrawable(batch, &self.Actor, style.VScrollKnob, knob.X, knob.Y, knob.W, knob.H)
+	}
+	if self.scrollX && self.scrollY {
+		drawDrawable(batch, &self.Actor, style.Corner, self.vScrollBounds.X, self.hScrollBounds.Y, self.vScrollBounds.W,
+			self.hScrollBounds.H)
+	}
+}
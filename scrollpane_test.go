@@ -0,0 +1,123 @@
+package spike
+
+import (
+	"testing"
+)
+
+// Sends the touch at the input time through the gesture detector to the pane, returns the events it received
+func feedPane(pane *ScrollPane, t InputType, x, y float32, time int64) []InputEvent {
+	inputClock = func() int64 {
+		return time
+	}
+	dispatchInput(newTouchEvent(t, x, y, 0))
+	var events []InputEvent
+	for len(InputChannel) > 0 {
+		e := <-InputChannel
+		pane.input(e)
+		events = append(events, e)
+	}
+	return events
+}
+
+func findEvent(events []InputEvent, t InputType) *InputEvent {
+	for i := range events {
+		if events[i].Type == t {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+// Returns a pane of 150 by 150 showing an actor of 300 by 600, it scrolls 450 down
+func newTestScrollPane() *ScrollPane {
+	pane := NewScrollPane(newTestBox(300, 600), &ScrollPaneStyle{})
+	pane.SmoothScrolling = false
+	pane.Validate()
+	return pane
+}
+
+func TestScrollPaneFling(t *testing.T) {
+	oldClock := inputClock
+	defer func() {
+		inputClock = oldClock
+		resetInput()
+	}()
+	resetInput()
+	pane := newTestScrollPane()
+	if pane.GetMaxX() != 150 || pane.GetMaxY() != 450 {
+		t.Fatalf("scrolls %v, %v", pane.GetMaxX(), pane.GetMaxY())
+	}
+
+	// dragging up 20 pixels every 10 ms scrolls down
+	start := int64(5000000000)
+	feedPane(pane, TouchDown, 75, 120, start)
+	for i := int64(1); i <= 5; i++ {
+		events := feedPane(pane, TouchDragged, 75, 120-float32(i)*20, start+i*10000000)
+		// the pan is at the position of the pointer and has the distance to the last drag
+		if pan := findEvent(events, Pan); i > 1 && (pan == nil || pan.X != 75 || pan.Y != 120-float32(i)*20 ||
+			pan.DeltaX != 0 || pan.DeltaY != -20) {
+			t.Errorf("drag %d panned with %v", i, pan)
+		}
+	}
+	if !pane.IsPanning() || pane.GetScrollY() != 100 || pane.GetScrollX() != 0 {
+		t.Errorf("panned to %v, %v", pane.GetScrollX(), pane.GetScrollY())
+	}
+	// released right after the last drag, the velocity is the mean of the drags and the release
+	events := feedPane(pane, TouchUp, 75, 20, start+60000000)
+	fling := findEvent(events, Fling)
+	if fling == nil {
+		t.Fatalf("no fling in %v", events)
+	}
+	if fling.DeltaX != 0 || !near(fling.DeltaY, -100.0/6/0.01, 0.1) {
+		t.Errorf("fling velocity %v, %v", fling.DeltaX, fling.DeltaY)
+	}
+	if fling.X != 75 || fling.Y != 20 || fling.ScreenX != 75 || fling.ScreenY != 20 {
+		t.Errorf("fling at %v, %v", fling.X, fling.Y)
+	}
+	if !pane.IsFlinging() || pane.IsPanning() {
+		t.Fatal("not flinging")
+	}
+	pane.act(0.1)
+	if !near(pane.GetScrollY(), 100+1666.667*0.1, 0.1) || pane.GetVisualScrollY() != pane.GetScrollY() {
+		t.Errorf("flung to %v", pane.GetScrollY())
+	}
+	// the fling stops at the end of the overscroll, 500, and the actor bounces back to its edge for the rest of the frame,
+	// a third of the overscroll duration
+	for i := 0; i < 10 && pane.IsFlinging(); i++ {
+		pane.act(0.1)
+	}
+	if pane.IsFlinging() || !near(pane.GetScrollY(), 500-50*(1-2.0/3*2.0/3), 0.01) || !pane.IsOverscrolling() {
+		t.Errorf("fling ended at %v", pane.GetScrollY())
+	}
+	pane.act(0.2)
+	if pane.GetScrollY() != 450 || pane.IsOverscrolling() {
+		t.Errorf("bounced back to %v", pane.GetScrollY())
+	}
+
+	// a touch held still before its release is not flung
+	pane.SetScrollY(0)
+	start += 1000000000
+	feedPane(pane, TouchDown, 75, 20, start)
+	feedPane(pane, TouchDragged, 75, 80, start+10000000)
+	if events = feedPane(pane, TouchUp, 75, 80, start+200000000); findEvent(events, Fling) != nil {
+		t.Errorf("late release flung %v", events)
+	}
+	// dragged past the top edge the actor is overscrolled up to the overscroll distance
+	if pane.IsFlinging() || pane.GetScrollY() != -50 || !pane.IsOverscrolling() {
+		t.Errorf("dragged past the edge to %v", pane.GetScrollY())
+	}
+	pane.act(0.5)
+	if pane.GetScrollY() != 0 {
+		t.Errorf("bounced back to %v", pane.GetScrollY())
+	}
+
+	// without overscroll the drag stops at the edge
+	pane.OverscrollY = false
+	start += 1000000000
+	feedPane(pane, TouchDown, 75, 20, start)
+	feedPane(pane, TouchDragged, 75, 80, start+10000000)
+	feedPane(pane, TouchUp, 75, 80, start+200000000)
+	if pane.GetScrollY() != 0 || pane.IsOverscrolling() {
+		t.Errorf("dragged without overscroll to %v", pane.GetScrollY())
+	}
+}
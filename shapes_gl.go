@@ -0,0 +1,102 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"encoding/binary"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+	"golang.org/x/mobile/exp/f32"
+	"golang.org/x/mobile/gl"
+)
+
+// The shaders the vertices of the shape renderers are drawn with, each vertex has a position and a color
+const (
+	shapeVertexShader = `#version 100
+uniform mat4 u_projection;
+attribute vec2 a_position;
+attribute vec4 a_color;
+varying vec4 v_color;
+void main() {
+	v_color = a_color;
+	gl_Position = u_projection * vec4(a_position, 0.0, 1.0);
+}`
+
+	shapeFragmentShader = `#version 100
+precision mediump float;
+varying vec4 v_color;
+void main() {
+	gl_FragColor = v_color;
+}`
+)
+
+// The program the shape renderers are drawn with, it is compiled for each context it is drawn with
+type shapeProgram struct {
+	ctx        GLContext
+	program    gl.Program
+	projection gl.Uniform
+	position   gl.Attrib
+	color      gl.Attrib
+	buffer     gl.Buffer
+	failed     bool
+}
+
+var (
+	shapes shapeProgram
+
+	// The GL context the scene is drawn with, nil while it is not drawn with OpenGL
+	drawContext gl.Context
+)
+
+// Returns a shape renderer whose shapes are drawn with the GL context of the frame
+func newGLShapeRenderer() *g2d.ShapeRenderer {
+	renderer := g2d.NewShapeRenderer()
+	renderer.Flush = flushShapes
+	return renderer
+}
+
+// The Flush of the shape renderers, the shapes are not drawn while there is no context
+func flushShapes(primitive int, vertices []float32, projection *vector.Matrix4) {
+	if drawContext == nil {
+		return
+	}
+	shapes.draw(drawContext, primitive, vertices, projection)
+}
+
+// Draws the vertices as the primitive, the program is compiled first if the context changed. If it does not compile the
+// error is printed once and no shapes are drawn.
+func (self *shapeProgram) draw(ctx GLContext, primitive int, vertices []float32, projection *vector.Matrix4) {
+	if ctx != self.ctx {
+		// the resources of a lost context are gone with it
+		self.ctx = ctx
+		program, err := compileProgram(ctx, shapeVertexShader, shapeFragmentShader)
+		self.failed = err != nil
+		if err != nil {
+			println("Shapes: " + err.Error())
+			return
+		}
+		self.program = program
+		self.projection = ctx.GetUniformLocation(program, "u_projection")
+		self.position = ctx.GetAttribLocation(program, "a_position")
+		self.color = ctx.GetAttribLocation(program, "a_color")
+		self.buffer = ctx.CreateBuffer()
+	}
+	if self.failed {
+		return
+	}
+	values := projection.GetValues()
+	ctx.UseProgram(self.program)
+	ctx.UniformMatrix4fv(self.projection, values[:])
+	ctx.BindBuffer(gl.ARRAY_BUFFER, self.buffer)
+	ctx.BufferData(gl.ARRAY_BUFFER, f32.Bytes(binary.LittleEndian, vertices...), gl.STREAM_DRAW)
+	ctx.EnableVertexAttribArray(self.position)
+	ctx.EnableVertexAttribArray(self.color)
+	ctx.VertexAttribPointer(self.position, 2, gl.FLOAT, false, g2d.ShapeVertexSize*4, 0)
+	ctx.VertexAttribPointer(self.color, 4, gl.FLOAT, false, g2d.ShapeVertexSize*4, 2*4)
+	ctx.DrawArrays(gl.Enum(primitive), 0, len(vertices)/g2d.ShapeVertexSize)
+	ctx.DisableVertexAttribArray(self.position)
+	ctx.DisableVertexAttribArray(self.color)
+}
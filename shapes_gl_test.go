@@ -0,0 +1,54 @@
+package spike
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+func TestShapeProgram(t *testing.T) {
+	fake := NewFakeGL()
+	var program shapeProgram
+	projection := vector.NewMatrix4Empty()
+	lines := []float32{0, 0, 1, 1, 1, 1, 10, 0, 1, 1, 1, 1}
+	program.draw(fake, g2d.PrimitiveLines, lines, projection)
+	if len(fake.Filter("CreateProgram")) != 1 || len(fake.Filter("CreateBuffer")) != 1 {
+		t.Errorf("compiled %v", fake.Calls)
+	}
+	var drawn []string
+	for _, call := range fake.Calls {
+		if strings.HasPrefix(call, "BufferData") || strings.HasPrefix(call, "VertexAttribPointer") ||
+			strings.HasPrefix(call, "DrawArrays") || strings.HasPrefix(call, "UniformMatrix4fv") {
+			drawn = append(drawn, call)
+		}
+	}
+	expected := []string{
+		"UniformMatrix4fv u_projection 1 0 0 0 0 1 0 0 0 0 1 0 0 0 0 1",
+		"BufferData ARRAY_BUFFER 48 STREAM_DRAW",
+		"VertexAttribPointer 0 2 FLOAT false 24 0",
+		"VertexAttribPointer 0 4 FLOAT false 24 8",
+		"DrawArrays LINES 0 2",
+	}
+	if !reflect.DeepEqual(drawn, expected) {
+		t.Errorf("lines drawn\n%v\nexpected\n%v", drawn, expected)
+	}
+
+	// the program is kept for the context
+	fake.Reset()
+	program.draw(fake, g2d.PrimitiveTriangles, append(lines, lines[:6]...), projection)
+	if len(fake.Filter("CreateProgram")) != 0 || len(fake.Filter("DrawArrays TRIANGLES 0 3")) != 1 {
+		t.Errorf("triangles drawn %v", fake.Calls)
+	}
+
+	// a program which does not compile is not tried again and nothing is drawn
+	broken := NewFakeGL()
+	broken.ShaderError = "a_color"
+	program.draw(broken, g2d.PrimitiveLines, lines, projection)
+	program.draw(broken, g2d.PrimitiveLines, lines, projection)
+	if len(broken.Filter("CreateShader")) != 1 || len(broken.Filter("DrawArrays")) != 0 {
+		t.Errorf("drawn with a broken program %v", broken.Calls)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"errors"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/g2d/spine"
+)
+
+// A SkeletonActor is an actor which plays the animations of a Spine skeleton. The skeleton is drawn in the coordinates of
+// the actor, with its root at the actor's position, tinted with its color.
+//
+//	hero := spike.NewSkeletonActor(data)
+//	hero.State.SetAnimation(0, data.FindAnimation("walk"), true)
+//	hero.AttachToBone("hand", &sword.Actor)
+//	scene.AddActor(&hero.Actor)
+//
+// Actors attached to bones are children of the skeleton actor which follow the position, rotation and scale of their bone.
+// Their origin is placed on the bone.
+type SkeletonActor struct {
+	Actor
+
+	Skeleton *spine.Skeleton
+	State    *spine.AnimationState
+
+	attached []boneAttachment
+}
+
+type boneAttachment struct {
+	bone  *spine.Bone
+	actor *Actor
+}
+
+// Creates an actor with a skeleton of the data in its setup pose, the animation state has no mix durations set
+func NewSkeletonActor(data *spine.SkeletonData) *SkeletonActor {
+	self := &SkeletonActor{
+		Skeleton: spine.NewSkeleton(data),
+		State:    spine.NewAnimationState(spine.NewAnimationStateData(data)),
+	}
+	self.Actor = Actor{SX: 1, SY: 1, W: data.Width, H: data.Height}
+	self.Act = func(a *Actor, delta float32) {
+		self.Update(delta)
+	}
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	return self
+}
+
+// Advances the animations by the time, poses the skeleton and moves the actors attached to its bones
+func (self *SkeletonActor) Update(delta float32) {
+	self.State.Update(delta)
+	self.State.Apply(self.Skeleton)
+	self.Skeleton.UpdateWorldTransform()
+	self.updateAttached()
+}
+
+// Adds the actor as a child which follows the bone
+func (self *SkeletonActor) AttachToBone(boneName string, actor *Actor) error {
+	bone := self.Skeleton.FindBone(boneName)
+	if bone == nil {
+		return errors.New("spike: bone not found " + boneName)
+	}
+	self.DetachFromBone(actor)
+	self.AddActor(actor)
+	self.attached = append(self.attached, boneAttachment{bone, actor})
+	self.updateAttached()
+	return nil
+}
+
+// Removes the actor attached to a bone
+func (self *SkeletonActor) DetachFromBone(actor *Actor) {
+	for i, attachment := range self.attached {
+		if attachment.actor == actor {
+			self.attached = append(self.attached[:i], self.attached[i+1:]...)
+			self.RemoveActor(actor)
+			return
+		}
+	}
+}
+
+func (self *SkeletonActor) updateAttached() {
+	for _, attachment := range self.attached {
+		bone, actor := attachment.bone, attachment.actor
+		actor.X = bone.GetWorldX() - actor.OX
+		actor.Y = bone.GetWorldY() - actor.OY
+		actor.Rotation = bone.GetWorldRotationX()
+		actor.SX, actor.SY = bone.GetWorldScaleX(), bone.GetWorldScaleY()
+	}
+}
+
+func (self *SkeletonActor) draw(batch g2d.Batch, parentAlpha float32) {
+	self.computeTransform()
+	if self.Color != nil {
+		self.Skeleton.Draw(batch, self.localTransform, self.Color.R, self.Color.G, self.Color.B, self.Color.A*parentAlpha)
+	} else {
+		self.Skeleton.Draw(batch, self.localTransform, 1, 1, 1, parentAlpha)
+	}
+}
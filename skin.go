@@ -0,0 +1,611 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pyros2097/spike/g2d"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"golang.org/x/mobile/asset"
+)
+
+// The style types a skin file can define, by the names of their sections
+var skinStyles = map[string]reflect.Type{
+	"LabelStyle":       reflect.TypeOf(LabelStyle{}),
+	"ButtonStyle":      reflect.TypeOf(ButtonStyle{}),
+	"TextButtonStyle":  reflect.TypeOf(TextButtonStyle{}),
+	"ImageButtonStyle": reflect.TypeOf(ImageButtonStyle{}),
+	"CheckBoxStyle":    reflect.TypeOf(CheckBoxStyle{}),
+	"ProgressBarStyle": reflect.TypeOf(ProgressBarStyle{}),
+	"SliderStyle":      reflect.TypeOf(SliderStyle{}),
+	"TextFieldStyle":   reflect.TypeOf(TextFieldStyle{}),
+	"ScrollPaneStyle":  reflect.TypeOf(ScrollPaneStyle{}),
+	"WindowStyle":      reflect.TypeOf(WindowStyle{}),
+}
+
+var (
+	drawableType = reflect.TypeOf((*ui.Drawable)(nil)).Elem()
+	colorType    = reflect.TypeOf((*Color)(nil))
+	fontType     = reflect.TypeOf((*g2d.BitmapFont)(nil))
+)
+
+// Makes the style type readable from skin files, in the section named after the type. The style is a struct or a pointer
+// to one, its drawable, color and font fields are read as the names of the resources of the skin.
+func RegisterSkinStyle(style interface{}) {
+	styleType := reflect.TypeOf(style)
+	if styleType.Kind() == reflect.Ptr {
+		styleType = styleType.Elem()
+	}
+	skinStyles[styleType.Name()] = styleType
+}
+
+// A Skin holds the colors, fonts, drawables and widget styles of a UI by name, so that the widgets can be created by the
+// names of their styles. It is read from a JSON file of sections of named resources:
+//
+//	{
+//	  "Color": {
+//	    "white": {"r": 1, "g": 1, "b": 1, "a": 1},
+//	    "gold": {"hex": "ffd700"}
+//	  },
+//	  "BitmapFont": {
+//	    "default": {"file": "arial-15.fnt"}
+//	  },
+//	  "NinePatch": {
+//	    "panel": {"region": "panel", "left": 4, "right": 4, "top": 4, "bottom": 4}
+//	  },
+//	  "TintedDrawable": {
+//	    "dialogDim": {"name": "white", "color": {"r": 0, "g": 0, "b": 0, "a": 0.45}}
+//	  },
+//	  "TextButtonStyle": {
+//	    "default": {"up": "button", "down": "button-down", "font": "default", "fontColor": "white"},
+//	    "toggle": {"parent": "default", "checked": "button-down"}
+//	  }
+//	}
+//
+// The drawables are the regions of the atlas of the skin, regions with splits are nine patches. A style with a parent
+// starts as a copy of the parent style of the same type. The sections may be named like the libgdx classes, with their
+// package and outer class.
+//
+//	skin := spike.LoadSkin("uiskin")
+//	play := skin.NewTextButton("Play", "default")
+//	...
+//	skin.Apply(spike.LoadSkin("uiskin-dark"))
+type Skin struct {
+	Atlas *g2d.TextureAtlas
+
+	colors    map[string]*Color
+	fonts     map[string]*g2d.BitmapFont
+	drawables map[string]ui.Drawable
+	styles    map[string]map[string]interface{}
+}
+
+// Creates an empty skin whose drawables are found in the atlas, which may be nil
+func NewSkin(atlas *g2d.TextureAtlas) *Skin {
+	return &Skin{Atlas: atlas, colors: make(map[string]*Color), fonts: make(map[string]*g2d.BitmapFont),
+		drawables: make(map[string]ui.Drawable), styles: make(map[string]map[string]interface{})}
+}
+
+// Reads the resources and styles of the skin file, the font files are in the directory dir of the assets. Resources with
+// the names of resources of the skin replace them.
+func (self *Skin) Read(r io.Reader, dir string) error {
+	sections := map[string]map[string]json.RawMessage{}
+	if err := json.NewDecoder(r).Decode(&sections); err != nil {
+		return errors.New("skin: " + err.Error())
+	}
+	kinds := map[string]map[string]json.RawMessage{}
+	for section, entries := range sections {
+		// the libgdx sections are named like com.badlogic.gdx.scenes.scene2d.ui.TextButton$TextButtonStyle
+		kind := section[strings.LastIndexAny(section, ".$")+1:]
+		kinds[kind] = entries
+	}
+	for _, kind := range []string{"Color", "BitmapFont", "NinePatch", "TintedDrawable"} {
+		for _, name := range sortedNames(kinds[kind]) {
+			if err := self.readResource(kind, name, kinds[kind][name], dir); err != nil {
+				return err
+			}
+		}
+		delete(kinds, kind)
+	}
+	for _, kind := range sortedNames(kinds) {
+		styleType, ok := skinStyles[kind]
+		if !ok {
+			return errors.New("skin: unknown section " + kind)
+		}
+		if err := self.readStyles(kind, styleType, kinds[kind]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the names of the map in order, so the skin is read the same way every time
+func sortedNames(entries interface{}) []string {
+	keys := reflect.ValueOf(entries).MapKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.String()
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (self *Skin) readResource(kind, name string, raw json.RawMessage, dir string) error {
+	switch kind {
+	case "Color":
+		color, err := self.readColor(raw)
+		if err != nil {
+			return err
+		}
+		self.AddColor(name, color)
+	case "BitmapFont":
+		var entry struct {
+			File  string
+			Scale float32
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return errors.New("skin: font " + name + ": " + err.Error())
+		}
+		rc, err := asset.Open(path.Join(dir, entry.File))
+		if err != nil {
+			return errors.New("skin: font " + name + ": " + err.Error())
+		}
+		defer rc.Close()
+		font, err := g2d.ReadBitmapFont(rc, path.Join(dir, path.Dir(entry.File)))
+		if err != nil {
+			return err
+		}
+		if entry.Scale != 0 {
+			font.SetScale(entry.Scale, entry.Scale)
+		}
+		self.AddFont(name, font)
+	case "NinePatch":
+		var entry struct {
+			Region                   string
+			Left, Right, Top, Bottom int
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return errors.New("skin: nine patch " + name + ": " + err.Error())
+		}
+		region := self.findRegion(entry.Region)
+		if region == nil {
+			return errors.New("skin: no region named " + entry.Region)
+		}
+		self.AddDrawable(name, g2d.NewNinePatch(&region.TextureRegion, entry.Left, entry.Right, entry.Top, entry.Bottom))
+	case "TintedDrawable":
+		var entry struct {
+			Name  string
+			Color json.RawMessage
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return errors.New("skin: tinted drawable " + name + ": " + err.Error())
+		}
+		drawable, err := self.drawable(entry.Name)
+		if err != nil {
+			return err
+		}
+		color, err := self.readColor(entry.Color)
+		if err != nil {
+			return err
+		}
+		self.AddDrawable(name, NewTintedDrawable(drawable, color))
+	}
+	return nil
+}
+
+// Reads a color, which is the name of a color of the skin or of the named colors, the components or a hex string
+func (self *Skin) readColor(raw json.RawMessage) (*Color, error) {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		if color, ok := self.colors[name]; ok {
+			return color, nil
+		}
+		if color := GetColor(name); color != nil {
+			return color, nil
+		}
+		return nil, errors.New("skin: no color named " + name)
+	}
+	var entry struct {
+		R, G, B float32
+		A       *float32
+		Hex     string
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, errors.New("skin: color: " + err.Error())
+	}
+	if entry.Hex != "" {
+		hex := strings.TrimPrefix(entry.Hex, "#")
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		rgba, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 8 {
+			return nil, errors.New("skin: bad color " + entry.Hex)
+		}
+		return NewColor(float32(rgba>>24)/255, float32(rgba>>16&0xff)/255, float32(rgba>>8&0xff)/255,
+			float32(rgba&0xff)/255), nil
+	}
+	alpha := float32(1)
+	if entry.A != nil {
+		alpha = *entry.A
+	}
+	return NewColor(entry.R, entry.G, entry.B, alpha), nil
+}
+
+// Reads the styles of the section, the parents of the styles are read before them
+func (self *Skin) readStyles(kind string, styleType reflect.Type, entries map[string]json.RawMessage) error {
+	done := map[string]bool{}
+	var read func(name string, children []string) error
+	read = func(name string, children []string) error {
+		if done[name] {
+			return nil
+		}
+		for _, child := range children {
+			if child == name {
+				return errors.New("skin: " + kind + " " + name + " is its own parent")
+			}
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(entries[name], &fields); err != nil {
+			return errors.New("skin: " + kind + " " + name + ": " + err.Error())
+		}
+		style := reflect.New(styleType)
+		if raw, ok := fields["parent"]; ok {
+			var parent string
+			if err := json.Unmarshal(raw, &parent); err != nil {
+				return errors.New("skin: " + kind + " " + name + ": " + err.Error())
+			}
+			if _, ok := entries[parent]; ok {
+				if err := read(parent, append(children, name)); err != nil {
+					return err
+				}
+			}
+			parentStyle, ok := self.styles[kind][parent]
+			if !ok {
+				return errors.New("skin: no " + kind + " named " + parent)
+			}
+			style.Elem().Set(reflect.ValueOf(parentStyle).Elem())
+			delete(fields, "parent")
+		}
+		for _, key := range sortedNames(fields) {
+			if err := self.setField(style.Elem(), key, fields[key]); err != nil {
+				return errors.New("skin: " + kind + " " + name + ": " + err.Error())
+			}
+		}
+		self.AddStyle(name, style.Interface())
+		done[name] = true
+		return nil
+	}
+	for _, name := range sortedNames(entries) {
+		if err := read(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sets the field of the style named like the key, the drawables, colors and fonts are looked up in the skin
+func (self *Skin) setField(style reflect.Value, key string, raw json.RawMessage) error {
+	field := style.FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, key)
+	})
+	if !field.IsValid() || !field.CanSet() {
+		return errors.New("unknown field " + key)
+	}
+	var name string
+	switch field.Type() {
+	case drawableType:
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return err
+		}
+		drawable, err := self.drawable(name)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(&drawable).Elem())
+	case colorType:
+		color, err := self.readColor(raw)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(color))
+	case fontType:
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return err
+		}
+		font, ok := self.fonts[name]
+		if !ok {
+			return errors.New("no font named " + name)
+		}
+		field.Set(reflect.ValueOf(font))
+	default:
+		return json.Unmarshal(raw, field.Addr().Interface())
+	}
+	return nil
+}
+
+// Returns the region of the atlas of the skin or of the loaded atlases with the name, or nil if there is none
+func (self *Skin) findRegion(name string) *g2d.AtlasRegion {
+	if self.Atlas != nil {
+		if region := self.Atlas.FindRegion(name); region != nil {
+			return region
+		}
+	}
+	for _, atlas := range atlases {
+		if region := atlas.FindRegion(name); region != nil {
+			return region
+		}
+	}
+	return nil
+}
+
+// Returns the drawable with the name, a region of the atlas is made a drawable the first time it is used
+func (self *Skin) drawable(name string) (ui.Drawable, error) {
+	if drawable, ok := self.drawables[name]; ok {
+		return drawable, nil
+	}
+	region := self.findRegion(name)
+	if region == nil {
+		return nil, errors.New("skin: no drawable named " + name)
+	}
+	var drawable ui.Drawable = ui.NewRegionDrawable(region)
+	if region.Splits != nil {
+		patch, err := g2d.NewNinePatchAtlas(region)
+		if err != nil {
+			return nil, err
+		}
+		drawable = patch
+	}
+	self.drawables[name] = drawable
+	return drawable, nil
+}
+
+// Returns a drawable which draws the drawable tinted with the color
+func NewTintedDrawable(drawable ui.Drawable, color *Color) ui.Drawable {
+	tint := g2d.Color{R: color.R, G: color.G, B: color.B, A: color.A}
+	if patch, ok := drawable.(*g2d.NinePatch); ok {
+		// nine patches are drawn with their own color
+		tinted := g2d.NewNinePatchCopy(patch)
+		tinted.Color = g2d.Color{R: patch.Color.R * tint.R, G: patch.Color.G * tint.G, B: patch.Color.B * tint.B,
+			A: patch.Color.A * tint.A}
+		return tinted
+	}
+	return ui.NewTintedDrawable(drawable, tint)
+}
+
+func (self *Skin) AddColor(name string, color *Color) {
+	self.colors[name] = color
+}
+
+// Returns the color with the name, it panics if there is none
+func (self *Skin) GetColor(name string) *Color {
+	color, ok := self.colors[name]
+	if !ok {
+		panic("skin: no color named " + name)
+	}
+	return color
+}
+
+func (self *Skin) AddFont(name string, font *g2d.BitmapFont) {
+	self.fonts[name] = font
+}
+
+// Returns the font with the name, it panics if there is none
+func (self *Skin) GetFont(name string) *g2d.BitmapFont {
+	font, ok := self.fonts[name]
+	if !ok {
+		panic("skin: no font named " + name)
+	}
+	return font
+}
+
+func (self *Skin) AddDrawable(name string, drawable ui.Drawable) {
+	self.drawables[name] = drawable
+}
+
+// Returns the drawable with the name, which is a drawable added to the skin or a region of its atlas. It panics if there
+// is none.
+func (self *Skin) GetDrawable(name string) ui.Drawable {
+	drawable, err := self.drawable(name)
+	if err != nil {
+		panic(err)
+	}
+	return drawable
+}
+
+// Returns the drawable with the name tinted with the color
+func (self *Skin) NewDrawable(name string, tint *Color) ui.Drawable {
+	return NewTintedDrawable(self.GetDrawable(name), tint)
+}
+
+// Adds the style, which is a pointer to a style struct, with the name. It replaces the style of the same type and name.
+func (self *Skin) AddStyle(name string, style interface{}) {
+	kind := reflect.TypeOf(style).Elem().Name()
+	if self.styles[kind] == nil {
+		self.styles[kind] = make(map[string]interface{})
+	}
+	self.styles[kind][name] = style
+}
+
+// Returns the style of the type, like "TextButtonStyle", with the name. It panics if there is none.
+func (self *Skin) GetStyle(kind, name string) interface{} {
+	style, ok := self.styles[kind][name]
+	if !ok {
+		panic("skin: no " + kind + " named " + name)
+	}
+	return style
+}
+
+// Returns whether the skin has a resource or style of the type with the name, the types of the resources are Color,
+// BitmapFont and Drawable
+func (self *Skin) Has(kind, name string) bool {
+	switch kind {
+	case "Color":
+		_, ok := self.colors[name]
+		return ok
+	case "BitmapFont":
+		_, ok := self.fonts[name]
+		return ok
+	case "Drawable":
+		_, err := self.drawable(name)
+		return err == nil
+	}
+	_, ok := self.styles[kind][name]
+	return ok
+}
+
+// Restyles the UI with the theme, a skin with the same names like a dark version of the skin. The resources and styles of
+// the theme replace those of the skin, the styles and colors are copied into those of the skin so that the widgets
+// created with them are drawn with the theme. The widgets of the scenes and their HUDs are laid out again.
+func (self *Skin) Apply(theme *Skin) {
+	for name, color := range theme.colors {
+		if old, ok := self.colors[name]; ok && old != color {
+			old.SetColor(color)
+		} else {
+			self.colors[name] = color
+		}
+	}
+	for name, font := range theme.fonts {
+		self.fonts[name] = font
+	}
+	for name, drawable := range theme.drawables {
+		self.drawables[name] = drawable
+	}
+	if theme.Atlas != nil {
+		self.Atlas = theme.Atlas
+	}
+	for kind, styles := range theme.styles {
+		for name, style := range styles {
+			if old, ok := self.styles[kind][name]; ok && old != style {
+				reflect.ValueOf(old).Elem().Set(reflect.ValueOf(style).Elem())
+			} else {
+				self.AddStyle(name, style)
+			}
+		}
+	}
+	for _, scene := range allScenes {
+		invalidateWidgets(&scene.Actor)
+		if scene.hud != nil {
+			invalidateWidgets(scene.hud)
+		}
+	}
+}
+
+// Invalidates the widgets of the actor and its descendants
+func invalidateWidgets(actor *Actor) {
+	if actor.Widget != nil {
+		actor.Widget.Invalidate()
+	}
+	for _, child := range actor.Children {
+		invalidateWidgets(child)
+	}
+}
+
+func (self *Skin) GetLabelStyle(name string) *LabelStyle {
+	return self.GetStyle("LabelStyle", name).(*LabelStyle)
+}
+
+func (self *Skin) GetButtonStyle(name string) *ButtonStyle {
+	return self.GetStyle("ButtonStyle", name).(*ButtonStyle)
+}
+
+func (self *Skin) GetTextButtonStyle(name string) *TextButtonStyle {
+	return self.GetStyle("TextButtonStyle", name).(*TextButtonStyle)
+}
+
+func (self *Skin) GetImageButtonStyle(name string) *ImageButtonStyle {
+	return self.GetStyle("ImageButtonStyle", name).(*ImageButtonStyle)
+}
+
+func (self *Skin) GetCheckBoxStyle(name string) *CheckBoxStyle {
+	return self.GetStyle("CheckBoxStyle", name).(*CheckBoxStyle)
+}
+
+func (self *Skin) GetProgressBarStyle(name string) *ProgressBarStyle {
+	return self.GetStyle("ProgressBarStyle", name).(*ProgressBarStyle)
+}
+
+func (self *Skin) GetSliderStyle(name string) *SliderStyle {
+	return self.GetStyle("SliderStyle", name).(*SliderStyle)
+}
+
+func (self *Skin) GetTextFieldStyle(name string) *TextFieldStyle {
+	return self.GetStyle("TextFieldStyle", name).(*TextFieldStyle)
+}
+
+func (self *Skin) GetScrollPaneStyle(name string) *ScrollPaneStyle {
+	return self.GetStyle("ScrollPaneStyle", name).(*ScrollPaneStyle)
+}
+
+func (self *Skin) GetWindowStyle(name string) *WindowStyle {
+	return self.GetStyle("WindowStyle", name).(*WindowStyle)
+}
+
+// Creates a label with the LabelStyle of the name
+func (self *Skin) NewLabel(text, style string) *Label {
+	return NewLabel(text, self.GetLabelStyle(style))
+}
+
+// Creates a button with the ButtonStyle of the name
+func (self *Skin) NewBaseButton(style string) *BaseButton {
+	return NewBaseButton(self.GetButtonStyle(style))
+}
+
+// Creates a text button with the TextButtonStyle of the name
+func (self *Skin) NewTextButton(text, style string) *TextButton {
+	return NewTextButton(text, self.GetTextButtonStyle(style))
+}
+
+// Creates an image button with the ImageButtonStyle of the name
+func (self *Skin) NewImageButton(style string) *ImageButton {
+	return NewImageButton(self.GetImageButtonStyle(style))
+}
+
+// Creates a check box with the CheckBoxStyle of the name
+func (self *Skin) NewCheckBox(text, style string) *CheckBox {
+	return NewCheckBox(text, self.GetCheckBoxStyle(style))
+}
+
+// Creates a progress bar with the ProgressBarStyle of the name
+func (self *Skin) NewProgressBar(min, max, stepSize float32, vertical bool, style string) *ProgressBar {
+	return NewProgressBar(min, max, stepSize, vertical, self.GetProgressBarStyle(style))
+}
+
+// Creates a slider with the SliderStyle of the name
+func (self *Skin) NewSlider(min, max, stepSize float32, vertical bool, style string) *Slider {
+	return NewSlider(min, max, stepSize, vertical, self.GetSliderStyle(style))
+}
+
+// Creates a text field with the TextFieldStyle of the name
+func (self *Skin) NewTextField(text, style string) *TextField {
+	return NewTextField(text, self.GetTextFieldStyle(style))
+}
+
+// Creates a scroll pane of the actor with the ScrollPaneStyle of the name
+func (self *Skin) NewScrollPane(actor *Actor, style string) *ScrollPane {
+	return NewScrollPane(actor, self.GetScrollPaneStyle(style))
+}
+
+// Creates an image of the drawable with the name
+func (self *Skin) NewImage(drawable string) *Image {
+	return NewImage(self.GetDrawable(drawable))
+}
+
+// Creates a window with the WindowStyle of the name
+func (self *Skin) NewWindow(title, style string) *Window {
+	return NewWindow(title, self.GetWindowStyle(style))
+}
+
+// Creates a dialog with the WindowStyle of the name
+func (self *Skin) NewDialog(title, style string) *Dialog {
+	return NewDialog(title, self.GetWindowStyle(style))
+}
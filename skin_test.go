@@ -0,0 +1,158 @@
+package spike
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"github.com/pyros2097/spike/utils"
+)
+
+// Returns a skin with the font named default and the drawable named panel
+func newTestSkin() *Skin {
+	skin := NewSkin(nil)
+	skin.AddFont("default", newTestFont())
+	skin.AddDrawable("panel", ui.NewRegionDrawable(&g2d.AtlasRegion{OriginalWidth: 10, OriginalHeight: 10}))
+	return skin
+}
+
+func checkColor(t *testing.T, name string, color *Color, r, g, b, a float32) {
+	if color == nil || !near(color.R, r, 0.001) || !near(color.G, g, 0.001) || !near(color.B, b, 0.001) ||
+		!near(color.A, a, 0.001) {
+		t.Errorf("%s is %v, expected %v, %v, %v, %v", name, color, r, g, b, a)
+	}
+}
+
+func TestSkinRead(t *testing.T) {
+	skin := newTestSkin()
+	err := skin.Read(strings.NewReader(`{
+		"Color": {
+			"white": {"r": 1, "g": 1, "b": 1},
+			"gold": {"hex": "#ffd70080"},
+			"dim": {"r": 0, "g": 0, "b": 0, "a": 0.5}
+		},
+		"TintedDrawable": {
+			"shade": {"name": "panel", "color": "dim"}
+		},
+		"com.badlogic.gdx.scenes.scene2d.ui.TextButton$TextButtonStyle": {
+			"default": {"up": "panel", "font": "default", "fontColor": "white"},
+			"toggle": {"parent": "default", "checked": "shade", "fontColor": "BLACK"},
+			"gold": {"parent": "toggle", "overFontColor": "gold"}
+		},
+		"LabelStyle": {
+			"default": {"font": "default", "fontColor": {"r": 0.5, "g": 0.5, "b": 0.5}}
+		}
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the alpha is 1 if it is not given
+	checkColor(t, "white", skin.GetColor("white"), 1, 1, 1, 1)
+	checkColor(t, "gold", skin.GetColor("gold"), 1, 215.0/255, 0, 128.0/255)
+	checkColor(t, "label color", skin.GetLabelStyle("default").FontColor, 0.5, 0.5, 0.5, 1)
+	panel := skin.GetDrawable("panel")
+	shade, ok := skin.GetDrawable("shade").(*ui.TintedDrawable)
+	if !ok || shade.Drawable != panel || shade.Tint != (g2d.Color{R: 0, G: 0, B: 0, A: 0.5}) {
+		t.Errorf("tinted drawable %v", skin.GetDrawable("shade"))
+	}
+
+	font := skin.GetFont("default")
+	base := skin.GetTextButtonStyle("default")
+	if base.Up != panel || base.Font != font || base.FontColor != skin.GetColor("white") || base.Checked != nil {
+		t.Errorf("default style %+v", base)
+	}
+	// the children start as copies of their parents, which are read first also when they are named after them
+	toggle := skin.GetTextButtonStyle("toggle")
+	if toggle.Up != panel || toggle.Font != font || toggle.Checked != shade || toggle.FontColor != BLACK {
+		t.Errorf("toggle style %+v", toggle)
+	}
+	gold := skin.GetTextButtonStyle("gold")
+	if gold.Up != panel || gold.Checked != shade || gold.FontColor != BLACK || gold.OverFontColor != skin.GetColor("gold") {
+		t.Errorf("gold style %+v", gold)
+	}
+	if toggle.OverFontColor != nil || base.FontColor != skin.GetColor("white") {
+		t.Error("a child changed its parent")
+	}
+
+	if !skin.Has("Drawable", "shade") || !skin.Has("TextButtonStyle", "gold") || skin.Has("LabelStyle", "gold") ||
+		!skin.Has("Color", "dim") || skin.Has("BitmapFont", "bold") {
+		t.Error("wrong resources")
+	}
+}
+
+func TestSkinReadErrors(t *testing.T) {
+	for _, test := range []struct{ json, err string }{
+		{`{"LabelStyle": {"a": {"parent": "a"}}}`, "LabelStyle a is its own parent"},
+		{`{"LabelStyle": {"a": {"parent": "b"}, "b": {"parent": "c"}, "c": {"parent": "a"}}}`, "is its own parent"},
+		{`{"LabelStyle": {"a": {"parent": "b"}}}`, "no LabelStyle named b"},
+		{`{"LabelStyle": {"a": {"size": 3}}}`, "unknown field size"},
+		{`{"LabelStyle": {"a": {"background": "none"}}}`, "no drawable named none"},
+		{`{"LabelStyle": {"a": {"font": "bold"}}}`, "no font named bold"},
+		{`{"Color": {"a": {"hex": "12"}}}`, "bad color 12"},
+		{`{"Color": {"a": "mauve"}}`, "no color named mauve"},
+		{`{"Widget": {}}`, "unknown section Widget"},
+		{`{"Color": [}`, "skin: "},
+	} {
+		err := newTestSkin().Read(strings.NewReader(test.json), "")
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error %v, expected %q", test.json, err, test.err)
+		}
+	}
+}
+
+func TestSkinApply(t *testing.T) {
+	skin := newTestSkin()
+	text := NewColor(1, 1, 1, 1)
+	skin.AddColor("text", text)
+	style := &TextButtonStyle{Font: skin.GetFont("default"), FontColor: text}
+	skin.AddStyle("default", style)
+	button := skin.NewTextButton("Play", "default")
+	if button.GetPrefWidth() != 40 {
+		t.Fatalf("button %v wide", button.GetPrefWidth())
+	}
+	windowStyle := &WindowStyle{TitleFont: skin.GetFont("default"), TitleFontColor: text}
+	skin.AddStyle("default", windowStyle)
+	window := skin.NewWindow("Menu", "default")
+	hudButton := skin.NewTextButton("Back", "default")
+	scene := &Scene{Name: "skin"}
+	scene.AddActor(&button.Actor)
+	scene.AddActor(&window.Actor)
+	scene.AddHud(&hudButton.Actor, utils.AlignmentTop|utils.AlignmentLeft, 0, 0)
+	allScenes[scene.Name] = scene
+	defer RemoveScene(scene.Name)
+
+	theme := NewSkin(nil)
+	big := newTestFont()
+	big.SetScale(2, 2)
+	theme.AddFont("default", big)
+	theme.AddColor("text", NewColor(0.2, 0.2, 0.2, 1))
+	theme.AddColor("accent", NewColor(1, 0, 0, 1))
+	theme.AddStyle("default", &TextButtonStyle{Font: big, FontColor: theme.colors["text"]})
+	theme.AddStyle("default", &LabelStyle{Font: big})
+	theme.AddStyle("default", &WindowStyle{TitleFont: big, TitleFontColor: theme.colors["accent"]})
+	skin.Apply(theme)
+
+	// the styles and colors are changed in place, so the widgets created with them use the theme
+	if skin.GetTextButtonStyle("default") != style || style.Font != big {
+		t.Errorf("style not copied %+v", skin.GetTextButtonStyle("default"))
+	}
+	checkColor(t, "text", text, 0.2, 0.2, 0.2, 1)
+	checkColor(t, "font color", style.FontColor, 0.2, 0.2, 0.2, 1)
+	if skin.GetFont("default") != big || skin.GetColor("accent") != theme.colors["accent"] ||
+		skin.GetLabelStyle("default").Font != big {
+		t.Error("resources of the theme not added")
+	}
+	// the label of the button is laid out with the font of the theme
+	if button.GetLabel().GetStyle().Font != big || button.GetLabel().GetPrefWidth() != 80 || button.GetPrefWidth() != 80 {
+		t.Errorf("label %v wide in a button %v wide", button.GetLabel().GetPrefWidth(), button.GetPrefWidth())
+	}
+	if hudButton.GetLabel().GetPrefWidth() != 80 {
+		t.Errorf("label of the HUD button %v wide", hudButton.GetLabel().GetPrefWidth())
+	}
+	// the title of the window too, with the font color of the theme
+	title := window.GetTitleLabel()
+	if title.GetStyle().Font != big || title.GetStyle().FontColor != skin.GetColor("accent") || title.GetPrefWidth() != 80 {
+		t.Errorf("title %v wide in %+v", title.GetPrefWidth(), title.GetStyle())
+	}
+}
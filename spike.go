@@ -8,7 +8,10 @@ import (
 	"encoding/binary"
 	"time"
 
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
 	"golang.org/x/mobile/app"
+	"golang.org/x/mobile/event/key"
 	"golang.org/x/mobile/event/lifecycle"
 	"golang.org/x/mobile/event/paint"
 	"golang.org/x/mobile/event/size"
@@ -23,7 +26,6 @@ var (
 	targetWidth  float32
 	targetHeight float32
 	PauseState   bool
-	// DebugColor Color{0, 1, 1, 1}
 
 	running   bool
 	fpsTicker *time.Ticker
@@ -42,16 +44,53 @@ var (
 	touchX float32
 	touchY float32
 
+	touchPointers = make(map[touch.Sequence]uint8)
+
 	err error
 )
 
 type SBatch struct {
+	transform  *vector.Matrix4
+	projection *vector.Matrix4
+	color      g2d.Color
 }
 
 func (b SBatch) Begin() {}
 func (b SBatch) End()   {}
 
-var tempBatch *SBatch
+func (b *SBatch) GetTransformMatrix() *vector.Matrix4 {
+	return b.transform
+}
+
+func (b *SBatch) SetTransformMatrix(transform *vector.Matrix4) {
+	b.transform.SetM4(transform)
+}
+
+func (b *SBatch) GetProjectionMatrix() *vector.Matrix4 {
+	return b.projection
+}
+
+func (b *SBatch) SetProjectionMatrix(projection *vector.Matrix4) {
+	b.projection.SetM4(projection)
+}
+
+func (b *SBatch) SetColor(red, green, blue, alpha float32) {
+	b.color = g2d.Color{R: red, G: green, B: blue, A: alpha}
+}
+
+func (b *SBatch) GetColor() g2d.Color {
+	return b.color
+}
+
+func (b *SBatch) Draw(region *g2d.TextureRegion, width, height float32, transform *vector.Affine2) {}
+
+func (b *SBatch) DrawVertices(texture *g2d.Texture, vertices []float32) {}
+
+func (b *SBatch) SetBlendFunction(srcFunc, dstFunc int) {}
+
+func (b *SBatch) Flush() {}
+
+var tempBatch = &SBatch{transform: vector.NewMatrix4Empty(), projection: vector.NewMatrix4Empty()}
 
 /*Important:
  *  The Target Width  and Target Height refer to the nominal width and height of the game for the
@@ -67,6 +106,7 @@ func Init(title string, width, height float32) {
 	println("Initializing Gdx")
 	targetWidth = width
 	targetHeight = height
+	viewport = NewStretchViewport(width, height, Camera2d)
 	allScenes = make(map[string]*Scene)
 	fpsTicker = time.NewTicker(1000 / 30 * time.Millisecond)
 	running = true
@@ -102,6 +142,8 @@ func Run() {
 					}
 				case size.Event: // resize event
 					sz = e
+					screenWidth, screenHeight = sz.WidthPx, sz.HeightPx
+					viewport.Update(screenWidth, screenHeight, true)
 					touchX = float32(sz.WidthPx / 2)
 					touchY = float32(sz.HeightPx / 2)
 				case paint.Event:
@@ -111,23 +153,25 @@ func Run() {
 						// Keep animating.
 						a.Send(paint.Event{})
 					}
+				case key.Event:
+					doKeyEvent(e)
 				case touch.Event:
 					// print("Touching")
 					// send input events here or before paint just store the last state
 					touchX = e.X
 					touchY = e.Y
+					pointer := touchPointer(e.Sequence)
 					switch e.Type {
 					case touch.TypeBegin:
 						// println("Begin")
-						doTouchDown(touchX, touchY, 0, 0)
+						feedInput(newTouchEvent(TouchDown, touchX, touchY, pointer))
 					case touch.TypeEnd:
 						// println("End")
-						doTouchUp(touchX, touchY, 0, 0)
+						delete(touchPointers, e.Sequence)
+						feedInput(newTouchEvent(TouchUp, touchX, touchY, pointer))
 					case touch.TypeMove:
 						// println("Moving")
-						doTouchDragged(touchX, touchY, 0)
-						// println(e.Sequence)
-						// log.Printf("%d", e.Sequence)
+						feedInput(newTouchEvent(TouchDragged, touchX, touchY, pointer))
 					}
 				}
 			}
@@ -135,6 +179,27 @@ func Run() {
 	})
 }
 
+// Returns the pointer index of a touch sequence. The first finger on the screen is pointer 0, the second pointer 1 and
+// so on, indices are reused once their finger is lifted.
+func touchPointer(sequence touch.Sequence) uint8 {
+	if pointer, ok := touchPointers[sequence]; ok {
+		return pointer
+	}
+	pointer := uint8(0)
+	for used := true; used; {
+		used = false
+		for _, p := range touchPointers {
+			if p == pointer {
+				used = true
+				pointer++
+				break
+			}
+		}
+	}
+	touchPointers[sequence] = pointer
+	return pointer
+}
+
 func GetTouchX() float32 {
 	return touchX
 }
@@ -207,22 +272,32 @@ func appResume() {
 
 // This is the main rendering call that updates the current scene and all children in the scene
 func appPaint(glctx gl.Context, sz size.Event, delta float32) {
+	// the gutters of the viewport are black, the scene is drawn in its screen bounds
+	glctx.Viewport(0, 0, sz.WidthPx, sz.HeightPx)
+	glctx.ClearColor(0, 0, 0, 1)
+	glctx.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	glctx.Viewport(viewport.ScreenX, viewport.ScreenY, viewport.ScreenWidth, viewport.ScreenHeight)
+	glctx.Enable(gl.SCISSOR_TEST)
+	glctx.Scissor(int32(viewport.ScreenX), int32(viewport.ScreenY), int32(viewport.ScreenWidth), int32(viewport.ScreenHeight))
 	glctx.ClearColor(currentScene.BGColor.R, currentScene.BGColor.G, currentScene.BGColor.B, currentScene.BGColor.A)
 	glctx.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-	for _, child := range currentScene.Children {
-		child.act(delta)
-		child.draw(tempBatch, 1.0)
-		if len(InputChannel) > 0 {
-			for e := range InputChannel {
-				if child.Input != nil {
-					child.Input(child, e)
-				}
-				if len(InputChannel) == 0 {
-					break
-				}
-			}
-		}
+	glctx.Disable(gl.SCISSOR_TEST)
+	if currentReplay != nil {
+		currentReplay.Step(currentScene)
+	} else {
+		update(currentScene, delta)
 	}
+	drawContext = glctx
+	post := beginPostProcessor(currentScene, glctx, viewport.ScreenWidth, viewport.ScreenHeight)
+	drawBackground(currentScene, tempBatch)
+	drawScene(currentScene, tempBatch)
+	if post != nil {
+		tempBatch.Flush()
+		post.End(viewport.ScreenX, viewport.ScreenY, viewport.ScreenWidth, viewport.ScreenHeight)
+	}
+	drawSceneDebug(currentScene, debugShapes)
+	drawHud(currentScene, tempBatch)
+	drawHudFocusOutline(currentScene, debugShapes)
 
 	glctx.UseProgram(program)
 
@@ -240,9 +315,24 @@ func appPaint(glctx gl.Context, sz size.Event, delta float32) {
 	glctx.DrawArrays(gl.TRIANGLES, 0, vertexCount)
 	glctx.DisableVertexAttribArray(position)
 
+	glctx.Viewport(0, 0, sz.WidthPx, sz.HeightPx)
 	fps.Draw(sz)
 }
 
+// Updates the scene for one frame without drawing it. The pending input is processed and then all the actors act.
+func update(scene *Scene, delta float32) {
+	frameTime = inputClock()
+	pollControllers()
+	pollTextInput()
+	recordFrame(delta)
+	scene.layoutHud()
+	processInput(scene)
+	actChildren(&scene.Actor, delta)
+	if scene.hud != nil {
+		actChildren(scene.hud, delta)
+	}
+}
+
 var triangleData = f32.Bytes(binary.LittleEndian,
 	0.0, 0.4, 0.0, // top left
 	0.0, 0.0, 0.0, // bottom left
@@ -0,0 +1,1008 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	mathutils "github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"github.com/pyros2097/spike/utils"
+)
+
+// The colors of the debug lines of tables, the cells are outlined in red and the actors in the cells in green. The table
+// itself is outlined in DebugColor.
+var (
+	CellDebugColor  = Color{1, 0, 0, 1}
+	ActorDebugColor = Color{0, 1, 0, 1}
+)
+
+// The size of a cell which is taken from its actor
+const fromActor = -1
+
+// A Table lays out its actors in the cells of rows and columns. The cells are added to the current row and Row starts a
+// new one:
+//
+//	table := spike.NewTable()
+//	table.SetFillParent(true)
+//	table.Add(&title.Actor).Colspan(2)
+//	table.Row()
+//	table.Add(&nameLabel.Actor).Right().Pad(4)
+//	table.Add(&nameField.Actor).ExpandX().FillX()
+//	table.Row()
+//	table.Add(&okButton.Actor).Colspan(2).Right()
+//
+// A column is as wide as the widest cell in it and a row as high as the highest cell in it. The cells are given their
+// preferred sizes if the table is large enough, the space which is left goes to the cells which expand. The table is laid
+// out when it is validated before it is drawn, after it was invalidated by changes to its cells or its size. Set Debug to
+// see the bounds of the cells.
+type Table struct {
+	Actor
+	widget
+
+	cells    []*Cell
+	defaults *Cell
+
+	padTop, padLeft, padBottom, padRight float32
+	align                                utils.Alignment
+	background                           ui.Drawable
+
+	sizeInvalid                                                    bool
+	columns, rows                                                  int
+	columnMinWidth, rowMinHeight, columnPrefWidth, rowPrefHeight   []float32
+	columnWidth, rowHeight, expandWidth, expandHeight              []float32
+	tableMinWidth, tableMinHeight, tablePrefWidth, tablePrefHeight float32
+
+	// the top left corner of the grid of cells from the top left corner of the table
+	gridX, gridY float32
+}
+
+func NewTable() *Table {
+	self := &Table{}
+	self.initTable()
+	return self
+}
+
+// Initializes the table, the widgets which embed a table call it in their constructors
+func (self *Table) initTable() {
+	self.align, self.sizeInvalid = utils.AlignmentCenter, true
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.widget.init(&self.Actor, self)
+	self.defaults = newCell(self)
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.DrawDebugLines = func(a *Actor, shapes *g2d.ShapeRenderer) {
+		self.drawDebugLines(shapes)
+	}
+}
+
+// Returns the cell whose options are given to the cells added after
+func (self *Table) Defaults() *Cell {
+	return self.defaults
+}
+
+// Adds a cell with the actor to the current row, the actor may be nil for an empty cell
+func (self *Table) Add(actor *Actor) *Cell {
+	cell := newCell(self)
+	*cell = *self.defaults
+	cell.endRow = false
+	if actor != nil {
+		self.AddActor(actor)
+		cell.Actor = actor
+	}
+	self.cells = append(self.cells, cell)
+	self.InvalidateHierarchy()
+	return cell
+}
+
+// Ends the current row, the cells added after are in a new row
+func (self *Table) Row() {
+	if len(self.cells) > 0 && !self.cells[len(self.cells)-1].endRow {
+		self.cells[len(self.cells)-1].endRow = true
+		self.InvalidateHierarchy()
+	}
+}
+
+// Returns the cells by row
+func (self *Table) GetCells() []*Cell {
+	return self.cells
+}
+
+// Returns the cell of the actor, or nil
+func (self *Table) GetCell(actor *Actor) *Cell {
+	for _, cell := range self.cells {
+		if cell.Actor == actor {
+			return cell
+		}
+	}
+	return nil
+}
+
+// Removes the cells and their actors
+func (self *Table) Clear() {
+	self.ClearChildren()
+	self.cells = nil
+	self.InvalidateHierarchy()
+}
+
+// Removes the actor and its cell, the cells after it move to fill its place
+func (self *Table) RemoveActor(actor *Actor) bool {
+	if !self.Actor.RemoveActor(actor) {
+		return false
+	}
+	for i, cell := range self.cells {
+		if cell.Actor == actor {
+			if cell.endRow && i > 0 {
+				self.cells[i-1].endRow = true
+			}
+			self.cells = append(self.cells[:i], self.cells[i+1:]...)
+			break
+		}
+	}
+	self.InvalidateHierarchy()
+	return true
+}
+
+// Sets the space between the edges of the table and its cells
+func (self *Table) Pad(top, left, bottom, right float32) *Table {
+	self.padTop, self.padLeft, self.padBottom, self.padRight = top, left, bottom, right
+	self.InvalidateHierarchy()
+	return self
+}
+
+// Sets the padding on all the sides of the table
+func (self *Table) PadAll(pad float32) *Table {
+	return self.Pad(pad, pad, pad, pad)
+}
+
+// Sets where the cells are in the table when it is larger than them. Default is center.
+func (self *Table) Align(align utils.Alignment) *Table {
+	self.align = align
+	self.Invalidate()
+	return self
+}
+
+// Sets the drawable drawn behind the cells at the size of the table, its padding is added to the padding of the table.
+// It may be nil.
+func (self *Table) SetBackground(background ui.Drawable) *Table {
+	self.background = background
+	self.InvalidateHierarchy()
+	return self
+}
+
+func (self *Table) GetBackground() ui.Drawable {
+	return self.background
+}
+
+// Returns the padding of the table with the padding of the background
+func (self *Table) GetPadTop() float32 {
+	if self.background != nil {
+		return self.padTop + self.background.GetPadTop()
+	}
+	return self.padTop
+}
+
+func (self *Table) GetPadLeft() float32 {
+	if self.background != nil {
+		return self.padLeft + self.background.GetPadLeft()
+	}
+	return self.padLeft
+}
+
+func (self *Table) GetPadBottom() float32 {
+	if self.background != nil {
+		return self.padBottom + self.background.GetPadBottom()
+	}
+	return self.padBottom
+}
+
+func (self *Table) GetPadRight() float32 {
+	if self.background != nil {
+		return self.padRight + self.background.GetPadRight()
+	}
+	return self.padRight
+}
+
+// Returns the number of columns, which is the number of columns of the longest row
+func (self *Table) GetColumns() int {
+	self.computeSize()
+	return self.columns
+}
+
+func (self *Table) GetRows() int {
+	self.computeSize()
+	return self.rows
+}
+
+// Returns the width of the column in the last layout
+func (self *Table) GetColumnWidth(column int) float32 {
+	return self.columnWidth[column]
+}
+
+// Returns the height of the row in the last layout
+func (self *Table) GetRowHeight(row int) float32 {
+	return self.rowHeight[row]
+}
+
+// Invalidates the layout and the sizes of the table
+func (self *Table) Invalidate() {
+	self.sizeInvalid = true
+	self.widget.Invalidate()
+}
+
+// Invalidates the table and its parents which are widgets
+func (self *Table) InvalidateHierarchy() {
+	self.widget.InvalidateHierarchy()
+}
+
+func (self *Table) GetMinWidth() float32 {
+	self.computeSize()
+	return self.tableMinWidth
+}
+
+func (self *Table) GetMinHeight() float32 {
+	self.computeSize()
+	return self.tableMinHeight
+}
+
+func (self *Table) GetPrefWidth() float32 {
+	self.computeSize()
+	return self.tablePrefWidth
+}
+
+func (self *Table) GetPrefHeight() float32 {
+	self.computeSize()
+	return self.tablePrefHeight
+}
+
+// Tables have no maximum size
+func (self *Table) GetMaxWidth() float32 {
+	return 0
+}
+
+func (self *Table) GetMaxHeight() float32 {
+	return 0
+}
+
+// Resizes the slice to the length with all values 0
+func resetSizes(values []float32, length int) []float32 {
+	if cap(values) < length {
+		return make([]float32, length)
+	}
+	values = values[:length]
+	for i := range values {
+		values[i] = 0
+	}
+	return values
+}
+
+// Computes the rows and columns of the cells and the minimum and preferred sizes of the columns, rows and table
+func (self *Table) computeSize() {
+	if !self.sizeInvalid {
+		return
+	}
+	self.sizeInvalid = false
+	cells := self.cells
+
+	self.columns, self.rows = 0, 0
+	row, column := 0, 0
+	for _, c := range cells {
+		c.row, c.column = row, column
+		column += c.colspan
+		if column > self.columns {
+			self.columns = column
+		}
+		if c.endRow {
+			row, column = row+1, 0
+		}
+	}
+	self.rows = row
+	if len(cells) > 0 && !cells[len(cells)-1].endRow {
+		self.rows++
+	}
+	columns, rows := self.columns, self.rows
+
+	self.columnMinWidth = resetSizes(self.columnMinWidth, columns)
+	self.columnPrefWidth = resetSizes(self.columnPrefWidth, columns)
+	self.columnWidth = resetSizes(self.columnWidth, columns)
+	self.expandWidth = resetSizes(self.expandWidth, columns)
+	self.rowMinHeight = resetSizes(self.rowMinHeight, rows)
+	self.rowPrefHeight = resetSizes(self.rowPrefHeight, rows)
+	self.rowHeight = resetSizes(self.rowHeight, rows)
+	self.expandHeight = resetSizes(self.expandHeight, rows)
+
+	spaceRightLast := float32(0)
+	for i, c := range cells {
+		column, row, colspan := c.column, c.row, c.colspan
+		if c.expandY != 0 && self.expandHeight[row] == 0 {
+			self.expandHeight[row] = float32(c.expandY)
+		}
+		if colspan == 1 && c.expandX != 0 && self.expandWidth[column] == 0 {
+			self.expandWidth[column] = float32(c.expandX)
+		}
+
+		// the space between cells is the larger space of the two cells, not their sum
+		c.computedPadLeft = c.padLeft
+		if column != 0 {
+			c.computedPadLeft += mathutils.MaxFloat32(0, c.spaceLeft-spaceRightLast)
+		}
+		c.computedPadTop = c.padTop
+		if above := self.cellAbove(i); above != nil {
+			c.computedPadTop += mathutils.MaxFloat32(0, c.spaceTop-above.spaceBottom)
+		}
+		c.computedPadRight = c.padRight
+		if column+colspan != columns {
+			c.computedPadRight += c.spaceRight
+		}
+		c.computedPadBottom = c.padBottom
+		if row != rows-1 {
+			c.computedPadBottom += c.spaceBottom
+		}
+		spaceRightLast = c.spaceRight
+
+		minWidth, minHeight, prefWidth, prefHeight, _, _ := c.sizes()
+		if colspan == 1 {
+			hpadding := c.computedPadLeft + c.computedPadRight
+			self.columnPrefWidth[column] = mathutils.MaxFloat32(self.columnPrefWidth[column], prefWidth+hpadding)
+			self.columnMinWidth[column] = mathutils.MaxFloat32(self.columnMinWidth[column], minWidth+hpadding)
+		}
+		vpadding := c.computedPadTop + c.computedPadBottom
+		self.rowPrefHeight[row] = mathutils.MaxFloat32(self.rowPrefHeight[row], prefHeight+vpadding)
+		self.rowMinHeight[row] = mathutils.MaxFloat32(self.rowMinHeight[row], minHeight+vpadding)
+	}
+
+	// the uniform cells all get the size of the largest of them
+	var uniformMinWidth, uniformPrefWidth, uniformMinHeight, uniformPrefHeight float32
+	for _, c := range cells {
+		if c.uniformX && c.colspan == 1 {
+			uniformMinWidth = mathutils.MaxFloat32(uniformMinWidth, self.columnMinWidth[c.column])
+			uniformPrefWidth = mathutils.MaxFloat32(uniformPrefWidth, self.columnPrefWidth[c.column])
+		}
+		if c.uniformY {
+			uniformMinHeight = mathutils.MaxFloat32(uniformMinHeight, self.rowMinHeight[c.row])
+			uniformPrefHeight = mathutils.MaxFloat32(uniformPrefHeight, self.rowPrefHeight[c.row])
+		}
+	}
+	for _, c := range cells {
+		if c.uniformX && c.colspan == 1 {
+			self.columnMinWidth[c.column] = uniformMinWidth
+			self.columnPrefWidth[c.column] = uniformPrefWidth
+		}
+		if c.uniformY {
+			self.rowMinHeight[c.row] = uniformMinHeight
+			self.rowPrefHeight[c.row] = uniformPrefHeight
+		}
+	}
+
+	// the cells spanning several columns widen the columns if they are not wide enough, the expanding columns first
+	for _, c := range cells {
+		if c.colspan == 1 {
+			continue
+		}
+		minWidth, _, prefWidth, _, _, _ := c.sizes()
+		spannedMinWidth := -(c.computedPadLeft + c.computedPadRight)
+		spannedPrefWidth := spannedMinWidth
+		totalExpandWidth := float32(0)
+		for i := c.column; i < c.column+c.colspan; i++ {
+			spannedMinWidth += self.columnMinWidth[i]
+			spannedPrefWidth += self.columnPrefWidth[i]
+			totalExpandWidth += self.expandWidth[i]
+		}
+		extraMinWidth := mathutils.MaxFloat32(0, minWidth-spannedMinWidth)
+		extraPrefWidth := mathutils.MaxFloat32(0, prefWidth-spannedPrefWidth)
+		for i := c.column; i < c.column+c.colspan; i++ {
+			ratio := 1 / float32(c.colspan)
+			if totalExpandWidth != 0 {
+				ratio = self.expandWidth[i] / totalExpandWidth
+			}
+			self.columnMinWidth[i] += extraMinWidth * ratio
+			self.columnPrefWidth[i] += extraPrefWidth * ratio
+		}
+	}
+
+	self.tableMinWidth, self.tablePrefWidth = 0, 0
+	for i := 0; i < columns; i++ {
+		self.columnPrefWidth[i] = mathutils.MaxFloat32(self.columnPrefWidth[i], self.columnMinWidth[i])
+		self.tableMinWidth += self.columnMinWidth[i]
+		self.tablePrefWidth += self.columnPrefWidth[i]
+	}
+	self.tableMinHeight, self.tablePrefHeight = 0, 0
+	for i := 0; i < rows; i++ {
+		self.rowPrefHeight[i] = mathutils.MaxFloat32(self.rowPrefHeight[i], self.rowMinHeight[i])
+		self.tableMinHeight += self.rowMinHeight[i]
+		self.tablePrefHeight += self.rowPrefHeight[i]
+	}
+	hpadding := self.GetPadLeft() + self.GetPadRight()
+	vpadding := self.GetPadTop() + self.GetPadBottom()
+	self.tableMinWidth += hpadding
+	self.tableMinHeight += vpadding
+	self.tablePrefWidth += hpadding
+	self.tablePrefHeight += vpadding
+	if self.background != nil {
+		self.tableMinWidth = mathutils.MaxFloat32(self.tableMinWidth, self.background.GetMinWidth())
+		self.tableMinHeight = mathutils.MaxFloat32(self.tableMinHeight, self.background.GetMinHeight())
+		self.tablePrefWidth = mathutils.MaxFloat32(self.tablePrefWidth, self.background.GetMinWidth())
+		self.tablePrefHeight = mathutils.MaxFloat32(self.tablePrefHeight, self.background.GetMinHeight())
+	}
+}
+
+// Returns the cell in the row above the cell which covers its column, or nil
+func (self *Table) cellAbove(index int) *Cell {
+	c := self.cells[index]
+	for i := index - 1; i >= 0; i-- {
+		other := self.cells[i]
+		if other.row < c.row-1 {
+			break
+		}
+		if other.row == c.row-1 && other.column <= c.column && c.column < other.column+other.colspan {
+			return other
+		}
+	}
+	return nil
+}
+
+// Sizes the columns and rows to the size of the table and positions and sizes the actors in their cells
+func (self *Table) Layout() {
+	self.computeSize()
+	cells := self.cells
+	columns, rows := self.columns, self.rows
+	padTop, padLeft := self.GetPadTop(), self.GetPadLeft()
+	hpadding := padLeft + self.GetPadRight()
+	vpadding := padTop + self.GetPadBottom()
+	layoutWidth, layoutHeight := self.W, self.H
+
+	totalExpandWidth, totalExpandHeight := float32(0), float32(0)
+	for _, expand := range self.expandWidth {
+		totalExpandWidth += expand
+	}
+	for _, expand := range self.expandHeight {
+		totalExpandHeight += expand
+	}
+
+	// the columns and rows get the space between their minimum and preferred sizes in proportion to that difference
+	columnWeightedWidth := weightedSizes(self.columnMinWidth, self.columnPrefWidth, self.tableMinWidth,
+		self.tablePrefWidth, layoutWidth)
+	rowWeightedHeight := weightedSizes(self.rowMinHeight, self.rowPrefHeight, self.tableMinHeight, self.tablePrefHeight,
+		layoutHeight)
+
+	for i := range self.columnWidth {
+		self.columnWidth[i] = 0
+	}
+	for i := range self.rowHeight {
+		self.rowHeight[i] = 0
+	}
+	for _, c := range cells {
+		spannedWeightedWidth := float32(0)
+		for i := c.column; i < c.column+c.colspan; i++ {
+			spannedWeightedWidth += columnWeightedWidth[i]
+		}
+		weightedHeight := rowWeightedHeight[c.row]
+		_, _, prefWidth, prefHeight, _, _ := c.sizes()
+		c.actorWidth = mathutils.MinFloat32(spannedWeightedWidth-c.computedPadLeft-c.computedPadRight, prefWidth)
+		c.actorHeight = mathutils.MinFloat32(weightedHeight-c.computedPadTop-c.computedPadBottom, prefHeight)
+		if c.colspan == 1 {
+			self.columnWidth[c.column] = mathutils.MaxFloat32(self.columnWidth[c.column], spannedWeightedWidth)
+		}
+		self.rowHeight[c.row] = mathutils.MaxFloat32(self.rowHeight[c.row], weightedHeight)
+	}
+
+	// the space which is left goes to the expanding columns and rows
+	if totalExpandWidth > 0 {
+		distribute(self.columnWidth, self.expandWidth, totalExpandWidth, layoutWidth-hpadding)
+	}
+	if totalExpandHeight > 0 {
+		distribute(self.rowHeight, self.expandHeight, totalExpandHeight, layoutHeight-vpadding)
+	}
+
+	// the width the cells spanning several columns need is shared by their columns
+	for _, c := range cells {
+		if c.colspan == 1 {
+			continue
+		}
+		extraWidth := float32(0)
+		for i := c.column; i < c.column+c.colspan; i++ {
+			extraWidth += columnWeightedWidth[i] - self.columnWidth[i]
+		}
+		extraWidth -= mathutils.MaxFloat32(0, c.computedPadLeft+c.computedPadRight)
+		extraWidth /= float32(c.colspan)
+		if extraWidth > 0 {
+			for i := c.column; i < c.column+c.colspan; i++ {
+				self.columnWidth[i] += extraWidth
+			}
+		}
+	}
+
+	tableWidth, tableHeight := hpadding, vpadding
+	for i := 0; i < columns; i++ {
+		tableWidth += self.columnWidth[i]
+	}
+	for i := 0; i < rows; i++ {
+		tableHeight += self.rowHeight[i]
+	}
+
+	// the grid is placed in the table by the alignment, the cells are positioned from the top left corner
+	x := padLeft
+	if self.align&utils.AlignmentRight != 0 {
+		x += layoutWidth - tableWidth
+	} else if self.align&utils.AlignmentLeft == 0 {
+		x += (layoutWidth - tableWidth) / 2
+	}
+	y := padTop
+	if self.align&utils.AlignmentBottom != 0 {
+		y += layoutHeight - tableHeight
+	} else if self.align&utils.AlignmentTop == 0 {
+		y += (layoutHeight - tableHeight) / 2
+	}
+	self.gridX, self.gridY = x, y
+
+	currentX, currentY := x, y
+	for _, c := range cells {
+		minWidth, minHeight, _, _, maxWidth, maxHeight := c.sizes()
+		spannedCellWidth := -c.computedPadLeft - c.computedPadRight
+		for i := c.column; i < c.column+c.colspan; i++ {
+			spannedCellWidth += self.columnWidth[i]
+		}
+		rowHeight := self.rowHeight[c.row]
+		currentX += c.computedPadLeft
+
+		if c.fillX > 0 {
+			c.actorWidth = mathutils.MaxFloat32(spannedCellWidth*c.fillX, minWidth)
+			if maxWidth > 0 {
+				c.actorWidth = mathutils.MinFloat32(c.actorWidth, maxWidth)
+			}
+		}
+		if c.fillY > 0 {
+			c.actorHeight = mathutils.MaxFloat32(rowHeight*c.fillY-c.computedPadTop-c.computedPadBottom, minHeight)
+			if maxHeight > 0 {
+				c.actorHeight = mathutils.MinFloat32(c.actorHeight, maxHeight)
+			}
+		}
+
+		switch {
+		case c.align&utils.AlignmentLeft != 0:
+			c.actorX = currentX
+		case c.align&utils.AlignmentRight != 0:
+			c.actorX = currentX + spannedCellWidth - c.actorWidth
+		default:
+			c.actorX = currentX + (spannedCellWidth-c.actorWidth)/2
+		}
+		// from the top of the table, it is flipped below
+		var top float32
+		switch {
+		case c.align&utils.AlignmentTop != 0:
+			top = currentY + c.computedPadTop
+		case c.align&utils.AlignmentBottom != 0:
+			top = currentY + rowHeight - c.actorHeight - c.computedPadBottom
+		default:
+			top = currentY + (rowHeight-c.actorHeight+c.computedPadTop-c.computedPadBottom)/2
+		}
+		c.actorY = layoutHeight - top - c.actorHeight
+
+		if c.endRow {
+			currentX = x
+			currentY += rowHeight
+		} else {
+			currentX += spannedCellWidth + c.computedPadRight
+		}
+	}
+
+	for _, c := range cells {
+		if c.Actor == nil {
+			continue
+		}
+		c.Actor.SetBounds(c.actorX, c.actorY, c.actorWidth, c.actorHeight)
+		if c.Actor.Widget != nil {
+			c.Actor.Widget.Validate()
+		}
+	}
+}
+
+// Returns the sizes of the columns or rows between their minimum and preferred sizes for the size of the table
+func weightedSizes(minSizes, prefSizes []float32, tableMin, tablePref, size float32) []float32 {
+	weighted := make([]float32, len(minSizes))
+	totalGrow := tablePref - tableMin
+	if totalGrow == 0 {
+		copy(weighted, minSizes)
+		return weighted
+	}
+	extra := mathutils.MinFloat32(totalGrow, mathutils.MaxFloat32(0, size-tableMin))
+	for i := range weighted {
+		weighted[i] = minSizes[i] + extra*(prefSizes[i]-minSizes[i])/totalGrow
+	}
+	return weighted
+}
+
+// Adds the space of the size left by the sizes to the expanding sizes, in proportion to their expand
+func distribute(sizes, expand []float32, totalExpand, size float32) {
+	extra := size
+	for _, s := range sizes {
+		extra -= s
+	}
+	if extra <= 0 {
+		return
+	}
+	used := float32(0)
+	last := 0
+	for i := range sizes {
+		if expand[i] == 0 {
+			continue
+		}
+		amount := extra * expand[i] / totalExpand
+		sizes[i] += amount
+		used += amount
+		last = i
+	}
+	// the rounding errors go to the last expanding size
+	sizes[last] += extra - used
+}
+
+func (self *Table) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	if self.background == nil {
+		return
+	}
+	color := self.Color
+	if color == nil {
+		color = &Color{1, 1, 1, 1}
+	}
+	batch.SetColor(color.R, color.G, color.B, color.A*parentAlpha)
+	self.computeTransform()
+	self.background.Draw(batch, self.W, self.H, self.localTransform)
+}
+
+// Draws the lines of the table, its cells and their actors in the coordinates of the table
+func (self *Table) drawDebugLines(shapes *g2d.ShapeRenderer) {
+	self.Validate()
+	shapes.Rect(0, 0, self.W, self.H)
+	shapes.SetColor(CellDebugColor.R, CellDebugColor.G, CellDebugColor.B, CellDebugColor.A)
+	for _, c := range self.cells {
+		x, width := self.gridX, float32(0)
+		for i := 0; i < c.column+c.colspan; i++ {
+			if i < c.column {
+				x += self.columnWidth[i]
+			} else {
+				width += self.columnWidth[i]
+			}
+		}
+		top := self.gridY
+		for i := 0; i < c.row; i++ {
+			top += self.rowHeight[i]
+		}
+		height := self.rowHeight[c.row]
+		shapes.Rect(x, self.H-top-height, width, height)
+	}
+	shapes.SetColor(ActorDebugColor.R, ActorDebugColor.G, ActorDebugColor.B, ActorDebugColor.A)
+	for _, c := range self.cells {
+		if c.Actor != nil {
+			shapes.Rect(c.actorX, c.actorY, c.actorWidth, c.actorHeight)
+		}
+	}
+}
+
+// A Cell is a place in a table for an actor, its options say how the actor is sized and positioned in it. The options
+// are set by chaining the methods of the cell returned by Table.Add.
+type Cell struct {
+	// The actor in the cell, nil if the cell is empty
+	Actor *Actor
+
+	minWidth, minHeight, prefWidth, prefHeight, maxWidth, maxHeight float32
+	spaceTop, spaceLeft, spaceBottom, spaceRight                    float32
+	padTop, padLeft, padBottom, padRight                            float32
+	fillX, fillY                                                    float32
+	align                                                           utils.Alignment
+	expandX, expandY                                                int
+	colspan                                                         int
+	uniformX, uniformY                                              bool
+	endRow                                                          bool
+
+	table                                                                *Table
+	column, row                                                          int
+	computedPadTop, computedPadLeft, computedPadBottom, computedPadRight float32
+	actorX, actorY, actorWidth, actorHeight                              float32
+}
+
+func newCell(table *Table) *Cell {
+	return &Cell{table: table, colspan: 1, align: utils.AlignmentCenter, minWidth: fromActor, minHeight: fromActor,
+		prefWidth: fromActor, prefHeight: fromActor, maxWidth: fromActor, maxHeight: fromActor}
+}
+
+// Invalidates the table of the cell and returns the cell
+func (self *Cell) changed() *Cell {
+	self.table.InvalidateHierarchy()
+	return self
+}
+
+// Returns the sizes of the cell, those which are not set are the sizes of its actor. The preferred size is kept between
+// the minimum and maximum size.
+func (self *Cell) sizes() (minWidth, minHeight, prefWidth, prefHeight, maxWidth, maxHeight float32) {
+	minWidth, minHeight, prefWidth, prefHeight = self.minWidth, self.minHeight, self.prefWidth, self.prefHeight
+	maxWidth, maxHeight = self.maxWidth, self.maxHeight
+	actor := self.Actor
+	if actor == nil {
+		actor = &Actor{}
+	}
+	if minWidth == fromActor {
+		minWidth = widgetMinWidth(actor)
+	}
+	if minHeight == fromActor {
+		minHeight = widgetMinHeight(actor)
+	}
+	if prefWidth == fromActor {
+		prefWidth = widgetPrefWidth(actor)
+	}
+	if prefHeight == fromActor {
+		prefHeight = widgetPrefHeight(actor)
+	}
+	if maxWidth == fromActor {
+		maxWidth = widgetMaxWidth(actor)
+	}
+	if maxHeight == fromActor {
+		maxHeight = widgetMaxHeight(actor)
+	}
+	prefWidth, prefHeight = mathutils.MaxFloat32(prefWidth, minWidth), mathutils.MaxFloat32(prefHeight, minHeight)
+	if maxWidth > 0 {
+		prefWidth = mathutils.MinFloat32(prefWidth, maxWidth)
+	}
+	if maxHeight > 0 {
+		prefHeight = mathutils.MinFloat32(prefHeight, maxHeight)
+	}
+	return
+}
+
+// Sets the minimum, preferred and maximum size of the cell, instead of the sizes of its actor
+func (self *Cell) Size(width, height float32) *Cell {
+	self.minWidth, self.prefWidth, self.maxWidth = width, width, width
+	self.minHeight, self.prefHeight, self.maxHeight = height, height, height
+	return self.changed()
+}
+
+// Sets the minimum, preferred and maximum width of the cell
+func (self *Cell) Width(width float32) *Cell {
+	self.minWidth, self.prefWidth, self.maxWidth = width, width, width
+	return self.changed()
+}
+
+// Sets the minimum, preferred and maximum height of the cell
+func (self *Cell) Height(height float32) *Cell {
+	self.minHeight, self.prefHeight, self.maxHeight = height, height, height
+	return self.changed()
+}
+
+func (self *Cell) MinSize(width, height float32) *Cell {
+	self.minWidth, self.minHeight = width, height
+	return self.changed()
+}
+
+func (self *Cell) MinWidth(width float32) *Cell {
+	self.minWidth = width
+	return self.changed()
+}
+
+func (self *Cell) MinHeight(height float32) *Cell {
+	self.minHeight = height
+	return self.changed()
+}
+
+func (self *Cell) PrefSize(width, height float32) *Cell {
+	self.prefWidth, self.prefHeight = width, height
+	return self.changed()
+}
+
+func (self *Cell) PrefWidth(width float32) *Cell {
+	self.prefWidth = width
+	return self.changed()
+}
+
+func (self *Cell) PrefHeight(height float32) *Cell {
+	self.prefHeight = height
+	return self.changed()
+}
+
+// Sets the maximum size of the cell, 0 means no maximum
+func (self *Cell) MaxSize(width, height float32) *Cell {
+	self.maxWidth, self.maxHeight = width, height
+	return self.changed()
+}
+
+func (self *Cell) MaxWidth(width float32) *Cell {
+	self.maxWidth = width
+	return self.changed()
+}
+
+func (self *Cell) MaxHeight(height float32) *Cell {
+	self.maxHeight = height
+	return self.changed()
+}
+
+// Sets the space between the cell and the cells around it. The space between two cells is the larger of their spaces,
+// there is no space at the edges of the table.
+func (self *Cell) Space(space float32) *Cell {
+	return self.SpaceTLBR(space, space, space, space)
+}
+
+func (self *Cell) SpaceTLBR(top, left, bottom, right float32) *Cell {
+	self.spaceTop, self.spaceLeft, self.spaceBottom, self.spaceRight = top, left, bottom, right
+	return self.changed()
+}
+
+func (self *Cell) SpaceTop(space float32) *Cell {
+	self.spaceTop = space
+	return self.changed()
+}
+
+func (self *Cell) SpaceLeft(space float32) *Cell {
+	self.spaceLeft = space
+	return self.changed()
+}
+
+func (self *Cell) SpaceBottom(space float32) *Cell {
+	self.spaceBottom = space
+	return self.changed()
+}
+
+func (self *Cell) SpaceRight(space float32) *Cell {
+	self.spaceRight = space
+	return self.changed()
+}
+
+// Sets the space between the edges of the cell and its actor, which is added to the space of the cells around it
+func (self *Cell) Pad(pad float32) *Cell {
+	return self.PadTLBR(pad, pad, pad, pad)
+}
+
+func (self *Cell) PadTLBR(top, left, bottom, right float32) *Cell {
+	self.padTop, self.padLeft, self.padBottom, self.padRight = top, left, bottom, right
+	return self.changed()
+}
+
+func (self *Cell) PadTop(pad float32) *Cell {
+	self.padTop = pad
+	return self.changed()
+}
+
+func (self *Cell) PadLeft(pad float32) *Cell {
+	self.padLeft = pad
+	return self.changed()
+}
+
+func (self *Cell) PadBottom(pad float32) *Cell {
+	self.padBottom = pad
+	return self.changed()
+}
+
+func (self *Cell) PadRight(pad float32) *Cell {
+	self.padRight = pad
+	return self.changed()
+}
+
+// Sizes the actor to the cell, instead of its preferred size
+func (self *Cell) Fill() *Cell {
+	return self.FillXY(1, 1)
+}
+
+func (self *Cell) FillX() *Cell {
+	return self.FillXY(1, self.fillY)
+}
+
+func (self *Cell) FillY() *Cell {
+	return self.FillXY(self.fillX, 1)
+}
+
+// Sizes the actor to the percentages of the width and height of the cell, 0 for its preferred size
+func (self *Cell) FillXY(x, y float32) *Cell {
+	self.fillX, self.fillY = x, y
+	return self.changed()
+}
+
+// Gives the column and row of the cell the space left in the table
+func (self *Cell) Expand() *Cell {
+	return self.ExpandXY(1, 1)
+}
+
+func (self *Cell) ExpandX() *Cell {
+	return self.ExpandXY(1, self.expandY)
+}
+
+func (self *Cell) ExpandY() *Cell {
+	return self.ExpandXY(self.expandX, 1)
+}
+
+// Sets the weights of the column and row of the cell in sharing the space left with the other expanding columns and rows
+func (self *Cell) ExpandXY(x, y int) *Cell {
+	self.expandX, self.expandY = x, y
+	return self.changed()
+}
+
+// Makes the cell as large as the other uniform cells
+func (self *Cell) Uniform() *Cell {
+	return self.UniformXY(true, true)
+}
+
+func (self *Cell) UniformX() *Cell {
+	return self.UniformXY(true, self.uniformY)
+}
+
+func (self *Cell) UniformY() *Cell {
+	return self.UniformXY(self.uniformX, true)
+}
+
+func (self *Cell) UniformXY(x, y bool) *Cell {
+	self.uniformX, self.uniformY = x, y
+	return self.changed()
+}
+
+// Sets the number of columns the cell spans
+func (self *Cell) Colspan(colspan int) *Cell {
+	self.colspan = colspan
+	return self.changed()
+}
+
+// Sets where the actor is in the cell when it is smaller than the cell. Default is center.
+func (self *Cell) Align(align utils.Alignment) *Cell {
+	self.align = align
+	return self.changed()
+}
+
+// Aligns the actor to the center of the cell
+func (self *Cell) Center() *Cell {
+	return self.Align(utils.AlignmentCenter)
+}
+
+// Aligns the actor to the left edge of the cell, keeping its vertical alignment
+func (self *Cell) Left() *Cell {
+	return self.Align(self.align&^(utils.AlignmentCenter|utils.AlignmentRight) | utils.AlignmentLeft)
+}
+
+// Aligns the actor to the right edge of the cell, keeping its vertical alignment
+func (self *Cell) Right() *Cell {
+	return self.Align(self.align&^(utils.AlignmentCenter|utils.AlignmentLeft) | utils.AlignmentRight)
+}
+
+// Aligns the actor to the top edge of the cell, keeping its horizontal alignment
+func (self *Cell) Top() *Cell {
+	return self.Align(self.align&^(utils.AlignmentCenter|utils.AlignmentBottom) | utils.AlignmentTop)
+}
+
+// Aligns the actor to the bottom edge of the cell, keeping its horizontal alignment
+func (self *Cell) Bottom() *Cell {
+	return self.Align(self.align&^(utils.AlignmentCenter|utils.AlignmentTop) | utils.AlignmentBottom)
+}
+
+// Ends the row of the cell, the cells added after are in a new row
+func (self *Cell) Row() {
+	self.endRow = true
+	self.changed()
+}
+
+// Returns the column of the cell, it is computed when the table is laid out or sized
+func (self *Cell) GetColumn() int {
+	return self.column
+}
+
+// Returns the row of the cell, it is computed when the table is laid out or sized
+func (self *Cell) GetRow() int {
+	return self.row
+}
+
+func (self *Cell) GetColspan() int {
+	return self.colspan
+}
+
+// Returns the bounds of the actor of the cell in the last layout, in the coordinates of the table
+func (self *Cell) GetActorX() float32 {
+	return self.actorX
+}
+
+func (self *Cell) GetActorY() float32 {
+	return self.actorY
+}
+
+func (self *Cell) GetActorWidth() float32 {
+	return self.actorWidth
+}
+
+func (self *Cell) GetActorHeight() float32 {
+	return self.actorHeight
+}
@@ -0,0 +1,222 @@
+package spike
+
+import (
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+	"github.com/pyros2097/spike/utils"
+)
+
+func newTestBox(w, h float32) *Actor {
+	return &Actor{W: w, H: h, SX: 1, SY: 1}
+}
+
+func checkBounds(t *testing.T, name string, actor *Actor, x, y, w, h float32) {
+	if !near(actor.X, x, 0.001) || !near(actor.Y, y, 0.001) || !near(actor.W, w, 0.001) || !near(actor.H, h, 0.001) {
+		t.Errorf("%s at %v, %v size %v, %v, expected %v, %v size %v, %v", name, actor.X, actor.Y, actor.W, actor.H, x, y, w,
+			h)
+	}
+}
+
+func TestTablePrefSize(t *testing.T) {
+	table := NewTable()
+	a, b, c := newTestBox(50, 20), newTestBox(30, 40), newTestBox(100, 10)
+	table.Add(a)
+	table.Add(b)
+	table.Row()
+	table.Add(c).Colspan(2)
+	table.Pad(1, 2, 3, 4)
+	if table.GetColumns() != 2 || table.GetRows() != 2 {
+		t.Fatalf("%d columns and %d rows", table.GetColumns(), table.GetRows())
+	}
+	// the spanning cell widens both columns by 10
+	if table.GetPrefWidth() != 106 || table.GetPrefHeight() != 54 {
+		t.Errorf("preferred size %v, %v", table.GetPrefWidth(), table.GetPrefHeight())
+	}
+	table.Pack()
+	if table.W != 106 || table.H != 54 {
+		t.Errorf("packed to %v, %v", table.W, table.H)
+	}
+	if table.GetColumnWidth(0) != 60 || table.GetColumnWidth(1) != 40 || table.GetRowHeight(0) != 40 {
+		t.Errorf("columns %v, %v and row %v", table.GetColumnWidth(0), table.GetColumnWidth(1), table.GetRowHeight(0))
+	}
+	// the actors are centered in their cells, y goes up from the bottom of the table
+	checkBounds(t, "a", a, 7, 23, 50, 20)
+	checkBounds(t, "b", b, 67, 13, 30, 40)
+	checkBounds(t, "c", c, 2, 3, 100, 10)
+}
+
+func TestTableExpandFill(t *testing.T) {
+	table := NewTable()
+	label, field := newTestBox(40, 10), newTestBox(60, 20)
+	table.Add(label).Left()
+	table.Add(field).ExpandX().FillX()
+	table.Row()
+	footer := newTestBox(10, 10)
+	table.Add(footer).Colspan(2).ExpandY().Bottom().Right()
+	table.SetSize(300, 100)
+	table.Validate()
+	checkBounds(t, "label", label, 0, 85, 40, 10)
+	checkBounds(t, "field", field, 40, 80, 260, 20)
+	checkBounds(t, "footer", footer, 290, 0, 10, 10)
+	if table.GetRowHeight(1) != 80 {
+		t.Errorf("expanding row is %v high", table.GetRowHeight(1))
+	}
+}
+
+func TestTableSpaceAndPad(t *testing.T) {
+	table := NewTable()
+	a, b := newTestBox(10, 10), newTestBox(10, 10)
+	table.Defaults().Space(5)
+	table.Add(a).SpaceRight(8)
+	table.Add(b).PadLeft(2)
+	table.Pack()
+	// the space between the cells is the larger of their spaces, the padding is added to it
+	if table.W != 30 || table.H != 10 {
+		t.Errorf("packed to %v, %v", table.W, table.H)
+	}
+	checkBounds(t, "b", b, 20, 0, 10, 10)
+}
+
+func TestTableUniformAndSizes(t *testing.T) {
+	table := NewTable()
+	small, large := newTestBox(10, 10), newTestBox(40, 30)
+	table.Add(small).Uniform().Fill()
+	table.Add(large).Uniform()
+	fixed := newTestBox(10, 10)
+	table.Add(fixed).Size(25, 15).Top()
+	limited := newTestBox(10, 10)
+	table.Add(limited).MaxWidth(20).MinHeight(12).ExpandX().FillX()
+	table.Pack()
+	if table.GetColumnWidth(0) != 40 || table.GetColumnWidth(1) != 40 {
+		t.Errorf("uniform columns %v, %v", table.GetColumnWidth(0), table.GetColumnWidth(1))
+	}
+	checkBounds(t, "small", small, 0, 0, 40, 30)
+	checkBounds(t, "fixed", fixed, 80, 15, 25, 15)
+	checkBounds(t, "limited", limited, 105, 9, 10, 12)
+	table.SetSize(table.W+100, table.H)
+	table.Validate()
+	if limited.W != 20 {
+		t.Errorf("filled to %v beyond the max width", limited.W)
+	}
+}
+
+func TestTableAlign(t *testing.T) {
+	table := NewTable()
+	a := newTestBox(20, 10)
+	table.Add(a)
+	table.Align(utils.AlignmentTopLeft).PadAll(5)
+	table.SetSize(100, 100)
+	table.Validate()
+	checkBounds(t, "top left", a, 5, 85, 20, 10)
+	table.Align(utils.AlignmentBottomRight)
+	table.Validate()
+	checkBounds(t, "bottom right", a, 75, 5, 20, 10)
+}
+
+// A widget counting its layouts
+type testWidget struct {
+	Actor
+	widget
+	prefWidth, prefHeight float32
+	layouts               int
+}
+
+func newTestWidget(prefWidth, prefHeight float32) *testWidget {
+	self := &testWidget{prefWidth: prefWidth, prefHeight: prefHeight}
+	self.Actor = Actor{SX: 1, SY: 1}
+	self.widget.init(&self.Actor, self)
+	return self
+}
+
+func (self *testWidget) Layout() {
+	self.layouts++
+}
+
+func (self *testWidget) GetMinWidth() float32   { return 0 }
+func (self *testWidget) GetMinHeight() float32  { return 0 }
+func (self *testWidget) GetPrefWidth() float32  { return self.prefWidth }
+func (self *testWidget) GetPrefHeight() float32 { return self.prefHeight }
+func (self *testWidget) GetMaxWidth() float32   { return 0 }
+func (self *testWidget) GetMaxHeight() float32  { return 0 }
+
+func TestTableLayoutOnlyWhenInvalid(t *testing.T) {
+	table := NewTable()
+	child := newTestWidget(30, 20)
+	table.Add(&child.Actor)
+	table.SetSize(100, 100)
+	table.Validate()
+	if child.layouts != 1 || child.W != 30 || child.H != 20 {
+		t.Fatalf("child laid out %d times at %v, %v", child.layouts, child.W, child.H)
+	}
+	child.X = 0
+	table.Validate()
+	if child.X != 0 || child.layouts != 1 {
+		t.Errorf("laid out again without being invalidated")
+	}
+	// a change of the sizes of the child lays out the table again
+	child.prefWidth = 50
+	child.InvalidateHierarchy()
+	if !table.NeedsLayout() {
+		t.Fatal("table not invalidated by its child")
+	}
+	table.Validate()
+	if child.X != 25 || child.W != 50 || child.layouts != 2 {
+		t.Errorf("child at %v size %v laid out %d times", child.X, child.W, child.layouts)
+	}
+	table.SetSize(200, 100)
+	table.Validate()
+	if child.X != 75 {
+		t.Errorf("not laid out after resizing, child at %v", child.X)
+	}
+	table.SetLayoutEnabled(false)
+	table.SetSize(100, 100)
+	table.Validate()
+	if child.X != 75 {
+		t.Errorf("laid out while disabled, child at %v", child.X)
+	}
+}
+
+func TestTableNested(t *testing.T) {
+	inner := NewTable()
+	a, b := newTestBox(10, 10), newTestBox(20, 10)
+	inner.Add(a)
+	inner.Row()
+	inner.Add(b)
+	outer := NewTable()
+	outer.Add(&inner.Actor).Expand().Fill()
+	outer.Add(newTestBox(30, 30))
+	if outer.GetPrefWidth() != 50 || outer.GetPrefHeight() != 30 {
+		t.Errorf("preferred size %v, %v", outer.GetPrefWidth(), outer.GetPrefHeight())
+	}
+	outer.SetSize(100, 50)
+	outer.Validate()
+	checkBounds(t, "inner", &inner.Actor, 0, 0, 70, 50)
+	// the inner table is laid out by the outer one
+	checkBounds(t, "b", b, 25, 15, 20, 10)
+	inner.RemoveActor(b)
+	if inner.GetRows() != 1 || outer.GetPrefHeight() != 30 || !outer.NeedsLayout() {
+		t.Errorf("%d rows after removing an actor", inner.GetRows())
+	}
+}
+
+func TestTableDebugLines(t *testing.T) {
+	table := NewTable()
+	table.Add(newTestBox(10, 10))
+	table.Add(nil).Size(5, 5)
+	table.Debug = true
+	table.SetSize(40, 20)
+	shapes := g2d.NewShapeRenderer()
+	lines := 0
+	shapes.Flush = func(primitive int, vertices []float32, projection *vector.Matrix4) {
+		lines += len(vertices) / g2d.ShapeVertexSize / 2
+	}
+	shapes.Begin(g2d.ShapeLine)
+	table.DrawDebug(shapes)
+	shapes.End()
+	// the table, two cells and one actor
+	if lines != 16 {
+		t.Errorf("%d debug lines", lines)
+	}
+}
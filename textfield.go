@@ -0,0 +1,668 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"unicode"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/utils"
+	ui "github.com/pyros2097/spike/ui/utils"
+	"github.com/pyros2097/spike/utils/clipboard"
+)
+
+// The time in seconds the cursor of a text field is shown and hidden when it blinks
+var TextFieldBlinkTime float32 = 0.32
+
+// The style of a text field. Font and FontColor are required, the focused and disabled colors and backgrounds are used
+// in those states if they are set. The message text is drawn with the message font and color, or with the font in gray.
+type TextFieldStyle struct {
+	Font                                           *g2d.BitmapFont
+	FontColor, FocusedFontColor, DisabledFontColor *Color
+
+	Background, FocusedBackground, DisabledBackground ui.Drawable
+
+	// Drawn at the cursor at the height of the line and behind the selected text, they may be nil
+	Cursor, Selection ui.Drawable
+
+	MessageFont      *g2d.BitmapFont
+	MessageFontColor *Color
+}
+
+// A TextField is a single line of editable text. It is given the keyboard focus when it is touched or activated, the text
+// is then typed at its cursor. The cursor is moved with the arrow keys and home and end, and with shift pressed they
+// select the text. The selection is also made by dragging over the text. Control with A, C, X and V selects all the text,
+// copies, cuts and pastes it with the clipboard.
+type TextField struct {
+	Actor
+	widget
+
+	style       *TextFieldStyle
+	text        []rune
+	messageText string
+
+	cursor, selectionStart int
+	hasSelection           bool
+
+	passwordMode      bool
+	passwordCharacter rune
+	disabled          bool
+	maxLength         int
+
+	// the text as it is drawn and the positions of its characters
+	displayText    []rune
+	glyphPositions []float32
+
+	// how far the text is scrolled to the left to show the cursor, and the characters which are visible
+	renderOffset             float32
+	visibleStart, visibleEnd int
+
+	cursorOn  bool
+	blinkTime float32
+
+	touching bool
+	pointer  uint8
+
+	// Returns whether the character can be typed, nil accepts all the printable characters
+	Filter func(self *TextField, c rune) bool
+
+	// If set SetText calls OnChange. Default is false.
+	ProgrammaticChangeEvents bool
+
+	// Called when the text changes
+	OnChange func(self *TextField)
+
+	// Called when enter is pressed while the field has the keyboard focus
+	OnEnter func(self *TextField)
+}
+
+func NewTextField(text string, style *TextFieldStyle) *TextField {
+	self := &TextField{style: style, passwordCharacter: '*', cursorOn: true}
+	self.Actor = Actor{SX: 1, SY: 1, Focusable: true}
+	self.widget.init(&self.Actor, self)
+	self.Act = func(a *Actor, delta float32) {
+		self.act(delta)
+	}
+	self.Draw = func(a *Actor, batch g2d.Batch, parentAlpha float32) {
+		self.draw(batch, parentAlpha)
+	}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	self.OnFocus = func(a *Actor, focused bool) {
+		self.cursorOn, self.blinkTime = true, TextFieldBlinkTime
+		self.touching = false
+		SetOnscreenKeyboardVisible(focused)
+	}
+	self.text, self.cursor = []rune(text), len([]rune(text))
+	self.updateDisplayText()
+	self.SetSize(self.GetPrefWidth(), self.GetPrefHeight())
+	return self
+}
+
+func (self *TextField) GetStyle() *TextFieldStyle {
+	return self.style
+}
+
+func (self *TextField) SetStyle(style *TextFieldStyle) {
+	self.style = style
+	self.updateDisplayText()
+	self.InvalidateHierarchy()
+}
+
+// Sets the text, the cursor is moved to its end. OnChange is called if ProgrammaticChangeEvents is set.
+func (self *TextField) SetText(text string) {
+	self.setText([]rune(text), self.ProgrammaticChangeEvents)
+	self.cursor = len(self.text)
+}
+
+func (self *TextField) GetText() string {
+	return string(self.text)
+}
+
+// Changes the text and keeps the cursor and the selection within it, returns false if it did not change
+func (self *TextField) setText(text []rune, fire bool) bool {
+	if string(text) == string(self.text) {
+		return false
+	}
+	self.text = text
+	if self.cursor > len(text) {
+		self.cursor = len(text)
+	}
+	if self.selectionStart > len(text) {
+		self.selectionStart = len(text)
+	}
+	self.hasSelection = self.hasSelection && self.selectionStart != self.cursor
+	self.updateDisplayText()
+	if fire && self.OnChange != nil {
+		self.OnChange(self)
+	}
+	return true
+}
+
+// Sets the text shown while the text is empty and the field does not have the keyboard focus
+func (self *TextField) SetMessageText(messageText string) {
+	self.messageText = messageText
+}
+
+func (self *TextField) GetMessageText() string {
+	return self.messageText
+}
+
+// In password mode the characters are drawn as the password character. Default is false.
+func (self *TextField) SetPasswordMode(passwordMode bool) {
+	self.passwordMode = passwordMode
+	self.updateDisplayText()
+}
+
+func (self *TextField) IsPasswordMode() bool {
+	return self.passwordMode
+}
+
+// Sets the character the text is drawn with in password mode. Default is '*'.
+func (self *TextField) SetPasswordCharacter(c rune) {
+	self.passwordCharacter = c
+	self.updateDisplayText()
+}
+
+// Sets the maximum number of characters which can be typed, 0 for no maximum. Default is 0.
+func (self *TextField) SetMaxLength(maxLength int) {
+	self.maxLength = maxLength
+}
+
+func (self *TextField) GetMaxLength() int {
+	return self.maxLength
+}
+
+// A disabled field cannot be focused or edited and is drawn with the disabled color and background
+func (self *TextField) SetDisabled(disabled bool) {
+	self.disabled = disabled
+	if disabled && self.HasKeyboardFocus() {
+		SetKeyboardFocus(nil)
+	}
+}
+
+func (self *TextField) IsDisabled() bool {
+	return self.disabled
+}
+
+// Returns whether the text is typed in this field
+func (self *TextField) HasKeyboardFocus() bool {
+	return keyboardFocus == &self.Actor
+}
+
+// Moves the cursor to the position in the text and clears the selection
+func (self *TextField) SetCursorPosition(position int) {
+	if position < 0 {
+		position = 0
+	}
+	if position > len(self.text) {
+		position = len(self.text)
+	}
+	self.cursor = position
+	self.ClearSelection()
+}
+
+func (self *TextField) GetCursorPosition() int {
+	return self.cursor
+}
+
+// Selects the text from start to end
+func (self *TextField) SetSelection(start, end int) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(self.text) {
+		end = len(self.text)
+	}
+	if start == end {
+		self.SetCursorPosition(start)
+		return
+	}
+	self.selectionStart, self.cursor, self.hasSelection = start, end, true
+}
+
+func (self *TextField) SelectAll() {
+	self.SetSelection(0, len(self.text))
+}
+
+func (self *TextField) ClearSelection() {
+	self.hasSelection = false
+}
+
+// Returns the selected text, empty if there is no selection
+func (self *TextField) GetSelection() string {
+	if !self.hasSelection {
+		return ""
+	}
+	start, end := self.selectionRange()
+	return string(self.text[start:end])
+}
+
+// Returns the start and end of the selection in order
+func (self *TextField) selectionRange() (start, end int) {
+	if self.selectionStart < self.cursor {
+		return self.selectionStart, self.cursor
+	}
+	return self.cursor, self.selectionStart
+}
+
+// Copies the selection to the clipboard, nothing is copied in password mode
+func (self *TextField) Copy() {
+	if self.hasSelection && !self.passwordMode {
+		clipboard.SetContents(self.GetSelection())
+	}
+}
+
+// Copies the selection to the clipboard and deletes it
+func (self *TextField) Cut() {
+	if self.hasSelection && !self.passwordMode && !self.disabled {
+		self.Copy()
+		self.deleteSelection(true)
+	}
+}
+
+// Replaces the selection with the text of the clipboard, the characters the field does not accept are left out
+func (self *TextField) Paste() {
+	if self.disabled {
+		return
+	}
+	self.insert([]rune(clipboard.GetContents()), true)
+}
+
+// Deletes the selected text and moves the cursor to where it was
+func (self *TextField) deleteSelection(fire bool) {
+	start, end := self.selectionRange()
+	text := make([]rune, 0, len(self.text)-(end-start))
+	text = append(append(text, self.text[:start]...), self.text[end:]...)
+	self.cursor, self.hasSelection = start, false
+	self.setText(text, fire)
+}
+
+// Inserts the characters which are accepted at the cursor, replacing the selection
+func (self *TextField) insert(characters []rune, fire bool) {
+	accepted := make([]rune, 0, len(characters))
+	for _, c := range characters {
+		if self.accepts(c) {
+			accepted = append(accepted, c)
+		}
+	}
+	text := self.text
+	if self.hasSelection {
+		start, end := self.selectionRange()
+		text = append(append(make([]rune, 0, len(text)), text[:start]...), text[end:]...)
+		self.cursor, self.hasSelection = start, false
+	}
+	if self.maxLength > 0 && len(text)+len(accepted) > self.maxLength {
+		n := self.maxLength - len(text)
+		if n < 0 {
+			n = 0
+		}
+		accepted = accepted[:n]
+	}
+	inserted := make([]rune, 0, len(text)+len(accepted))
+	inserted = append(append(append(inserted, text[:self.cursor]...), accepted...), text[self.cursor:]...)
+	self.cursor += len(accepted)
+	self.setText(inserted, fire)
+}
+
+// Returns whether the character can be typed in the field
+func (self *TextField) accepts(c rune) bool {
+	if !unicode.IsPrint(c) {
+		return false
+	}
+	if self.Filter != nil {
+		return self.Filter(self, c)
+	}
+	return true
+}
+
+// Updates the drawn text and the positions of its characters
+func (self *TextField) updateDisplayText() {
+	self.displayText = self.displayText[:0]
+	for _, c := range self.text {
+		if self.passwordMode {
+			c = self.passwordCharacter
+		} else if !self.style.Font.HasGlyph(c) {
+			c = ' '
+		}
+		self.displayText = append(self.displayText, c)
+	}
+	self.glyphPositions = self.style.Font.ComputeGlyphPositions(self.displayText, self.glyphPositions)
+}
+
+func (self *TextField) background() ui.Drawable {
+	style := self.style
+	switch {
+	case self.disabled && style.DisabledBackground != nil:
+		return style.DisabledBackground
+	case self.HasKeyboardFocus() && style.FocusedBackground != nil:
+		return style.FocusedBackground
+	}
+	return style.Background
+}
+
+func (self *TextField) fontColor() *Color {
+	style := self.style
+	switch {
+	case self.disabled && style.DisabledFontColor != nil:
+		return style.DisabledFontColor
+	case self.HasKeyboardFocus() && style.FocusedFontColor != nil:
+		return style.FocusedFontColor
+	}
+	return style.FontColor
+}
+
+// Returns the left and right padding of the background
+func (self *TextField) horizontalPads() (left, right float32) {
+	if background := self.background(); background != nil {
+		return background.GetPadLeft(), background.GetPadRight()
+	}
+	return 0, 0
+}
+
+// Scrolls the text so that the cursor is visible and finds the characters which are visible
+func (self *TextField) calculateOffsets() {
+	left, right := self.horizontalPads()
+	visibleWidth := self.W - left - right
+	positions := self.glyphPositions
+	cursorX := positions[self.cursor]
+	if cursorX-self.renderOffset > visibleWidth {
+		self.renderOffset = cursorX - visibleWidth
+	} else if cursorX < self.renderOffset {
+		self.renderOffset = cursorX
+	}
+	// do not scroll further than the end of the text
+	textWidth := positions[len(positions)-1]
+	if textWidth-self.renderOffset < visibleWidth {
+		self.renderOffset = utils.MaxFloat32(0, textWidth-visibleWidth)
+	}
+	self.visibleStart = 0
+	for self.visibleStart < len(self.displayText) && positions[self.visibleStart] < self.renderOffset {
+		self.visibleStart++
+	}
+	self.visibleEnd = self.visibleStart
+	for self.visibleEnd < len(self.displayText) && positions[self.visibleEnd+1]-self.renderOffset <= visibleWidth {
+		self.visibleEnd++
+	}
+}
+
+// Returns the position in the text closest to x in the coordinates of the field
+func (self *TextField) positionAt(x float32) int {
+	left, _ := self.horizontalPads()
+	x += self.renderOffset - left
+	positions := self.glyphPositions
+	for i := 1; i < len(positions); i++ {
+		if positions[i] > x {
+			if positions[i]-x < x-positions[i-1] {
+				return i
+			}
+			return i - 1
+		}
+	}
+	return len(positions) - 1
+}
+
+// Moves the cursor, with shift pressed the selection is extended to it
+func (self *TextField) moveCursor(position int, extend bool) {
+	if position < 0 {
+		position = 0
+	}
+	if position > len(self.text) {
+		position = len(self.text)
+	}
+	if extend {
+		if !self.hasSelection {
+			self.selectionStart, self.hasSelection = self.cursor, true
+		}
+	} else {
+		self.hasSelection = false
+	}
+	self.cursor = position
+	self.hasSelection = self.hasSelection && self.selectionStart != self.cursor
+}
+
+// Returns the position of the start of the word before or after the cursor
+func (self *TextField) wordPosition(forward bool) int {
+	i := self.cursor
+	if forward {
+		for i < len(self.text) && !isWordCharacter(self.text[i]) {
+			i++
+		}
+		for i < len(self.text) && isWordCharacter(self.text[i]) {
+			i++
+		}
+		return i
+	}
+	for i > 0 && !isWordCharacter(self.text[i-1]) {
+		i--
+	}
+	for i > 0 && isWordCharacter(self.text[i-1]) {
+		i--
+	}
+	return i
+}
+
+func isWordCharacter(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func (self *TextField) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if !self.Hit(e.X, e.Y, widgetPoint) {
+			if self.HasKeyboardFocus() {
+				SetKeyboardFocus(nil)
+			}
+			return
+		}
+		if self.disabled || self.touching {
+			return
+		}
+		SetKeyboardFocus(&self.Actor)
+		self.touching, self.pointer = true, e.Pointer
+		self.moveCursor(self.positionAt(widgetPoint.X), IsShiftPressed())
+	case TouchDragged:
+		if self.touching && e.Pointer == self.pointer {
+			self.Hit(e.X, e.Y, widgetPoint)
+			self.moveCursor(self.positionAt(widgetPoint.X), true)
+		}
+	case TouchUp:
+		if e.Pointer == self.pointer {
+			self.touching = false
+		}
+	case KeyDown:
+		if self.HasKeyboardFocus() && e.Controller == 0 {
+			self.keyDown(e.KeyCode)
+		}
+	case KeyTyped:
+		if self.HasKeyboardFocus() && !self.disabled && !IsControlPressed() {
+			self.insert([]rune{e.Character}, true)
+			self.cursorOn, self.blinkTime = true, TextFieldBlinkTime
+		}
+	case Activated:
+		if !self.disabled {
+			SetKeyboardFocus(&self.Actor)
+		}
+	}
+}
+
+func (self *TextField) keyDown(key KeyCode) {
+	control, shift := IsControlPressed(), IsShiftPressed()
+	switch key {
+	case KeyDpadLeft:
+		if control {
+			self.moveCursor(self.wordPosition(false), shift)
+		} else if self.hasSelection && !shift {
+			start, _ := self.selectionRange()
+			self.moveCursor(start, false)
+		} else {
+			self.moveCursor(self.cursor-1, shift)
+		}
+	case KeyDpadRight:
+		if control {
+			self.moveCursor(self.wordPosition(true), shift)
+		} else if self.hasSelection && !shift {
+			_, end := self.selectionRange()
+			self.moveCursor(end, false)
+		} else {
+			self.moveCursor(self.cursor+1, shift)
+		}
+	case KeyHome:
+		self.moveCursor(0, shift)
+	case KeyEnd:
+		self.moveCursor(len(self.text), shift)
+	case KeyDel:
+		if self.disabled {
+			return
+		}
+		if !self.hasSelection && self.cursor > 0 {
+			self.selectionStart, self.cursor, self.hasSelection = self.cursor, self.cursor-1, true
+		}
+		if self.hasSelection {
+			self.deleteSelection(true)
+		}
+	case KeyForwardDel:
+		if self.disabled {
+			return
+		}
+		if !self.hasSelection && self.cursor < len(self.text) {
+			self.selectionStart, self.hasSelection = self.cursor+1, true
+		}
+		if self.hasSelection {
+			self.deleteSelection(true)
+		}
+	case KeyEnter:
+		if self.OnEnter != nil {
+			self.OnEnter(self)
+		}
+	case KeyA:
+		if control {
+			self.SelectAll()
+		}
+	case KeyC:
+		if control {
+			self.Copy()
+		}
+	case KeyX:
+		if control {
+			self.Cut()
+		}
+	case KeyV:
+		if control {
+			self.Paste()
+		}
+	case KeyInsert:
+		if shift {
+			self.Paste()
+		} else if control {
+			self.Copy()
+		}
+	default:
+		return
+	}
+	self.cursorOn, self.blinkTime = true, TextFieldBlinkTime
+}
+
+// Blinks the cursor while the field has the keyboard focus
+func (self *TextField) act(delta float32) {
+	if !self.HasKeyboardFocus() {
+		return
+	}
+	self.blinkTime -= delta
+	if self.blinkTime <= 0 {
+		self.cursorOn = !self.cursorOn
+		self.blinkTime = TextFieldBlinkTime
+	}
+}
+
+// The field has nothing to lay out, the text is scrolled when it is drawn
+func (self *TextField) Layout() {
+}
+
+func (self *TextField) GetPrefWidth() float32 {
+	return 150
+}
+
+func (self *TextField) GetPrefHeight() float32 {
+	height := self.style.Font.GetLineHeight()
+	if background := self.background(); background != nil {
+		height = utils.MaxFloat32(height+background.GetPadTop()+background.GetPadBottom(), background.GetMinHeight())
+	}
+	return height
+}
+
+func (self *TextField) GetMinWidth() float32 {
+	return self.GetPrefWidth()
+}
+
+func (self *TextField) GetMinHeight() float32 {
+	return self.GetPrefHeight()
+}
+
+func (self *TextField) GetMaxWidth() float32 {
+	return 0
+}
+
+func (self *TextField) GetMaxHeight() float32 {
+	return 0
+}
+
+func (self *TextField) draw(batch g2d.Batch, parentAlpha float32) {
+	self.Validate()
+	style := self.style
+	font := style.Font
+	background := self.background()
+	focused := self.HasKeyboardFocus()
+	left, _ := self.horizontalPads()
+	bottom, top := float32(0), float32(0)
+	if background != nil {
+		bottom, top = background.GetPadBottom(), background.GetPadTop()
+	}
+	lineHeight := font.GetLineHeight()
+	// the top of the line of text, centered in the field
+	textY := bottom + (self.H-bottom-top+lineHeight)/2
+	self.calculateOffsets()
+
+	setBatchColor(batch, &self.Actor, nil, parentAlpha)
+	drawDrawable(batch, &self.Actor, background, 0, 0, self.W, self.H)
+	positions := self.glyphPositions
+	if focused && self.hasSelection && style.Selection != nil {
+		start, end := self.selectionRange()
+		if start < self.visibleStart {
+			start = self.visibleStart
+		}
+		if end > self.visibleEnd {
+			end = self.visibleEnd
+		}
+		if start < end {
+			x := left + positions[start] - self.renderOffset
+			drawDrawable(batch, &self.Actor, style.Selection, x, textY-lineHeight, positions[end]-positions[start],
+				lineHeight)
+		}
+	}
+	if len(self.displayText) == 0 {
+		if !focused && self.messageText != "" {
+			messageFont, color := style.MessageFont, style.MessageFontColor
+			if messageFont == nil {
+				messageFont = font
+			}
+			if color == nil {
+				color = &Color{0.7, 0.7, 0.7, self.fontColor().A}
+			}
+			setBatchColor(batch, &self.Actor, color, parentAlpha)
+			messageFont.Draw(batch, []rune(self.messageText), left, textY, self.computeLocalTransform())
+		}
+	} else {
+		setBatchColor(batch, &self.Actor, self.fontColor(), parentAlpha)
+		x := left + positions[self.visibleStart] - self.renderOffset
+		font.Draw(batch, self.displayText[self.visibleStart:self.visibleEnd], x, textY, self.computeLocalTransform())
+	}
+	if focused && !self.disabled && self.cursorOn && style.Cursor != nil {
+		setBatchColor(batch, &self.Actor, nil, parentAlpha)
+		x := left + positions[self.cursor] - self.renderOffset - style.Cursor.GetMinWidth()/2
+		drawDrawable(batch, &self.Actor, style.Cursor, x, textY-lineHeight, style.Cursor.GetMinWidth(), lineHeight)
+	}
+}
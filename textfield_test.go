@@ -0,0 +1,147 @@
+package spike
+
+import (
+	"testing"
+
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/utils/clipboard"
+)
+
+// Returns a font of the printable ASCII characters which are all 10 pixels wide
+func newTestFont() *g2d.BitmapFont {
+	page := &g2d.Texture{Width: 100, Height: 100}
+	font := &g2d.BitmapFont{Pages: []*g2d.Texture{page}, Glyphs: map[rune]*g2d.Glyph{}, LineHeight: 12, Base: 10,
+		ScaleX: 1, ScaleY: 1}
+	for c := rune(32); c < 127; c++ {
+		glyph := &g2d.Glyph{ID: c, Width: 8, Height: 10, XAdvance: 10}
+		glyph.TextureRegion = *g2d.NewTextureRegion(page, 0, 0, 8, 10)
+		font.Glyphs[c] = glyph
+	}
+	return font
+}
+
+func newTestTextField(text string) *TextField {
+	field := NewTextField(text, &TextFieldStyle{Font: newTestFont(), FontColor: &Color{R: 1, G: 1, B: 1, A: 1}})
+	field.SetSize(200, 20)
+	SetKeyboardFocus(&field.Actor)
+	return field
+}
+
+// Presses the key while the modifiers are held
+func pressKey(field *TextField, key KeyCode, modifiers ...KeyCode) {
+	for _, modifier := range modifiers {
+		pressedKeys[modifier] = true
+	}
+	field.input(InputEvent{Type: KeyDown, KeyCode: key})
+	for _, modifier := range modifiers {
+		delete(pressedKeys, modifier)
+	}
+}
+
+func checkSelection(t *testing.T, name string, field *TextField, cursor int, selection string) {
+	if field.GetCursorPosition() != cursor || field.GetSelection() != selection {
+		t.Errorf("%s: cursor at %d with %q selected, expected %d with %q", name, field.GetCursorPosition(),
+			field.GetSelection(), cursor, selection)
+	}
+}
+
+func TestTextFieldSelection(t *testing.T) {
+	field := newTestTextField("hello world")
+	defer SetKeyboardFocus(nil)
+	checkSelection(t, "new", field, 11, "")
+	pressKey(field, KeyHome)
+	pressKey(field, KeyEnd, KeyShiftLeft)
+	checkSelection(t, "shift end", field, 11, "hello world")
+	// left without shift goes to the start of the selection
+	pressKey(field, KeyDpadLeft)
+	checkSelection(t, "left", field, 0, "")
+	pressKey(field, KeyDpadRight, KeyControlLeft)
+	checkSelection(t, "next word", field, 5, "")
+	pressKey(field, KeyDpadRight, KeyControlLeft, KeyShiftRight)
+	checkSelection(t, "select next word", field, 11, " world")
+	pressKey(field, KeyDpadLeft, KeyShiftLeft)
+	checkSelection(t, "shrink selection", field, 10, " worl")
+	pressKey(field, KeyDel)
+	if field.GetText() != "hellod" {
+		t.Errorf("deleted selection %q", field.GetText())
+	}
+	checkSelection(t, "deleted", field, 5, "")
+
+	// the selection may be set backwards and is replaced by typed text
+	field.SetSelection(3, 1)
+	checkSelection(t, "set backwards", field, 1, "el")
+	field.input(InputEvent{Type: KeyTyped, Character: 'X'})
+	if field.GetText() != "hXlod" {
+		t.Errorf("typed over selection %q", field.GetText())
+	}
+	checkSelection(t, "typed", field, 2, "")
+	field.SetSelection(-5, 50)
+	checkSelection(t, "clamped", field, 5, "hXlod")
+
+	// dragging selects from the character closest to the touch
+	field.SetText("abcdefgh")
+	field.input(InputEvent{Type: TouchDown, X: 26, Y: 5})
+	field.input(InputEvent{Type: TouchDragged, X: 64, Y: 5})
+	field.input(InputEvent{Type: TouchUp, X: 64, Y: 5})
+	checkSelection(t, "dragged", field, 6, "def")
+	field.input(InputEvent{Type: TouchDown, X: 500, Y: 5})
+	if field.HasKeyboardFocus() {
+		t.Error("kept the focus after a touch outside")
+	}
+}
+
+func TestTextFieldClipboard(t *testing.T) {
+	field := newTestTextField("copy me")
+	defer SetKeyboardFocus(nil)
+	clipboard.SetContents("")
+	changes := 0
+	field.OnChange = func(f *TextField) {
+		changes++
+	}
+	pressKey(field, KeyA, KeyControlLeft)
+	pressKey(field, KeyC, KeyControlLeft)
+	if clipboard.GetContents() != "copy me" || changes != 0 {
+		t.Errorf("copied %q with %d changes", clipboard.GetContents(), changes)
+	}
+	field.SetSelection(0, 5)
+	pressKey(field, KeyX, KeyControlRight)
+	if clipboard.GetContents() != "copy " || field.GetText() != "me" || changes != 1 {
+		t.Errorf("cut %q leaving %q with %d changes", clipboard.GetContents(), field.GetText(), changes)
+	}
+	pressKey(field, KeyEnd)
+	pressKey(field, KeyV, KeyControlLeft)
+	if field.GetText() != "mecopy " || field.GetCursorPosition() != 7 || changes != 2 {
+		t.Errorf("pasted %q with the cursor at %d", field.GetText(), field.GetCursorPosition())
+	}
+
+	// the characters which are filtered out or do not fit are not pasted
+	clipboard.SetContents("a1b2\n3")
+	field.SetText("")
+	field.Filter = func(f *TextField, c rune) bool {
+		return c >= '0' && c <= '9'
+	}
+	field.SetMaxLength(2)
+	field.Paste()
+	if field.GetText() != "12" {
+		t.Errorf("pasted %q with a filter and a max length", field.GetText())
+	}
+
+	// passwords are not copied and disabled fields are not changed
+	field.Filter = nil
+	field.SetMaxLength(0)
+	field.SetText("secret")
+	field.SetPasswordMode(true)
+	field.SelectAll()
+	field.Copy()
+	field.Cut()
+	if clipboard.GetContents() != "a1b2\n3" || field.GetText() != "secret" {
+		t.Errorf("password copied %q and left %q", clipboard.GetContents(), field.GetText())
+	}
+	field.SetPasswordMode(false)
+	field.SetDisabled(true)
+	field.Paste()
+	field.Cut()
+	if field.GetText() != "secret" {
+		t.Errorf("disabled field changed to %q", field.GetText())
+	}
+}
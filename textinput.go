@@ -0,0 +1,238 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"sync/atomic"
+	"unicode"
+)
+
+// Text can be requested from the user with GetTextInput. On platforms with a TextInputBackend a native dialog is shown,
+// otherwise the on-screen keyboard is shown if there is one and the text is typed with the key events, Enter submits it
+// and Escape cancels it. The result is delivered on the main loop like all the other input.
+//
+//   spike.GetTextInput(spike.TextInputRequest{Title: "Name", Hint: "Your name"}, func(text string) {
+//     player.Name = text
+//   }, nil)
+//
+// While a request without a native dialog is pending, GetPendingTextInput returns the text typed so far so that the game
+// can draw it. TextComposed events are sent whenever it changes.
+
+// The kind of text that is requested, which decides the on-screen keyboard and the characters that can be typed
+type TextInputType uint8
+
+const (
+	TextInputText TextInputType = iota
+	TextInputNumber
+	TextInputPassword
+	TextInputEmail
+)
+
+// A TextInputRequest describes the dialog in which the text is entered
+type TextInputRequest struct {
+	// The title of the dialog
+	Title string
+
+	// The initial text
+	Text string
+
+	// The text shown while the text is empty
+	Hint string
+
+	Type TextInputType
+}
+
+// A TextInputBackend shows the native text input dialogs and the on-screen keyboard of a platform. Its functions are
+// called on the main loop, done may be called from any goroutine. Input methods report their composition with
+// ComposeText and CommitText.
+type TextInputBackend interface {
+	// Shows a dialog for the request and calls done with the text once it is closed, ok is false if it was canceled.
+	ShowTextInput(request TextInputRequest, done func(text string, ok bool))
+
+	SetOnscreenKeyboardVisible(visible bool)
+}
+
+type textInputRequest struct {
+	request    TextInputRequest
+	onInput    func(text string)
+	onCanceled func()
+	native     bool
+	text       []rune
+}
+
+var (
+	textInputBackend TextInputBackend
+	textInput        *textInputRequest
+	keyboardVisible  bool
+
+	// Counts the requests so that the late result of a replaced native dialog is dropped
+	textInputID int32
+
+	// The text events of the platform, they may be sent from any goroutine and are fed to the input on the main loop
+	platformTextEvents = make(chan InputEvent, 100)
+)
+
+// SetTextInputBackend sets the backend for the native text input dialogs and the on-screen keyboard. Without a backend
+// the text is typed with the key events.
+func SetTextInputBackend(backend TextInputBackend) {
+	textInputBackend = backend
+}
+
+// GetTextInput requests text from the user. onInput is called with the text on the main loop once it is entered, or
+// onCanceled if the request was canceled, either may be nil. Only one request can be pending, a new request cancels the
+// pending one.
+func GetTextInput(request TextInputRequest, onInput func(text string), onCanceled func()) {
+	if textInput != nil {
+		cancelTextInput()
+	}
+	textInput = &textInputRequest{
+		request:    request,
+		onInput:    onInput,
+		onCanceled: onCanceled,
+		native:     textInputBackend != nil,
+		text:       []rune(request.Text),
+	}
+	id := atomic.AddInt32(&textInputID, 1)
+	if textInput.native {
+		textInputBackend.ShowTextInput(request, func(text string, ok bool) {
+			if atomic.LoadInt32(&textInputID) != id {
+				return
+			}
+			if ok {
+				platformTextEvents <- InputEvent{Type: TextInput, Text: text}
+			} else {
+				platformTextEvents <- InputEvent{Type: TextInputCanceled}
+			}
+		})
+	} else {
+		SetOnscreenKeyboardVisible(true)
+	}
+}
+
+// CancelTextInput cancels the pending text input request, if any. Its onCanceled is called right away, so that a request
+// made after it is not canceled too.
+func CancelTextInput() {
+	if textInput != nil {
+		cancelTextInput()
+	}
+}
+
+// Returns the request and the text typed so far while a request without a native dialog is pending. ok is false if there
+// is none. Passwords should be masked when drawn.
+func GetPendingTextInput() (request TextInputRequest, text string, ok bool) {
+	if textInput == nil || textInput.native {
+		return TextInputRequest{}, "", false
+	}
+	return textInput.request, string(textInput.text), true
+}
+
+// Shows or hides the on-screen keyboard if the platform has one
+func SetOnscreenKeyboardVisible(visible bool) {
+	keyboardVisible = visible
+	if textInputBackend != nil {
+		textInputBackend.SetOnscreenKeyboardVisible(visible)
+	}
+}
+
+// Returns true if the on-screen keyboard was made visible
+func IsOnscreenKeyboardVisible() bool {
+	return keyboardVisible
+}
+
+// ComposeText reports the text an input method is composing. It can be called from any goroutine.
+func ComposeText(text string) {
+	platformTextEvents <- InputEvent{Type: TextComposed, Text: text}
+}
+
+// CommitText reports the text an input method has committed, which is typed as KeyTyped events. It can be called from
+// any goroutine.
+func CommitText(text string) {
+	for _, r := range text {
+		platformTextEvents <- InputEvent{Type: KeyTyped, Character: r}
+	}
+	platformTextEvents <- InputEvent{Type: TextComposed}
+}
+
+// Feeds the text events of the platform to the input. This is called once every frame.
+func pollTextInput() {
+	for len(platformTextEvents) > 0 {
+		feedInput(<-platformTextEvents)
+	}
+}
+
+func cancelTextInput() {
+	request := textInput
+	textInput = nil
+	atomic.AddInt32(&textInputID, 1)
+	if !request.native {
+		SetOnscreenKeyboardVisible(false)
+	}
+	if request.onCanceled != nil {
+		request.onCanceled()
+	}
+}
+
+// Returns whether the character can be typed for the type of text
+func acceptsCharacter(inputType TextInputType, c rune) bool {
+	switch inputType {
+	case TextInputNumber:
+		return unicode.IsDigit(c) || c == '.' || c == '-' || c == '+'
+	case TextInputEmail:
+		return !unicode.IsSpace(c)
+	}
+	return unicode.IsPrint(c)
+}
+
+// Completes the pending request on its result and types its text with the key events if there is no native dialog
+func processTextInput(e InputEvent) {
+	if textInput == nil {
+		return
+	}
+	switch e.Type {
+	case TextInput:
+		request := textInput
+		textInput = nil
+		atomic.AddInt32(&textInputID, 1)
+		if !request.native {
+			SetOnscreenKeyboardVisible(false)
+		}
+		if request.onInput != nil {
+			request.onInput(e.Text)
+		}
+		return
+	case TextInputCanceled:
+		cancelTextInput()
+		return
+	}
+	if textInput.native || e.Controller != 0 {
+		return
+	}
+	switch e.Type {
+	case KeyTyped:
+		if !acceptsCharacter(textInput.request.Type, e.Character) {
+			return
+		}
+		textInput.text = append(textInput.text, e.Character)
+	case KeyDown:
+		switch e.KeyCode {
+		case KeyDel:
+			if len(textInput.text) == 0 {
+				return
+			}
+			textInput.text = textInput.text[:len(textInput.text)-1]
+		case KeyEnter:
+			dispatchInput(InputEvent{Type: TextInput, Text: string(textInput.text)})
+			return
+		case KeyEscape, KeyBack:
+			dispatchInput(InputEvent{Type: TextInputCanceled})
+			return
+		default:
+			return
+		}
+	default:
+		return
+	}
+	dispatchInput(InputEvent{Type: TextComposed, Text: string(textInput.text)})
+}
@@ -0,0 +1,244 @@
+package spike
+
+import (
+	"testing"
+)
+
+// A backend which keeps the shown dialogs so that the test can close them
+type testTextInputBackend struct {
+	requests []TextInputRequest
+	done     []func(text string, ok bool)
+	keyboard bool
+}
+
+func (self *testTextInputBackend) ShowTextInput(request TextInputRequest, done func(text string, ok bool)) {
+	self.requests = append(self.requests, request)
+	self.done = append(self.done, done)
+}
+
+func (self *testTextInputBackend) SetOnscreenKeyboardVisible(visible bool) {
+	self.keyboard = visible
+}
+
+// Returns a scene with an actor which keeps the text events it receives
+func newTextInputScene() (*Scene, *[]InputEvent) {
+	events := &[]InputEvent{}
+	actor := &Actor{Name: "text"}
+	actor.Input = func(a *Actor, e InputEvent) {
+		switch e.Type {
+		case TextInput, TextInputCanceled, TextComposed, KeyTyped:
+			*events = append(*events, e)
+		}
+	}
+	scene := &Scene{Name: "text"}
+	scene.Children = []*Actor{actor}
+	return scene, events
+}
+
+// Records the results of a request
+type textInputResult struct {
+	text     []string
+	canceled int
+}
+
+func (self *textInputResult) request(request TextInputRequest) {
+	GetTextInput(request, func(text string) {
+		self.text = append(self.text, text)
+	}, func() {
+		self.canceled++
+	})
+}
+
+func resetTextInput() {
+	SetTextInputBackend(nil)
+	textInput = nil
+	keyboardVisible = false
+	for len(platformTextEvents) > 0 {
+		<-platformTextEvents
+	}
+	drainInput()
+	releaseBindings()
+}
+
+func TestTextInputTyped(t *testing.T) {
+	defer resetTextInput()
+	scene, events := newTextInputScene()
+	result := &textInputResult{}
+	result.request(TextInputRequest{Title: "Name", Text: "ab"})
+	if !IsOnscreenKeyboardVisible() {
+		t.Error("on-screen keyboard not shown")
+	}
+
+	dispatchInput(InputEvent{Type: KeyTyped, Character: 'c'})
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyDel})
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyDel})
+	dispatchInput(InputEvent{Type: KeyTyped, Character: 'd'})
+	// the keys of controllers do not type
+	dispatchInput(InputEvent{Type: KeyTyped, Character: 'x', Controller: 1})
+	update(scene, 0)
+	if _, text, ok := GetPendingTextInput(); !ok || text != "ad" {
+		t.Errorf("pending text %q %v", text, ok)
+	}
+	var composed []string
+	for _, e := range *events {
+		if e.Type == TextComposed {
+			composed = append(composed, e.Text)
+		}
+	}
+	if len(composed) != 4 || composed[0] != "abc" || composed[3] != "ad" {
+		t.Errorf("composed %q", composed)
+	}
+
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyEnter})
+	if len(result.text) != 0 {
+		t.Error("text entered before the frame")
+	}
+	update(scene, 0)
+	if len(result.text) != 1 || result.text[0] != "ad" || result.canceled != 0 {
+		t.Errorf("entered %q canceled %d", result.text, result.canceled)
+	}
+	if _, _, ok := GetPendingTextInput(); ok || IsOnscreenKeyboardVisible() {
+		t.Error("request still pending after the text was entered")
+	}
+	if last := (*events)[len(*events)-1]; last.Type != TextInput || last.Text != "ad" {
+		t.Errorf("last event %v", last)
+	}
+}
+
+func TestTextInputCharacters(t *testing.T) {
+	defer resetTextInput()
+	scene, _ := newTextInputScene()
+	result := &textInputResult{}
+	result.request(TextInputRequest{Type: TextInputNumber})
+	for _, c := range "1a.-x2 " {
+		dispatchInput(InputEvent{Type: KeyTyped, Character: c})
+	}
+	update(scene, 0)
+	if _, text, _ := GetPendingTextInput(); text != "1.-2" {
+		t.Errorf("number text %q", text)
+	}
+	CancelTextInput()
+	result.request(TextInputRequest{Type: TextInputEmail})
+	for _, c := range "a b@c" {
+		dispatchInput(InputEvent{Type: KeyTyped, Character: c})
+	}
+	update(scene, 0)
+	if _, text, _ := GetPendingTextInput(); text != "ab@c" {
+		t.Errorf("email text %q", text)
+	}
+}
+
+func TestCancelTextInput(t *testing.T) {
+	defer resetTextInput()
+	scene, _ := newTextInputScene()
+	first, second := &textInputResult{}, &textInputResult{}
+	first.request(TextInputRequest{})
+	// a new request cancels the pending one
+	second.request(TextInputRequest{Text: "b"})
+	if first.canceled != 1 || !IsOnscreenKeyboardVisible() {
+		t.Errorf("replaced request canceled %d times", first.canceled)
+	}
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyEscape})
+	update(scene, 0)
+	if second.canceled != 1 || len(second.text) != 0 || IsOnscreenKeyboardVisible() {
+		t.Errorf("escape canceled %d times and entered %q", second.canceled, second.text)
+	}
+
+	second.request(TextInputRequest{})
+	CancelTextInput()
+	CancelTextInput()
+	if second.canceled != 2 {
+		t.Errorf("canceled request canceled %d times", second.canceled)
+	}
+	if _, _, ok := GetPendingTextInput(); ok || IsOnscreenKeyboardVisible() {
+		t.Error("canceled request still pending")
+	}
+	// a request made right after the cancel stays pending
+	first.request(TextInputRequest{})
+	update(scene, 0)
+	if _, _, ok := GetPendingTextInput(); !ok || first.canceled != 1 {
+		t.Error("request after a cancel canceled")
+	}
+}
+
+func TestNativeTextInput(t *testing.T) {
+	defer resetTextInput()
+	scene, _ := newTextInputScene()
+	backend := &testTextInputBackend{}
+	SetTextInputBackend(backend)
+	first, second := &textInputResult{}, &textInputResult{}
+	first.request(TextInputRequest{Title: "Name"})
+	if len(backend.requests) != 1 || backend.requests[0].Title != "Name" || backend.keyboard {
+		t.Fatalf("shown %v keyboard %v", backend.requests, backend.keyboard)
+	}
+	if _, _, ok := GetPendingTextInput(); ok {
+		t.Error("native request pending for drawing")
+	}
+	// the keys do not type into a native dialog
+	dispatchInput(InputEvent{Type: KeyTyped, Character: 'a'})
+	dispatchInput(InputEvent{Type: KeyDown, KeyCode: KeyEnter})
+	update(scene, 0)
+	if len(first.text) != 0 {
+		t.Errorf("native request entered %q with the keys", first.text)
+	}
+
+	// the dialog may be closed from another goroutine, the result comes on the main loop
+	closed := make(chan bool)
+	go func() {
+		backend.done[0]("Ann", true)
+		closed <- true
+	}()
+	<-closed
+	if len(first.text) != 0 {
+		t.Error("text entered outside of the main loop")
+	}
+	update(scene, 0)
+	if len(first.text) != 1 || first.text[0] != "Ann" {
+		t.Errorf("entered %q", first.text)
+	}
+
+	// the late result of a replaced dialog is dropped
+	first.request(TextInputRequest{})
+	second.request(TextInputRequest{})
+	backend.done[1]("late", true)
+	backend.done[2]("", false)
+	update(scene, 0)
+	if first.canceled != 1 || len(first.text) != 1 || second.canceled != 1 || len(second.text) != 0 {
+		t.Errorf("first canceled %d entered %q, second canceled %d entered %q", first.canceled, first.text,
+			second.canceled, second.text)
+	}
+}
+
+func TestTextComposition(t *testing.T) {
+	defer resetTextInput()
+	scene, events := newTextInputScene()
+	ComposeText("ni")
+	if len(*events) != 0 {
+		t.Error("composition delivered outside of the frame")
+	}
+	update(scene, 0)
+	if len(*events) != 1 || (*events)[0].Type != TextComposed || (*events)[0].Text != "ni" {
+		t.Fatalf("composed %v", *events)
+	}
+
+	// the committed text is typed into a pending request
+	result := &textInputResult{}
+	result.request(TextInputRequest{})
+	*events = nil
+	CommitText("你好")
+	update(scene, 0)
+	var typed []rune
+	ended := false
+	for _, e := range *events {
+		if e.Type == KeyTyped {
+			typed = append(typed, e.Character)
+		}
+		ended = ended || e.Type == TextComposed && e.Text == ""
+	}
+	if string(typed) != "你好" || !ended {
+		t.Errorf("typed %q, composition ended %v", string(typed), ended)
+	}
+	if _, text, _ := GetPendingTextInput(); text != "你好" {
+		t.Errorf("pending text %q", text)
+	}
+}
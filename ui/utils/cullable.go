@@ -6,8 +6,7 @@ import (
 
 // Allows a parent to set the area that is visible on a child actor to allow the child to cull when drawing itself. This must only
 // be used for actors that are not rotated or scaled.
-// When Group is given a culling rectangle with {@link Group#setCullingArea(Rectangle)}, it will automatically call
-// {@link #setCullingArea(Rectangle)} on its children.
+// Actors implement it by skipping their children outside of the area, a ScrollPane sets it to the visible part of its actor.
 type Cullable interface {
 	// param cullingArea The culling area in the child actor's coordinates.
 	SetCullingArea(cullingArea *shape.Rectangle)
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// A Drawable draws the background of a widget at the size of the widget, like a g2d.NinePatch. Its padding is the space
+// around the content of the widget and its minimum size is added to the minimum size of the widget by the layout.
+type Drawable interface {
+	// Draws at the width and height, the bottom left corner at the origin transformed by the transform
+	Draw(batch g2d.Batch, width, height float32, transform *vector.Affine2)
+
+	GetPadLeft() float32
+	GetPadRight() float32
+	GetPadTop() float32
+	GetPadBottom() float32
+
+	GetMinWidth() float32
+	GetMinHeight() float32
+}
+
+// A RegionDrawable draws a region of a texture atlas stretched to the size, it has no padding and its minimum size is
+// the original size of the image
+type RegionDrawable struct {
+	Region *g2d.AtlasRegion
+}
+
+func NewRegionDrawable(region *g2d.AtlasRegion) *RegionDrawable {
+	return &RegionDrawable{Region: region}
+}
+
+func (self *RegionDrawable) Draw(batch g2d.Batch, width, height float32, transform *vector.Affine2) {
+	self.Region.Draw(batch, width, height, transform)
+}
+
+func (self *RegionDrawable) GetPadLeft() float32   { return 0 }
+func (self *RegionDrawable) GetPadRight() float32  { return 0 }
+func (self *RegionDrawable) GetPadTop() float32    { return 0 }
+func (self *RegionDrawable) GetPadBottom() float32 { return 0 }
+
+func (self *RegionDrawable) GetMinWidth() float32 {
+	return float32(self.Region.OriginalWidth)
+}
+
+func (self *RegionDrawable) GetMinHeight() float32 {
+	return float32(self.Region.OriginalHeight)
+}
+
+// A TintedDrawable draws a drawable with the color of the batch multiplied by the tint, like a darkened copy of a white
+// region to dim the screen behind a dialog
+type TintedDrawable struct {
+	Drawable
+	Tint g2d.Color
+}
+
+func NewTintedDrawable(drawable Drawable, tint g2d.Color) *TintedDrawable {
+	return &TintedDrawable{Drawable: drawable, Tint: tint}
+}
+
+func (self *TintedDrawable) Draw(batch g2d.Batch, width, height float32, transform *vector.Affine2) {
+	color := batch.GetColor()
+	batch.SetColor(color.R*self.Tint.R, color.G*self.Tint.G, color.B*self.Tint.B, color.A*self.Tint.A)
+	self.Drawable.Draw(batch, width, height, transform)
+	batch.SetColor(color.R, color.G, color.B, color.A)
+}
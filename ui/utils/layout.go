@@ -41,17 +41,17 @@ type ILayout interface {
 	 * true. */
 	SetLayoutEnabled(enabled bool)
 
-	GetMinWidth() bool
+	GetMinWidth() float32
 
-	GetMinHeight() bool
+	GetMinHeight() float32
 
-	GetPrefWidth() bool
+	GetPrefWidth() float32
 
-	GetPrefHeight() bool
+	GetPrefHeight() float32
 
 	/** Zero indicates no max width. */
-	GetMaxWidth() bool
+	GetMaxWidth() float32
 
 	/** Zero indicates no max height. */
-	GetMaxHeight() bool
+	GetMaxHeight() float32
 }
@@ -5,13 +5,39 @@
 // A very simple clipboard interface for text content.
 package clipboard
 
+// A Backend is the clipboard of a platform
+type Backend interface {
+	GetContents() string
+	SetContents(content string)
+}
+
+var (
+	backend Backend
+
+	// the contents of the clipboard when the platform has none
+	contents string
+)
+
+// Sets the clipboard of the platform, without one the contents are kept in memory
+func SetBackend(b Backend) {
+	backend = b
+}
+
 // gets the current content of the clipboard if it contains text
-// return the clipboard content or nil
+// return the clipboard content or an empty string
 func GetContents() string {
-
+	if backend != nil {
+		return backend.GetContents()
+	}
+	return contents
 }
 
 // Sets the content of the system clipboard.
 // @param content the content
 func SetContents(content string) {
+	if backend != nil {
+		backend.SetContents(content)
+		return
+	}
+	contents = content
 }
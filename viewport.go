@@ -0,0 +1,272 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"math"
+
+	. "github.com/pyros2097/spike/math/collision"
+	. "github.com/pyros2097/spike/math/vector"
+	"github.com/pyros2097/spike/utils/scaling"
+)
+
+// A Viewport manages a Camera and determines how world coordinates are mapped to and from the screen. The world has a
+// virtual size in world units, the viewport scales it to the screen whenever the screen is resized and sets the
+// rectangle of the screen it is drawn to, the remaining space are the gutters (black bars).
+//
+// The viewport of the game is set with SetViewport, by default it is a StretchViewport of the target size given to
+// Init. It is updated on every resize and the positions of the actors are in its world units.
+type Viewport struct {
+	Camera *Camera
+
+	// The size of the world in world units
+	WorldWidth, WorldHeight float32
+
+	// The rectangle of the screen the world is drawn to in pixels, the origin is in the lower left corner like glViewport
+	ScreenX, ScreenY, ScreenWidth, ScreenHeight int
+
+	// How the world is scaled to the screen. Used by: NewScalingViewport.
+	Scaling scaling.Scaling
+
+	// The world size is kept between the min and max size, a max of 0 means no limit. Used by: NewExtendViewport.
+	MinWorldWidth, MinWorldHeight float32
+	MaxWorldWidth, MaxWorldHeight float32
+
+	// The number of world units per screen pixel. Used by: NewScreenViewport.
+	UnitsPerPixel float32
+
+	// The size of the whole screen in pixels
+	fullWidth, fullHeight int
+
+	// Sets the world size and screen bounds for the size of the screen
+	update func(self *Viewport, screenWidth, screenHeight int)
+}
+
+var (
+	viewport *Viewport
+
+	// The size of the screen in pixels
+	screenWidth, screenHeight int
+)
+
+// A viewport that scales the world using the scaling. The world is centered on the screen.
+func NewScalingViewport(s scaling.Scaling, worldWidth, worldHeight float32, camera *Camera) *Viewport {
+	return &Viewport{
+		Camera:      camera,
+		WorldWidth:  worldWidth,
+		WorldHeight: worldHeight,
+		Scaling:     s,
+		update:      updateScalingViewport,
+	}
+}
+
+// A viewport that keeps the aspect ratio by scaling the world up to fit the screen, adding black bars (letterboxing) for
+// the remaining space.
+func NewFitViewport(worldWidth, worldHeight float32, camera *Camera) *Viewport {
+	return NewScalingViewport(scaling.Fit, worldWidth, worldHeight, camera)
+}
+
+// A viewport that keeps the aspect ratio by scaling the world up to take the whole screen, some of the world may be off
+// screen.
+func NewFillViewport(worldWidth, worldHeight float32, camera *Camera) *Viewport {
+	return NewScalingViewport(scaling.Fill, worldWidth, worldHeight, camera)
+}
+
+// A viewport that does not keep the aspect ratio, the world is scaled to take the whole screen.
+func NewStretchViewport(worldWidth, worldHeight float32, camera *Camera) *Viewport {
+	return NewScalingViewport(scaling.Stretch, worldWidth, worldHeight, camera)
+}
+
+// A viewport that keeps the world aspect ratio by extending the world in one direction. The world is first scaled to fit
+// within the screen, then the shorter dimension is lengthened to fill the screen up to the max size, which may leave
+// black bars once it is reached. A max size of 0 means no limit.
+func NewExtendViewport(minWorldWidth, minWorldHeight, maxWorldWidth, maxWorldHeight float32, camera *Camera) *Viewport {
+	return &Viewport{
+		Camera:         camera,
+		WorldWidth:     minWorldWidth,
+		WorldHeight:    minWorldHeight,
+		MinWorldWidth:  minWorldWidth,
+		MinWorldHeight: minWorldHeight,
+		MaxWorldWidth:  maxWorldWidth,
+		MaxWorldHeight: maxWorldHeight,
+		update:         updateExtendViewport,
+	}
+}
+
+// A viewport where the world size is based on the size of the screen. By default 1 world unit == 1 screen pixel, but this
+// ratio can be changed with UnitsPerPixel.
+func NewScreenViewport(camera *Camera) *Viewport {
+	return &Viewport{
+		Camera:        camera,
+		UnitsPerPixel: 1,
+		update:        updateScreenViewport,
+	}
+}
+
+func updateScalingViewport(self *Viewport, screenWidth, screenHeight int) {
+	scaled := self.Scaling.Apply(self.WorldWidth, self.WorldHeight, float32(screenWidth), float32(screenHeight))
+	viewportWidth := round(scaled.X)
+	viewportHeight := round(scaled.Y)
+	self.SetScreenBounds((screenWidth-viewportWidth)/2, (screenHeight-viewportHeight)/2, viewportWidth, viewportHeight)
+}
+
+func updateExtendViewport(self *Viewport, screenWidth, screenHeight int) {
+	worldWidth := self.MinWorldWidth
+	worldHeight := self.MinWorldHeight
+	scaled := scaling.Scaling(scaling.Fit).Apply(worldWidth, worldHeight, float32(screenWidth), float32(screenHeight))
+	viewportWidth := round(scaled.X)
+	viewportHeight := round(scaled.Y)
+	if viewportWidth < screenWidth {
+		toViewportSpace := float32(viewportHeight) / worldHeight
+		lengthen := float32(screenWidth-viewportWidth) * worldHeight / float32(viewportHeight)
+		if self.MaxWorldWidth > 0 && lengthen > self.MaxWorldWidth-self.MinWorldWidth {
+			lengthen = self.MaxWorldWidth - self.MinWorldWidth
+		}
+		worldWidth += lengthen
+		viewportWidth += round(lengthen * toViewportSpace)
+	} else if viewportHeight < screenHeight {
+		toViewportSpace := float32(viewportWidth) / worldWidth
+		lengthen := float32(screenHeight-viewportHeight) * worldWidth / float32(viewportWidth)
+		if self.MaxWorldHeight > 0 && lengthen > self.MaxWorldHeight-self.MinWorldHeight {
+			lengthen = self.MaxWorldHeight - self.MinWorldHeight
+		}
+		worldHeight += lengthen
+		viewportHeight += round(lengthen * toViewportSpace)
+	}
+	self.WorldWidth = worldWidth
+	self.WorldHeight = worldHeight
+	self.SetScreenBounds((screenWidth-viewportWidth)/2, (screenHeight-viewportHeight)/2, viewportWidth, viewportHeight)
+}
+
+func updateScreenViewport(self *Viewport, screenWidth, screenHeight int) {
+	self.SetScreenBounds(0, 0, screenWidth, screenHeight)
+	self.WorldWidth = float32(screenWidth) * self.UnitsPerPixel
+	self.WorldHeight = float32(screenHeight) * self.UnitsPerPixel
+}
+
+func round(value float32) int {
+	return int(math.Floor(float64(value) + 0.5))
+}
+
+// SetViewport sets the viewport of the game and updates it for the current screen size
+func SetViewport(v *Viewport) {
+	viewport = v
+	if screenWidth > 0 && screenHeight > 0 {
+		viewport.Update(screenWidth, screenHeight, true)
+	}
+}
+
+// Returns the viewport of the game
+func GetViewport() *Viewport {
+	return viewport
+}
+
+// Returns the width of the screen in pixels
+func GetScreenWidth() int {
+	return screenWidth
+}
+
+// Returns the height of the screen in pixels
+func GetScreenHeight() int {
+	return screenHeight
+}
+
+// Updates the world size and screen bounds for the size of the screen and applies them to the camera. If centerCamera
+// is true the camera is moved so that the lower left corner of the world is at (0, 0).
+func (self *Viewport) Update(screenWidth, screenHeight int, centerCamera bool) {
+	self.fullWidth = screenWidth
+	self.fullHeight = screenHeight
+	self.update(self, screenWidth, screenHeight)
+	self.Apply(centerCamera)
+}
+
+// Sets the viewport size of the camera to the world size and updates it. The GL viewport is set to the screen bounds
+// before the scene is drawn.
+func (self *Viewport) Apply(centerCamera bool) {
+	self.Camera.ViewportWidth = self.WorldWidth
+	self.Camera.ViewportHeight = self.WorldHeight
+	if centerCamera {
+		self.Camera.Position.Set(self.WorldWidth/2, self.WorldHeight/2, 0)
+	}
+	self.Camera.Update()
+}
+
+// Sets the rectangle of the screen the world is drawn to, the origin is in the lower left corner
+func (self *Viewport) SetScreenBounds(screenX, screenY, screenWidth, screenHeight int) {
+	self.ScreenX = screenX
+	self.ScreenY = screenY
+	self.ScreenWidth = screenWidth
+	self.ScreenHeight = screenHeight
+}
+
+// Transforms the screen coordinates to world coordinates. The screen origin is the upper left corner like the touch
+// events, the world origin is the lower left corner.
+func (self *Viewport) Unproject(screenCoords *Vector2) *Vector2 {
+	tmp := NewVector3(screenCoords.X, screenCoords.Y, 1)
+	self.Camera.unproject(tmp, float32(self.fullHeight), float32(self.ScreenX), float32(self.ScreenY),
+		float32(self.ScreenWidth), float32(self.ScreenHeight))
+	return screenCoords.Set(tmp.X, tmp.Y)
+}
+
+// Transforms the world coordinates to screen coordinates, the origin of both is the lower left corner
+func (self *Viewport) Project(worldCoords *Vector2) *Vector2 {
+	tmp := NewVector3(worldCoords.X, worldCoords.Y, 1)
+	self.Camera.Project(tmp, float32(self.ScreenX), float32(self.ScreenY), float32(self.ScreenWidth), float32(self.ScreenHeight))
+	return worldCoords.Set(tmp.X, tmp.Y)
+}
+
+// Returns the world coordinates of the screen point, the screen origin is the upper left corner
+func (self *Viewport) ToWorld(screenX, screenY float32) (x, y float32) {
+	v := self.Unproject(NewVector2(screenX, screenY))
+	return v.X, v.Y
+}
+
+// Returns the screen coordinates of the world point, the screen origin is the upper left corner
+func (self *Viewport) ToScreen(x, y float32) (screenX, screenY float32) {
+	v := self.Project(NewVector2(x, y))
+	return v.X, float32(self.fullHeight) - v.Y
+}
+
+// Creates a picking Ray from the screen coordinates, the screen origin is the upper left corner. The returned instance
+// is the ray of the camera.
+func (self *Viewport) GetPickRay(screenX, screenY float32) *Ray {
+	ray := self.Camera.Ray
+	self.Camera.unproject(ray.Origin.Set(screenX, screenY, 0), float32(self.fullHeight), float32(self.ScreenX),
+		float32(self.ScreenY), float32(self.ScreenWidth), float32(self.ScreenHeight))
+	self.Camera.unproject(ray.Direction.Set(screenX, screenY, 1), float32(self.fullHeight), float32(self.ScreenX),
+		float32(self.ScreenY), float32(self.ScreenWidth), float32(self.ScreenHeight))
+	ray.Direction.SubV(ray.Origin).Nor()
+	return ray
+}
+
+// Returns the left gutter (black bar) width in screen coordinates
+func (self *Viewport) GetLeftGutterWidth() int {
+	return self.ScreenX
+}
+
+// Returns the right gutter (black bar) x in screen coordinates
+func (self *Viewport) GetRightGutterX() int {
+	return self.ScreenX + self.ScreenWidth
+}
+
+// Returns the right gutter (black bar) width in screen coordinates
+func (self *Viewport) GetRightGutterWidth() int {
+	return self.fullWidth - (self.ScreenX + self.ScreenWidth)
+}
+
+// Returns the bottom gutter (black bar) height in screen coordinates
+func (self *Viewport) GetBottomGutterHeight() int {
+	return self.ScreenY
+}
+
+// Returns the top gutter (black bar) y in screen coordinates, the origin is in the lower left corner
+func (self *Viewport) GetTopGutterY() int {
+	return self.ScreenY + self.ScreenHeight
+}
+
+// Returns the top gutter (black bar) height in screen coordinates
+func (self *Viewport) GetTopGutterHeight() int {
+	return self.fullHeight - (self.ScreenY + self.ScreenHeight)
+}
@@ -0,0 +1,86 @@
+package spike
+
+import (
+	"testing"
+)
+
+func TestViewports(t *testing.T) {
+	camera := func() *Camera {
+		return NewOrthographicCamera(800, 480)
+	}
+	for _, c := range []struct {
+		name                    string
+		viewport                *Viewport
+		screenWidth             int
+		screenHeight            int
+		worldWidth, worldHeight float32
+		bounds                  [4]int
+	}{
+		// letterboxed at the top and bottom
+		{"fit", NewFitViewport(800, 480, camera()), 1000, 1000, 800, 480, [4]int{0, 200, 1000, 600}},
+		// cut at the left and right
+		{"fill", NewFillViewport(800, 480, camera()), 1000, 1000, 800, 480, [4]int{-333, 0, 1667, 1000}},
+		{"stretch", NewStretchViewport(800, 480, camera()), 1000, 1000, 800, 480, [4]int{0, 0, 1000, 1000}},
+		// the height is extended to fill the screen
+		{"extend", NewExtendViewport(800, 480, 0, 0, camera()), 1000, 1000, 800, 800, [4]int{0, 0, 1000, 1000}},
+		// the height is extended up to the max height, then letterboxed
+		{"extend max", NewExtendViewport(800, 480, 0, 600, camera()), 1000, 1000, 800, 600, [4]int{0, 125, 1000, 750}},
+		{"extend wide", NewExtendViewport(800, 480, 0, 0, camera()), 2000, 1000, 800 + 333*0.48, 480,
+			[4]int{0, 0, 2000, 1000}},
+		{"screen", NewScreenViewport(NewOrthographicCamera(1, 1)), 1000, 600, 1000, 600, [4]int{0, 0, 1000, 600}},
+	} {
+		v := c.viewport
+		v.Update(c.screenWidth, c.screenHeight, true)
+		if !near(v.WorldWidth, c.worldWidth, 0.01) || !near(v.WorldHeight, c.worldHeight, 0.01) {
+			t.Errorf("%s world size %v %v, expected %v %v", c.name, v.WorldWidth, v.WorldHeight, c.worldWidth, c.worldHeight)
+		}
+		if bounds := [4]int{v.ScreenX, v.ScreenY, v.ScreenWidth, v.ScreenHeight}; bounds != c.bounds {
+			t.Errorf("%s screen bounds %v, expected %v", c.name, bounds, c.bounds)
+		}
+		if v.Camera.ViewportWidth != v.WorldWidth || v.Camera.ViewportHeight != v.WorldHeight ||
+			v.Camera.Position.X != v.WorldWidth/2 || v.Camera.Position.Y != v.WorldHeight/2 {
+			t.Errorf("%s camera not set to the world", c.name)
+		}
+
+		// the corners of the screen bounds are the corners of the world, the screen origin is the upper left corner and the
+		// rows are unprojected at their bottom edge like in libgdx
+		left, right := float32(v.ScreenX), float32(v.ScreenX+v.ScreenWidth)
+		top, bottom := float32(c.screenHeight-v.ScreenY-v.ScreenHeight), float32(c.screenHeight-v.ScreenY)
+		if x, y := v.ToWorld(left, bottom-1); !near(x, 0, 0.1) || !near(y, 0, 0.1) {
+			t.Errorf("%s lower left corner at %v %v", c.name, x, y)
+		}
+		if x, y := v.ToWorld(right, top-1); !near(x, v.WorldWidth, 0.1) || !near(y, v.WorldHeight, 0.1) {
+			t.Errorf("%s upper right corner at %v %v", c.name, x, y)
+		}
+		if x, y := v.ToWorld((left+right)/2, (top+bottom)/2-1); !near(x, v.WorldWidth/2, 0.1) ||
+			!near(y, v.WorldHeight/2, 0.1) {
+			t.Errorf("%s center at %v %v", c.name, x, y)
+		}
+		if x, y := v.ToScreen(v.WorldWidth/4, v.WorldHeight/4); !near(x, left+(right-left)/4, 0.1) ||
+			!near(y, bottom-(bottom-top)/4, 0.1) {
+			t.Errorf("%s quarter of the world on the screen at %v %v", c.name, x, y)
+		}
+	}
+}
+
+func TestScreenViewportUnitsPerPixel(t *testing.T) {
+	v := NewScreenViewport(NewOrthographicCamera(1, 1))
+	v.UnitsPerPixel = 0.5
+	v.Update(1000, 600, true)
+	if v.WorldWidth != 500 || v.WorldHeight != 300 || v.ScreenWidth != 1000 || v.ScreenHeight != 600 {
+		t.Errorf("world %v %v screen %v %v", v.WorldWidth, v.WorldHeight, v.ScreenWidth, v.ScreenHeight)
+	}
+	if x, y := v.ToWorld(100, 99); !near(x, 50, 0.01) || !near(y, 250, 0.01) {
+		t.Errorf("unprojected to %v %v", x, y)
+	}
+}
+
+func TestViewportGutters(t *testing.T) {
+	v := NewFitViewport(800, 480, NewOrthographicCamera(800, 480))
+	v.Update(2000, 960, true)
+	if v.GetLeftGutterWidth() != 200 || v.GetRightGutterX() != 1800 || v.GetRightGutterWidth() != 200 ||
+		v.GetBottomGutterHeight() != 0 || v.GetTopGutterY() != 960 || v.GetTopGutterHeight() != 0 {
+		t.Errorf("gutters %v %v %v %v %v %v", v.GetLeftGutterWidth(), v.GetRightGutterX(), v.GetRightGutterWidth(),
+			v.GetBottomGutterHeight(), v.GetTopGutterY(), v.GetTopGutterHeight())
+	}
+}
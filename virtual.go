@@ -0,0 +1,210 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/math/vector"
+)
+
+// On-screen thumbsticks and buttons for touch screens. They are actors which claim the first touch pointer that goes down
+// on them and keep it while it is dragged, even outside of the actor, until it is lifted. They feed the input like a
+// controller would, the joystick sends AxisMoved events and the button sends KeyDown and KeyUp events, so they can be
+// bound to actions and axes with NewControllerAxisBinding and NewKeyBinding.
+//
+//   stick := spike.NewVirtualJoystick(20, 260, 200, 200)
+//   jump := spike.NewVirtualButton(680, 340, 100, 100, spike.KeyButtonA)
+//   scene.AddActor(&stick.Actor)
+//   scene.AddActor(&jump.Actor)
+//   spike.BindAxis("move_x", 0, spike.NewControllerAxisBinding(spike.AxisLeftX, 1))
+//   spike.BindAction("jump", spike.NewKeyBinding(spike.KeyButtonA))
+//
+// They have no default look, set their Draw func to draw them.
+
+// A VirtualJoystick is a thumbstick whose knob follows the touch within its radius
+type VirtualJoystick struct {
+	Actor
+
+	// If true the joystick is centered where the touch goes down inside of the actor, otherwise it is always centered in
+	// the actor. Default is false.
+	Floating bool
+
+	// The distance the knob can move away from the center. Default is half the smaller side of the actor.
+	Radius float32
+
+	// The radius of the knob, for drawing. Default is a third of the radius.
+	KnobRadius float32
+
+	// The fraction of the radius around the center in which the knob reports no movement. Default is 0.1.
+	DeadZone float32
+
+	// The controller axes the joystick moves. Default is AxisLeftX and AxisLeftY.
+	XAxis, YAxis ControllerAxis
+
+	// The position of the knob in the range [-1, 1] with the dead zone applied. Like controllers, up and left are
+	// negative.
+	Value vector.Vector2
+
+	// The center of the joystick and of the knob relative to the actor
+	CenterX, CenterY float32
+	KnobX, KnobY     float32
+
+	pointer int
+	point   vector.Vector2
+}
+
+// Creates a fixed joystick centered in the given bounds in stage coordinates
+func NewVirtualJoystick(x, y, w, h float32) *VirtualJoystick {
+	radius := w / 2
+	if h < w {
+		radius = h / 2
+	}
+	self := &VirtualJoystick{
+		Radius:     radius,
+		KnobRadius: radius / 3,
+		DeadZone:   0.1,
+		XAxis:      AxisLeftX,
+		YAxis:      AxisLeftY,
+		pointer:    -1,
+	}
+	self.Actor = Actor{X: x, Y: y, W: w, H: h, SX: 1, SY: 1}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	self.centerKnob()
+	return self
+}
+
+// Returns true while the joystick holds a pointer
+func (self *VirtualJoystick) IsTouched() bool {
+	return self.pointer != -1
+}
+
+func (self *VirtualJoystick) centerKnob() {
+	if !self.Floating || self.pointer == -1 {
+		self.CenterX = self.W / 2
+		self.CenterY = self.H / 2
+	}
+	self.KnobX = self.CenterX
+	self.KnobY = self.CenterY
+}
+
+func (self *VirtualJoystick) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if self.pointer != -1 || !self.IsTouchable() || !self.Hit(e.X, e.Y, &self.point) {
+			return
+		}
+		x, y := self.point.X, self.point.Y
+		if self.Floating {
+			self.CenterX = x
+			self.CenterY = y
+		} else if vector.DstV2(x, y, self.CenterX, self.CenterY) > self.Radius {
+			return
+		}
+		self.pointer = int(e.Pointer)
+		self.moveKnob(x, y)
+	case TouchDragged:
+		if self.pointer == int(e.Pointer) {
+			self.StageToLocalCoordinates(self.point.Set(e.X, e.Y))
+			self.moveKnob(self.point.X, self.point.Y)
+		}
+	case TouchUp:
+		if self.pointer == int(e.Pointer) {
+			self.pointer = -1
+			self.centerKnob()
+			self.setValue(0, 0)
+		}
+	}
+}
+
+// Moves the knob towards x, y relative to the actor, keeping it within the radius
+func (self *VirtualJoystick) moveKnob(x, y float32) {
+	dx, dy := x-self.CenterX, y-self.CenterY
+	length := vector.LenV2(dx, dy)
+	if self.Radius <= 0 || length == 0 {
+		self.KnobX, self.KnobY = self.CenterX, self.CenterY
+		self.setValue(0, 0)
+		return
+	}
+	if length > self.Radius {
+		dx, dy = dx*self.Radius/length, dy*self.Radius/length
+		length = self.Radius
+	}
+	self.KnobX, self.KnobY = self.CenterX+dx, self.CenterY+dy
+	scale := applyDeadZone(length/self.Radius, self.DeadZone) / length
+	// the stage y axis points up while the controller y axis points down
+	self.setValue(dx*scale, -dy*scale)
+}
+
+func (self *VirtualJoystick) setValue(x, y float32) {
+	if x != self.Value.X {
+		self.Value.X = x
+		dispatchInput(InputEvent{Type: AxisMoved, Axis: self.XAxis, Value: x})
+	}
+	if y != self.Value.Y {
+		self.Value.Y = y
+		dispatchInput(InputEvent{Type: AxisMoved, Axis: self.YAxis, Value: y})
+	}
+}
+
+// A VirtualButton is pressed while the pointer it holds is over it
+type VirtualButton struct {
+	Actor
+
+	// The key code the button sends, usually one of the controller buttons like KeyButtonA
+	KeyCode KeyCode
+
+	// True while the button is pressed
+	Pressed bool
+
+	pointer int
+	point   vector.Vector2
+}
+
+// Creates a button in the given bounds in stage coordinates that sends keycode
+func NewVirtualButton(x, y, w, h float32, keycode KeyCode) *VirtualButton {
+	self := &VirtualButton{KeyCode: keycode, pointer: -1}
+	self.Actor = Actor{X: x, Y: y, W: w, H: h, SX: 1, SY: 1}
+	self.Input = func(a *Actor, e InputEvent) {
+		self.input(e)
+	}
+	return self
+}
+
+// Returns true while the button holds a pointer
+func (self *VirtualButton) IsTouched() bool {
+	return self.pointer != -1
+}
+
+func (self *VirtualButton) input(e InputEvent) {
+	switch e.Type {
+	case TouchDown:
+		if self.pointer == -1 && self.IsTouchable() && self.Hit(e.X, e.Y, &self.point) {
+			self.pointer = int(e.Pointer)
+			self.setPressed(true)
+		}
+	case TouchDragged:
+		if self.pointer == int(e.Pointer) {
+			self.setPressed(self.Hit(e.X, e.Y, &self.point))
+		}
+	case TouchUp:
+		if self.pointer == int(e.Pointer) {
+			self.pointer = -1
+			self.setPressed(false)
+		}
+	}
+}
+
+func (self *VirtualButton) setPressed(pressed bool) {
+	if pressed == self.Pressed {
+		return
+	}
+	self.Pressed = pressed
+	if pressed {
+		dispatchInput(InputEvent{Type: KeyDown, KeyCode: self.KeyCode})
+	} else {
+		dispatchInput(InputEvent{Type: KeyUp, KeyCode: self.KeyCode})
+	}
+}
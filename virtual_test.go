@@ -0,0 +1,133 @@
+package spike
+
+import (
+	"testing"
+)
+
+// Returns the events the virtual controls sent since the last call
+func drainInput() []InputEvent {
+	var events []InputEvent
+	for len(InputChannel) > 0 {
+		events = append(events, <-InputChannel)
+	}
+	return events
+}
+
+func TestVirtualJoystick(t *testing.T) {
+	drainInput()
+	// the joystick is moved with its parent, its center is at 220, 170 on the stage and its radius is 100
+	parent := &Actor{X: 100, Y: 50, W: 400, H: 400}
+	stick := NewVirtualJoystick(20, 20, 200, 200)
+	parent.AddActor(&stick.Actor)
+
+	stick.input(newTouchEvent(TouchDown, 50, 50, 0))
+	if stick.IsTouched() {
+		t.Fatal("touch outside of the joystick taken")
+	}
+	stick.input(newTouchEvent(TouchDown, 220, 170, 0))
+	if !stick.IsTouched() || stick.Value.X != 0 || stick.Value.Y != 0 {
+		t.Fatalf("touch on the center gave %v", stick.Value)
+	}
+
+	for _, c := range []struct {
+		x, y, valueX, valueY, knobX, knobY float32
+	}{
+		// within the dead zone
+		{225, 170, 0, 0, 105, 100},
+		// half way, the dead zone is cut from the value
+		{270, 170, 0.4 / 0.9, 0, 150, 100},
+		// the knob stops at the radius
+		{420, 170, 1, 0, 200, 100},
+		// the stage y axis points up and the controller y axis down
+		{220, 70, 0, 1, 100, 0},
+		{220, 270, 0, -1, 100, 200},
+	} {
+		stick.input(newTouchEvent(TouchDragged, c.x, c.y, 0))
+		if !near(stick.Value.X, c.valueX, 0.001) || !near(stick.Value.Y, c.valueY, 0.001) {
+			t.Errorf("dragged to %v %v, value %v expected %v %v", c.x, c.y, stick.Value, c.valueX, c.valueY)
+		}
+		if !near(stick.KnobX, c.knobX, 0.001) || !near(stick.KnobY, c.knobY, 0.001) {
+			t.Errorf("dragged to %v %v, knob %v %v expected %v %v", c.x, c.y, stick.KnobX, stick.KnobY, c.knobX, c.knobY)
+		}
+	}
+	var moved *InputEvent
+	for _, e := range drainInput() {
+		if e.Type == AxisMoved && e.Axis == AxisLeftY {
+			e := e
+			moved = &e
+		}
+	}
+	if moved == nil || moved.Value != -1 {
+		t.Errorf("last y axis event %v", moved)
+	}
+
+	// another pointer does not take the joystick
+	stick.input(newTouchEvent(TouchDown, 220, 170, 1))
+	stick.input(newTouchEvent(TouchUp, 220, 170, 1))
+	if !stick.IsTouched() || stick.Value.Y != -1 {
+		t.Error("joystick taken by a second pointer")
+	}
+	stick.input(newTouchEvent(TouchUp, 220, 270, 0))
+	if stick.IsTouched() || stick.Value.X != 0 || stick.Value.Y != 0 || stick.KnobX != 100 || stick.KnobY != 100 {
+		t.Errorf("released joystick value %v knob %v %v", stick.Value, stick.KnobX, stick.KnobY)
+	}
+
+	// a floating joystick is centered where the touch goes down
+	stick.Floating = true
+	stick.input(newTouchEvent(TouchDown, 150, 100, 0))
+	if stick.CenterX != 30 || stick.CenterY != 30 || stick.Value.X != 0 {
+		t.Errorf("floating center %v %v value %v", stick.CenterX, stick.CenterY, stick.Value)
+	}
+	stick.input(newTouchEvent(TouchDragged, 250, 100, 0))
+	if stick.Value.X != 1 || stick.KnobX != 130 {
+		t.Errorf("floating value %v knob %v", stick.Value, stick.KnobX)
+	}
+	stick.input(newTouchEvent(TouchUp, 250, 100, 0))
+	drainInput()
+}
+
+func TestVirtualButton(t *testing.T) {
+	drainInput()
+	// the button covers 110, 60 to 160, 110 on the stage
+	parent := &Actor{X: 100, Y: 50, W: 400, H: 400}
+	button := NewVirtualButton(10, 10, 50, 50, KeyButtonA)
+	parent.AddActor(&button.Actor)
+
+	sent := func() []InputType {
+		var types []InputType
+		for _, e := range drainInput() {
+			if e.KeyCode == KeyButtonA {
+				types = append(types, e.Type)
+			}
+		}
+		return types
+	}
+
+	button.input(newTouchEvent(TouchDown, 30, 30, 0))
+	if button.IsTouched() || len(sent()) != 0 {
+		t.Fatal("touch outside of the button taken")
+	}
+	button.input(newTouchEvent(TouchDown, 120, 70, 0))
+	if types := sent(); !button.Pressed || len(types) != 1 || types[0] != KeyDown {
+		t.Errorf("pressed button sent %v", types)
+	}
+	// the button is released while the pointer is dragged off it and pressed again when it comes back
+	button.input(newTouchEvent(TouchDragged, 200, 200, 0))
+	if types := sent(); button.Pressed || !button.IsTouched() || len(types) != 1 || types[0] != KeyUp {
+		t.Errorf("dragged off button sent %v", types)
+	}
+	button.input(newTouchEvent(TouchDragged, 150, 100, 0))
+	if types := sent(); !button.Pressed || len(types) != 1 || types[0] != KeyDown {
+		t.Errorf("dragged back button sent %v", types)
+	}
+	button.input(newTouchEvent(TouchUp, 150, 100, 0))
+	if types := sent(); button.Pressed || button.IsTouched() || len(types) != 1 || types[0] != KeyUp {
+		t.Errorf("released button sent %v", types)
+	}
+
+	button.Hidden = true
+	button.input(newTouchEvent(TouchDown, 120, 70, 0))
+	if button.IsTouched() {
+		t.Error("hidden button pressed")
+	}
+}
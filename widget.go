@@ -0,0 +1,170 @@
+// Copyright 2015 pyros2097. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package spike
+
+import (
+	"github.com/pyros2097/spike/g2d"
+	"github.com/pyros2097/spike/math/vector"
+	ui "github.com/pyros2097/spike/ui/utils"
+)
+
+var widgetTransform = vector.NewAffine2Empty()
+
+// The layout state of a widget, which is embedded in the widgets to implement the parts of ui.ILayout which do not depend
+// on their sizes. The widget is laid out by Validate when it was invalidated or its size changed since its last layout.
+type widget struct {
+	actor  *Actor
+	layout ui.ILayout
+
+	needsLayout    bool
+	fillParent     bool
+	layoutDisabled bool
+
+	// the size of the actor when it was last laid out
+	width, height float32
+}
+
+// Makes the actor a widget laid out by the layout, which is the widget embedding the state
+func (self *widget) init(actor *Actor, layout ui.ILayout) {
+	self.actor, self.layout = actor, layout
+	self.needsLayout = true
+	actor.Widget = layout
+}
+
+// Lays the widget out the next time it is validated
+func (self *widget) Invalidate() {
+	self.needsLayout = true
+}
+
+// Invalidates the widget and its parents which are widgets, it is called when the sizes of the widget change
+func (self *widget) InvalidateHierarchy() {
+	self.layout.Invalidate()
+	for parent := self.actor.Parent; parent != nil && parent.Widget != nil; parent = parent.Parent {
+		parent.Widget.Invalidate()
+	}
+}
+
+// Returns whether the widget will be laid out the next time it is validated
+func (self *widget) NeedsLayout() bool {
+	return self.needsLayout || self.actor.W != self.width || self.actor.H != self.height
+}
+
+// Lays the widget out if it needs it. A widget which fills its parent is sized to it first, or to the world of the
+// viewport if its parent is the scene.
+func (self *widget) Validate() {
+	if self.layoutDisabled {
+		return
+	}
+	actor := self.actor
+	if self.fillParent && actor.Parent != nil {
+		if actor.Parent.Parent == nil && viewport != nil {
+			actor.SetSize(viewport.WorldWidth, viewport.WorldHeight)
+		} else {
+			actor.SetSize(actor.Parent.W, actor.Parent.H)
+		}
+	}
+	// the layout may invalidate the widget again, like when it resizes itself, it is laid out a few more times then
+	for i := 0; i < 5 && self.NeedsLayout(); i++ {
+		self.needsLayout = false
+		self.width, self.height = actor.W, actor.H
+		self.layout.Layout()
+	}
+}
+
+// Sizes the widget to its preferred size and lays it out
+func (self *widget) Pack() {
+	self.actor.SetSize(self.layout.GetPrefWidth(), self.layout.GetPrefHeight())
+	self.layout.Invalidate()
+	self.Validate()
+}
+
+// If set the widget is sized to its parent when it is validated, for parents which do not lay out their children like the
+// scene
+func (self *widget) SetFillParent(fillParent bool) {
+	self.fillParent = fillParent
+}
+
+// Enables or disables the layout of the widget and of its children which are widgets. Default is true.
+func (self *widget) SetLayoutEnabled(enabled bool) {
+	self.layoutDisabled = !enabled
+	if enabled {
+		self.InvalidateHierarchy()
+	}
+	for _, child := range self.actor.Children {
+		if child.Widget != nil {
+			child.Widget.SetLayoutEnabled(enabled)
+		}
+	}
+}
+
+// Returns the minimum width of the actor, its width if it is not a widget
+func widgetMinWidth(actor *Actor) float32 {
+	if actor.Widget != nil {
+		return actor.Widget.GetMinWidth()
+	}
+	return actor.W
+}
+
+// Returns the minimum height of the actor, its height if it is not a widget
+func widgetMinHeight(actor *Actor) float32 {
+	if actor.Widget != nil {
+		return actor.Widget.GetMinHeight()
+	}
+	return actor.H
+}
+
+// Returns the preferred width of the actor, its width if it is not a widget
+func widgetPrefWidth(actor *Actor) float32 {
+	if actor.Widget != nil {
+		return actor.Widget.GetPrefWidth()
+	}
+	return actor.W
+}
+
+// Returns the preferred height of the actor, its height if it is not a widget
+func widgetPrefHeight(actor *Actor) float32 {
+	if actor.Widget != nil {
+		return actor.Widget.GetPrefHeight()
+	}
+	return actor.H
+}
+
+// Returns the maximum width of the actor, 0 for no maximum or if it is not a widget
+func widgetMaxWidth(actor *Actor) float32 {
+	if actor.Widget != nil {
+		return actor.Widget.GetMaxWidth()
+	}
+	return 0
+}
+
+// Returns the maximum height of the actor, 0 for no maximum or if it is not a widget
+func widgetMaxHeight(actor *Actor) float32 {
+	if actor.Widget != nil {
+		return actor.Widget.GetMaxHeight()
+	}
+	return 0
+}
+
+// Sets the color of the batch to the color of the actor tinted by the tint, with the alpha multiplied by the parent alpha.
+// Actors without a color are white and a nil tint leaves the color of the actor.
+func setBatchColor(batch g2d.Batch, actor *Actor, tint *Color, parentAlpha float32) {
+	r, g, b, a := float32(1), float32(1), float32(1), parentAlpha
+	if actor.Color != nil {
+		r, g, b, a = actor.Color.R, actor.Color.G, actor.Color.B, actor.Color.A*parentAlpha
+	}
+	if tint != nil {
+		r, g, b, a = r*tint.R, g*tint.G, b*tint.B, a*tint.A
+	}
+	batch.SetColor(r, g, b, a)
+}
+
+// Draws the drawable at the bounds in the coordinates of the actor, if it is not nil
+func drawDrawable(batch g2d.Batch, actor *Actor, drawable ui.Drawable, x, y, width, height float32) {
+	if drawable == nil {
+		return
+	}
+	widgetTransform.Set(actor.computeLocalTransform()).Translate(x, y)
+	drawable.Draw(batch, width, height, widgetTransform)
+}